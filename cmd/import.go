@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/EugeneKrivoshein/music_library/internal/importer"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+)
+
+// runImport выполняет подкоманду import: тот же импорт, что и POST /songs/import,
+// но из локального файла. Отчёт по записям печатается в stdout.
+//
+//	music_library import [-format csv|json|ndjson] [-create-groups=false] [-enrich] [-batch-size N] файл
+func runImport(songService *services.SongService, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "формат файла: csv, json или ndjson; по умолчанию по расширению")
+	createGroups := fs.Bool("create-groups", true, "создавать группы, которых нет в библиотеке")
+	enrich := fs.Bool("enrich", false, "поставить песни с пустыми датой, текстом или ссылкой в очередь обогащения")
+	batchSize := fs.Int("batch-size", services.DefaultImportBatchSize, "число песен в одной транзакции")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("ожидается один файл для импорта, получено %d аргументов", fs.NArg())
+	}
+
+	path := fs.Arg(0)
+	if *format == "" {
+		*format = importer.DetectFormat(path, "")
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := importer.Read(file, *format)
+	if err != nil {
+		return fmt.Errorf("ошибка разбора файла: %w", err)
+	}
+	opts := services.ImportOptions{CreateGroups: *createGroups, Enrich: *enrich, BatchSize: *batchSize}
+	report, err := songService.ImportSongs(rows, opts)
+	if err != nil {
+		return err
+	}
+	printImportReport(os.Stdout, report)
+	return nil
+}
+
+// printImportReport печатает итоги импорта и записи, которые не были добавлены.
+func printImportReport(w io.Writer, report models.ImportReport) {
+	fmt.Fprintf(w, "Добавлено: %d, пропущено: %d, с ошибками: %d\n", report.Created, report.Skipped, report.Failed)
+	for _, row := range report.Rows {
+		if row.Status == models.ImportCreated {
+			continue
+		}
+		fmt.Fprintf(w, "запись %d\t%s\t%s - %s\t%s\n", row.Row, row.Status, row.Group, row.Song, row.Reason)
+	}
+}
@@ -1,18 +1,37 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/EugeneKrivoshein/music_library/config"
 	"github.com/EugeneKrivoshein/music_library/internal/api"
 	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
 	"github.com/EugeneKrivoshein/music_library/internal/db/migrations"
 	"github.com/EugeneKrivoshein/music_library/internal/handlers"
+	"github.com/EugeneKrivoshein/music_library/internal/metadata"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/postgres"
 	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+	"github.com/EugeneKrivoshein/music_library/internal/utils"
 	"github.com/sirupsen/logrus"
 	_ "github.com/swaggo/swag/gen"
 )
 
+const (
+	// defaultServerAddress — адрес сервера, если SERVER_ADDRESS не задан.
+	defaultServerAddress = ":8080"
+	// shutdownTimeout — сколько ждать завершения запросов при остановке сервера.
+	shutdownTimeout = 30 * time.Second
+)
+
 // @title Music Library API
 // @version 1.0
 // @description API для управления библиотекой песен
@@ -49,15 +68,139 @@ func main() {
 	}
 	log.Info("Миграции успешно выполнены")
 
-	songService := services.NewSongService(connect, cfg)
+	blobs, err := storage.NewLocalStorage(cfg.StorageDir)
+	if err != nil {
+		log.Fatalf("Ошибка подготовки хранилища файлов: %v", err)
+	}
+
+	songRepo := postgres.NewSongRepository(connect)
+	groupRepo := postgres.NewGroupRepository(connect)
+	albumRepo := postgres.NewAlbumRepository(connect)
+	tagRepo := postgres.NewTagRepository(connect)
+	playlistRepo := postgres.NewPlaylistRepository(connect)
+	smartPlaylistRepo := postgres.NewSmartPlaylistRepository(connect)
+	audioRepo := postgres.NewAudioRepository(connect)
+	coverRepo := postgres.NewCoverRepository(connect)
+	lyricsRepo := postgres.NewLyricsRepository(connect)
+	enrichmentRepo := postgres.NewEnrichmentRepository(connect)
+
+	apiClient := utils.NewAPIClient(cfg)
+	// Ответы внешнего API кэшируются в памяти и, если включено, в таблице metadata_cache
+	var cacheRepo repository.MetadataCacheRepository
+	if cfg.MetadataCacheDB {
+		cacheRepo = postgres.NewMetadataCacheRepository(connect)
+		if n, err := cacheRepo.DeleteExpired(); err != nil {
+			log.Warnf("Ошибка очистки кэша сведений о песнях: %v", err)
+		} else if n > 0 {
+			log.Infof("Из кэша сведений о песнях удалено устаревших записей: %d", n)
+		}
+	}
+	detailsCache := metadata.NewDetailsCache(apiClient, cacheRepo, metadata.DetailsCacheConfig{
+		Size:        cfg.MetadataCacheSize,
+		TTL:         cfg.MetadataCacheTTL,
+		NegativeTTL: cfg.MetadataCacheNegativeTTL,
+	})
+	providers, err := metadata.NewChainFromConfig(cfg, detailsCache)
+	if err != nil {
+		log.Fatalf("Ошибка настройки источников сведений о песнях: %v", err)
+	}
+	log.Infof("Источники сведений о песнях: %s", strings.Join(providers.Providers(), ", "))
+
+	songService := services.NewSongService(songRepo, groupRepo, albumRepo, blobs)
+	albumService := services.NewAlbumService(albumRepo, groupRepo)
+	groupService := services.NewGroupService(groupRepo, blobs)
+	duplicateService := services.NewDuplicateService(groupRepo, songRepo)
+	tagService := services.NewTagService(tagRepo, songRepo)
+	playlistService := services.NewPlaylistService(playlistRepo, songRepo)
+	smartPlaylistService := services.NewSmartPlaylistService(smartPlaylistRepo, songRepo)
+	audioService := services.NewAudioService(audioRepo, blobs, songService)
+	coverService := services.NewCoverService(coverRepo, blobs, songRepo, groupRepo)
+	lyricsService := services.NewLyricsService(lyricsRepo, songRepo)
+	enrichmentService := services.NewEnrichmentService(enrichmentRepo, songRepo, providers, services.EnrichmentConfig{
+		Workers:      cfg.EnrichWorkers,
+		MaxAttempts:  cfg.EnrichMaxAttempts,
+		PollInterval: cfg.EnrichPollInterval,
+		RetryDelay:   cfg.EnrichRetryDelay,
+		JobTimeout:   cfg.EnrichJobTimeout,
+		Policies:     cfg.EnrichPolicies,
+		Refresh: services.RefreshConfig{
+			Interval: cfg.RefreshInterval,
+			MaxAge:   cfg.RefreshMaxAge,
+			BlankAge: cfg.RefreshBlankAge,
+			Batch:    cfg.RefreshBatch,
+		},
+	})
+
+	if err := groupService.BackfillNormalizedNames(); err != nil {
+		log.Fatalf("Ошибка нормализации названий групп: %v", err)
+	}
+
+	// Подкоманда import выполняет массовый импорт файла и завершает работу, не запуская сервер
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		if err := runImport(songService, os.Args[2:]); err != nil {
+			log.Fatalf("Ошибка импорта: %v", err)
+		}
+		return
+	}
 
 	songHandler := handlers.NewSongHandler(connect, songService, cfg)
+	albumHandler := handlers.NewAlbumHandler(albumService)
+	groupHandler := handlers.NewGroupHandler(groupService)
+	duplicateHandler := handlers.NewDuplicateHandler(duplicateService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	playlistHandler := handlers.NewPlaylistHandler(playlistService)
+	smartPlaylistHandler := handlers.NewSmartPlaylistHandler(smartPlaylistService)
+	audioHandler := handlers.NewAudioHandler(audioService)
+	coverHandler := handlers.NewCoverHandler(coverService)
+	lyricsHandler := handlers.NewLyricsHandler(lyricsService)
+	enrichmentHandler := handlers.NewEnrichmentHandler(enrichmentService)
+	healthHandler := handlers.NewHealthHandler(connect, apiClient, detailsCache)
 
 	// Создаем маршруты для API
-	router := api.NewRouter(songHandler, connect)
+	router := api.NewRouter(songHandler, albumHandler, groupHandler, duplicateHandler, tagHandler, playlistHandler, smartPlaylistHandler, audioHandler, coverHandler, lyricsHandler, enrichmentHandler, healthHandler, connect)
+
+	// Сигнал остановки отменяет ctx: сервер перестаёт принимать запросы, а обработчики
+	// очереди возвращают прерванные задачи в очередь
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Песни, добавленные в том числе подкомандой import, обогащаются в фоне
+	var workers sync.WaitGroup
+	workers.Add(2)
+	go func() {
+		defer workers.Done()
+		enrichmentService.Run(ctx)
+	}()
+	go func() {
+		defer workers.Done()
+		enrichmentService.RunRefresh(ctx)
+	}()
 
-	log.Infof("Сервер запущен на порту %s", cfg.ServerAddress)
-	if err := http.ListenAndServe(":8080", router); err != nil {
+	addr := cfg.ServerAddress
+	if addr == "" {
+		addr = defaultServerAddress
+	}
+	server := &http.Server{Addr: addr, Handler: router}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	log.Infof("Сервер запущен на %s", addr)
+
+	select {
+	case err := <-serverErr:
 		log.Fatalf("Ошибка при запуске сервера: %v", err)
+	case <-ctx.Done():
+	}
+	// Повторный сигнал завершает процесс сразу
+	stop()
+	log.Info("Получен сигнал остановки, завершаем работу")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("Ошибка остановки сервера: %v", err)
 	}
+	workers.Wait()
+	log.Info("Сервер остановлен")
 }
@@ -1,9 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/EugeneKrivoshein/music_library/internal/models"
 	"github.com/joho/godotenv"
 )
 
@@ -17,6 +22,53 @@ type Config struct {
 	ServerAddress string
 	APIURL        string
 	MigrationPass string
+	// StorageDir — каталог локального хранилища аудиофайлов и обложек.
+	StorageDir string
+	// APITimeout ограничивает одну попытку запроса к внешнему API.
+	APITimeout time.Duration
+	// APIMaxRetries — число повторов запроса к внешнему API после первой попытки.
+	APIMaxRetries int
+	// APIBreakerThreshold — число неудачных попыток подряд, после которого
+	// запросы к внешнему API отклоняются сразу.
+	APIBreakerThreshold int
+	// APIBreakerCooldown — сколько запросы отклоняются до пробного.
+	APIBreakerCooldown time.Duration
+	// APIRateLimit — не больше стольких запросов к внешнему API в секунду; 0 — без ограничения.
+	APIRateLimit float64
+	// APIRateBurst — сколько запросов к внешнему API можно сделать подряд без ожидания.
+	APIRateBurst int
+	// MetadataProviders — источники сведений о песнях в порядке опроса: api, fixtures, manual.
+	MetadataProviders []string
+	// MetadataFixtureDir — каталог файлов JSON и YAML для источника fixtures.
+	MetadataFixtureDir string
+	// MetadataCacheSize — сколько ответов внешнего API держать в памяти; 0 отключает кэш в памяти.
+	MetadataCacheSize int
+	// MetadataCacheTTL — сколько хранить найденные сведения о песне.
+	MetadataCacheTTL time.Duration
+	// MetadataCacheNegativeTTL — сколько хранить ответ «песня не найдена».
+	MetadataCacheNegativeTTL time.Duration
+	// MetadataCacheDB включает кэш ответов в таблице базы данных, общий для всех процессов.
+	MetadataCacheDB bool
+	// EnrichWorkers — число обработчиков очереди обогащения; 0 отключает их в этом процессе.
+	EnrichWorkers int
+	// EnrichMaxAttempts — число попыток задачи обогащения до признания её неудачной.
+	EnrichMaxAttempts int
+	// EnrichPollInterval — как часто свободный обработчик проверяет очередь.
+	EnrichPollInterval time.Duration
+	// EnrichRetryDelay — задержка перед первым повтором задачи, дальше она удваивается.
+	EnrichRetryDelay time.Duration
+	// EnrichJobTimeout ограничивает одну попытку задачи обогащения.
+	EnrichJobTimeout time.Duration
+	// EnrichPolicies — политика слияния для каждого поля из models.SongFields.
+	EnrichPolicies map[string]models.MergePolicy
+	// RefreshInterval — как часто планировщик ищет песни для повторного обогащения.
+	RefreshInterval time.Duration
+	// RefreshMaxAge — через сколько после обогащения сведения песни считаются устаревшими.
+	RefreshMaxAge time.Duration
+	// RefreshBlankAge — через сколько обогащения повторяются для песни с пустым текстом или ссылкой.
+	RefreshBlankAge time.Duration
+	// RefreshBatch — сколько песен планировщик ставит в очередь за раз; 0 отключает его.
+	RefreshBatch int
 }
 
 // Функция загрузки конфигурации
@@ -25,7 +77,7 @@ func LoadConfig(envPath string) (*Config, error) {
 		log.Printf("Не удалось загрузить .env: %v. Используются переменные окружения.", err)
 	}
 
-	return &Config{
+	cfg := &Config{
 		DBUser:        os.Getenv("DB_USER"),
 		DBPass:        os.Getenv("DB_PASSWORD"),
 		DBName:        os.Getenv("DB_NAME"),
@@ -34,5 +86,154 @@ func LoadConfig(envPath string) (*Config, error) {
 		ServerAddress: os.Getenv("SERVER_ADDRESS"),
 		APIURL:        os.Getenv("API_URL"),
 		MigrationPass: os.Getenv("MIGRATIONS_PATH"),
-	}, nil
+		StorageDir:    os.Getenv("STORAGE_DIR"),
+		// Без настройки песни, как и раньше, берутся только из внешнего API
+		MetadataProviders:  listEnv("METADATA_PROVIDERS", "api"),
+		MetadataFixtureDir: os.Getenv("METADATA_FIXTURE_DIR"),
+	}
+	var err error
+	if cfg.APITimeout, err = durationEnv("API_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.APIMaxRetries, err = intEnv("API_MAX_RETRIES", 3); err != nil {
+		return nil, err
+	}
+	if cfg.APIBreakerThreshold, err = intEnv("API_BREAKER_THRESHOLD", 5); err != nil {
+		return nil, err
+	}
+	if cfg.APIBreakerCooldown, err = durationEnv("API_BREAKER_COOLDOWN", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.APIRateLimit, err = floatEnv("API_RATE_LIMIT", 5); err != nil {
+		return nil, err
+	}
+	if cfg.APIRateBurst, err = intEnv("API_RATE_BURST", 5); err != nil {
+		return nil, err
+	}
+	if cfg.MetadataCacheSize, err = intEnv("METADATA_CACHE_SIZE", 1000); err != nil {
+		return nil, err
+	}
+	if cfg.MetadataCacheTTL, err = durationEnv("METADATA_CACHE_TTL", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.MetadataCacheNegativeTTL, err = durationEnv("METADATA_CACHE_NEGATIVE_TTL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.MetadataCacheDB, err = boolEnv("METADATA_CACHE_DB", false); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichWorkers, err = intEnv("ENRICH_WORKERS", 4); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichMaxAttempts, err = intEnv("ENRICH_MAX_ATTEMPTS", 5); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichPollInterval, err = durationEnv("ENRICH_POLL_INTERVAL", 2*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichRetryDelay, err = durationEnv("ENRICH_RETRY_DELAY", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.EnrichJobTimeout, err = durationEnv("ENRICH_JOB_TIMEOUT", time.Minute); err != nil {
+		return nil, err
+	}
+	cfg.EnrichPolicies = map[string]models.MergePolicy{}
+	for _, field := range models.SongFields {
+		key := "ENRICH_POLICY_" + strings.ToUpper(field)
+		if cfg.EnrichPolicies[field], err = policyEnv(key, models.MergeKeepManual); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.RefreshInterval, err = durationEnv("REFRESH_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshMaxAge, err = durationEnv("REFRESH_MAX_AGE", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshBlankAge, err = durationEnv("REFRESH_BLANK_AGE", 24*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.RefreshBatch, err = intEnv("REFRESH_BATCH", 100); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// durationEnv читает длительность вида 10s или 1m30s; без переменной возвращает def.
+func durationEnv(key string, def time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("некорректное значение %s: %q", key, value)
+	}
+	return d, nil
+}
+
+// listEnv читает список через запятую; без переменной разбирает def.
+func listEnv(key, def string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = def
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// intEnv читает неотрицательное целое; без переменной возвращает def.
+func intEnv(key string, def int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("некорректное значение %s: %q", key, value)
+	}
+	return n, nil
+}
+
+// floatEnv читает неотрицательное число; без переменной возвращает def.
+func floatEnv(key string, def float64) (float64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("некорректное значение %s: %q", key, value)
+	}
+	return f, nil
+}
+
+// policyEnv читает политику слияния: keep-manual, fill-blank или overwrite; без переменной возвращает def.
+func policyEnv(key string, def models.MergePolicy) (models.MergePolicy, error) {
+	value := models.MergePolicy(os.Getenv(key))
+	if value == "" {
+		return def, nil
+	}
+	if !models.ValidMergePolicy(value) {
+		return "", fmt.Errorf("некорректное значение %s: %q", key, value)
+	}
+	return value, nil
+}
+
+// boolEnv читает true или false; без переменной возвращает def.
+func boolEnv(key string, def bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("некорректное значение %s: %q", key, value)
+	}
+	return b, nil
 }
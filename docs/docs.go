@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
+        "/albums": {
             "get": {
-                "description": "Возвращает список песен с фильтрацией по группе и названию песни, а также поддержкой пагинации.",
+                "description": "Возвращает альбомы с фильтрацией по группе и названию и поддержкой пагинации.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,22 +25,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Songs"
+                    "Albums"
                 ],
-                "summary": "Получить список песен",
+                "summary": "Получить список альбомов",
                 "parameters": [
                     {
-                        "type": "string",
-                        "default": "",
-                        "description": "Название группы",
-                        "name": "group",
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "group_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "default": "",
-                        "description": "Название песни",
-                        "name": "song",
+                        "description": "Название альбома",
+                        "name": "title",
                         "in": "query"
                     },
                     {
@@ -60,11 +58,11 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список песен",
+                        "description": "Список альбомов",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/handlers.Song"
+                                "$ref": "#/definitions/handlers.Album"
                             }
                         }
                     },
@@ -74,6 +72,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "Ошибка сервера",
                         "schema": {
@@ -81,11 +85,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/songs/add": {
+            },
             "post": {
-                "description": "Добавляет новую песню, используя данные внешнего API.",
+                "description": "Создаёт альбом группы. Тип альбома по умолчанию — LP.",
                 "consumes": [
                     "application/json"
                 ],
@@ -93,25 +95,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Songs"
+                    "Albums"
                 ],
-                "summary": "Добавить песню через API",
+                "summary": "Создать альбом",
                 "parameters": [
                     {
-                        "description": "Данные песни",
+                        "description": "Данные альбома",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.Song"
+                            "$ref": "#/definitions/handlers.CreateAlbumRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Песня успешно добавлена",
+                        "description": "Созданный альбом",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.Album"
                         }
                     },
                     "400": {
@@ -120,8 +122,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Ошибка добавления песни",
+                        "description": "Ошибка создания альбома",
                         "schema": {
                             "type": "string"
                         }
@@ -129,9 +137,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/songs/{id}": {
+        "/albums/{id}": {
             "get": {
-                "description": "Возвращает текст песни построчно.",
+                "description": "Возвращает альбом по ID. Песни альбома доступны через GET /songs?album_id=.",
                 "consumes": [
                     "application/json"
                 ],
@@ -139,13 +147,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Songs"
+                    "Albums"
                 ],
-                "summary": "Получить текст песни",
+                "summary": "Получить альбом",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -153,9 +161,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Текст песни",
+                        "description": "Альбом",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handlers.Album"
                         }
                     },
                     "400": {
@@ -164,8 +172,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Ошибка получения текста песни",
+                        "description": "Ошибка сервера",
                         "schema": {
                             "type": "string"
                         }
@@ -173,7 +187,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Обновляет данные песни по её ID.",
+                "description": "Обновляет переданные поля альбома.",
                 "consumes": [
                     "application/json"
                 ],
@@ -181,30 +195,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Songs"
+                    "Albums"
                 ],
-                "summary": "Обновить песню",
+                "summary": "Обновить альбом",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Обновляемые данные песни",
+                        "description": "Обновляемые данные альбома",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.Song"
+                            "$ref": "#/definitions/handlers.UpdateAlbumRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Песня успешно обновлена",
+                        "description": "Альбом успешно обновлён",
                         "schema": {
                             "type": "string"
                         }
@@ -215,8 +229,14 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Альбом или группа не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Ошибка обновления песни",
+                        "description": "Ошибка обновления альбома",
                         "schema": {
                             "type": "string"
                         }
@@ -224,7 +244,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Удаляет песню из библиотеки по ID.",
+                "description": "Удаляет альбом по ID. Песни альбома остаются в библиотеке без привязки к нему.",
                 "consumes": [
                     "application/json"
                 ],
@@ -232,13 +252,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Songs"
+                    "Albums"
                 ],
-                "summary": "Удалить песню",
+                "summary": "Удалить альбом",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID песни",
+                        "description": "ID альбома",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -246,7 +266,7 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "204": {
-                        "description": "Песня успешно удалена",
+                        "description": "Альбом успешно удалён",
                         "schema": {
                             "type": "string"
                         }
@@ -257,31 +277,5039 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
-                        "description": "Ошибка удаления песни",
+                        "description": "Ошибка удаления альбома",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.Song": {
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "release_date": {
-                    "type": "string"
-                },
-                "song": {
-                    "type": "string"
+        },
+        "/covers/{kind}/{id}": {
+            "get": {
+                "description": "Отдаёт обложку песни или группы: исходное изображение или, с параметром size, миниатюру, большая сторона которой не больше size. Миниатюры создаются при первом запросе и хранятся на диске; изображение меньше запрошенного размера отдаётся как есть. Адрес с параметром v из cover_url кешируется бессрочно, остальные ответы сверяются по ETag.",
+                "produces": [
+                    "image/jpeg",
+                    "image/png"
+                ],
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Получить обложку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "enum": [
+                            "song",
+                            "group"
+                        ],
+                        "description": "Владелец обложки",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID песни или группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "enum": [
+                            64,
+                            128,
+                            256,
+                            512
+                        ],
+                        "description": "Размер миниатюры в пикселях",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Версия обложки из cover_url",
+                        "name": "v",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Изображение",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или размер",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Владелец или обложка не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка чтения файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/duplicates": {
+            "get": {
+                "description": "Возвращает пары групп и песен, названия которых совпадают без учёта регистра, артикля «The» и алфавита или похожи по триграммам не меньше порога. Песни сравниваются в пределах групп с одинаковым нормализованным названием.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Найти дубликаты",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "default": 0.6,
+                        "description": "Минимальное сходство названий (0..1)",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимум пар каждого вида",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Кандидаты в дубликаты",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DuplicatesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrichment/jobs": {
+            "get": {
+                "description": "Возвращает задачи очереди обогащения, начиная с недавно изменённых. У каждой песни не больше одной задачи; у неудачных задач в last_error — ошибка последней попытки.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enrichment"
+                ],
+                "summary": "Получить задачи обогащения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "enum": [
+                            "queued",
+                            "running",
+                            "done",
+                            "failed"
+                        ],
+                        "description": "Состояние задачи",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "song_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество задач на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задачи обогащения",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.EnrichmentJob"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения задач",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrichment/jobs/requeue": {
+            "post": {
+                "description": "Ставит в очередь заново все задачи в состоянии failed, сбрасывая число попыток и последнюю ошибку.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enrichment"
+                ],
+                "summary": "Перезапустить неудачные задачи обогащения",
+                "responses": {
+                    "200": {
+                        "description": "Число перезапущенных задач",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RequeueFailedResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка перезапуска задач",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrichment/jobs/{id}": {
+            "get": {
+                "description": "Возвращает задачу очереди обогащения по её ID: состояние, число попыток, время следующей попытки и последнюю ошибку.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enrichment"
+                ],
+                "summary": "Получить задачу обогащения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача обогащения",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EnrichmentJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения задачи",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrichment/jobs/{id}/requeue": {
+            "post": {
+                "description": "Ставит задачу в очередь заново: сбрасывает число попыток и последнюю ошибку, песня получает статус pending. Выполняющуюся задачу перезапустить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enrichment"
+                ],
+                "summary": "Перезапустить задачу обогащения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID задачи",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Задача поставлена в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EnrichmentJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Задача не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача выполняется",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка перезапуска задачи",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/enrichment/refresh": {
+            "post": {
+                "description": "Делает то же, что очередной проход планировщика: ставит в очередь песни, обогащённые больше REFRESH_MAX_AGE назад, а песни с пустым текстом или ссылкой — обогащённые больше REFRESH_BLANK_AGE назад или ни разу. Полные песни, которые ни разу не обогащались (добавленные до появления очереди или вручную), не выбираются. Песни с неудачным обогащением не выбираются. Заполненные поля обновляются по политикам ENRICH_POLICY_*: keep-manual не трогает поля, введённые вручную, fill-blank только заполняет пустые, overwrite всегда перезаписывает.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enrichment"
+                ],
+                "summary": "Поставить устаревшие песни в очередь обогащения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Сколько песен поставить; по умолчанию REFRESH_BATCH",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Число поставленных песен",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный limit",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка постановки в очередь",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Возвращает жанры по алфавиту вместе с числом песен.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Получить список жанров",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список жанров",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
+                "description": "Возвращает группы с поиском по названию, фильтром по стране и поддержкой пагинации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Получить список групп",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поиск по названию группы",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Страна",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список групп",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Group"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Добавляет группу в библиотеку. Название группы должно быть уникальным.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Создать группу",
+                "parameters": [
+                    {
+                        "description": "Данные группы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданная группа",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Группа уже существует",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания группы",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "description": "Возвращает группу по ID вместе с числом её песен и прежними названиями слитых в неё дубликатов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Получить группу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Группа",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Обновляет переданные поля группы, в том числе переименовывает её.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Обновить группу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновляемые данные группы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Группа успешно обновлена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Группа с таким названием уже существует",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления группы",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет группу по ID. Группа с песнями или альбомами удаляется только при cascade=true вместе с ними, иначе возвращается 409.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Удалить группу",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Удалить песни и альбомы группы",
+                        "name": "cascade",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Группа успешно удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или параметр cascade",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "У группы есть песни или альбомы",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления группы",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/albums": {
+            "get": {
+                "description": "Возвращает альбомы группы в порядке выхода.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Albums"
+                ],
+                "summary": "Получить альбомы группы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список альбомов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Album"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создаёт альбом группы, указанной в пути. Тип альбома по умолчанию — LP.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Albums"
+                ],
+                "summary": "Создать альбом группы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные альбома",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateAlbumRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданный альбом",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания альбома",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/cover": {
+            "post": {
+                "description": "Сохраняет обложку группы, заменяя прежнюю. Принимает изображение JPEG или PNG либо аудиофайл со встроенной обложкой. Обложка группы показывается у песен без своей обложки.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Загрузить обложку группы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Изображение или аудиофайл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обложка сохранена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Cover"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID, файл не изображение или в аудиофайле нет обложки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет обложку группы вместе с миниатюрами.",
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Удалить обложку группы",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Обложка удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа или обложка не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления обложки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}/merge": {
+            "post": {
+                "description": "Переносит песни и альбомы группы в target_id, сохраняет её название псевдонимом целевой группы (псевдоним с таким названием у другой группы переходит к целевой) и удаляет её.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Groups"
+                ],
+                "summary": "Слить группу-дубликат",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID группы-дубликата",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Целевая группа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MergeGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Группа после слияния",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Group"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка слияния групп",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Проверяет базу данных и показывает состояние клиента внешнего API: выключатель (closed, open, half-open), счётчики запросов, повторов и отказов, последнюю ошибку, а также счётчики кэша его ответов (попадания, промахи, объединённые запросы). Без базы данных сервис недоступен (503); если выключатель внешнего API не замкнут, сервис работает с ограничениями (degraded), но отвечает 200.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Состояние сервиса",
+                "responses": {
+                    "200": {
+                        "description": "Сервис работает",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Health"
+                        }
+                    },
+                    "503": {
+                        "description": "База данных недоступна",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists": {
+            "get": {
+                "description": "Возвращает плейлисты по названию с числом песен и поддержкой пагинации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Получить список плейлистов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список плейлистов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Playlist"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Создаёт пустой плейлист.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Создать плейлист",
+                "parameters": [
+                    {
+                        "description": "Данные плейлиста",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreatePlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданный плейлист",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "description": "Возвращает плейлист по ID. Песни плейлиста доступны через GET /playlists/{id}/items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Получить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Переименовывает плейлист или меняет его описание.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Обновить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновляемые данные плейлиста",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdatePlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Плейлист успешно обновлён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет плейлист. Песни остаются в библиотеке.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Удалить плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Плейлист успешно удалён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/items": {
+            "get": {
+                "description": "Возвращает песни плейлиста по порядку позиций в том же представлении, что и GET /songs, с ID элемента и позицией. Параметр format или заголовок Accept переключают ответ на потоковую выгрузку M3U8, XSPF, CSV или NDJSON; без page и limit выгружается весь плейлист.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Получить песни плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "json",
+                            "m3u8",
+                            "xspf",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "description": "Формат ответа; важнее заголовка Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песни плейлиста",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.PlaylistItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Вставляет песню на указанную позицию, сдвигая последующие, или в конец плейлиста. Одна песня может входить в плейлист несколько раз.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Добавить песню в плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Песня и позиция",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddPlaylistItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "ID нового элемента",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddPlaylistItemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или песня не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка добавления песни в плейлист",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/items/{item_id}": {
+            "put": {
+                "description": "Переносит элемент на новую позицию; позиции остальных элементов пересчитываются без пропусков.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Переместить песню в плейлисте",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID элемента плейлиста",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая позиция",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.MovePlaylistItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня перемещена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или элемент не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка перемещения песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет элемент плейлиста; последующие элементы сдвигаются на его место.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Playlists"
+                ],
+                "summary": "Убрать песню из плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID элемента плейлиста",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Песня убрана из плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Плейлист или элемент не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления песни из плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/smart-playlists": {
+            "get": {
+                "description": "Возвращает умные плейлисты вместе с их правилами и поддержкой пагинации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartPlaylists"
+                ],
+                "summary": "Получить список умных плейлистов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список умных плейлистов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.SmartPlaylist"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Сохраняет правила отбора песен. Условия задают поле, оператор и значение, группы объединяют вложенные правила через match=all|any. Правила проверяются при сохранении.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartPlaylists"
+                ],
+                "summary": "Создать умный плейлист",
+                "parameters": [
+                    {
+                        "description": "Данные умного плейлиста",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateSmartPlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданный умный плейлист",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SmartPlaylist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные или правила",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка создания умного плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/smart-playlists/{id}": {
+            "get": {
+                "description": "Возвращает умный плейлист по ID. Подходящие под правила песни доступны через GET /smart-playlists/{id}/songs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartPlaylists"
+                ],
+                "summary": "Получить умный плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID умного плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Умный плейлист",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SmartPlaylist"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Умный плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Переименовывает умный плейлист, меняет описание или заменяет правила целиком.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartPlaylists"
+                ],
+                "summary": "Обновить умный плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID умного плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновляемые данные умного плейлиста",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateSmartPlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Умный плейлист успешно обновлён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID, формат данных или правила",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Умный плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления умного плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет умный плейлист по ID. Песни библиотеки не затрагиваются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "SmartPlaylists"
+                ],
+                "summary": "Удалить умный плейлист",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID умного плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Умный плейлист успешно удалён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Умный плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления умного плейлиста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/smart-playlists/{id}/songs": {
+            "get": {
+                "description": "Подбирает песни по правилам плейлиста на момент запроса с поддержкой пагинации. Параметр format или заголовок Accept переключают ответ на потоковую выгрузку M3U8, XSPF, CSV или NDJSON; без page и limit выгружаются все подходящие песни.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "SmartPlaylists"
+                ],
+                "summary": "Получить песни умного плейлиста",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID умного плейлиста",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "json",
+                            "m3u8",
+                            "xspf",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "description": "Формат ответа; важнее заголовка Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песни, подходящие под правила",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Умный плейлист не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Возвращает список песен с фильтрацией по группе, названию песни, жанрам и тегам, а также поддержкой пагинации. При fuzzy=true поиск ведётся по сходству триграмм с учётом опечаток и транслитерации, результаты упорядочены по score. Параметр format или заголовок Accept переключают ответ на потоковую выгрузку M3U8 (песни со ссылкой), XSPF, CSV или NDJSON; без page и limit выгружается весь список.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Получить список песен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "",
+                        "description": "Название группы",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "",
+                        "description": "Название песни",
+                        "name": "song",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID альбома",
+                        "name": "album_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Нечёткий поиск по сходству",
+                        "name": "fuzzy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.25,
+                        "description": "Минимальное сходство для нечёткого поиска (0..1)",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Жанр; параметр можно повторять или перечислять жанры через запятую",
+                        "name": "genre",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тег; параметр можно повторять или перечислять теги через запятую",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "any",
+                        "enum": [
+                            "any",
+                            "all"
+                        ],
+                        "description": "Песня должна иметь хотя бы один (any) или все (all) перечисленные жанры и теги",
+                        "name": "match",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "json",
+                            "m3u8",
+                            "xspf",
+                            "csv",
+                            "ndjson"
+                        ],
+                        "description": "Формат ответа; важнее заголовка Accept",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список песен",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Song"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/add": {
+            "post": {
+                "description": "Сразу добавляет песню со статусом обогащения pending и ставит её в очередь. Обработчики очереди запрашивают дату выпуска, текст и ссылку у первого источника, который знает песню; источники и их порядок задаёт METADATA_PROVIDERS: внешний API (api), каталог файлов JSON/YAML (fixtures) и ручное заполнение (manual). Неудачные попытки повторяются с нарастающей задержкой, итог виден в enrichment_status песни и в GET /enrichment/jobs. Группа ищется по названию без учёта регистра, артикля «The» и алфавита, а также по прежним названиям. Неизвестная группа создаётся только при create_group=true, иначе возвращается 422 с похожими названиями.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Добавить песню через API",
+                "parameters": [
+                    {
+                        "description": "Данные песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddSongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Песня добавлена и поставлена в очередь обогащения",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AddSongResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные входные данные",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Альбом не найден",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка добавления песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/audio": {
+            "post": {
+                "description": "Добавляет по песне на каждый файл из полей file: группа, название, альбом, дата, номер трека и текст берутся из тегов, а без исполнителя или названия в тегах — из имени файла «Исполнитель - Название». Файл песни, которая уже есть у группы без аудио, привязывается к ней; песни с аудиофайлом пропускаются. Номер записи в отчёте — номер файла в запросе.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audio"
+                ],
+                "summary": "Добавить песни по аудиофайлам",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Аудиофайл; поле можно повторять",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Создавать группы, которых нет в библиотеке",
+                        "name": "create_groups",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчёт о загрузке",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная форма",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файлы слишком большие",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import": {
+            "post": {
+                "description": "Добавляет песни из CSV (колонки group, song и необязательные release_date, text, link, genres, tags), JSON-массива или NDJSON пакетами в транзакциях. Песни, которые уже есть у группы, пропускаются. Возвращает отчёт по каждой записи.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Импортировать песни из файла",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Файл с песнями",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "csv",
+                            "json",
+                            "ndjson"
+                        ],
+                        "description": "Формат файла; по умолчанию определяется по расширению",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Создавать группы, которых нет в библиотеке",
+                        "name": "create_groups",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Поставить песни с пустыми датой, текстом или ссылкой в очередь обогащения",
+                        "name": "enrich",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Число песен в одной транзакции",
+                        "name": "batch_size",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчёт об импорте",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный файл или параметры",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка импорта",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/import/playlist": {
+            "post": {
+                "description": "Разбирает плейлист M3U/M3U8 (строки #EXTINF:длительность,Исполнитель - Название или имена файлов) или XSPF и добавляет песни по одной, как POST /songs/add: сведения о них заполнит очередь обогащения. При dry_run=true только показывает, какие песни и группы будут добавлены.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Импортировать песни из плейлиста",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Плейлист",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "m3u",
+                            "xspf"
+                        ],
+                        "description": "Формат плейлиста; по умолчанию определяется по расширению",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": true,
+                        "description": "Создавать группы, которых нет в библиотеке",
+                        "name": "create_groups",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Пробный импорт без сохранения",
+                        "name": "dry_run",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчёт об импорте",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный файл или параметры",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/search": {
+            "get": {
+                "description": "Ищет по текстам, названиям песен и групп с учётом русской и английской морфологии. Результаты упорядочены по релевантности, совпадения в фрагменте выделены тегом <b>.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Полнотекстовый поиск песен",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос (поддерживает синтаксис websearch: кавычки, or, -)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "enum": [
+                            "ru",
+                            "en"
+                        ],
+                        "description": "Морфология: ru, en или пусто для обеих",
+                        "name": "lang",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Найденные песни",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Возвращает текст песни построчно.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Получить текст песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения текста песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Обновляет данные песни по её ID. Изменённый текст сохраняется новой версией в истории текста вместе с автором правки. Изменённые дата выпуска, текст и ссылка отмечаются как введённые вручную, и повторное обогащение с политикой keep-manual их не перезаписывает.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Обновить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Обновляемые данные песни",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateSongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня успешно обновлена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или альбом не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "422": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка обновления песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет песню из библиотеки по ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Удалить песню",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Песня успешно удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления песни",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/audio": {
+            "get": {
+                "description": "Возвращает формат, размер, контрольную сумму и длительность аудиофайла песни.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audio"
+                ],
+                "summary": "Получить сведения об аудиофайле песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аудиофайл песни",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AudioFile"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или аудиофайл не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Сохраняет аудиофайл MP3, FLAC, Ogg Vorbis/Opus или MP4/M4A, заменяя прежний. По тегам файла (ID3v2/ID3v1, комментарии Vorbis, атомы MP4) заполняются пустые поля песни: текст, дата выпуска (если в тегах только год — 1 января этого года), альбом, номера трека и диска; группа и название не меняются.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audio"
+                ],
+                "summary": "Загрузить аудиофайл песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Аудиофайл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аудиофайл сохранён",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AudioUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или неподдерживаемый формат файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет аудиофайл песни из хранилища; сама песня остаётся.",
+                "tags": [
+                    "Audio"
+                ],
+                "summary": "Удалить аудиофайл песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Аудиофайл удалён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или аудиофайл не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления аудиофайла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/cover": {
+            "post": {
+                "description": "Сохраняет обложку песни, заменяя прежнюю. Принимает изображение JPEG или PNG либо аудиофайл MP3, FLAC, Ogg или M4A — тогда берётся встроенная в него обложка (передняя, если их несколько).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Загрузить обложку песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Изображение или аудиофайл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обложка сохранена",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Cover"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID, файл не изображение или в аудиофайле нет обложки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет обложку песни вместе с миниатюрами.",
+                "tags": [
+                    "Covers"
+                ],
+                "summary": "Удалить обложку песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Обложка удалена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или обложка не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления обложки",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/enrich": {
+            "post": {
+                "description": "Ставит песню в очередь обогащения или перезапускает её задачу; обработчики заполнят пустые дату выпуска, текст и ссылку, а заполненные обновят по политикам ENRICH_POLICY_*; при keep-manual поля, введённые или очищенные вручную, не меняются. Песню, задача которой выполняется, поставить нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Enrichment"
+                ],
+                "summary": "Поставить песню в очередь обогащения",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Песня поставлена в очередь",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.EnrichmentJob"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "Задача песни выполняется",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка постановки в очередь",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/genres": {
+            "post": {
+                "description": "Отмечает песню жанрами, создавая недостающие. Названия приводятся к нижнему регистру.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Добавить жанры песне",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Названия жанров",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AttachTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня с обновлёнными жанрами",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка добавления жанров",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/genres/{name}": {
+            "delete": {
+                "description": "Снимает жанр с песни. Сам жанр остаётся в справочнике.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Убрать жанр у песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название жанра",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Жанр снят",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Жанр не привязан к песне",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка снятия жанра",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics": {
+            "get": {
+                "description": "Возвращает текст LRC как загружен (format=lrc), массив строк с временем в миллисекундах (format=json) или текст без отметок времени (format=plain). С параметром at возвращает только строку, которая звучит в этот момент: в json — объект с номером строки и временем следующей, в lrc — строку с отметкой, в plain — сам текст строки; до первой строки номер равен -1, а в plain и lrc ответ пустой.",
+                "produces": [
+                    "application/json",
+                    "text/plain"
+                ],
+                "tags": [
+                    "Lyrics"
+                ],
+                "summary": "Получить синхронизированный текст песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "enum": [
+                            "lrc",
+                            "json",
+                            "plain"
+                        ],
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Момент воспроизведения: миллисекунды или длительность вида 1m23.5s",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Строки текста; с at — handlers.CurrentLyricsLine",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LyricsLine"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID, формат или момент",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или синхронизированный текст не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения текста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Проверяет текст в формате LRC и сохраняет его, заменяя прежний. Текст передаётся телом запроса или файлом в поле file multipart-формы. Поддерживаются отметки [mm:ss], [mm:ss.xx] и [mm:ss.xxx], несколько отметок у одной строки, теги сведений ([ar:], [ti:], [al:]) и сдвиг [offset:]; отметки слов расширенного LRC (<mm:ss.xx>) отбрасываются. Строка без отметки времени — ошибка с номером строки. Простой текст песни не меняется.",
+                "consumes": [
+                    "text/plain",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lyrics"
+                ],
+                "summary": "Загрузить синхронизированный текст песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Текст LRC",
+                        "name": "lyrics",
+                        "in": "body",
+                        "required": false,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текст сохранён",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.LyricsUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или ошибка в тексте LRC",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "Текст слишком большой",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сохранения текста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Удаляет текст LRC песни; простой текст песни остаётся.",
+                "tags": [
+                    "Lyrics"
+                ],
+                "summary": "Удалить синхронизированный текст песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Текст удалён",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или синхронизированный текст не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка удаления текста",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions": {
+            "get": {
+                "description": "Возвращает версии текста песни постранично, начиная с последней: номер, автора, время и размер текста. Версия добавляется при каждом изменении текста.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Revisions"
+                ],
+                "summary": "Получить историю текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество версий на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Версии текста",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TextRevisionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения версий",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/diff": {
+            "get": {
+                "description": "Возвращает построчную разницу между версиями from и to в едином формате (diff -u). По умолчанию to — последняя версия, а from — предыдущая перед to; версия 0 — пустой текст. Если тексты совпадают, разница пустая; если им нужно больше 1000 правок, всё между общими началом и концом показывается заменённым целиком. С format=json разница возвращается вместе со сведениями о версиях.",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "Revisions"
+                ],
+                "summary": "Сравнить версии текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Старая версия; по умолчанию — предыдущая перед to",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Новая версия; по умолчанию — последняя",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Число общих строк вокруг изменений",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "diff",
+                        "enum": [
+                            "diff",
+                            "json"
+                        ],
+                        "description": "Формат ответа",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Разница в формате diff -u; с format=json — handlers.TextDiff",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или версия не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сравнения версий",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}": {
+            "get": {
+                "description": "Возвращает версию текста песни по номеру вместе с текстом.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Revisions"
+                ],
+                "summary": "Получить версию текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер версии",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Версия текста",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TextRevisionDetails"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или номер версии",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или версия не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения версии",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions/{rev}/restore": {
+            "post": {
+                "description": "Делает текст выбранной версии текущим текстом песни. Откат сохраняется в истории новой версией с указанным автором, так что его тоже можно отменить; если текст уже совпадает, новая версия не создаётся. Возвращает последнюю версию.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Revisions"
+                ],
+                "summary": "Восстановить версию текста песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер версии",
+                        "name": "rev",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Автор отката",
+                        "name": "input",
+                        "in": "body",
+                        "required": false,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RestoreRevisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Текущая версия текста",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TextRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID, номер версии или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или версия не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка восстановления версии",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/stream": {
+            "get": {
+                "description": "Отдаёт аудиофайл песни с поддержкой диапазонов (Range, ответ 206), чтобы плеер мог перематывать. ETag — контрольная сумма файла; поддерживаются If-Range, If-None-Match и If-Modified-Since.",
+                "produces": [
+                    "audio/mpeg",
+                    "audio/flac",
+                    "audio/ogg",
+                    "audio/mp4"
+                ],
+                "tags": [
+                    "Audio"
+                ],
+                "summary": "Воспроизвести аудиофайл песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Диапазон байтов, например bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Аудиофайл целиком",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Запрошенный диапазон",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня или аудиофайл не найдены",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "416": {
+                        "description": "Диапазон вне файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка чтения файла",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/tags": {
+            "post": {
+                "description": "Отмечает песню тегами, создавая недостающие. Названия приводятся к нижнему регистру.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Добавить теги песне",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Названия тегов",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AttachTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Песня с обновлёнными тегами",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Song"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID или формат данных",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка добавления тегов",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/tags/{name}": {
+            "delete": {
+                "description": "Снимает тег с песни. Сам тег остаётся в справочнике.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Убрать тег у песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название тега",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Тег снят",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Тег не привязан к песне",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка снятия тега",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/verses": {
+            "get": {
+                "description": "Делит текст песни на куплеты по пустым строкам и возвращает их постранично.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Songs"
+                ],
+                "summary": "Получить куплеты песни",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID песни",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество куплетов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Куплеты песни",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VersesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Песня не найдена",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка получения куплетов",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags": {
+            "get": {
+                "description": "Возвращает теги по алфавиту вместе с числом песен.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Получить список тегов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Номер страницы",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Количество элементов на странице",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список тегов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.Tag"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/tags/cloud": {
+            "get": {
+                "description": "Возвращает самые частые теги с числом песен и относительным весом.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tags"
+                ],
+                "summary": "Получить облако тегов",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное число тегов",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Облако тегов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.TagCloudItem"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры запроса",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.AddPlaylistItemRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "description": "Позиция вставки, начиная с 1; если не задана — в конец",
+                    "type": "integer"
+                },
+                "song_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.AddPlaylistItemResponse": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.AddSongRequest": {
+            "type": "object",
+            "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "create_group": {
+                    "description": "Создать группу, если её нет в библиотеке",
+                    "type": "boolean"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.AddSongResponse": {
+            "type": "object",
+            "properties": {
+                "enrichment_status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.Album": {
+            "type": "object",
+            "properties": {
+                "album_type": {
+                    "type": "string",
+                    "enum": [
+                        "LP",
+                        "EP",
+                        "single"
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AttachTagsRequest": {
+            "type": "object",
+            "properties": {
+                "names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.AudioFile": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "SHA-256 содержимого",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "description": "Длительность в миллисекундах, 0 — не удалось определить",
+                    "type": "integer"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "mp3",
+                        "flac",
+                        "ogg",
+                        "mp4"
+                    ]
+                },
+                "mime_type": {
+                    "type": "string",
+                    "example": "audio/mpeg"
+                },
+                "size": {
+                    "description": "Размер файла в байтах",
+                    "type": "integer"
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AudioTags": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "artist": {
+                    "type": "string"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "has_cover": {
+                    "description": "В файле есть обложка",
+                    "type": "boolean"
+                },
+                "has_lyrics": {
+                    "description": "В файле есть текст песни",
+                    "type": "boolean"
+                },
+                "release_date": {
+                    "description": "Полная дата выпуска, если она указана в тегах",
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "year": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.AudioUploadResponse": {
+            "type": "object",
+            "properties": {
+                "audio": {
+                    "$ref": "#/definitions/handlers.AudioFile"
+                },
+                "filled": {
+                    "description": "Поля песни, заполненные по тегам файла",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tags": {
+                    "$ref": "#/definitions/handlers.AudioTags"
+                }
+            }
+        },
+        "handlers.ComponentHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "unavailable"
+                    ]
+                }
+            }
+        },
+        "handlers.Cover": {
+            "type": "object",
+            "properties": {
+                "checksum": {
+                    "description": "SHA-256 исходного изображения",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "song",
+                        "group"
+                    ]
+                },
+                "mime_type": {
+                    "type": "string",
+                    "example": "image/jpeg"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "size": {
+                    "description": "Размер файла в байтах",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "Адрес исходного изображения; миниатюра — с параметром size",
+                    "type": "string",
+                    "example": "/covers/song/1?v=9f86d081"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.CreateAlbumRequest": {
+            "type": "object",
+            "properties": {
+                "album_type": {
+                    "type": "string",
+                    "enum": [
+                        "LP",
+                        "EP",
+                        "single"
+                    ]
+                },
+                "group_id": {
+                    "description": "ID группы; при создании через /groups/{id}/albums берётся из пути",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "formed_year": {
+                    "type": "integer",
+                    "example": 1960
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreatePlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateSmartPlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "$ref": "#/definitions/handlers.SmartRule"
+                }
+            }
+        },
+        "handlers.DuplicatesResponse": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.GroupDuplicate"
+                    }
+                },
+                "songs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SongDuplicate"
+                    }
+                }
+            }
+        },
+        "handlers.EnrichmentJob": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "description": "Число начатых попыток",
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "description": "Ошибка последней неудачной попытки",
+                    "type": "string"
+                },
+                "locked_at": {
+                    "description": "Когда обработчик взял задачу; только у выполняющихся задач",
+                    "type": "string"
+                },
+                "run_at": {
+                    "description": "Не раньше какого времени задачу возьмёт обработчик",
+                    "type": "string"
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "queued"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExternalAPIHealth": {
+            "type": "object",
+            "properties": {
+                "circuit_state": {
+                    "description": "Состояние выключателя: closed — запросы идут, open — отклоняются сразу, half-open — идёт пробный запрос",
+                    "type": "string",
+                    "enum": [
+                        "closed",
+                        "open",
+                        "half-open"
+                    ]
+                },
+                "consecutive_failures": {
+                    "description": "Неудачные попытки подряд",
+                    "type": "integer"
+                },
+                "failures": {
+                    "description": "Неудачные попытки за всё время",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "external-api"
+                },
+                "opened_at": {
+                    "type": "string"
+                },
+                "rejected": {
+                    "description": "Запросы, отклонённые разомкнутым выключателем",
+                    "type": "integer"
+                },
+                "requests": {
+                    "type": "integer"
+                },
+                "retries": {
+                    "type": "integer"
+                },
+                "retry_at": {
+                    "description": "Когда разомкнутый выключатель пропустит пробный запрос",
+                    "type": "string"
+                },
+                "throttled": {
+                    "description": "Попытки, которые ждали ограничителя частоты запросов",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.Group": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "cover_url": {
+                    "type": "string",
+                    "example": "/covers/group/1?v=2c26b46b"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "formed_year": {
+                    "type": "integer",
+                    "example": 1960
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "song_count": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.GroupDuplicate": {
+            "type": "object",
+            "properties": {
+                "duplicate_id": {
+                    "type": "integer"
+                },
+                "duplicate_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "same_normalized": {
+                    "type": "boolean"
+                },
+                "similarity": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.Health": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "$ref": "#/definitions/handlers.ComponentHealth"
+                },
+                "external_api": {
+                    "$ref": "#/definitions/handlers.ExternalAPIHealth"
+                },
+                "metadata_cache": {
+                    "description": "Кэш ответов внешнего API",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.MetadataCacheHealth"
+                        }
+                    ]
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ok",
+                        "degraded",
+                        "unavailable"
+                    ]
+                }
+            }
+        },
+        "handlers.ImportReport": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "description": "Пробный импорт: created — сколько песен будет добавлено, ничего не сохранено",
+                    "type": "boolean"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ImportRowResult"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.ImportRowResult": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string"
+                },
+                "new_group": {
+                    "description": "Для записи создана (при пробном импорте — будет создана) новая группа",
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "row": {
+                    "description": "Номер записи в файле, начиная с 1, без строки заголовка CSV",
+                    "type": "integer"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "song_id": {
+                    "description": "ID добавленной песни или уже существующей, из-за которой запись пропущена",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "skipped",
+                        "failed"
+                    ]
+                }
+            }
+        },
+        "handlers.LyricsLine": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "string"
+                },
+                "time_ms": {
+                    "description": "Момент начала строки в миллисекундах с учётом сдвига [offset:]",
+                    "type": "integer",
+                    "example": 12340
+                }
+            }
+        },
+        "handlers.LyricsUploadResponse": {
+            "type": "object",
+            "properties": {
+                "line_count": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LyricsLine"
+                    }
+                },
+                "meta": {
+                    "description": "Теги сведений из текста, например ar, ti, al",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.MergeGroupRequest": {
+            "type": "object",
+            "properties": {
+                "target_id": {
+                    "description": "ID группы, в которую сливается дубликат",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MetadataCacheHealth": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                },
+                "negative_hits": {
+                    "description": "Попадания с ответом «песня не найдена»",
+                    "type": "integer"
+                },
+                "shared": {
+                    "description": "Вызовы, дождавшиеся ответа на такой же запрос вместо своего",
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "store": {
+                    "description": "Хранятся ли ответы также в таблице базы данных",
+                    "type": "boolean"
+                },
+                "store_hits": {
+                    "description": "Попадания, найденные в таблице, а не в памяти",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.MovePlaylistItemRequest": {
+            "type": "object",
+            "properties": {
+                "position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.Playlist": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PlaylistItem": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "album": {
+                    "type": "string"
+                },
+                "album_id": {
+                    "type": "integer"
+                },
+                "cover_url": {
+                    "description": "Обложки песни и её группы; миниатюра — с параметром size",
+                    "type": "string",
+                    "example": "/covers/song/1?v=9f86d081"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "enriched_at": {
+                    "type": "string"
+                },
+                "enrichment_status": {
+                    "description": "Состояние обогащения сведениями из источников: pending, done или failed",
+                    "type": "string",
+                    "example": "done"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_cover_url": {
+                    "type": "string",
+                    "example": "/covers/group/1?v=2c26b46b"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "manual_fields": {
+                    "description": "Поля, введённые вручную: повторное обогащение с политикой keep-manual их не меняет",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": "text"
+                },
+                "position": {
+                    "type": "integer"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "score": {
+                    "description": "Сходство с запросом, заполняется только при нечётком поиске",
+                    "type": "number",
+                    "example": 0.42
+                },
+                "song": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RefreshResponse": {
+            "type": "object",
+            "properties": {
+                "queued": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "handlers.RequeueFailedResponse": {
+            "type": "object",
+            "properties": {
+                "requeued": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "handlers.RestoreRevisionRequest": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "editor"
+                }
+            }
+        },
+        "handlers.SearchResult": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "album_id": {
+                    "type": "integer"
+                },
+                "cover_url": {
+                    "description": "Обложки песни и её группы; миниатюра — с параметром size",
+                    "type": "string",
+                    "example": "/covers/song/1?v=9f86d081"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "enriched_at": {
+                    "type": "string"
+                },
+                "enrichment_status": {
+                    "description": "Состояние обогащения сведениями из источников: pending, done или failed",
+                    "type": "string",
+                    "example": "done"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_cover_url": {
+                    "type": "string",
+                    "example": "/covers/group/1?v=2c26b46b"
+                },
+                "headline": {
+                    "type": "string",
+                    "example": "Фрагмент текста с <b>совпадением</b>"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "manual_fields": {
+                    "description": "Поля, введённые вручную: повторное обогащение с политикой keep-manual их не меняет",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": "text"
+                },
+                "rank": {
+                    "type": "number"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "score": {
+                    "description": "Сходство с запросом, заполняется только при нечётком поиске",
+                    "type": "number",
+                    "example": 0.42
+                },
+                "song": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SmartPlaylist": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "$ref": "#/definitions/handlers.SmartRule"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SmartRule": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Поле песни",
+                    "type": "string",
+                    "enum": [
+                        "group",
+                        "song",
+                        "album",
+                        "text",
+                        "release_date",
+                        "genre",
+                        "tag"
+                    ]
+                },
+                "match": {
+                    "description": "Способ объединения вложенных правил группы",
+                    "type": "string",
+                    "example": "all",
+                    "enum": [
+                        "all",
+                        "any"
+                    ]
+                },
+                "op": {
+                    "description": "Оператор сравнения",
+                    "type": "string",
+                    "enum": [
+                        "eq",
+                        "contains",
+                        "not_contains",
+                        "starts_with",
+                        "like",
+                        "before",
+                        "after",
+                        "between",
+                        "is",
+                        "is_not"
+                    ]
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.SmartRule"
+                    }
+                },
+                "value": {
+                    "description": "Значение условия: строка, год или дата YYYY-MM-DD",
+                    "type": "string"
+                },
+                "values": {
+                    "description": "Значения для оператора between: начало и конец периода",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.Song": {
+            "type": "object",
+            "properties": {
+                "album": {
+                    "type": "string"
+                },
+                "album_id": {
+                    "type": "integer"
+                },
+                "cover_url": {
+                    "description": "Обложки песни и её группы; миниатюра — с параметром size",
+                    "type": "string",
+                    "example": "/covers/song/1?v=9f86d081"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "enriched_at": {
+                    "type": "string"
+                },
+                "enrichment_status": {
+                    "description": "Состояние обогащения сведениями из источников: pending, done или failed",
+                    "type": "string",
+                    "example": "done"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "group": {
+                    "type": "string"
+                },
+                "group_cover_url": {
+                    "type": "string",
+                    "example": "/covers/group/1?v=2c26b46b"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "manual_fields": {
+                    "description": "Поля, введённые вручную: повторное обогащение с политикой keep-manual их не меняет",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": "text"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "score": {
+                    "description": "Сходство с запросом, заполняется только при нечётком поиске",
+                    "type": "number",
+                    "example": 0.42
+                },
+                "song": {
+                    "type": "string"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SongDuplicate": {
+            "type": "object",
+            "properties": {
+                "duplicate_group": {
+                    "type": "string"
+                },
+                "duplicate_id": {
+                    "type": "integer"
+                },
+                "duplicate_song": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "same_normalized": {
+                    "type": "boolean"
+                },
+                "similarity": {
+                    "type": "number"
+                },
+                "song": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Tag": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "song_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.TagCloudItem": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "weight": {
+                    "description": "Вес относительно самого частого тега (0..1], для выбора размера шрифта",
+                    "type": "number",
+                    "example": 0.5
+                }
+            }
+        },
+        "handlers.TextRevision": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "Число строк и символов текста",
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "song_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.TextRevisionDetails": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "length": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "Число строк и символов текста",
+                    "type": "integer"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.TextRevisionsResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TextRevision"
+                    }
+                },
+                "song_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.UpdateAlbumRequest": {
+            "type": "object",
+            "properties": {
+                "album_type": {
+                    "type": "string",
+                    "enum": [
+                        "LP",
+                        "EP",
+                        "single"
+                    ]
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "bio": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "formed_year": {
+                    "type": "integer",
+                    "example": 1960
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdatePlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.UpdateSmartPlaylistRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rules": {
+                    "$ref": "#/definitions/handlers.SmartRule"
+                }
+            }
+        },
+        "handlers.UpdateSongRequest": {
+            "type": "object",
+            "properties": {
+                "album_id": {
+                    "type": "integer"
+                },
+                "author": {
+                    "description": "Автор правки; сохраняется в истории версий, если меняется текст",
+                    "type": "string",
+                    "example": "editor"
+                },
+                "create_group": {
+                    "description": "Создать группу, если её нет в библиотеке",
+                    "type": "boolean"
+                },
+                "disc_number": {
+                    "type": "integer"
+                },
+                "group": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "Формат даты: \"YYYY-MM-DD\"",
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "song": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "track_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.Verse": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.VersesResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "song_id": {
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "type": "integer"
+                },
+                "total_verses": {
+                    "type": "integer"
+                },
+                "verses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.Verse"
+                    }
                 }
             }
         }
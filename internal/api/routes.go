@@ -8,18 +8,90 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func NewRouter(songHandler *handlers.SongHandler, dbProvider *conn.PostgresProvider) *mux.Router {
+func NewRouter(songHandler *handlers.SongHandler, albumHandler *handlers.AlbumHandler, groupHandler *handlers.GroupHandler, duplicateHandler *handlers.DuplicateHandler,
+	tagHandler *handlers.TagHandler, playlistHandler *handlers.PlaylistHandler, smartPlaylistHandler *handlers.SmartPlaylistHandler,
+	audioHandler *handlers.AudioHandler, coverHandler *handlers.CoverHandler,
+	lyricsHandler *handlers.LyricsHandler, enrichmentHandler *handlers.EnrichmentHandler,
+	healthHandler *handlers.HealthHandler, dbProvider *conn.PostgresProvider) *mux.Router {
 	router := mux.NewRouter()
 
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("Сервер работает!"))
 	}).Methods("GET")
+	router.HandleFunc("/health", healthHandler.GetHealth).Methods("GET")
 	router.HandleFunc("/songs", songHandler.GetSongs).Methods("GET")
+	router.HandleFunc("/songs/search", songHandler.SearchSongs).Methods("GET")
 	router.HandleFunc("/songs/{id:[0-9]+}", songHandler.GetSongText).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/verses", songHandler.GetSongVerses).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/revisions", songHandler.GetTextRevisions).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/revisions/diff", songHandler.DiffTextRevisions).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/revisions/{rev:[0-9]+}", songHandler.GetTextRevision).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/revisions/{rev:[0-9]+}/restore", songHandler.RestoreTextRevision).Methods("POST")
 	router.HandleFunc("/songs/add", songHandler.AddSongWithAPI).Methods("POST")
+	router.HandleFunc("/songs/import", songHandler.ImportSongs).Methods("POST")
+	router.HandleFunc("/songs/import/playlist", songHandler.ImportPlaylist).Methods("POST")
+	router.HandleFunc("/songs/audio", audioHandler.ImportAudio).Methods("POST")
 	router.HandleFunc("/songs/{id:[0-9]+}", songHandler.UpdateSong).Methods("PUT")
 	router.HandleFunc("/songs/{id:[0-9]+}", songHandler.DeleteSong).Methods("DELETE")
 
+	router.HandleFunc("/albums", albumHandler.GetAlbums).Methods("GET")
+	router.HandleFunc("/albums", albumHandler.CreateAlbum).Methods("POST")
+	router.HandleFunc("/albums/{id:[0-9]+}", albumHandler.GetAlbum).Methods("GET")
+	router.HandleFunc("/albums/{id:[0-9]+}", albumHandler.UpdateAlbum).Methods("PUT")
+	router.HandleFunc("/albums/{id:[0-9]+}", albumHandler.DeleteAlbum).Methods("DELETE")
+	router.HandleFunc("/groups", groupHandler.GetGroups).Methods("GET")
+	router.HandleFunc("/groups", groupHandler.CreateGroup).Methods("POST")
+	router.HandleFunc("/groups/{id:[0-9]+}", groupHandler.GetGroup).Methods("GET")
+	router.HandleFunc("/groups/{id:[0-9]+}", groupHandler.UpdateGroup).Methods("PUT")
+	router.HandleFunc("/groups/{id:[0-9]+}", groupHandler.DeleteGroup).Methods("DELETE")
+	router.HandleFunc("/groups/{id:[0-9]+}/merge", groupHandler.MergeGroup).Methods("POST")
+	router.HandleFunc("/groups/{id:[0-9]+}/cover", coverHandler.UploadGroupCover).Methods("POST")
+	router.HandleFunc("/groups/{id:[0-9]+}/cover", coverHandler.DeleteGroupCover).Methods("DELETE")
+	router.HandleFunc("/groups/{id:[0-9]+}/albums", albumHandler.GetGroupAlbums).Methods("GET")
+	router.HandleFunc("/groups/{id:[0-9]+}/albums", albumHandler.CreateGroupAlbum).Methods("POST")
+	router.HandleFunc("/genres", tagHandler.GetGenres).Methods("GET")
+	router.HandleFunc("/tags", tagHandler.GetTags).Methods("GET")
+	router.HandleFunc("/tags/cloud", tagHandler.GetTagCloud).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/genres", tagHandler.AttachGenres).Methods("POST")
+	router.HandleFunc("/songs/{id:[0-9]+}/genres/{name}", tagHandler.DetachGenre).Methods("DELETE")
+	router.HandleFunc("/songs/{id:[0-9]+}/tags", tagHandler.AttachTags).Methods("POST")
+	router.HandleFunc("/songs/{id:[0-9]+}/tags/{name}", tagHandler.DetachTag).Methods("DELETE")
+	router.HandleFunc("/songs/{id:[0-9]+}/audio", audioHandler.GetSongAudio).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/audio", audioHandler.UploadSongAudio).Methods("POST")
+	router.HandleFunc("/songs/{id:[0-9]+}/audio", audioHandler.DeleteSongAudio).Methods("DELETE")
+	router.HandleFunc("/songs/{id:[0-9]+}/stream", audioHandler.StreamSongAudio).Methods("GET", "HEAD")
+	router.HandleFunc("/songs/{id:[0-9]+}/lyrics", lyricsHandler.GetSongLyrics).Methods("GET")
+	router.HandleFunc("/songs/{id:[0-9]+}/lyrics", lyricsHandler.PutSongLyrics).Methods("PUT")
+	router.HandleFunc("/songs/{id:[0-9]+}/lyrics", lyricsHandler.DeleteSongLyrics).Methods("DELETE")
+	router.HandleFunc("/songs/{id:[0-9]+}/cover", coverHandler.UploadSongCover).Methods("POST")
+	router.HandleFunc("/songs/{id:[0-9]+}/cover", coverHandler.DeleteSongCover).Methods("DELETE")
+	router.HandleFunc("/covers/{kind:song|group}/{id:[0-9]+}", coverHandler.GetCover).Methods("GET", "HEAD")
+	router.HandleFunc("/songs/{id:[0-9]+}/enrich", enrichmentHandler.EnrichSong).Methods("POST")
+	router.HandleFunc("/enrichment/jobs", enrichmentHandler.GetJobs).Methods("GET")
+	router.HandleFunc("/enrichment/jobs/requeue", enrichmentHandler.RequeueFailed).Methods("POST")
+	router.HandleFunc("/enrichment/refresh", enrichmentHandler.RefreshStale).Methods("POST")
+	router.HandleFunc("/enrichment/jobs/{id:[0-9]+}", enrichmentHandler.GetJob).Methods("GET")
+	router.HandleFunc("/enrichment/jobs/{id:[0-9]+}/requeue", enrichmentHandler.RequeueJob).Methods("POST")
+
+	router.HandleFunc("/playlists", playlistHandler.GetPlaylists).Methods("GET")
+	router.HandleFunc("/playlists", playlistHandler.CreatePlaylist).Methods("POST")
+	router.HandleFunc("/playlists/{id:[0-9]+}", playlistHandler.GetPlaylist).Methods("GET")
+	router.HandleFunc("/playlists/{id:[0-9]+}", playlistHandler.UpdatePlaylist).Methods("PUT")
+	router.HandleFunc("/playlists/{id:[0-9]+}", playlistHandler.DeletePlaylist).Methods("DELETE")
+	router.HandleFunc("/playlists/{id:[0-9]+}/items", playlistHandler.GetPlaylistItems).Methods("GET")
+	router.HandleFunc("/playlists/{id:[0-9]+}/items", playlistHandler.AddPlaylistItem).Methods("POST")
+	router.HandleFunc("/playlists/{id:[0-9]+}/items/{item_id:[0-9]+}", playlistHandler.MovePlaylistItem).Methods("PUT")
+	router.HandleFunc("/playlists/{id:[0-9]+}/items/{item_id:[0-9]+}", playlistHandler.RemovePlaylistItem).Methods("DELETE")
+
+	router.HandleFunc("/smart-playlists", smartPlaylistHandler.GetSmartPlaylists).Methods("GET")
+	router.HandleFunc("/smart-playlists", smartPlaylistHandler.CreateSmartPlaylist).Methods("POST")
+	router.HandleFunc("/smart-playlists/{id:[0-9]+}", smartPlaylistHandler.GetSmartPlaylist).Methods("GET")
+	router.HandleFunc("/smart-playlists/{id:[0-9]+}", smartPlaylistHandler.UpdateSmartPlaylist).Methods("PUT")
+	router.HandleFunc("/smart-playlists/{id:[0-9]+}", smartPlaylistHandler.DeleteSmartPlaylist).Methods("DELETE")
+	router.HandleFunc("/smart-playlists/{id:[0-9]+}/songs", smartPlaylistHandler.GetSmartPlaylistSongs).Methods("GET")
+
+	router.HandleFunc("/duplicates", duplicateHandler.GetDuplicates).Methods("GET")
+
 	return router
 }
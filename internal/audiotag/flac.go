@@ -0,0 +1,193 @@
+package audiotag
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Типы блоков метаданных FLAC.
+const (
+	flacStreamInfo    = 0
+	flacVorbisComment = 4
+	flacPicture       = 6
+)
+
+// maxCommentSize ограничивает размер блока комментариев Vorbis, читаемого в память.
+const maxCommentSize = 16 << 20
+
+// readFLAC разбирает блоки метаданных FLAC, начиная с сигнатуры fLaC по смещению offset:
+// STREAMINFO даёт длительность, VORBIS_COMMENT — теги, PICTURE — обложку. Остальные блоки пропускаются.
+func readFLAC(r io.ReadSeeker, offset int64) (Tags, error) {
+	if _, err := r.Seek(offset+4, io.SeekStart); err != nil {
+		return Tags{}, fmt.Errorf("ошибка чтения FLAC: %w", err)
+	}
+	tags := Tags{Format: FormatFLAC}
+	header := make([]byte, 4)
+	for last := false; !last; {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return Tags{}, fmt.Errorf("ошибка чтения блока FLAC: %w", err)
+		}
+		last = header[0]&0x80 != 0
+		kind := header[0] & 0x7F
+		size := int64(header[1])<<16 | int64(header[2])<<8 | int64(header[3])
+		switch kind {
+		case flacStreamInfo:
+			data, err := readBlock(r, size)
+			if err != nil {
+				return Tags{}, err
+			}
+			if len(data) >= 18 {
+				// Частота — 20 бит с 10-го байта, число отсчётов — младшие 36 бит байтов 13–17
+				rate := uint64(data[10])<<12 | uint64(data[11])<<4 | uint64(data[12])>>4
+				samples := uint64(data[13]&0x0F)<<32 | uint64(binary.BigEndian.Uint32(data[14:18]))
+				tags.Duration = durationOf(samples, rate)
+			}
+		case flacVorbisComment:
+			data, err := readBlock(r, size)
+			if err != nil {
+				return Tags{}, err
+			}
+			comments, err := vorbisComments(data)
+			if err != nil {
+				return Tags{}, err
+			}
+			duration, picture := tags.Duration, tags.Picture
+			tags = comments
+			tags.Format, tags.Duration = FormatFLAC, duration
+			tags.setPicture(picture)
+		case flacPicture:
+			data, err := readBlock(r, size)
+			if err != nil {
+				return Tags{}, err
+			}
+			tags.setPicture(parsePicture(data))
+		default:
+			if _, err := r.Seek(size, io.SeekCurrent); err != nil {
+				return Tags{}, fmt.Errorf("ошибка чтения FLAC: %w", err)
+			}
+		}
+	}
+	return tags, nil
+}
+
+func readBlock(r io.Reader, size int64) ([]byte, error) {
+	if size > maxCommentSize {
+		return nil, fmt.Errorf("блок метаданных слишком большой: %d байт", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("ошибка чтения блока метаданных: %w", err)
+	}
+	return data, nil
+}
+
+var errBadComment = errors.New("некорректный блок комментариев Vorbis")
+
+// vorbisComments разбирает комментарии Vorbis (FLAC, Ogg Vorbis и Opus): строку поставщика
+// и список «ПОЛЕ=значение». Названия полей не зависят от регистра; из повторов берётся первое.
+func vorbisComments(data []byte) (Tags, error) {
+	next := func() ([]byte, bool) {
+		if len(data) < 4 {
+			return nil, false
+		}
+		n := binary.LittleEndian.Uint32(data)
+		if uint64(n) > uint64(len(data)-4) {
+			return nil, false
+		}
+		value := data[4 : 4+n]
+		data = data[4+n:]
+		return value, true
+	}
+	if _, ok := next(); !ok {
+		return Tags{}, errBadComment
+	}
+	if len(data) < 4 {
+		return Tags{}, errBadComment
+	}
+	count := binary.LittleEndian.Uint32(data)
+	data = data[4:]
+
+	fields := map[string]string{}
+	for i := uint32(0); i < count; i++ {
+		comment, ok := next()
+		if !ok {
+			return Tags{}, errBadComment
+		}
+		key, value, ok := strings.Cut(string(comment), "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(key)
+		if _, seen := fields[key]; !seen {
+			fields[key] = value
+		}
+	}
+
+	tags := Tags{
+		Artist: firstField(fields, "ARTIST", "ALBUMARTIST", "ALBUM ARTIST"),
+		Title:  fields["TITLE"],
+		Album:  fields["ALBUM"],
+		Track:  parseNumber(fields["TRACKNUMBER"]),
+		Disc:   parseNumber(fields["DISCNUMBER"]),
+		Lyrics: firstField(fields, "LYRICS", "UNSYNCEDLYRICS"),
+	}
+	tags.Year, tags.Date = parseDate(firstField(fields, "DATE", "YEAR"))
+	// Ogg хранит обложку в комментарии: блок PICTURE формата FLAC в base64
+	if encoded := fields["METADATA_BLOCK_PICTURE"]; encoded != "" {
+		if data, err := base64.StdEncoding.DecodeString(encoded); err == nil {
+			tags.setPicture(parsePicture(data))
+		}
+	}
+	return tags, nil
+}
+
+// parsePicture разбирает блок PICTURE FLAC: тип картинки, тип содержимого, описание,
+// четыре поля с размерами и цветностью и сами данные; все длины — 32 бита big-endian.
+func parsePicture(data []byte) *Picture {
+	field := func() ([]byte, bool) {
+		if len(data) < 4 {
+			return nil, false
+		}
+		n := binary.BigEndian.Uint32(data)
+		if uint64(n) > uint64(len(data)-4) {
+			return nil, false
+		}
+		value := data[4 : 4+n]
+		data = data[4+n:]
+		return value, true
+	}
+	if len(data) < 4 {
+		return nil
+	}
+	kind := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	mimeType, ok := field()
+	if !ok {
+		return nil
+	}
+	if _, ok := field(); !ok {
+		return nil
+	}
+	if len(data) < 16 {
+		return nil
+	}
+	data = data[16:]
+	picture, ok := field()
+	if !ok {
+		return nil
+	}
+	return &Picture{MimeType: string(mimeType), Data: picture, front: kind == pictureFront}
+}
+
+func firstField(fields map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if v := strings.TrimSpace(fields[key]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,175 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+)
+
+// flacFile собирает файл FLAC из блоков метаданных; последний блок помечается флагом.
+func flacFile(blocks ...[]byte) []byte {
+	file := []byte("fLaC")
+	for i, block := range blocks {
+		header := block[0]
+		if i == len(blocks)-1 {
+			header |= 0x80
+		}
+		n := len(block) - 1
+		file = append(file, header, byte(n>>16), byte(n>>8), byte(n))
+		file = append(file, block[1:]...)
+	}
+	return file
+}
+
+func flacBlock(kind byte, data []byte) []byte {
+	return append([]byte{kind}, data...)
+}
+
+// streamInfo собирает STREAMINFO с частотой rate и числом отсчётов samples.
+func streamInfo(rate uint32, samples uint64) []byte {
+	data := make([]byte, 34)
+	data[10] = byte(rate >> 12)
+	data[11] = byte(rate >> 4)
+	data[12] = byte(rate<<4) | 0x02
+	data[13] = 0xF0 | byte(samples>>32&0x0F)
+	binary.BigEndian.PutUint32(data[14:18], uint32(samples))
+	return flacBlock(flacStreamInfo, data)
+}
+
+// comments собирает блок комментариев Vorbis с заданными строками «ПОЛЕ=значение».
+func comments(fields ...string) []byte {
+	vendor := "reference libFLAC 1.4.3"
+	data := binary.LittleEndian.AppendUint32(nil, uint32(len(vendor)))
+	data = append(data, vendor...)
+	data = binary.LittleEndian.AppendUint32(data, uint32(len(fields)))
+	for _, field := range fields {
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(field)))
+		data = append(data, field...)
+	}
+	return data
+}
+
+// picture собирает блок PICTURE FLAC.
+func picture(kind uint32, mimeType, data string) []byte {
+	block := binary.BigEndian.AppendUint32(nil, kind)
+	block = binary.BigEndian.AppendUint32(block, uint32(len(mimeType)))
+	block = append(block, mimeType...)
+	block = binary.BigEndian.AppendUint32(block, 0)
+	block = append(block, make([]byte, 16)...)
+	block = binary.BigEndian.AppendUint32(block, uint32(len(data)))
+	return append(block, data...)
+}
+
+func TestReadFLAC(t *testing.T) {
+	file := flacFile(
+		streamInfo(44100, 44100*305+22050),
+		flacBlock(1, make([]byte, 100)),
+		flacBlock(flacPicture, picture(0, "image/png", "other")),
+		flacBlock(flacVorbisComment, comments(
+			"albumartist=Album Artist",
+			"TITLE=Uprising",
+			"Title=Повтор",
+			"ALBUM=The Resistance",
+			"DATE=2009-09-14",
+			"TRACKNUMBER=1/11",
+			"DISCNUMBER=1",
+			"UNSYNCEDLYRICS=Куплет",
+			"без знака равенства",
+		)),
+		flacBlock(flacPicture, picture(pictureFront, "image/jpeg", "front")),
+	)
+	tags := readBytes(t, file)
+	want := Tags{
+		Format: FormatFLAC, Artist: "Album Artist", Title: "Uprising", Album: "The Resistance",
+		Year: 2009, Date: "2009-09-14", Track: 1, Disc: 1, Duration: 305*time.Second + 500*time.Millisecond, Lyrics: "Куплет",
+	}
+	got := tags
+	got.Picture = nil
+	if got != want {
+		t.Errorf("Tags = %+v, want %+v", got, want)
+	}
+	if tags.Picture == nil || string(tags.Picture.Data) != "front" {
+		t.Errorf("Picture = %+v, want передняя обложка", tags.Picture)
+	}
+}
+
+func TestReadFLACWithID3(t *testing.T) {
+	file := id3Tag(3, 0, id3Frame23("TPE1", 0, text(0, "Muse")), id3Frame23("TIT2", 0, text(0, "ID3")))
+	file = append(file, flacFile(streamInfo(48000, 48000), flacBlock(flacVorbisComment, comments("TITLE=Uprising")))...)
+	tags := readBytes(t, file)
+	// Комментарии FLAC важнее ID3, пустые поля дополняются из ID3
+	if tags.Format != FormatFLAC || tags.Title != "Uprising" || tags.Artist != "Muse" || tags.Duration != time.Second {
+		t.Errorf("Tags = %+v", tags)
+	}
+}
+
+func TestVorbisCommentPicture(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(picture(pictureFront, "image/jpeg", "cover"))
+	tags, err := vorbisComments(comments("ARTIST=Muse", "METADATA_BLOCK_PICTURE="+encoded))
+	if err != nil {
+		t.Fatalf("vorbisComments: %v", err)
+	}
+	if tags.Picture == nil || string(tags.Picture.Data) != "cover" || tags.Picture.MimeType != "image/jpeg" {
+		t.Errorf("Picture = %+v", tags.Picture)
+	}
+	if _, err := vorbisComments(comments("METADATA_BLOCK_PICTURE=не base64")); err != nil {
+		t.Errorf("испорченная обложка: %v", err)
+	}
+}
+
+func TestReadFLACTruncated(t *testing.T) {
+	full := flacFile(streamInfo(44100, 44100), flacBlock(flacVorbisComment, comments("TITLE=Uprising")))
+
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{name: "только сигнатура", file: []byte("fLaC")},
+		{name: "блок обрезан", file: full[:len(full)-3]},
+		{name: "нет последнего блока", file: flacFile(streamInfo(44100, 44100), flacBlock(1, nil))[:4+4+34]},
+		{name: "пропускаемый блок за концом файла", file: append([]byte("fLaC\x01\x00\x10\x00"), make([]byte, 10)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Read(bytes.NewReader(tt.file)); err == nil {
+				t.Error("обрезанный файл прочитан без ошибки")
+			}
+		})
+	}
+}
+
+func TestVorbisCommentsMalformed(t *testing.T) {
+	good := comments("TITLE=Uprising", "ARTIST=Muse")
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "пусто", data: nil},
+		{name: "поставщик длиннее блока", data: []byte{0xFF, 0, 0, 0, 'x'}},
+		{name: "нет числа комментариев", data: good[:4+23]},
+		{name: "комментарий обрезан", data: good[:len(good)-2]},
+		{name: "комментариев меньше заявленного", data: comments("TITLE=x")[:4+23+4]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := vorbisComments(tt.data); !errors.Is(err, errBadComment) {
+				t.Errorf("error = %v, want errBadComment", err)
+			}
+		})
+	}
+}
+
+func TestParsePictureTruncated(t *testing.T) {
+	full := picture(pictureFront, "image/jpeg", "cover")
+	for n := 0; n < len(full); n++ {
+		if p := parsePicture(full[:n]); p != nil {
+			t.Errorf("parsePicture(%d байт) = %+v, want nil", n, p)
+		}
+	}
+	if p := parsePicture(full); p == nil || string(p.Data) != "cover" || !p.front {
+		t.Errorf("parsePicture = %+v", p)
+	}
+}
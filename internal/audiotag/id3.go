@@ -0,0 +1,402 @@
+package audiotag
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+const (
+	id3HeaderSize = 10
+	id3v1Size     = 128
+	// maxID3Size ограничивает размер тега ID3v2, который читается в память.
+	maxID3Size = 64 << 20
+)
+
+// readMP3 разбирает файл MPEG: теги ID3v2 в начале, ID3v1 в конце и длительность по кадрам.
+// Теги ID3v2 важнее ID3v1, который хранит только латиницу и обрезает поля до 30 символов.
+func readMP3(r io.ReadSeeker, size int64) (Tags, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return Tags{}, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	tags, tagSize, err := readID3v2(r)
+	if err != nil {
+		return Tags{}, err
+	}
+
+	// Иногда ID3v2 дописывают и перед FLAC
+	magic := make([]byte, 4)
+	if _, err := r.Seek(tagSize, io.SeekStart); err == nil {
+		if _, err := io.ReadFull(r, magic); err == nil && string(magic) == "fLaC" {
+			flac, err := readFLAC(r, tagSize)
+			if err != nil {
+				return Tags{}, err
+			}
+			flac.merge(tags)
+			return flac, nil
+		}
+	}
+	tags.Format = FormatMP3
+
+	end := size
+	if v1, ok, err := readID3v1(r, size); err != nil {
+		return Tags{}, err
+	} else if ok {
+		tags.merge(v1)
+		end -= id3v1Size
+	}
+
+	duration, err := mpegDuration(r, tagSize, end)
+	if err != nil {
+		return Tags{}, err
+	}
+	if duration > 0 {
+		tags.Duration = duration
+	}
+	return tags, nil
+}
+
+// readID3v2 читает тег ID3v2 с текущей позиции и возвращает его размер вместе с заголовком
+// и окончанием; если тега нет, размер равен нулю.
+func readID3v2(r io.Reader) (Tags, int64, error) {
+	header := make([]byte, id3HeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return Tags{}, 0, nil
+		}
+		return Tags{}, 0, fmt.Errorf("ошибка чтения ID3v2: %w", err)
+	}
+	if string(header[:3]) != "ID3" {
+		return Tags{}, 0, nil
+	}
+	version, flags := header[3], header[5]
+	size := syncsafe(header[6:10])
+	total := int64(id3HeaderSize + size)
+	if flags&0x10 != 0 {
+		// Окончание тега ID3v2.4 повторяет заголовок
+		total += id3HeaderSize
+	}
+	if version < 2 || version > 4 {
+		// Неизвестную версию не разбираем, но знаем, где начинается звук
+		return Tags{}, total, nil
+	}
+	if size > maxID3Size {
+		return Tags{}, 0, fmt.Errorf("тег ID3v2 слишком большой: %d байт", size)
+	}
+	body := make([]byte, size)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return Tags{}, 0, fmt.Errorf("ошибка чтения ID3v2: %w", err)
+	}
+
+	// В версиях 2.2 и 2.3 рассинхронизация применяется ко всему тегу, в 2.4 — к каждому кадру
+	unsync := flags&0x80 != 0
+	if unsync && version < 4 {
+		body = removeUnsync(body)
+	}
+	if flags&0x40 != 0 && version >= 3 && len(body) >= 4 {
+		// Расширенный заголовок: в 2.3 размер не включает своё поле, в 2.4 — включает
+		ext := int(binary.BigEndian.Uint32(body))
+		if version == 4 {
+			ext = syncsafe(body[:4])
+		} else {
+			ext += 4
+		}
+		if ext > len(body) {
+			return Tags{}, 0, errors.New("некорректный расширенный заголовок ID3v2")
+		}
+		body = body[ext:]
+	}
+
+	var tags Tags
+	var length time.Duration
+	var date, ddmm string
+	for _, frame := range id3Frames(body, version, unsync) {
+		switch frame.id {
+		case "TPE1", "TP1":
+			tags.Artist = id3Text(frame.data)
+		case "TPE2", "TP2":
+			// Исполнитель альбома — запасной вариант для группы
+			if tags.Artist == "" {
+				tags.Artist = id3Text(frame.data)
+			}
+		case "TIT2", "TT2":
+			tags.Title = id3Text(frame.data)
+		case "TALB", "TAL":
+			tags.Album = id3Text(frame.data)
+		case "TDRC", "TYER", "TYE":
+			date = id3Text(frame.data)
+		case "TDAT", "TDA":
+			ddmm = id3Text(frame.data)
+		case "TRCK", "TRK":
+			tags.Track = parseNumber(id3Text(frame.data))
+		case "TPOS", "TPA":
+			tags.Disc = parseNumber(id3Text(frame.data))
+		case "TLEN", "TLE":
+			if ms, err := strconv.ParseInt(id3Text(frame.data), 10, 64); err == nil && ms > 0 {
+				length = time.Duration(ms) * time.Millisecond
+			}
+		case "USLT", "ULT":
+			if tags.Lyrics == "" {
+				tags.Lyrics = id3Lyrics(frame.data)
+			}
+		case "APIC", "PIC":
+			tags.setPicture(id3Picture(frame.data, frame.id == "PIC"))
+		}
+	}
+	tags.Year, tags.Date = parseDate(date)
+	if tags.Date == "" && tags.Year > 0 && len(ddmm) == 4 {
+		// В ID3v2.3 день и месяц хранятся отдельно от года в кадре TDAT (DDMM)
+		tags.Year, tags.Date = parseDate(fmt.Sprintf("%04d-%s-%s", tags.Year, ddmm[2:], ddmm[:2]))
+	}
+	tags.Duration = length
+	return tags, total, nil
+}
+
+type id3Frame struct {
+	id   string
+	data []byte
+}
+
+// id3Frames делит тело тега на кадры. Сжатые кадры распаковываются,
+// зашифрованные и повреждённые пропускаются.
+func id3Frames(body []byte, version byte, unsync bool) []id3Frame {
+	idSize, headerSize := 4, 10
+	if version == 2 {
+		idSize, headerSize = 3, 6
+	}
+	var frames []id3Frame
+	for len(body) >= headerSize && body[0] != 0 {
+		id := string(body[:idSize])
+		var size int
+		var flags uint16
+		switch version {
+		case 2:
+			size = int(body[3])<<16 | int(body[4])<<8 | int(body[5])
+		case 3:
+			size = int(binary.BigEndian.Uint32(body[4:8]))
+			flags = binary.BigEndian.Uint16(body[8:10])
+		default:
+			size = syncsafe(body[4:8])
+			flags = binary.BigEndian.Uint16(body[8:10])
+		}
+		if size < 0 || size > len(body)-headerSize {
+			break
+		}
+		data := body[headerSize : headerSize+size]
+		body = body[headerSize+size:]
+		if data, ok := id3FrameData(data, version, flags, unsync); ok {
+			frames = append(frames, id3Frame{id: id, data: data})
+		}
+	}
+	return frames
+}
+
+// id3FrameData снимает с данных кадра служебные поля, рассинхронизацию и сжатие по флагам кадра.
+func id3FrameData(data []byte, version byte, flags uint16, unsync bool) ([]byte, bool) {
+	var compressed bool
+	switch version {
+	case 3:
+		if flags&0x0040 != 0 {
+			return nil, false
+		}
+		compressed = flags&0x0080 != 0
+		if compressed {
+			// Размер распакованных данных
+			if len(data) < 4 {
+				return nil, false
+			}
+			data = data[4:]
+		}
+		if flags&0x0020 != 0 {
+			if len(data) < 1 {
+				return nil, false
+			}
+			data = data[1:]
+		}
+	case 4:
+		if flags&0x0004 != 0 {
+			return nil, false
+		}
+		compressed = flags&0x0008 != 0
+		if flags&0x0040 != 0 {
+			if len(data) < 1 {
+				return nil, false
+			}
+			data = data[1:]
+		}
+		if flags&0x0001 != 0 {
+			if len(data) < 4 {
+				return nil, false
+			}
+			data = data[4:]
+		}
+		if unsync || flags&0x0002 != 0 {
+			data = removeUnsync(data)
+		}
+	}
+	if compressed {
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, false
+		}
+		defer zr.Close()
+		if data, err = io.ReadAll(io.LimitReader(zr, maxID3Size)); err != nil {
+			return nil, false
+		}
+	}
+	return data, true
+}
+
+// id3Text декодирует текстовый кадр. В ID3v2.4 кадр может содержать несколько
+// значений через нулевой символ — берётся первое.
+func id3Text(data []byte) string {
+	if len(data) < 1 {
+		return ""
+	}
+	text, _ := id3String(data[0], data[1:])
+	return strings.TrimSpace(text)
+}
+
+// id3Lyrics декодирует кадр USLT: кодировка, язык, описание и сам текст.
+func id3Lyrics(data []byte) string {
+	if len(data) < 4 {
+		return ""
+	}
+	encoding := data[0]
+	_, rest := id3String(encoding, data[4:])
+	text, _ := id3String(encoding, rest)
+	return text
+}
+
+// id3Picture декодирует кадр APIC: кодировка, тип содержимого, тип картинки, описание и данные.
+// В ID3v2.2 (кадр PIC) вместо типа содержимого — трёхбуквенный формат, например «JPG».
+func id3Picture(data []byte, v22 bool) *Picture {
+	if len(data) < 2 {
+		return nil
+	}
+	encoding := data[0]
+	var mimeType string
+	rest := data[1:]
+	if v22 {
+		if len(rest) < 3 {
+			return nil
+		}
+		mimeType = "image/" + strings.ToLower(string(rest[:3]))
+		if mimeType == "image/jpg" {
+			mimeType = "image/jpeg"
+		}
+		rest = rest[3:]
+	} else {
+		mimeType, rest = id3String(0, rest)
+	}
+	if len(rest) < 1 {
+		return nil
+	}
+	kind := rest[0]
+	_, rest = id3String(encoding, rest[1:])
+	return &Picture{MimeType: mimeType, Data: rest, front: kind == pictureFront}
+}
+
+// id3String декодирует строку в кодировке encoding до завершающего нуля
+// и возвращает остаток данных после него.
+func id3String(encoding byte, data []byte) (string, []byte) {
+	if encoding == 1 || encoding == 2 {
+		// В UTF-16 нулевой символ занимает два байта и выровнен по символу
+		end := len(data)
+		rest := []byte(nil)
+		for i := 0; i+1 < len(data); i += 2 {
+			if data[i] == 0 && data[i+1] == 0 {
+				end, rest = i, data[i+2:]
+				break
+			}
+		}
+		return decodeUTF16(data[:end], encoding == 2), rest
+	}
+	end, rest := len(data), []byte(nil)
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		end, rest = i, data[i+1:]
+	}
+	if encoding == 3 {
+		return string(data[:end]), rest
+	}
+	return latin1(data[:end]), rest
+}
+
+// decodeUTF16 декодирует UTF-16 с меткой порядка байтов; без метки используется bigEndian.
+func decodeUTF16(data []byte, bigEndian bool) string {
+	if len(data) >= 2 {
+		switch {
+		case data[0] == 0xFF && data[1] == 0xFE:
+			bigEndian, data = false, data[2:]
+		case data[0] == 0xFE && data[1] == 0xFF:
+			bigEndian, data = true, data[2:]
+		}
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = binary.BigEndian.Uint16(data[2*i:])
+		} else {
+			units[i] = binary.LittleEndian.Uint16(data[2*i:])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// removeUnsync отменяет рассинхронизацию: после каждого байта 0xFF вставленный 0x00 удаляется.
+func removeUnsync(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		out = append(out, data[i])
+		if data[i] == 0xFF && i+1 < len(data) && data[i+1] == 0 {
+			i++
+		}
+	}
+	return out
+}
+
+// syncsafe разбирает 28-битное число ID3v2, в каждом байте которого старший бит равен нулю.
+func syncsafe(b []byte) int {
+	return int(b[0]&0x7F)<<21 | int(b[1]&0x7F)<<14 | int(b[2]&0x7F)<<7 | int(b[3]&0x7F)
+}
+
+// readID3v1 читает тег ID3v1 из последних 128 байт файла.
+func readID3v1(r io.ReadSeeker, size int64) (Tags, bool, error) {
+	if size < id3v1Size {
+		return Tags{}, false, nil
+	}
+	if _, err := r.Seek(size-id3v1Size, io.SeekStart); err != nil {
+		return Tags{}, false, fmt.Errorf("ошибка чтения ID3v1: %w", err)
+	}
+	data := make([]byte, id3v1Size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return Tags{}, false, fmt.Errorf("ошибка чтения ID3v1: %w", err)
+	}
+	if string(data[:3]) != "TAG" {
+		return Tags{}, false, nil
+	}
+	field := func(b []byte) string {
+		if i := bytes.IndexByte(b, 0); i >= 0 {
+			b = b[:i]
+		}
+		return strings.TrimSpace(latin1(b))
+	}
+	tags := Tags{
+		Title:  field(data[3:33]),
+		Artist: field(data[33:63]),
+		Album:  field(data[63:93]),
+	}
+	tags.Year, _ = parseDate(field(data[93:97]))
+	// ID3v1.1: номер трека в последнем байте комментария, перед ним ноль
+	if data[125] == 0 && data[126] != 0 {
+		tags.Track = int(data[126])
+	}
+	return tags, true, nil
+}
@@ -0,0 +1,239 @@
+package audiotag
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// id3Tag собирает тег ID3v2 заданной версии из готовых кадров.
+func id3Tag(version, flags byte, frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	return append(append([]byte{'I', 'D', '3', version, 0, flags}, syncsafeBytes(len(body))...), body...)
+}
+
+func syncsafeBytes(n int) []byte {
+	return []byte{byte(n >> 21 & 0x7F), byte(n >> 14 & 0x7F), byte(n >> 7 & 0x7F), byte(n & 0x7F)}
+}
+
+// id3Frame23 собирает кадр ID3v2.3; в версии 2.4 размер записывается как syncsafe.
+func id3Frame23(id string, flags uint16, data []byte) []byte {
+	frame := append([]byte(id), binary.BigEndian.AppendUint32(nil, uint32(len(data)))...)
+	return append(binary.BigEndian.AppendUint16(frame, flags), data...)
+}
+
+func id3Frame24(id string, flags uint16, data []byte) []byte {
+	frame := append([]byte(id), syncsafeBytes(len(data))...)
+	return append(binary.BigEndian.AppendUint16(frame, flags), data...)
+}
+
+func id3Frame22(id string, data []byte) []byte {
+	n := len(data)
+	return append(append([]byte(id), byte(n>>16), byte(n>>8), byte(n)), data...)
+}
+
+// text собирает данные текстового кадра: байт кодировки и строку.
+func text(encoding byte, s string) []byte {
+	return append([]byte{encoding}, s...)
+}
+
+// utf16LE кодирует строку в UTF-16LE с меткой порядка байтов.
+func utf16LE(s string) []byte {
+	out := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(s)) {
+		out = binary.LittleEndian.AppendUint16(out, u)
+	}
+	return out
+}
+
+// mpegFrames — n кадров MPEG-1 Layer III 128 кбит/с 44.1 кГц без заголовка Xing.
+func mpegFrames(n int) []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	return bytes.Repeat(frame, n)
+}
+
+// id3v1 собирает тег ID3v1.1 с номером трека.
+func id3v1(title, artist, album, year string, track byte) []byte {
+	tag := make([]byte, id3v1Size)
+	copy(tag, "TAG")
+	copy(tag[3:33], title)
+	copy(tag[33:63], artist)
+	copy(tag[63:93], album)
+	copy(tag[93:97], year)
+	tag[126] = track
+	return tag
+}
+
+func readBytes(t *testing.T, data []byte) Tags {
+	t.Helper()
+	tags, err := Read(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	return tags
+}
+
+func TestReadID3v23(t *testing.T) {
+	back := append([]byte("\x00image/png\x00\x00обложка\x00"), "back"...)
+	front := append([]byte("\x00image/jpeg\x00\x03\x00"), "front"...)
+	lyrics := append([]byte("\x01rus"), utf16LE("описание")...)
+	lyrics = append(append(lyrics, 0, 0), utf16LE("Куплет")...)
+	tag := id3Tag(3, 0,
+		id3Frame23("TPE2", 0, text(0, "Album Artist")),
+		id3Frame23("TPE1", 0, text(1, string(utf16LE("Мьюз")))),
+		id3Frame23("TIT2", 0, text(0, "Uprising\x00")),
+		id3Frame23("TALB", 0, text(3, "The Resistance")),
+		id3Frame23("TYER", 0, text(0, "2009")),
+		id3Frame23("TDAT", 0, text(0, "1607")),
+		id3Frame23("TRCK", 0, text(0, "1/11")),
+		id3Frame23("TPOS", 0, text(0, "1/1")),
+		id3Frame23("TLEN", 0, text(0, "305000")),
+		id3Frame23("USLT", 0, lyrics),
+		id3Frame23("APIC", 0, back),
+		id3Frame23("APIC", 0, front),
+	)
+
+	tags := readBytes(t, tag)
+	want := Tags{
+		Format: FormatMP3, Artist: "Мьюз", Title: "Uprising", Album: "The Resistance",
+		Year: 2009, Date: "2009-07-16", Track: 1, Disc: 1, Duration: 305 * time.Second, Lyrics: "Куплет",
+	}
+	got := tags
+	got.Picture = nil
+	if got != want {
+		t.Errorf("Tags = %+v, want %+v", got, want)
+	}
+	if tags.Picture == nil || string(tags.Picture.Data) != "front" || tags.Picture.MimeType != "image/jpeg" {
+		t.Errorf("Picture = %+v, want передняя обложка", tags.Picture)
+	}
+}
+
+func TestReadID3v24(t *testing.T) {
+	compressed := func(data []byte) []byte {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		return append(binary.BigEndian.AppendUint32(nil, uint32(len(data))), buf.Bytes()...)
+	}
+	tag := id3Tag(4, 0,
+		// Несколько значений через нулевой символ: берётся первое
+		id3Frame24("TPE1", 0, text(3, "Muse\x00Matt Bellamy")),
+		// Сжатый кадр с длиной данных
+		id3Frame24("TIT2", 0x0008|0x0001, compressed(text(3, "Uprising"))),
+		// Рассинхронизированный кадр
+		id3Frame24("TALB", 0x0002, text(3, "A\xff\x00B")),
+		id3Frame24("TDRC", 0, text(3, "2009-09-14")),
+		// Зашифрованный кадр пропускается
+		id3Frame24("TRCK", 0x0004, text(3, "5")),
+	)
+	tags := readBytes(t, tag)
+	if tags.Artist != "Muse" || tags.Title != "Uprising" || tags.Album != "A\xffB" || tags.Date != "2009-09-14" || tags.Track != 0 {
+		t.Errorf("Tags = %+v", tags)
+	}
+}
+
+func TestReadID3v22(t *testing.T) {
+	tag := id3Tag(2, 0,
+		id3Frame22("TP1", text(0, "Caf\xe9")),
+		id3Frame22("TT2", text(0, "Song")),
+		id3Frame22("PIC", append([]byte("\x00JPG\x03\x00"), "jpeg"...)),
+	)
+	tags := readBytes(t, tag)
+	if tags.Artist != "Café" || tags.Title != "Song" {
+		t.Errorf("Tags = %+v", tags)
+	}
+	if tags.Picture == nil || tags.Picture.MimeType != "image/jpeg" || string(tags.Picture.Data) != "jpeg" {
+		t.Errorf("Picture = %+v", tags.Picture)
+	}
+}
+
+func TestReadID3v23Unsync(t *testing.T) {
+	// В ID3v2.3 размер кадра считается до рассинхронизации, а она применяется ко всему тегу
+	frame := id3Frame23("TIT2", 0, text(0, "A\xffB"))
+	frame = bytes.ReplaceAll(frame, []byte{0xFF}, []byte{0xFF, 0x00})
+	tags := readBytes(t, id3Tag(3, 0x80, frame))
+	if tags.Title != "AÿB" {
+		t.Errorf("Title = %q, want рассинхронизация снята", tags.Title)
+	}
+}
+
+func TestReadID3v1(t *testing.T) {
+	file := append(mpegFrames(100), id3v1("Uprising", "Muse", "The Resistance", "2009", 1)...)
+	tags := readBytes(t, file)
+	if tags.Title != "Uprising" || tags.Artist != "Muse" || tags.Album != "The Resistance" || tags.Year != 2009 || tags.Track != 1 {
+		t.Errorf("Tags = %+v", tags)
+	}
+	// 100 кадров по 417 байт при 128 кбит/с, без учёта ID3v1
+	if want := time.Duration(100*417*8) * time.Second / 128000; tags.Duration != want {
+		t.Errorf("Duration = %v, want %v", tags.Duration, want)
+	}
+}
+
+func TestReadID3v2OverridesV1(t *testing.T) {
+	file := id3Tag(3, 0, id3Frame23("TIT2", 0, text(3, "Полное название песни длиннее тридцати")))
+	file = append(file, mpegFrames(10)...)
+	file = append(file, id3v1("Полное название", "Muse", "Album", "2009", 0)...)
+	tags := readBytes(t, file)
+	if tags.Title != "Полное название песни длиннее тридцати" || tags.Artist != "Muse" || tags.Album != "Album" {
+		t.Errorf("Tags = %+v", tags)
+	}
+	if tags.Duration <= 0 {
+		t.Errorf("Duration = %v, want по кадрам MPEG", tags.Duration)
+	}
+}
+
+func TestReadID3Truncated(t *testing.T) {
+	full := id3Tag(3, 0, id3Frame23("TIT2", 0, text(0, "Uprising")), id3Frame23("TPE1", 0, text(0, "Muse")))
+
+	t.Run("тело короче заголовка", func(t *testing.T) {
+		_, err := Read(bytes.NewReader(full[:len(full)-5]))
+		if err == nil || !strings.Contains(err.Error(), "ID3v2") {
+			t.Errorf("error = %v, want ошибку чтения ID3v2", err)
+		}
+	})
+	t.Run("только заголовок", func(t *testing.T) {
+		tags := readBytes(t, []byte("ID3\x03\x00"))
+		if tags.Format != FormatMP3 || tags.Title != "" {
+			t.Errorf("Tags = %+v", tags)
+		}
+	})
+	t.Run("кадр длиннее тега", func(t *testing.T) {
+		good := id3Frame23("TIT2", 0, text(0, "Uprising"))
+		bad := id3Frame23("TPE1", 0, text(0, "Muse"))
+		binary.BigEndian.PutUint32(bad[4:], 1000)
+		tags := readBytes(t, id3Tag(3, 0, good, bad))
+		if tags.Title != "Uprising" || tags.Artist != "" {
+			t.Errorf("Tags = %+v, want кадры до повреждённого", tags)
+		}
+	})
+	t.Run("пустые кадры", func(t *testing.T) {
+		tags := readBytes(t, id3Tag(3, 0,
+			id3Frame23("TIT2", 0, nil),
+			id3Frame23("USLT", 0, []byte{0}),
+			id3Frame23("APIC", 0, []byte{0}),
+			id3Frame23("TIT2", 0x0080, []byte{1, 2}),
+		))
+		if tags.Title != "" || tags.Lyrics != "" || tags.Picture != nil {
+			t.Errorf("Tags = %+v", tags)
+		}
+	})
+	t.Run("слишком большой тег", func(t *testing.T) {
+		header := append([]byte("ID3\x03\x00\x00"), syncsafeBytes(maxID3Size+1)...)
+		if _, err := Read(bytes.NewReader(header)); err == nil || !strings.Contains(err.Error(), "слишком большой") {
+			t.Errorf("error = %v", err)
+		}
+	})
+	t.Run("неизвестная версия", func(t *testing.T) {
+		file := append(id3Tag(5, 0, id3Frame23("TIT2", 0, text(0, "x"))), mpegFrames(10)...)
+		tags := readBytes(t, file)
+		if tags.Title != "" || tags.Duration <= 0 {
+			t.Errorf("Tags = %+v, want без тегов, но с длительностью", tags)
+		}
+	})
+}
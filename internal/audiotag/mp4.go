@@ -0,0 +1,204 @@
+package audiotag
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// atom — атом (бокс) MP4: тип и расположение его содержимого в файле.
+type atom struct {
+	kind  string
+	start int64 // начало содержимого после заголовка
+	end   int64
+}
+
+// mp4Items — поля списка ilst, которые нужны для тегов.
+var mp4Items = map[string]bool{
+	"\xa9ART": true, "aART": true, "\xa9nam": true, "\xa9alb": true,
+	"\xa9day": true, "trkn": true, "disk": true, "\xa9lyr": true, "covr": true,
+}
+
+// Типы значений атома data для обложки covr.
+const (
+	mp4TypeJPEG = 13
+	mp4TypePNG  = 14
+)
+
+// readMP4 разбирает MP4/M4A: длительность берётся из moov/mvhd,
+// теги — из списка moov/udta/meta/ilst в формате iTunes.
+func readMP4(r io.ReadSeeker, size int64) (Tags, error) {
+	tags := Tags{Format: FormatMP4}
+	var albumArtist string
+	err := eachAtom(r, 0, size, func(a atom) error {
+		if a.kind != "moov" {
+			return nil
+		}
+		return eachAtom(r, a.start, a.end, func(a atom) error {
+			switch a.kind {
+			case "mvhd":
+				duration, err := mvhdDuration(r, a)
+				if err != nil {
+					return err
+				}
+				tags.Duration = duration
+			case "udta", "meta":
+				return eachMeta(r, a, func(kind string, dataType uint32, data []byte) {
+					switch kind {
+					case "\xa9ART":
+						tags.Artist = string(data)
+					case "aART":
+						albumArtist = string(data)
+					case "\xa9nam":
+						tags.Title = string(data)
+					case "\xa9alb":
+						tags.Album = string(data)
+					case "\xa9day":
+						tags.Year, tags.Date = parseDate(string(data))
+					case "trkn":
+						tags.Track = mp4Number(data)
+					case "disk":
+						tags.Disc = mp4Number(data)
+					case "\xa9lyr":
+						tags.Lyrics = string(data)
+					case "covr":
+						switch dataType {
+						case mp4TypeJPEG:
+							tags.setPicture(&Picture{MimeType: "image/jpeg", Data: data})
+						case mp4TypePNG:
+							tags.setPicture(&Picture{MimeType: "image/png", Data: data})
+						}
+					}
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return Tags{}, err
+	}
+	if strings.TrimSpace(tags.Artist) == "" {
+		tags.Artist = albumArtist
+	}
+	return tags, nil
+}
+
+// eachAtom передаёт fn атомы, лежащие подряд между start и end.
+func eachAtom(r io.ReadSeeker, start, end int64, fn func(atom) error) error {
+	header := make([]byte, 16)
+	for offset := start; offset+8 <= end; {
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return fmt.Errorf("ошибка чтения MP4: %w", err)
+		}
+		if _, err := io.ReadFull(r, header[:8]); err != nil {
+			return fmt.Errorf("ошибка чтения атома MP4: %w", err)
+		}
+		size := int64(binary.BigEndian.Uint32(header))
+		a := atom{kind: string(header[4:8]), start: offset + 8}
+		switch size {
+		case 0:
+			// Атом до конца файла
+			size = end - offset
+		case 1:
+			// 64-битный размер сразу после типа
+			if _, err := io.ReadFull(r, header[8:16]); err != nil {
+				return fmt.Errorf("ошибка чтения атома MP4: %w", err)
+			}
+			size = int64(binary.BigEndian.Uint64(header[8:16]))
+			a.start += 8
+		}
+		if size < a.start-offset || offset+size > end {
+			return errors.New("некорректный размер атома MP4")
+		}
+		a.end = offset + size
+		if err := fn(a); err != nil {
+			return err
+		}
+		offset = a.end
+	}
+	return nil
+}
+
+// mvhdDuration читает длительность из заголовка фильма: версия 1 хранит поля в 64 битах.
+func mvhdDuration(r io.ReadSeeker, a atom) (time.Duration, error) {
+	data, err := readAtom(r, a, 32)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case len(data) >= 32 && data[0] == 1:
+		return durationOf(binary.BigEndian.Uint64(data[24:32]), uint64(binary.BigEndian.Uint32(data[20:24]))), nil
+	case len(data) >= 20:
+		return durationOf(uint64(binary.BigEndian.Uint32(data[16:20])), uint64(binary.BigEndian.Uint32(data[12:16]))), nil
+	}
+	return 0, nil
+}
+
+// eachMeta находит список ilst внутри udta/meta или meta и передаёт fn
+// тип и содержимое атомов data нужных полей.
+func eachMeta(r io.ReadSeeker, a atom, fn func(kind string, dataType uint32, data []byte)) error {
+	if a.kind == "udta" {
+		return eachAtom(r, a.start, a.end, func(a atom) error {
+			if a.kind != "meta" {
+				return nil
+			}
+			return eachMeta(r, a, fn)
+		})
+	}
+	// meta в ISO-формате — полный атом с версией и флагами, в QuickTime их нет
+	start := a.start
+	probe, err := readAtom(r, atom{start: a.start, end: a.end}, 8)
+	if err != nil {
+		return err
+	}
+	if len(probe) == 8 && string(probe[4:8]) != "hdlr" {
+		start += 4
+	}
+	return eachAtom(r, start, a.end, func(a atom) error {
+		if a.kind != "ilst" {
+			return nil
+		}
+		return eachAtom(r, a.start, a.end, func(item atom) error {
+			if !mp4Items[item.kind] {
+				return nil
+			}
+			return eachAtom(r, item.start, item.end, func(a atom) error {
+				if a.kind != "data" || a.end-a.start > maxCommentSize {
+					return nil
+				}
+				data, err := readAtom(r, a, int(a.end-a.start))
+				if err != nil {
+					return err
+				}
+				// Тип значения (4 байта) и локаль (4 байта) перед самим значением
+				if len(data) >= 8 {
+					fn(item.kind, binary.BigEndian.Uint32(data)&0xFFFFFF, data[8:])
+				}
+				return nil
+			})
+		})
+	})
+}
+
+// readAtom читает до limit байт содержимого атома.
+func readAtom(r io.ReadSeeker, a atom, limit int) ([]byte, error) {
+	if _, err := r.Seek(a.start, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("ошибка чтения MP4: %w", err)
+	}
+	data := make([]byte, min(int64(limit), a.end-a.start))
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("ошибка чтения атома MP4: %w", err)
+	}
+	return data, nil
+}
+
+// mp4Number разбирает номер трека или диска: два байта резерва, номер и общее число.
+func mp4Number(data []byte) int {
+	if len(data) < 4 {
+		return 0
+	}
+	return int(binary.BigEndian.Uint16(data[2:4]))
+}
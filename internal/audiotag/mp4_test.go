@@ -0,0 +1,149 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+)
+
+// box собирает атом MP4 из типа и содержимого.
+func box(kind string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	return append(append(binary.BigEndian.AppendUint32(nil, uint32(8+len(body))), kind...), body...)
+}
+
+// mvhd собирает заголовок фильма версии 0 с масштабом времени и длительностью.
+func mvhd(timescale, duration uint32) []byte {
+	data := make([]byte, 100)
+	binary.BigEndian.PutUint32(data[12:], timescale)
+	binary.BigEndian.PutUint32(data[16:], duration)
+	return box("mvhd", data)
+}
+
+// item собирает поле списка ilst со значением data заданного типа.
+func item(kind string, dataType uint32, value []byte) []byte {
+	header := binary.BigEndian.AppendUint32(nil, dataType)
+	return box(kind, box("data", header, make([]byte, 4), value))
+}
+
+func mp4File(moov ...[]byte) []byte {
+	return append(box("ftyp", []byte("M4A \x00\x00\x00\x00isomM4A ")), box("moov", moov...)...)
+}
+
+// iTunesMeta — udta/meta/ilst в формате iTunes: meta — полный атом с версией и флагами.
+func iTunesMeta(items ...[]byte) []byte {
+	return box("udta", box("meta", make([]byte, 4), box("hdlr", make([]byte, 25)), box("ilst", items...)))
+}
+
+func TestReadMP4(t *testing.T) {
+	file := mp4File(
+		mvhd(1000, 305500),
+		iTunesMeta(
+			item("aART", 1, []byte("Album Artist")),
+			item("\xa9nam", 1, []byte("Uprising")),
+			item("\xa9alb", 1, []byte("The Resistance")),
+			item("\xa9day", 1, []byte("2009-09-14T07:00:00Z")),
+			item("trkn", 0, []byte{0, 0, 0, 1, 0, 11, 0, 0}),
+			item("disk", 0, []byte{0, 0, 0, 1, 0, 1}),
+			item("\xa9lyr", 1, []byte("Куплет")),
+			item("\xa9too", 1, []byte("Lavf")),
+			item("covr", mp4TypePNG, []byte("png")),
+		),
+	)
+	tags := readBytes(t, file)
+	want := Tags{
+		Format: FormatMP4, Artist: "Album Artist", Title: "Uprising", Album: "The Resistance",
+		Year: 2009, Date: "2009-09-14", Track: 1, Disc: 1, Duration: 305500 * time.Millisecond, Lyrics: "Куплет",
+	}
+	got := tags
+	got.Picture = nil
+	if got != want {
+		t.Errorf("Tags = %+v, want %+v", got, want)
+	}
+	if tags.Picture == nil || tags.Picture.MimeType != "image/png" || string(tags.Picture.Data) != "png" {
+		t.Errorf("Picture = %+v", tags.Picture)
+	}
+}
+
+func TestReadMP4Variants(t *testing.T) {
+	mvhdV1 := make([]byte, 100)
+	mvhdV1[0] = 1
+	binary.BigEndian.PutUint32(mvhdV1[20:], 44100)
+	binary.BigEndian.PutUint64(mvhdV1[24:], 44100*2)
+
+	// 64-битный размер атома: размер 1 и настоящий размер после типа
+	large := append(binary.BigEndian.AppendUint32(nil, 1), "free"...)
+	large = binary.BigEndian.AppendUint64(large, 16+4)
+	large = append(large, "xxxx"...)
+
+	tests := []struct {
+		name  string
+		file  []byte
+		check func(Tags) bool
+	}{
+		{
+			name:  "mvhd версии 1",
+			file:  mp4File(box("mvhd", mvhdV1)),
+			check: func(tags Tags) bool { return tags.Duration == 2*time.Second },
+		},
+		{
+			name: "meta в формате QuickTime без версии",
+			file: mp4File(box("meta", box("hdlr", make([]byte, 25)), box("ilst", item("\xa9ART", 1, []byte("Muse"))))),
+			check: func(tags Tags) bool {
+				return tags.Artist == "Muse"
+			},
+		},
+		{
+			name:  "исполнитель важнее исполнителя альбома",
+			file:  mp4File(iTunesMeta(item("\xa9ART", 1, []byte("Muse")), item("aART", 1, []byte("Various")))),
+			check: func(tags Tags) bool { return tags.Artist == "Muse" },
+		},
+		{
+			name:  "64-битный размер атома",
+			file:  mp4File(large, iTunesMeta(item("\xa9nam", 1, []byte("Uprising")))),
+			check: func(tags Tags) bool { return tags.Title == "Uprising" },
+		},
+		{
+			name: "атом до конца файла",
+			file: append(box("ftyp", []byte("M4A ")), append([]byte{0, 0, 0, 0}, box("moov", iTunesMeta(item("\xa9nam", 1, []byte("Uprising"))))[4:]...)...),
+			check: func(tags Tags) bool {
+				return tags.Title == "Uprising"
+			},
+		},
+		{
+			name:  "обложка неизвестного типа и короткие поля пропускаются",
+			file:  mp4File(iTunesMeta(item("covr", 27, []byte("bmp")), item("trkn", 0, []byte{0, 0}), box("\xa9nam", box("data", []byte{0, 0, 0, 1})))),
+			check: func(tags Tags) bool { return tags.Picture == nil && tags.Track == 0 && tags.Title == "" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tags := readBytes(t, tt.file); !tt.check(tags) {
+				t.Errorf("Tags = %+v", tags)
+			}
+		})
+	}
+}
+
+func TestReadMP4Truncated(t *testing.T) {
+	full := mp4File(mvhd(1000, 1000), iTunesMeta(item("\xa9nam", 1, []byte("Uprising"))))
+
+	tests := []struct {
+		name string
+		file []byte
+	}{
+		{name: "moov обрезан", file: full[:len(full)-10]},
+		{name: "атом меньше заголовка", file: append(box("ftyp", []byte("M4A ")), 0, 0, 0, 4, 'm', 'o', 'o', 'v')},
+		{name: "64-битный размер обрезан", file: append(box("ftyp", []byte("M4A ")), 0, 0, 0, 1, 'm', 'o', 'o', 'v', 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tt.file))
+			if err == nil || !strings.Contains(err.Error(), "MP4") {
+				t.Errorf("error = %v, want ошибку разбора MP4", err)
+			}
+		})
+	}
+}
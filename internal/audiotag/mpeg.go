@@ -0,0 +1,151 @@
+package audiotag
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxSyncSearch — на сколько байт после тегов искать первый кадр MPEG.
+const maxSyncSearch = 256 << 10
+
+// Битрейты в кбит/с по индексу из заголовка кадра; индекс 0 (свободный битрейт) не поддерживается.
+var (
+	bitratesV1 = [3][15]int{
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448}, // Layer I
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384},    // Layer II
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320},     // Layer III
+	}
+	bitratesV2 = [3][15]int{
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160},
+	}
+	sampleRatesV1 = [3]int{44100, 48000, 32000}
+)
+
+// frameHeader — разобранный заголовок кадра MPEG.
+type frameHeader struct {
+	mpeg1      bool
+	layer      int // 1, 2 или 3
+	bitrate    int // бит/с
+	sampleRate int
+	mono       bool
+	length     int // длина кадра в байтах вместе с заголовком
+	samples    int // отсчётов на кадр
+}
+
+// parseFrameHeader разбирает четыре байта заголовка кадра.
+func parseFrameHeader(b []byte) (frameHeader, bool) {
+	if b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return frameHeader{}, false
+	}
+	version := (b[1] >> 3) & 3 // 0 — MPEG 2.5, 2 — MPEG 2, 3 — MPEG 1
+	layerBits := (b[1] >> 1) & 3
+	bitrateIndex := int(b[2] >> 4)
+	rateIndex := int((b[2] >> 2) & 3)
+	if version == 1 || layerBits == 0 || bitrateIndex == 0 || bitrateIndex == 15 || rateIndex == 3 {
+		return frameHeader{}, false
+	}
+	h := frameHeader{mpeg1: version == 3, layer: 4 - int(layerBits), mono: b[3]>>6 == 3}
+	h.sampleRate = sampleRatesV1[rateIndex]
+	switch version {
+	case 2:
+		h.sampleRate /= 2
+	case 0:
+		h.sampleRate /= 4
+	}
+	padding := int((b[2] >> 1) & 1)
+	if h.mpeg1 {
+		h.bitrate = bitratesV1[h.layer-1][bitrateIndex] * 1000
+	} else {
+		h.bitrate = bitratesV2[h.layer-1][bitrateIndex] * 1000
+	}
+	switch {
+	case h.layer == 1:
+		h.samples = 384
+		h.length = (12*h.bitrate/h.sampleRate + padding) * 4
+	case h.layer == 3 && !h.mpeg1:
+		h.samples = 576
+		h.length = 72*h.bitrate/h.sampleRate + padding
+	default:
+		h.samples = 1152
+		h.length = 144*h.bitrate/h.sampleRate + padding
+	}
+	return h, true
+}
+
+// isFrameHeader сообщает, похожи ли первые байты файла на заголовок кадра MPEG.
+func isFrameHeader(b []byte) bool {
+	_, ok := parseFrameHeader(b)
+	return ok
+}
+
+// mpegDuration вычисляет длительность звука между start и end. Для файлов с переменным
+// битрейтом число кадров берётся из заголовка Xing/Info или VBRI, иначе длительность
+// оценивается по битрейту первого кадра. Ноль означает, что кадры не найдены.
+func mpegDuration(r io.ReadSeeker, start, end int64) (time.Duration, error) {
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	br := bufio.NewReaderSize(r, 64<<10)
+	offset := start
+	for offset-start < maxSyncSearch && offset+4 <= end {
+		b, err := br.Peek(4)
+		if errors.Is(err, io.EOF) {
+			return 0, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("ошибка чтения файла: %w", err)
+		}
+		h, ok := parseFrameHeader(b)
+		if !ok {
+			br.Discard(1)
+			offset++
+			continue
+		}
+		// Случайное совпадение с синхрословом проверяется по заголовку следующего кадра
+		frame, err := br.Peek(min(h.length+4, br.Size()))
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, fmt.Errorf("ошибка чтения файла: %w", err)
+		}
+		if len(frame) >= h.length+4 {
+			if _, ok := parseFrameHeader(frame[h.length:]); !ok {
+				br.Discard(1)
+				offset++
+				continue
+			}
+		}
+		if frames := vbrFrames(frame, h); frames > 0 {
+			return durationOf(uint64(frames)*uint64(h.samples), uint64(h.sampleRate)), nil
+		}
+		bits := uint64(end-offset) * 8
+		return durationOf(bits, uint64(h.bitrate)), nil
+	}
+	return 0, nil
+}
+
+// vbrFrames возвращает число кадров из заголовка Xing/Info или VBRI первого кадра, либо 0.
+func vbrFrames(frame []byte, h frameHeader) uint32 {
+	// Заголовок Xing идёт после служебной информации кадра, её размер зависит от версии и каналов
+	side := 32
+	switch {
+	case h.mpeg1 && h.mono, !h.mpeg1 && !h.mono:
+		side = 17
+	case !h.mpeg1 && h.mono:
+		side = 9
+	}
+	if x := 4 + side; len(frame) >= x+12 {
+		tag := string(frame[x : x+4])
+		if (tag == "Xing" || tag == "Info") && binary.BigEndian.Uint32(frame[x+4:])&1 != 0 {
+			return binary.BigEndian.Uint32(frame[x+8:])
+		}
+	}
+	if v := 4 + 32; len(frame) >= v+18 && string(frame[v:v+4]) == "VBRI" {
+		return binary.BigEndian.Uint32(frame[v+14:])
+	}
+	return 0
+}
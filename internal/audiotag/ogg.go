@@ -0,0 +1,124 @@
+package audiotag
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	oggHeaderSize = 27
+	// oggTailSize — сколько байт с конца файла просматривать в поисках последней страницы.
+	oggTailSize = 64 << 10
+	// opusRate — частота, в которой Opus считает позицию гранулы, независимо от исходной.
+	opusRate = 48000
+)
+
+// readOgg разбирает Ogg Vorbis и Opus: первый пакет потока задаёт кодек и частоту,
+// второй содержит комментарии, а длительность берётся из позиции последней страницы.
+func readOgg(r io.ReadSeeker, size int64) (Tags, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return Tags{}, fmt.Errorf("ошибка чтения Ogg: %w", err)
+	}
+	packets, err := oggPackets(r, 2)
+	if err != nil {
+		return Tags{}, err
+	}
+	if len(packets) < 2 {
+		return Tags{}, errors.New("в потоке Ogg нет заголовков кодека")
+	}
+
+	var rate, preSkip uint64
+	var comments []byte
+	ident, comment := packets[0], packets[1]
+	switch {
+	case len(ident) >= 16 && bytes.HasPrefix(ident, []byte("\x01vorbis")) && bytes.HasPrefix(comment, []byte("\x03vorbis")):
+		rate = uint64(binary.LittleEndian.Uint32(ident[12:16]))
+		comments = comment[7:]
+	case len(ident) >= 12 && bytes.HasPrefix(ident, []byte("OpusHead")) && bytes.HasPrefix(comment, []byte("OpusTags")):
+		rate = opusRate
+		preSkip = uint64(binary.LittleEndian.Uint16(ident[10:12]))
+		comments = comment[8:]
+	default:
+		return Tags{}, ErrUnsupported
+	}
+
+	tags, err := vorbisComments(comments)
+	if err != nil {
+		return Tags{}, err
+	}
+	tags.Format = FormatOgg
+	granule, err := lastGranule(r, size)
+	if err != nil {
+		return Tags{}, err
+	}
+	if granule > preSkip {
+		tags.Duration = durationOf(granule-preSkip, rate)
+	}
+	return tags, nil
+}
+
+// oggPackets собирает первые count пакетов потока из сегментов страниц Ogg.
+// Пакет продолжается, пока длина сегмента равна 255, в том числе на следующей странице.
+func oggPackets(r io.Reader, count int) ([][]byte, error) {
+	var packets [][]byte
+	var current []byte
+	header := make([]byte, oggHeaderSize)
+	for len(packets) < count {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return packets, nil
+			}
+			return nil, fmt.Errorf("ошибка чтения страницы Ogg: %w", err)
+		}
+		if string(header[:4]) != "OggS" {
+			return nil, errors.New("некорректная страница Ogg")
+		}
+		segments := make([]byte, header[26])
+		if _, err := io.ReadFull(r, segments); err != nil {
+			return nil, fmt.Errorf("ошибка чтения страницы Ogg: %w", err)
+		}
+		for _, n := range segments {
+			data := make([]byte, n)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return nil, fmt.Errorf("ошибка чтения страницы Ogg: %w", err)
+			}
+			if len(packets) == count {
+				continue
+			}
+			current = append(current, data...)
+			if len(current) > maxCommentSize {
+				return nil, errors.New("заголовок Ogg слишком большой")
+			}
+			if n < 255 {
+				packets = append(packets, current)
+				current = nil
+			}
+		}
+	}
+	return packets, nil
+}
+
+// lastGranule возвращает позицию гранулы последней страницы файла — число отсчётов потока.
+func lastGranule(r io.ReadSeeker, size int64) (uint64, error) {
+	start := max(size-oggTailSize, 0)
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("ошибка чтения Ogg: %w", err)
+	}
+	tail, err := io.ReadAll(r)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка чтения Ogg: %w", err)
+	}
+	for i := bytes.LastIndex(tail, []byte("OggS")); i >= 0; i = bytes.LastIndex(tail[:i], []byte("OggS")) {
+		if i+14 <= len(tail) {
+			granule := binary.LittleEndian.Uint64(tail[i+6 : i+14])
+			// У страниц без завершённых пакетов позиция равна -1
+			if granule != ^uint64(0) {
+				return granule, nil
+			}
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,194 @@
+package audiotag
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Форматы аудиофайлов.
+const (
+	FormatMP3  = "mp3"
+	FormatFLAC = "flac"
+	FormatOgg  = "ogg"
+	FormatMP4  = "mp4"
+)
+
+// ErrUnsupported возвращается, если формат файла не распознан.
+var ErrUnsupported = errors.New("неподдерживаемый формат аудиофайла")
+
+// formats описывает распознаваемые форматы: тип содержимого и расширение файла.
+var formats = map[string]struct {
+	mimeType  string
+	extension string
+}{
+	FormatMP3:  {"audio/mpeg", ".mp3"},
+	FormatFLAC: {"audio/flac", ".flac"},
+	FormatOgg:  {"audio/ogg", ".ogg"},
+	FormatMP4:  {"audio/mp4", ".m4a"},
+}
+
+// MimeType возвращает тип содержимого для формата.
+func MimeType(format string) string {
+	return formats[format].mimeType
+}
+
+// Extension возвращает расширение файла для формата вместе с точкой.
+func Extension(format string) string {
+	return formats[format].extension
+}
+
+// Tags — метаданные аудиофайла. Пустые строки и нули означают, что поле не заполнено.
+type Tags struct {
+	Format string
+	Artist string
+	Title  string
+	Album  string
+	// Year — год выпуска; Date заполняется, только если в тегах указана полная дата (YYYY-MM-DD).
+	Year     int
+	Date     string
+	Track    int
+	Disc     int
+	Duration time.Duration
+	// Lyrics — текст песни без синхронизации (USLT в ID3, LYRICS в Vorbis, ©lyr в MP4).
+	Lyrics string
+	// Picture — встроенная обложка: передняя, а если её нет — первая из найденных.
+	Picture *Picture
+}
+
+// Picture — изображение, встроенное в аудиофайл.
+type Picture struct {
+	MimeType string
+	Data     []byte
+	// front — картинка помечена как передняя обложка (тип 3 в ID3 и FLAC).
+	front bool
+}
+
+// pictureFront — тип «передняя обложка» в ID3 APIC и блоке PICTURE FLAC.
+const pictureFront = 3
+
+// setPicture запоминает картинку, если обложки ещё нет или новая — передняя, а прежняя нет.
+func (t *Tags) setPicture(p *Picture) {
+	if p == nil || len(p.Data) == 0 {
+		return
+	}
+	if t.Picture == nil || (p.front && !t.Picture.front) {
+		t.Picture = p
+	}
+}
+
+// merge дополняет пустые поля значениями из other.
+func (t *Tags) merge(other Tags) {
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&t.Artist, other.Artist)
+	fill(&t.Title, other.Title)
+	fill(&t.Album, other.Album)
+	fill(&t.Lyrics, other.Lyrics)
+	if t.Year == 0 {
+		t.Year, t.Date = other.Year, other.Date
+	}
+	if t.Track == 0 {
+		t.Track = other.Track
+	}
+	if t.Disc == 0 {
+		t.Disc = other.Disc
+	}
+	t.setPicture(other.Picture)
+}
+
+// Read определяет формат файла по сигнатуре и разбирает его теги и длительность.
+// Файл читается с начала; положение в r после вызова не определено.
+func Read(r io.ReadSeeker) (Tags, error) {
+	size, err := r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return Tags{}, fmt.Errorf("ошибка определения размера файла: %w", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return Tags{}, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	head := make([]byte, 12)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return Tags{}, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	head = head[:n]
+
+	var tags Tags
+	switch {
+	case bytes.HasPrefix(head, []byte("ID3")):
+		tags, err = readMP3(r, size)
+	case bytes.HasPrefix(head, []byte("fLaC")):
+		tags, err = readFLAC(r, 0)
+	case bytes.HasPrefix(head, []byte("OggS")):
+		tags, err = readOgg(r, size)
+	case len(head) >= 8 && string(head[4:8]) == "ftyp":
+		tags, err = readMP4(r, size)
+	case len(head) >= 4 && isFrameHeader(head):
+		tags, err = readMP3(r, size)
+	default:
+		return Tags{}, ErrUnsupported
+	}
+	if err != nil {
+		return Tags{}, err
+	}
+	tags.Artist = strings.TrimSpace(tags.Artist)
+	tags.Title = strings.TrimSpace(tags.Title)
+	tags.Album = strings.TrimSpace(tags.Album)
+	tags.Lyrics = strings.TrimSpace(tags.Lyrics)
+	return tags, nil
+}
+
+// parseDate разбирает дату из тегов: «2004», «2004-05-12» или «2004-05-12T10:00:00».
+func parseDate(value string) (year int, date string) {
+	value = strings.TrimSpace(value)
+	if len(value) < 4 {
+		return 0, ""
+	}
+	year, err := strconv.Atoi(value[:4])
+	if err != nil || year <= 0 {
+		return 0, ""
+	}
+	if len(value) >= 10 {
+		if _, err := time.Parse("2006-01-02", value[:10]); err == nil {
+			return year, value[:10]
+		}
+	}
+	return year, ""
+}
+
+// parseNumber разбирает номер трека или диска: «3» или «3/12».
+func parseNumber(value string) int {
+	value, _, _ = strings.Cut(strings.TrimSpace(value), "/")
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// latin1 декодирует строку в ISO-8859-1: каждый байт — символ с тем же кодом.
+func latin1(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// durationOf переводит число отсчётов при частоте rate в длительность.
+func durationOf(samples uint64, rate uint64) time.Duration {
+	if rate == 0 {
+		return 0
+	}
+	seconds := samples / rate
+	rest := samples % rate
+	return time.Duration(seconds)*time.Second + time.Duration(rest*uint64(time.Second)/rate)
+}
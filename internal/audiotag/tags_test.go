@@ -0,0 +1,46 @@
+package audiotag
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantYear int
+		wantDate string
+	}{
+		{value: "", wantYear: 0},
+		{value: "09", wantYear: 0},
+		{value: "2009", wantYear: 2009},
+		{value: " 2009-07-16 ", wantYear: 2009, wantDate: "2009-07-16"},
+		{value: "2009-07-16T10:00:00", wantYear: 2009, wantDate: "2009-07-16"},
+		{value: "2009-13-40", wantYear: 2009},
+		{value: "0000", wantYear: 0},
+		{value: "abcd", wantYear: 0},
+	}
+	for _, tt := range tests {
+		year, date := parseDate(tt.value)
+		if year != tt.wantYear || date != tt.wantDate {
+			t.Errorf("parseDate(%q) = %d, %q, want %d, %q", tt.value, year, date, tt.wantYear, tt.wantDate)
+		}
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	for value, want := range map[string]int{"": 0, "3": 3, " 3 / 12 ": 3, "07/12": 7, "-1": 0, "A1": 0} {
+		if got := parseNumber(value); got != want {
+			t.Errorf("parseNumber(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestReadUnsupported(t *testing.T) {
+	for _, data := range [][]byte{nil, []byte("RIFF\x00\x00\x00\x00WAVE"), []byte("ab")} {
+		if _, err := Read(bytes.NewReader(data)); !errors.Is(err, ErrUnsupported) {
+			t.Errorf("Read(%q): error = %v, want ErrUnsupported", data, err)
+		}
+	}
+}
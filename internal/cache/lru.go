@@ -0,0 +1,109 @@
+// Package cache — кэш LRU и объединение одновременных запросов по ключу (singleflight).
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// LRU хранит не больше capacity значений и при переполнении вытесняет то,
+// к которому дольше всего не обращались. Безопасен для одновременного использования.
+type LRU[K comparable, V any] struct {
+	mu        sync.Mutex
+	capacity  int
+	items     map[K]*list.Element
+	order     *list.List // от недавних к давним
+	evictions int64
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// NewLRU создаёт кэш на capacity значений; при capacity <= 0 кэш ничего не хранит.
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{capacity: capacity, items: map[K]*list.Element{}, order: list.New()}
+}
+
+// Get возвращает значение по ключу и отмечает его как недавнее.
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry[K, V]).value, true
+}
+
+// Add сохраняет значение, заменяя прежнее, и вытесняет самое давнее при переполнении.
+func (c *LRU[K, V]) Add(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.capacity <= 0 {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry[K, V]).value = value
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry[K, V]).key)
+		c.evictions++
+	}
+}
+
+// Remove удаляет значение по ключу.
+func (c *LRU[K, V]) Remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.order.Remove(el)
+		delete(c.items, key)
+	}
+}
+
+// RemoveIf удаляет значение по ключу, если stale(значение) истинно. Проверка и удаление
+// выполняются под одной блокировкой, поэтому значение, заменённое параллельным Add,
+// не удаляется по ошибке. Сообщает, было ли значение удалено.
+func (c *LRU[K, V]) RemoveIf(key K, stale func(V) bool) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok || !stale(el.Value.(*lruEntry[K, V]).value) {
+		return false
+	}
+	c.order.Remove(el)
+	delete(c.items, key)
+	return true
+}
+
+// Len возвращает число значений в кэше.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Capacity возвращает наибольшее число значений в кэше.
+func (c *LRU[K, V]) Capacity() int {
+	return c.capacity
+}
+
+// Evictions возвращает число значений, вытесненных при переполнении.
+func (c *LRU[K, V]) Evictions() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictions
+}
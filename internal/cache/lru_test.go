@@ -0,0 +1,87 @@
+package cache
+
+import "testing"
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	// Обращение к «a» делает самым давним «b»
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %v", v, ok)
+	}
+	c.Add("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error("b не вытеснен")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if v, ok := c.Get(key); !ok || v != want {
+			t.Errorf("Get(%s) = %d, %v, want %d", key, v, ok, want)
+		}
+	}
+	if c.Len() != 2 || c.Evictions() != 1 {
+		t.Errorf("Len = %d, Evictions = %d, want 2 и 1", c.Len(), c.Evictions())
+	}
+}
+
+func TestLRUAddReplaces(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Add("a", 1)
+	c.Add("b", 2)
+	c.Add("a", 10)
+	// Замена не вытесняет и делает «a» недавним
+	c.Add("c", 3)
+
+	if v, ok := c.Get("a"); !ok || v != 10 {
+		t.Errorf("Get(a) = %d, %v, want 10", v, ok)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("b не вытеснен")
+	}
+	if c.Evictions() != 1 {
+		t.Errorf("Evictions = %d, want 1", c.Evictions())
+	}
+}
+
+func TestLRUZeroCapacity(t *testing.T) {
+	for _, capacity := range []int{0, -1} {
+		c := NewLRU[string, int](capacity)
+		c.Add("a", 1)
+		if _, ok := c.Get("a"); ok || c.Len() != 0 || c.Evictions() != 0 {
+			t.Errorf("capacity %d: кэш сохранил значение", capacity)
+		}
+	}
+}
+
+func TestLRURemove(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Add("a", 1)
+	c.Remove("a")
+	c.Remove("missing")
+	if _, ok := c.Get("a"); ok || c.Len() != 0 {
+		t.Error("a не удалён")
+	}
+}
+
+func TestLRURemoveIf(t *testing.T) {
+	c := NewLRU[string, int](2)
+	c.Add("a", 1)
+	stale := func(v int) bool { return v == 1 }
+
+	c.Add("a", 2)
+	if c.RemoveIf("a", stale) {
+		t.Error("RemoveIf удалил значение, заменённое после проверки")
+	}
+	if v, ok := c.Get("a"); !ok || v != 2 {
+		t.Errorf("Get(a) = %d, %v, want 2", v, ok)
+	}
+
+	c.Add("a", 1)
+	if !c.RemoveIf("a", stale) || c.Len() != 0 {
+		t.Error("RemoveIf не удалил устаревшее значение")
+	}
+	if c.RemoveIf("missing", stale) {
+		t.Error("RemoveIf для отсутствующего ключа вернул true")
+	}
+}
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Group объединяет одновременные вызовы с одним ключом: функция выполняется один раз,
+// а все вызвавшие получают её результат. Нулевое значение готово к использованию.
+type Group[V any] struct {
+	mu    sync.Mutex
+	calls map[string]*call[V]
+}
+
+// call — выполняющийся вызов; done закрывается, когда val и err заполнены.
+type call[V any] struct {
+	done chan struct{}
+	val  V
+	err  error
+}
+
+// Do выполняет fn для ключа, если такой вызов ещё не идёт, и ждёт результата или отмены ctx.
+// shared сообщает, что результат получен чужим вызовом. fn получает ctx без отмены:
+// вызвавший первым может уйти, не прерывая запрос для остальных, поэтому fn должна
+// ограничивать своё время сама.
+func (g *Group[V]) Do(ctx context.Context, key string, fn func(context.Context) (V, error)) (val V, shared bool, err error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*call[V]{}
+	}
+	c, shared := g.calls[key]
+	if !shared {
+		c = &call[V]{done: make(chan struct{})}
+		g.calls[key] = c
+		go func() {
+			c.val, c.err = fn(context.WithoutCancel(ctx))
+			g.mu.Lock()
+			delete(g.calls, key)
+			g.mu.Unlock()
+			close(c.done)
+		}()
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, shared, c.err
+	case <-ctx.Done():
+		var zero V
+		return zero, shared, ctx.Err()
+	}
+}
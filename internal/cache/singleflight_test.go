@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroupCollapsesConcurrentCalls(t *testing.T) {
+	var g Group[int]
+	var calls atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		return 42, nil
+	}
+
+	const n = 10
+	var wg sync.WaitGroup
+	var shared atomic.Int32
+	results := make([]int, n)
+	do := func(i int) {
+		defer wg.Done()
+		v, s, err := g.Do(context.Background(), "key", fn)
+		if err != nil {
+			t.Errorf("Do: %v", err)
+		}
+		if s {
+			shared.Add(1)
+		}
+		results[i] = v
+	}
+	wg.Add(n)
+	go do(0)
+	<-started
+	for i := 1; i < n; i++ {
+		go do(i)
+	}
+	// Даём остальным вызовам встать в ожидание запущенного fn
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn вызвана %d раз, want 1", calls.Load())
+	}
+	if shared.Load() != n-1 {
+		t.Errorf("shared = %d, want %d", shared.Load(), n-1)
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("results[%d] = %d, want 42", i, v)
+		}
+	}
+}
+
+func TestGroupDistinctKeys(t *testing.T) {
+	var g Group[string]
+	for _, key := range []string{"a", "b"} {
+		v, shared, err := g.Do(context.Background(), key, func(ctx context.Context) (string, error) {
+			return key, nil
+		})
+		if err != nil || shared || v != key {
+			t.Errorf("Do(%s) = %q, %v, %v", key, v, shared, err)
+		}
+	}
+}
+
+func TestGroupError(t *testing.T) {
+	var g Group[int]
+	boom := errors.New("boom")
+	_, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 0, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("error = %v, want boom", err)
+	}
+	// Ошибка не запоминается: следующий вызов выполняет fn заново
+	v, _, err := g.Do(context.Background(), "key", func(ctx context.Context) (int, error) {
+		return 1, nil
+	})
+	if err != nil || v != 1 {
+		t.Errorf("повторный Do = %d, %v", v, err)
+	}
+}
+
+func TestGroupCallerCancel(t *testing.T) {
+	var g Group[int]
+	release := make(chan struct{})
+	fnCtx := make(chan context.Context, 1)
+	fn := func(ctx context.Context) (int, error) {
+		fnCtx <- ctx
+		<-release
+		return 7, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := g.Do(ctx, "key", fn)
+		first <- err
+	}()
+	inner := <-fnCtx
+
+	second := make(chan int, 1)
+	go func() {
+		v, _, _ := g.Do(context.Background(), "key", fn)
+		second <- v
+	}()
+
+	cancel()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("первый вызов: error = %v, want context.Canceled", err)
+	}
+	// Уход первого вызвавшего не отменяет fn для остальных
+	if inner.Err() != nil {
+		t.Errorf("ctx внутри fn отменён: %v", inner.Err())
+	}
+	close(release)
+	if v := <-second; v != 7 {
+		t.Errorf("второй вызов = %d, want 7", v)
+	}
+}
@@ -22,13 +22,17 @@ func (p *PostgresProvider) DB() *sql.DB {
 }
 
 func NewPostgresProvider(cfg *config.Config) (*PostgresProvider, error) {
-	log := logrus.New()
-	log.SetLevel(logrus.DebugLevel)
-
 	dsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=disable",
 		cfg.DBUser, cfg.DBPass, cfg.DBHost, cfg.DBPort, cfg.DBName,
 	)
+	return Open(dsn)
+}
+
+// Open подключается к базе данных по строке подключения dsn.
+func Open(dsn string) (*PostgresProvider, error) {
+	log := logrus.New()
+	log.SetLevel(logrus.DebugLevel)
 
 	db, err := sql.Open("postgres", dsn)
 	if err != nil {
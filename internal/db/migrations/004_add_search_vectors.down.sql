@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_groups_search_vector;
+ALTER TABLE groups DROP COLUMN IF EXISTS search_vector;
+DROP INDEX IF EXISTS idx_songs_search_vector;
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
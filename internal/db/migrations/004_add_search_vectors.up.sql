@@ -0,0 +1,17 @@
+-- Полнотекстовый поиск по названию и тексту песни (английская и русская морфология)
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(song_name, '')), 'A') ||
+        setweight(to_tsvector('russian', COALESCE(song_name, '')), 'A') ||
+        setweight(to_tsvector('english', COALESCE(text, '')), 'C') ||
+        setweight(to_tsvector('russian', COALESCE(text, '')), 'C')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
+
+-- Полнотекстовый поиск по названию группы
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', COALESCE(name, '')), 'B') ||
+        setweight(to_tsvector('russian', COALESCE(name, '')), 'B')
+    ) STORED;
+CREATE INDEX IF NOT EXISTS idx_groups_search_vector ON groups USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_songs_song_name_trgm;
+DROP INDEX IF EXISTS idx_groups_name_trgm;
+DROP EXTENSION IF EXISTS pg_trgm;
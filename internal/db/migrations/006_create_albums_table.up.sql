@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS albums (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    release_date DATE,
+    album_type VARCHAR(16) NOT NULL DEFAULT 'LP' CHECK (album_type IN ('LP', 'EP', 'single')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_albums_group_id ON albums (group_id);
+
+-- Привязка песен к альбому: номер трека и диска
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS album_id INT REFERENCES albums(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS track_number INT CHECK (track_number > 0),
+    ADD COLUMN IF NOT EXISTS disc_number INT CHECK (disc_number > 0);
+CREATE INDEX IF NOT EXISTS idx_songs_album_id ON songs (album_id);
@@ -0,0 +1,4 @@
+ALTER TABLE groups
+    DROP COLUMN IF EXISTS bio,
+    DROP COLUMN IF EXISTS formed_year,
+    DROP COLUMN IF EXISTS country;
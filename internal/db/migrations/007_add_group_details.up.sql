@@ -0,0 +1,4 @@
+ALTER TABLE groups
+    ADD COLUMN IF NOT EXISTS country VARCHAR(64),
+    ADD COLUMN IF NOT EXISTS formed_year INT CHECK (formed_year BETWEEN 1000 AND 9999),
+    ADD COLUMN IF NOT EXISTS bio TEXT;
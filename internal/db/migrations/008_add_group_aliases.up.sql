@@ -0,0 +1,8 @@
+-- Прежние названия групп, сохраняемые при слиянии дубликатов
+CREATE TABLE IF NOT EXISTS group_aliases (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_group_aliases_group_id ON group_aliases (group_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_group_aliases_normalized_alias;
+DROP INDEX IF EXISTS idx_groups_normalized_name;
+ALTER TABLE group_aliases DROP COLUMN IF EXISTS normalized_alias;
+ALTER TABLE groups DROP COLUMN IF EXISTS normalized_name;
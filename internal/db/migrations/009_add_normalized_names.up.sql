@@ -0,0 +1,6 @@
+-- Нормализованные названия для поиска группы без учёта регистра, артикля и алфавита.
+-- Заполняются приложением (normalize.Name), в том числе для существующих строк при запуске.
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS normalized_name VARCHAR(255);
+ALTER TABLE group_aliases ADD COLUMN IF NOT EXISTS normalized_alias VARCHAR(255);
+CREATE INDEX IF NOT EXISTS idx_groups_normalized_name ON groups (normalized_name);
+CREATE INDEX IF NOT EXISTS idx_group_aliases_normalized_alias ON group_aliases (normalized_alias);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS playlists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Песня может входить в плейлист несколько раз, поэтому у элемента свой ID.
+-- Уникальность позиции проверяется в конце транзакции: при перестановке
+-- позиции элементов временно совпадают.
+CREATE TABLE IF NOT EXISTS playlist_items (
+    id SERIAL PRIMARY KEY,
+    playlist_id INT NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    position INT NOT NULL CHECK (position > 0),
+    added_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT playlist_items_position_key UNIQUE (playlist_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+CREATE INDEX IF NOT EXISTS idx_playlist_items_song_id ON playlist_items (song_id);
@@ -0,0 +1,9 @@
+-- Умные плейлисты: песни подбираются по правилам (JSON) при каждом чтении
+CREATE TABLE IF NOT EXISTS smart_playlists (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    rules JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,13 @@
+-- Аудиофайлы песен: содержимое хранится в хранилище объектов, здесь — ключ и сведения о файле
+CREATE TABLE IF NOT EXISTS song_audio (
+    song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    storage_key VARCHAR(512) NOT NULL,
+    file_name VARCHAR(255),
+    format VARCHAR(16) NOT NULL,
+    mime_type VARCHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    checksum CHAR(64) NOT NULL,
+    duration_ms BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,16 @@
+-- Обложки песен и групп: изображение хранится в хранилище объектов, здесь — ключ и сведения о нём.
+-- У каждой обложки ровно один владелец, у владельца не больше одной обложки
+CREATE TABLE IF NOT EXISTS covers (
+    id SERIAL PRIMARY KEY,
+    song_id INT UNIQUE REFERENCES songs(id) ON DELETE CASCADE,
+    group_id INT UNIQUE REFERENCES groups(id) ON DELETE CASCADE,
+    storage_key VARCHAR(512) NOT NULL,
+    mime_type VARCHAR(64) NOT NULL,
+    width INT NOT NULL CHECK (width > 0),
+    height INT NOT NULL CHECK (height > 0),
+    size_bytes BIGINT NOT NULL CHECK (size_bytes >= 0),
+    checksum CHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((song_id IS NULL) <> (group_id IS NULL))
+);
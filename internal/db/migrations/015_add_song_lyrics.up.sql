@@ -0,0 +1,8 @@
+-- Синхронизированные тексты песен в формате LRC; простой текст по-прежнему хранится в songs.text
+CREATE TABLE IF NOT EXISTS song_lyrics (
+    song_id INT PRIMARY KEY REFERENCES songs(id) ON DELETE CASCADE,
+    lrc TEXT NOT NULL,
+    line_count INT NOT NULL CHECK (line_count > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,16 @@
+-- История текстов песен: каждая правка songs.text сохраняется отдельной версией
+CREATE TABLE IF NOT EXISTS song_text_revisions (
+    song_id INT NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+    revision INT NOT NULL CHECK (revision > 0),
+    text TEXT NOT NULL,
+    author VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (song_id, revision)
+);
+
+-- Уже сохранённые тексты становятся первой версией
+INSERT INTO song_text_revisions (song_id, revision, text, created_at)
+SELECT id, 1, text, COALESCE(updated_at, CURRENT_TIMESTAMP)
+FROM songs
+WHERE text IS NOT NULL AND text <> ''
+ON CONFLICT DO NOTHING;
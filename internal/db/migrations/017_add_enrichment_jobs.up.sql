@@ -0,0 +1,24 @@
+-- Состояние обогащения песни сведениями из внешних источников: pending — ждёт очереди,
+-- done — сведения получены (или не нужны), failed — попытки исчерпаны
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS enrichment_status VARCHAR(16) NOT NULL DEFAULT 'done'
+        CHECK (enrichment_status IN ('pending', 'done', 'failed')),
+    ADD COLUMN IF NOT EXISTS enriched_at TIMESTAMP;
+
+-- Очередь обогащения: не больше одной задачи на песню. Обработчики забирают готовые задачи
+-- через SELECT ... FOR UPDATE SKIP LOCKED; задача running с просроченным locked_at
+-- считается брошенной и забирается снова
+CREATE TABLE IF NOT EXISTS enrichment_jobs (
+    id SERIAL PRIMARY KEY,
+    song_id INT NOT NULL UNIQUE REFERENCES songs(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'queued'
+        CHECK (status IN ('queued', 'running', 'done', 'failed')),
+    attempts INT NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_at TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_enrichment_jobs_ready ON enrichment_jobs (run_at)
+    WHERE status IN ('queued', 'running');
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_songs_enriched_at;
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS manual_fields;
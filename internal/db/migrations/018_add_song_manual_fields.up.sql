@@ -0,0 +1,8 @@
+-- Поля песни (release_date, text, link), введённые вручную, а не полученные от источников
+-- сведений; политика слияния при повторном обогащении может их не перезаписывать
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS manual_fields TEXT[] NOT NULL DEFAULT '{}';
+
+-- Планировщик обновления выбирает песни с давним обогащением
+CREATE INDEX IF NOT EXISTS idx_songs_enriched_at ON songs (enriched_at NULLS FIRST, id)
+    WHERE enrichment_status = 'done';
@@ -0,0 +1,12 @@
+-- Кэш ответов внешнего API о песнях, общий для всех процессов сервиса.
+-- not_found — API ответил 404; такие ответы хранятся меньше
+CREATE TABLE IF NOT EXISTS metadata_cache (
+    key TEXT PRIMARY KEY,
+    release_date TEXT,
+    text TEXT,
+    link TEXT,
+    not_found BOOLEAN NOT NULL DEFAULT FALSE,
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_metadata_cache_expires_at ON metadata_cache (expires_at);
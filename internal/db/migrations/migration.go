@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
 	"github.com/sirupsen/logrus"
@@ -23,8 +24,9 @@ func getMigrations(path string) ([]Migration, error) {
 	}
 
 	for _, file := range files {
-		if filepath.Ext(file.Name()) == ".up.sql" {
-			version := file.Name()[:len(file.Name())-len(filepath.Ext(file.Name()))-3]
+		// filepath.Ext вернёт только ".sql", поэтому проверяем составной суффикс целиком
+		if strings.HasSuffix(file.Name(), ".up.sql") {
+			version := strings.TrimSuffix(file.Name(), ".up.sql")
 			migrations = append(migrations, Migration{
 				Version: version,
 				Up:      filepath.Join(path, file.Name()),
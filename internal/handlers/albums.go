@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+type AlbumHandler struct {
+	AlbumService *services.AlbumService
+}
+
+func NewAlbumHandler(service *services.AlbumService) *AlbumHandler {
+	return &AlbumHandler{AlbumService: service}
+}
+
+// GetAlbums godoc
+// @Summary Получить список альбомов
+// @Description Возвращает альбомы с фильтрацией по группе и названию и поддержкой пагинации.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param group_id query int false "ID группы"
+// @Param title query string false "Название альбома"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Album "Список альбомов"
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /albums [get]
+func (h *AlbumHandler) GetAlbums(w http.ResponseWriter, r *http.Request) {
+	filter := models.AlbumFilter{Title: r.URL.Query().Get("title")}
+	if v := r.URL.Query().Get("group_id"); v != "" {
+		groupID, err := strconv.Atoi(v)
+		if err != nil || groupID <= 0 {
+			http.Error(w, "Некорректное значение group_id", http.StatusBadRequest)
+			return
+		}
+		filter.GroupID = groupID
+	}
+	h.writeAlbums(w, r, filter)
+}
+
+// GetGroupAlbums godoc
+// @Summary Получить альбомы группы
+// @Description Возвращает альбомы группы в порядке выхода.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Album "Список альбомов"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /groups/{id}/albums [get]
+func (h *AlbumHandler) GetGroupAlbums(w http.ResponseWriter, r *http.Request) {
+	groupID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	h.writeAlbums(w, r, models.AlbumFilter{GroupID: groupID})
+}
+
+func (h *AlbumHandler) writeAlbums(w http.ResponseWriter, r *http.Request, filter models.AlbumFilter) {
+	page, limit := parsePagination(r)
+
+	albums, err := h.AlbumService.GetAlbums(filter, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения альбомов: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAlbums(albums)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetAlbum godoc
+// @Summary Получить альбом
+// @Description Возвращает альбом по ID. Песни альбома доступны через GET /songs?album_id=.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 200 {object} handlers.Album "Альбом"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /albums/{id} [get]
+func (h *AlbumHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	album, err := h.AlbumService.GetAlbum(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения альбома: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newAlbum(album))
+}
+
+// CreateAlbum godoc
+// @Summary Создать альбом
+// @Description Создаёт альбом группы. Тип альбома по умолчанию — LP.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param input body CreateAlbumRequest true "Данные альбома"
+// @Success 201 {object} handlers.Album "Созданный альбом"
+// @Failure 400 {string} string "Некорректные входные данные"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Ошибка создания альбома"
+// @Router /albums [post]
+func (h *AlbumHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	var input CreateAlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректные входные данные", http.StatusBadRequest)
+		return
+	}
+	h.createAlbum(w, input)
+}
+
+// CreateGroupAlbum godoc
+// @Summary Создать альбом группы
+// @Description Создаёт альбом группы, указанной в пути. Тип альбома по умолчанию — LP.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param input body CreateAlbumRequest true "Данные альбома"
+// @Success 201 {object} handlers.Album "Созданный альбом"
+// @Failure 400 {string} string "Некорректные входные данные"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Ошибка создания альбома"
+// @Router /groups/{id}/albums [post]
+func (h *AlbumHandler) CreateGroupAlbum(w http.ResponseWriter, r *http.Request) {
+	groupID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	var input CreateAlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректные входные данные", http.StatusBadRequest)
+		return
+	}
+	input.GroupID = groupID
+	h.createAlbum(w, input)
+}
+
+func (h *AlbumHandler) createAlbum(w http.ResponseWriter, input CreateAlbumRequest) {
+	if input.GroupID <= 0 || input.Title == "" {
+		http.Error(w, "Группа и название альбома обязательны", http.StatusBadRequest)
+		return
+	}
+	album := models.Album{GroupID: input.GroupID, Title: input.Title, Type: input.AlbumType}
+	if input.ReleaseDate != "" {
+		releaseDate, err := parseDate(&input.ReleaseDate)
+		if err != nil {
+			http.Error(w, "Некорректный формат даты", http.StatusBadRequest)
+			return
+		}
+		album.ReleaseDate = releaseDate
+	}
+
+	created, err := h.AlbumService.CreateAlbum(album)
+	if err != nil {
+		http.Error(w, "Ошибка создания альбома: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newAlbum(created))
+}
+
+// UpdateAlbum godoc
+// @Summary Обновить альбом
+// @Description Обновляет переданные поля альбома.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Param input body UpdateAlbumRequest true "Обновляемые данные альбома"
+// @Success 200 {string} string "Альбом успешно обновлён"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Альбом или группа не найдены"
+// @Failure 500 {string} string "Ошибка обновления альбома"
+// @Router /albums/{id} [put]
+func (h *AlbumHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input UpdateAlbumRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+	releaseDate, err := parseDate(input.ReleaseDate)
+	if err != nil {
+		http.Error(w, "Некорректный формат даты", http.StatusBadRequest)
+		return
+	}
+
+	update := models.AlbumUpdate{
+		GroupID:     input.GroupID,
+		Title:       input.Title,
+		ReleaseDate: releaseDate,
+		Type:        input.AlbumType,
+	}
+	if err := h.AlbumService.UpdateAlbum(id, update); err != nil {
+		http.Error(w, "Ошибка обновления альбома: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Альбом успешно обновлён"))
+}
+
+// DeleteAlbum godoc
+// @Summary Удалить альбом
+// @Description Удаляет альбом по ID. Песни альбома остаются в библиотеке без привязки к нему.
+// @Tags Albums
+// @Accept json
+// @Produce json
+// @Param id path int true "ID альбома"
+// @Success 204 {string} string "Альбом успешно удалён"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 500 {string} string "Ошибка удаления альбома"
+// @Router /albums/{id} [delete]
+func (h *AlbumHandler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.AlbumService.DeleteAlbum(id); err != nil {
+		http.Error(w, "Ошибка удаления альбома: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/audiotag"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+// maxAudioSize ограничивает размер запроса с аудиофайлами.
+const maxAudioSize = 1 << 30
+
+type AudioHandler struct {
+	AudioService *services.AudioService
+}
+
+func NewAudioHandler(service *services.AudioService) *AudioHandler {
+	return &AudioHandler{AudioService: service}
+}
+
+// GetSongAudio godoc
+// @Summary Получить сведения об аудиофайле песни
+// @Description Возвращает формат, размер, контрольную сумму и длительность аудиофайла песни.
+// @Tags Audio
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 200 {object} handlers.AudioFile "Аудиофайл песни"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня или аудиофайл не найдены"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /songs/{id}/audio [get]
+func (h *AudioHandler) GetSongAudio(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	audio, err := h.AudioService.GetSongAudio(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения аудиофайла: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAudioFile(audio)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// StreamSongAudio godoc
+// @Summary Воспроизвести аудиофайл песни
+// @Description Отдаёт аудиофайл песни с поддержкой диапазонов (Range, ответ 206), чтобы плеер мог перематывать. ETag — контрольная сумма файла; поддерживаются If-Range, If-None-Match и If-Modified-Since.
+// @Tags Audio
+// @Produce audio/mpeg,audio/flac,audio/ogg,audio/mp4
+// @Param id path int true "ID песни"
+// @Param Range header string false "Диапазон байтов, например bytes=0-1023"
+// @Success 200 {file} file "Аудиофайл целиком"
+// @Success 206 {file} file "Запрошенный диапазон"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня или аудиофайл не найдены"
+// @Failure 416 {string} string "Диапазон вне файла"
+// @Failure 500 {string} string "Ошибка чтения файла"
+// @Router /songs/{id}/stream [get]
+func (h *AudioHandler) StreamSongAudio(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	audio, blob, err := h.AudioService.OpenSongAudio(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения аудиофайла: "+err.Error(), errorStatus(err))
+		return
+	}
+	defer blob.Close()
+
+	name := audio.FileName
+	if name == "" {
+		name = fmt.Sprintf("song-%d%s", id, audiotag.Extension(audio.Format))
+	}
+	// Тип и ETag задаются заранее: ServeContent не угадывает тип по содержимому
+	// и сверяет с ETag заголовки If-Range и If-None-Match
+	w.Header().Set("Content-Type", audio.MimeType)
+	w.Header().Set("ETag", `"`+audio.Checksum+`"`)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": name}))
+	http.ServeContent(w, r, name, audio.UpdatedAt, blob)
+}
+
+// UploadSongAudio godoc
+// @Summary Загрузить аудиофайл песни
+// @Description Сохраняет аудиофайл MP3, FLAC, Ogg Vorbis/Opus или MP4/M4A, заменяя прежний. По тегам файла (ID3v2/ID3v1, комментарии Vorbis, атомы MP4) заполняются пустые поля песни: текст, дата выпуска (если в тегах только год — 1 января этого года), альбом, номера трека и диска; группа и название не меняются.
+// @Tags Audio
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param file formData file true "Аудиофайл"
+// @Success 200 {object} handlers.AudioUploadResponse "Аудиофайл сохранён"
+// @Failure 400 {string} string "Некорректный ID или неподдерживаемый формат файла"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Failure 500 {string} string "Ошибка сохранения файла"
+// @Router /songs/{id}/audio [post]
+func (h *AudioHandler) UploadSongAudio(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	if !parseUploadForm(w, r, maxAudioSize) {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Не передан файл", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.AudioService.UploadSongAudio(id, services.AudioUpload{FileName: header.Filename, File: file})
+	if err != nil {
+		http.Error(w, "Ошибка загрузки аудиофайла: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newAudioUploadResponse(result)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ImportAudio godoc
+// @Summary Добавить песни по аудиофайлам
+// @Description Добавляет по песне на каждый файл из полей file: группа, название, альбом, дата, номер трека и текст берутся из тегов, а без исполнителя или названия в тегах — из имени файла «Исполнитель - Название». Файл песни, которая уже есть у группы без аудио, привязывается к ней; песни с аудиофайлом пропускаются. Номер записи в отчёте — номер файла в запросе.
+// @Tags Audio
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Аудиофайл; поле можно повторять"
+// @Param create_groups formData bool false "Создавать группы, которых нет в библиотеке" default(true)
+// @Success 200 {object} handlers.ImportReport "Отчёт о загрузке"
+// @Failure 400 {string} string "Некорректная форма"
+// @Failure 413 {string} string "Файлы слишком большие"
+// @Router /songs/audio [post]
+func (h *AudioHandler) ImportAudio(w http.ResponseWriter, r *http.Request) {
+	if !parseUploadForm(w, r, maxAudioSize) {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	headers := r.MultipartForm.File["file"]
+	if len(headers) == 0 {
+		http.Error(w, "Не переданы файлы", http.StatusBadRequest)
+		return
+	}
+	var opts services.AudioImportOptions
+	var err error
+	if opts.CreateGroups, err = formBool(r, "create_groups", true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	uploads := make([]services.AudioUpload, 0, len(headers))
+	for _, header := range headers {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "Ошибка чтения файла "+header.Filename+": "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		uploads = append(uploads, services.AudioUpload{FileName: header.Filename, File: file})
+	}
+	report := h.AudioService.ImportAudio(uploads, opts)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newImportReport(report)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// DeleteSongAudio godoc
+// @Summary Удалить аудиофайл песни
+// @Description Удаляет аудиофайл песни из хранилища; сама песня остаётся.
+// @Tags Audio
+// @Param id path int true "ID песни"
+// @Success 204 {string} string "Аудиофайл удалён"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня или аудиофайл не найдены"
+// @Failure 500 {string} string "Ошибка удаления аудиофайла"
+// @Router /songs/{id}/audio [delete]
+func (h *AudioHandler) DeleteSongAudio(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.AudioService.DeleteSongAudio(id); err != nil {
+		http.Error(w, "Ошибка удаления аудиофайла: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
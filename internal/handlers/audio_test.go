@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/memory"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+// testMP3 — три кадра MPEG-1 Layer III без тегов; байты кадров различаются, чтобы диапазоны
+// можно было сверить с содержимым.
+func testMP3() []byte {
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	var file []byte
+	for i := 0; i < 3; i++ {
+		for j := 4; j < len(frame); j++ {
+			frame[j] = byte(i*31 + j)
+		}
+		file = append(file, frame...)
+	}
+	return file
+}
+
+// newAudioFixture возвращает обработчик аудио на хранилище в памяти и песню с загруженным файлом.
+func newAudioFixture(t *testing.T, file []byte) (*AudioHandler, int, models.AudioFile) {
+	t.Helper()
+	store := memory.NewStore()
+	blobs, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	songs := services.NewSongService(memory.NewSongRepository(store), memory.NewGroupRepository(store),
+		memory.NewAlbumRepository(store), blobs)
+	audio := services.NewAudioService(memory.NewAudioRepository(store), blobs, songs)
+
+	id, err := songs.AddSongWithAPI("Muse", "Uprising", services.AddSongOptions{CreateGroup: true})
+	if err != nil {
+		t.Fatalf("AddSongWithAPI: %v", err)
+	}
+	result, err := audio.UploadSongAudio(id, services.AudioUpload{FileName: "uprising.mp3", File: bytes.NewReader(file)})
+	if err != nil {
+		t.Fatalf("UploadSongAudio: %v", err)
+	}
+	return NewAudioHandler(audio), id, result.Audio
+}
+
+func stream(h *AudioHandler, id int, header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/songs/%d/stream", id), nil)
+	r.Header = header
+	r = mux.SetURLVars(r, map[string]string{"id": strconv.Itoa(id)})
+	w := httptest.NewRecorder()
+	h.StreamSongAudio(w, r)
+	return w
+}
+
+func TestStreamSongAudio(t *testing.T) {
+	file := testMP3()
+	h, id, audio := newAudioFixture(t, file)
+	etag := `"` + audio.Checksum + `"`
+	size := len(file)
+
+	tests := []struct {
+		name       string
+		header     http.Header
+		wantStatus int
+		wantBody   []byte
+		wantRange  string
+		wantNoBody bool
+	}{
+		{
+			name:       "файл целиком",
+			header:     http.Header{},
+			wantStatus: http.StatusOK,
+			wantBody:   file,
+		},
+		{
+			name:       "один диапазон",
+			header:     http.Header{"Range": {"bytes=100-199"}},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   file[100:200],
+			wantRange:  fmt.Sprintf("bytes 100-199/%d", size),
+		},
+		{
+			name:       "хвост файла",
+			header:     http.Header{"Range": {"bytes=-10"}},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   file[size-10:],
+			wantRange:  fmt.Sprintf("bytes %d-%d/%d", size-10, size-1, size),
+		},
+		{
+			name:       "диапазон за концом файла",
+			header:     http.Header{"Range": {fmt.Sprintf("bytes=%d-", size)}},
+			wantStatus: http.StatusRequestedRangeNotSatisfiable,
+			wantRange:  fmt.Sprintf("bytes */%d", size),
+			wantNoBody: true,
+		},
+		{
+			name:       "If-None-Match с тем же ETag",
+			header:     http.Header{"If-None-Match": {etag}},
+			wantStatus: http.StatusNotModified,
+			wantNoBody: true,
+		},
+		{
+			name:       "If-None-Match с другим ETag",
+			header:     http.Header{"If-None-Match": {`"other"`}},
+			wantStatus: http.StatusOK,
+			wantBody:   file,
+		},
+		{
+			name:       "If-Range с тем же ETag",
+			header:     http.Header{"Range": {"bytes=0-9"}, "If-Range": {etag}},
+			wantStatus: http.StatusPartialContent,
+			wantBody:   file[:10],
+			wantRange:  fmt.Sprintf("bytes 0-9/%d", size),
+		},
+		{
+			name:       "If-Range с устаревшим ETag",
+			header:     http.Header{"Range": {"bytes=0-9"}, "If-Range": {`"stale"`}},
+			wantStatus: http.StatusOK,
+			wantBody:   file,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := stream(h, id, tt.header)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("статус = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Range"); got != tt.wantRange {
+				t.Errorf("Content-Range = %q, want %q", got, tt.wantRange)
+			}
+			if tt.wantNoBody {
+				if tt.wantStatus == http.StatusNotModified && w.Body.Len() != 0 {
+					t.Errorf("тело ответа 304 = %d байт", w.Body.Len())
+				}
+				return
+			}
+			if !bytes.Equal(w.Body.Bytes(), tt.wantBody) {
+				t.Errorf("тело = %d байт, want %d", w.Body.Len(), len(tt.wantBody))
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			if got := w.Header().Get("Content-Type"); got != "audio/mpeg" {
+				t.Errorf("Content-Type = %q, want audio/mpeg", got)
+			}
+			if got := w.Header().Get("Accept-Ranges"); got != "bytes" {
+				t.Errorf("Accept-Ranges = %q, want bytes", got)
+			}
+		})
+	}
+}
+
+func TestStreamSongAudioMissing(t *testing.T) {
+	h, id, _ := newAudioFixture(t, testMP3())
+	if w := stream(h, id+100, http.Header{}); w.Code != http.StatusNotFound {
+		t.Errorf("неизвестная песня: статус = %d, want 404", w.Code)
+	}
+	if err := h.AudioService.DeleteSongAudio(id); err != nil {
+		t.Fatalf("DeleteSongAudio: %v", err)
+	}
+	if w := stream(h, id, http.Header{}); w.Code != http.StatusNotFound {
+		t.Errorf("песня без файла: статус = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/imaging"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+type CoverHandler struct {
+	CoverService *services.CoverService
+}
+
+func NewCoverHandler(service *services.CoverService) *CoverHandler {
+	return &CoverHandler{CoverService: service}
+}
+
+// GetCover godoc
+// @Summary Получить обложку
+// @Description Отдаёт обложку песни или группы: исходное изображение или, с параметром size, миниатюру, большая сторона которой не больше size. Миниатюры создаются при первом запросе и хранятся на диске; изображение меньше запрошенного размера отдаётся как есть. Адрес с параметром v из cover_url кешируется бессрочно, остальные ответы сверяются по ETag.
+// @Tags Covers
+// @Produce image/jpeg,image/png
+// @Param kind path string true "Владелец обложки" Enums(song, group)
+// @Param id path int true "ID песни или группы"
+// @Param size query int false "Размер миниатюры в пикселях" Enums(64, 128, 256, 512)
+// @Param v query string false "Версия обложки из cover_url"
+// @Success 200 {file} file "Изображение"
+// @Failure 400 {string} string "Некорректный ID или размер"
+// @Failure 404 {string} string "Владелец или обложка не найдены"
+// @Failure 500 {string} string "Ошибка чтения файла"
+// @Router /covers/{kind}/{id} [get]
+func (h *CoverHandler) GetCover(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	var size int
+	if value := r.URL.Query().Get("size"); value != "" {
+		if size, err = strconv.Atoi(value); err != nil {
+			http.Error(w, "Некорректный размер миниатюры", http.StatusBadRequest)
+			return
+		}
+	}
+
+	cover, blob, err := h.CoverService.OpenCover(models.CoverKind(vars["kind"]), id, size)
+	if err != nil {
+		http.Error(w, "Ошибка получения обложки: "+err.Error(), errorStatus(err))
+		return
+	}
+	defer blob.Close()
+
+	// Адрес с актуальной версией не изменится, пока не сменится сама обложка
+	if version := r.URL.Query().Get("v"); version != "" && strings.HasPrefix(cover.Checksum, version) {
+		w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	w.Header().Set("Content-Type", cover.MimeType)
+	w.Header().Set("ETag", fmt.Sprintf(`"%s-%d"`, cover.Checksum, size))
+	name := fmt.Sprintf("%s-%d%s", cover.Kind, cover.OwnerID, imaging.Extension(strings.TrimPrefix(cover.MimeType, "image/")))
+	http.ServeContent(w, r, name, cover.UpdatedAt, blob)
+}
+
+// UploadSongCover godoc
+// @Summary Загрузить обложку песни
+// @Description Сохраняет обложку песни, заменяя прежнюю. Принимает изображение JPEG или PNG либо аудиофайл MP3, FLAC, Ogg или M4A — тогда берётся встроенная в него обложка (передняя, если их несколько).
+// @Tags Covers
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param file formData file true "Изображение или аудиофайл"
+// @Success 200 {object} handlers.Cover "Обложка сохранена"
+// @Failure 400 {string} string "Некорректный ID, файл не изображение или в аудиофайле нет обложки"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Failure 500 {string} string "Ошибка сохранения файла"
+// @Router /songs/{id}/cover [post]
+func (h *CoverHandler) UploadSongCover(w http.ResponseWriter, r *http.Request) {
+	h.uploadCover(w, r, models.CoverKindSong)
+}
+
+// DeleteSongCover godoc
+// @Summary Удалить обложку песни
+// @Description Удаляет обложку песни вместе с миниатюрами.
+// @Tags Covers
+// @Param id path int true "ID песни"
+// @Success 204 {string} string "Обложка удалена"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня или обложка не найдены"
+// @Failure 500 {string} string "Ошибка удаления обложки"
+// @Router /songs/{id}/cover [delete]
+func (h *CoverHandler) DeleteSongCover(w http.ResponseWriter, r *http.Request) {
+	h.deleteCover(w, r, models.CoverKindSong)
+}
+
+// UploadGroupCover godoc
+// @Summary Загрузить обложку группы
+// @Description Сохраняет обложку группы, заменяя прежнюю. Принимает изображение JPEG или PNG либо аудиофайл со встроенной обложкой. Обложка группы показывается у песен без своей обложки.
+// @Tags Covers
+// @Accept mpfd
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param file formData file true "Изображение или аудиофайл"
+// @Success 200 {object} handlers.Cover "Обложка сохранена"
+// @Failure 400 {string} string "Некорректный ID, файл не изображение или в аудиофайле нет обложки"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Failure 500 {string} string "Ошибка сохранения файла"
+// @Router /groups/{id}/cover [post]
+func (h *CoverHandler) UploadGroupCover(w http.ResponseWriter, r *http.Request) {
+	h.uploadCover(w, r, models.CoverKindGroup)
+}
+
+// DeleteGroupCover godoc
+// @Summary Удалить обложку группы
+// @Description Удаляет обложку группы вместе с миниатюрами.
+// @Tags Covers
+// @Param id path int true "ID группы"
+// @Success 204 {string} string "Обложка удалена"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Группа или обложка не найдены"
+// @Failure 500 {string} string "Ошибка удаления обложки"
+// @Router /groups/{id}/cover [delete]
+func (h *CoverHandler) DeleteGroupCover(w http.ResponseWriter, r *http.Request) {
+	h.deleteCover(w, r, models.CoverKindGroup)
+}
+
+func (h *CoverHandler) uploadCover(w http.ResponseWriter, r *http.Request, kind models.CoverKind) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	// Вместо изображения можно прислать аудиофайл, поэтому лимит как у аудио
+	if !parseUploadForm(w, r, maxAudioSize) {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "Не передан файл", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	cover, err := h.CoverService.UploadCover(kind, id, services.CoverUpload{FileName: header.Filename, File: file})
+	if err != nil {
+		http.Error(w, "Ошибка загрузки обложки: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newCover(cover)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *CoverHandler) deleteCover(w http.ResponseWriter, r *http.Request, kind models.CoverKind) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.CoverService.DeleteCover(kind, id); err != nil {
+		http.Error(w, "Ошибка удаления обложки: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,968 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/EugeneKrivoshein/music_library/internal/httpclient"
+	"github.com/EugeneKrivoshein/music_library/internal/lrc"
+	"github.com/EugeneKrivoshein/music_library/internal/metadata"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/EugeneKrivoshein/music_library/internal/textdiff"
+)
+
+// Song — представление песни в ответах API.
+type Song struct {
+	ID          int       `json:"id"`
+	Group       string    `json:"group"`
+	Song        string    `json:"song"`
+	ReleaseDate string    `json:"release_date,omitempty" example:"2006-07-16"`
+	Text        string    `json:"text,omitempty"`
+	Link        string    `json:"link,omitempty"`
+	AlbumID     *int      `json:"album_id,omitempty"`
+	Album       string    `json:"album,omitempty"`
+	TrackNumber *int      `json:"track_number,omitempty"`
+	DiscNumber  *int      `json:"disc_number,omitempty"`
+	Genres      []string  `json:"genres,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	// Состояние обогащения сведениями из источников: pending, done или failed
+	EnrichmentStatus string     `json:"enrichment_status" example:"done"`
+	EnrichedAt       *time.Time `json:"enriched_at,omitempty"`
+	// Поля, введённые вручную: повторное обогащение с политикой keep-manual их не меняет
+	ManualFields []string `json:"manual_fields,omitempty" example:"text"`
+	// Сходство с запросом, заполняется только при нечётком поиске
+	Score float64 `json:"score,omitempty" example:"0.42"`
+	// Обложки песни и её группы; миниатюра — с параметром size
+	CoverURL      string `json:"cover_url,omitempty" example:"/covers/song/1?v=9f86d081"`
+	GroupCoverURL string `json:"group_cover_url,omitempty" example:"/covers/group/1?v=2c26b46b"`
+}
+
+// AddSongRequest — тело запроса на добавление песни.
+type AddSongRequest struct {
+	Group       string `json:"group"`
+	Song        string `json:"song"`
+	AlbumID     *int   `json:"album_id,omitempty"`
+	TrackNumber *int   `json:"track_number,omitempty"`
+	DiscNumber  *int   `json:"disc_number,omitempty"`
+	// Создать группу, если её нет в библиотеке
+	CreateGroup bool `json:"create_group,omitempty"`
+}
+
+// AddSongResponse — ответ на добавление песни.
+type AddSongResponse struct {
+	ID               int    `json:"id"`
+	EnrichmentStatus string `json:"enrichment_status" example:"pending"`
+}
+
+// UpdateSongRequest — тело запроса на обновление песни.
+type UpdateSongRequest struct {
+	Group       string  `json:"group,omitempty"`
+	Song        string  `json:"song,omitempty"`
+	ReleaseDate *string `json:"release_date,omitempty" example:"2006-07-16"` // Формат даты: "YYYY-MM-DD"
+	Text        *string `json:"text,omitempty"`
+	Link        *string `json:"link,omitempty"`
+	AlbumID     *int    `json:"album_id,omitempty"`
+	TrackNumber *int    `json:"track_number,omitempty"`
+	DiscNumber  *int    `json:"disc_number,omitempty"`
+	// Создать группу, если её нет в библиотеке
+	CreateGroup bool `json:"create_group,omitempty"`
+	// Автор правки; сохраняется в истории версий, если меняется текст
+	Author string `json:"author,omitempty" example:"editor"`
+}
+
+func newSong(song models.Song) Song {
+	dto := Song{
+		ID:               song.ID,
+		Group:            song.GroupName,
+		Song:             song.SongName,
+		Text:             song.Text,
+		Link:             song.Link,
+		AlbumID:          song.AlbumID,
+		Album:            song.AlbumTitle,
+		TrackNumber:      song.TrackNumber,
+		DiscNumber:       song.DiscNumber,
+		Genres:           song.Genres,
+		Tags:             song.Tags,
+		CreatedAt:        song.CreatedAt,
+		UpdatedAt:        song.UpdatedAt,
+		EnrichmentStatus: string(song.EnrichmentStatus),
+		EnrichedAt:       song.EnrichedAt,
+		ManualFields:     song.ManualFields,
+		Score:            song.Score,
+		CoverURL:         coverURL(models.CoverKindSong, song.ID, song.CoverChecksum),
+		GroupCoverURL:    coverURL(models.CoverKindGroup, song.GroupID, song.GroupCoverChecksum),
+	}
+	if song.ReleaseDate != nil {
+		dto.ReleaseDate = song.ReleaseDate.Format(models.DateLayout)
+	}
+	return dto
+}
+
+func newSongs(songs []models.Song) []Song {
+	dtos := make([]Song, 0, len(songs))
+	for _, song := range songs {
+		dtos = append(dtos, newSong(song))
+	}
+	return dtos
+}
+
+// Verse — куплет песни.
+type Verse struct {
+	Index int    `json:"index" example:"1"`
+	Text  string `json:"text"`
+}
+
+// VersesResponse — страница куплетов песни.
+type VersesResponse struct {
+	SongID      int     `json:"song_id"`
+	Page        int     `json:"page"`
+	Limit       int     `json:"limit"`
+	TotalVerses int     `json:"total_verses"`
+	TotalPages  int     `json:"total_pages"`
+	Verses      []Verse `json:"verses"`
+}
+
+func newVersesResponse(page *models.VersePage) VersesResponse {
+	resp := VersesResponse{
+		SongID:      page.SongID,
+		Page:        page.Page,
+		Limit:       page.Limit,
+		TotalVerses: page.Total,
+		TotalPages:  page.TotalPages,
+		Verses:      make([]Verse, 0, len(page.Verses)),
+	}
+	for _, verse := range page.Verses {
+		resp.Verses = append(resp.Verses, Verse{Index: verse.Index, Text: verse.Text})
+	}
+	return resp
+}
+
+// SearchResult — песня, найденная полнотекстовым поиском.
+type SearchResult struct {
+	Song
+	Rank     float64 `json:"rank"`
+	Headline string  `json:"headline" example:"Фрагмент текста с <b>совпадением</b>"`
+}
+
+func newSearchResults(results []models.SearchResult) []SearchResult {
+	dtos := make([]SearchResult, 0, len(results))
+	for _, result := range results {
+		dtos = append(dtos, SearchResult{Song: newSong(result.Song), Rank: result.Rank, Headline: result.Headline})
+	}
+	return dtos
+}
+
+// Album — представление альбома в ответах API.
+type Album struct {
+	ID          int       `json:"id"`
+	GroupID     int       `json:"group_id"`
+	Group       string    `json:"group"`
+	Title       string    `json:"title"`
+	ReleaseDate string    `json:"release_date,omitempty" example:"2006-07-16"`
+	AlbumType   string    `json:"album_type" enums:"LP,EP,single"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateAlbumRequest — тело запроса на создание альбома.
+type CreateAlbumRequest struct {
+	// ID группы; при создании через /groups/{id}/albums берётся из пути
+	GroupID     int    `json:"group_id,omitempty"`
+	Title       string `json:"title"`
+	ReleaseDate string `json:"release_date,omitempty" example:"2006-07-16"`
+	AlbumType   string `json:"album_type,omitempty" enums:"LP,EP,single"`
+}
+
+// UpdateAlbumRequest — тело запроса на обновление альбома.
+type UpdateAlbumRequest struct {
+	GroupID     *int    `json:"group_id,omitempty"`
+	Title       *string `json:"title,omitempty"`
+	ReleaseDate *string `json:"release_date,omitempty" example:"2006-07-16"`
+	AlbumType   *string `json:"album_type,omitempty" enums:"LP,EP,single"`
+}
+
+func newAlbum(album models.Album) Album {
+	dto := Album{
+		ID:        album.ID,
+		GroupID:   album.GroupID,
+		Group:     album.GroupName,
+		Title:     album.Title,
+		AlbumType: album.Type,
+		CreatedAt: album.CreatedAt,
+		UpdatedAt: album.UpdatedAt,
+	}
+	if album.ReleaseDate != nil {
+		dto.ReleaseDate = album.ReleaseDate.Format(models.DateLayout)
+	}
+	return dto
+}
+
+func newAlbums(albums []models.Album) []Album {
+	dtos := make([]Album, 0, len(albums))
+	for _, album := range albums {
+		dtos = append(dtos, newAlbum(album))
+	}
+	return dtos
+}
+
+// Group — представление группы в ответах API.
+type Group struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Country    string    `json:"country,omitempty"`
+	FormedYear *int      `json:"formed_year,omitempty" example:"1960"`
+	Bio        string    `json:"bio,omitempty"`
+	SongCount  int       `json:"song_count"`
+	Aliases    []string  `json:"aliases,omitempty"`
+	CoverURL   string    `json:"cover_url,omitempty" example:"/covers/group/1?v=2c26b46b"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CreateGroupRequest — тело запроса на создание группы.
+type CreateGroupRequest struct {
+	Name       string `json:"name"`
+	Country    string `json:"country,omitempty"`
+	FormedYear *int   `json:"formed_year,omitempty" example:"1960"`
+	Bio        string `json:"bio,omitempty"`
+}
+
+// UpdateGroupRequest — тело запроса на обновление группы.
+type UpdateGroupRequest struct {
+	Name       *string `json:"name,omitempty"`
+	Country    *string `json:"country,omitempty"`
+	FormedYear *int    `json:"formed_year,omitempty" example:"1960"`
+	Bio        *string `json:"bio,omitempty"`
+}
+
+// MergeGroupRequest — тело запроса на слияние группы-дубликата.
+type MergeGroupRequest struct {
+	// ID группы, в которую сливается дубликат
+	TargetID int `json:"target_id"`
+}
+
+func newGroup(group models.Group) Group {
+	return Group{
+		ID:         group.ID,
+		Name:       group.Name,
+		Country:    group.Country,
+		FormedYear: group.FormedYear,
+		Bio:        group.Bio,
+		SongCount:  group.SongCount,
+		Aliases:    group.Aliases,
+		CoverURL:   coverURL(models.CoverKindGroup, group.ID, group.CoverChecksum),
+		CreatedAt:  group.CreatedAt,
+		UpdatedAt:  group.UpdatedAt,
+	}
+}
+
+func newGroups(groups []models.Group) []Group {
+	dtos := make([]Group, 0, len(groups))
+	for _, group := range groups {
+		dtos = append(dtos, newGroup(group))
+	}
+	return dtos
+}
+
+// GroupDuplicate — пара групп-кандидатов в дубликаты.
+type GroupDuplicate struct {
+	ID             int     `json:"id"`
+	Name           string  `json:"name"`
+	DuplicateID    int     `json:"duplicate_id"`
+	DuplicateName  string  `json:"duplicate_name"`
+	Similarity     float64 `json:"similarity"`
+	SameNormalized bool    `json:"same_normalized"`
+}
+
+// SongDuplicate — пара песен-кандидатов в дубликаты.
+type SongDuplicate struct {
+	ID             int     `json:"id"`
+	Group          string  `json:"group"`
+	Song           string  `json:"song"`
+	DuplicateID    int     `json:"duplicate_id"`
+	DuplicateGroup string  `json:"duplicate_group"`
+	DuplicateSong  string  `json:"duplicate_song"`
+	Similarity     float64 `json:"similarity"`
+	SameNormalized bool    `json:"same_normalized"`
+}
+
+// DuplicatesResponse — отчёт о кандидатах в дубликаты.
+type DuplicatesResponse struct {
+	Groups []GroupDuplicate `json:"groups"`
+	Songs  []SongDuplicate  `json:"songs"`
+}
+
+func newDuplicatesResponse(duplicates models.Duplicates) DuplicatesResponse {
+	resp := DuplicatesResponse{
+		Groups: make([]GroupDuplicate, 0, len(duplicates.Groups)),
+		Songs:  make([]SongDuplicate, 0, len(duplicates.Songs)),
+	}
+	for _, d := range duplicates.Groups {
+		resp.Groups = append(resp.Groups, GroupDuplicate{
+			ID:             d.ID,
+			Name:           d.Name,
+			DuplicateID:    d.DuplicateID,
+			DuplicateName:  d.DuplicateName,
+			Similarity:     d.Similarity,
+			SameNormalized: d.SameNormalized,
+		})
+	}
+	for _, d := range duplicates.Songs {
+		resp.Songs = append(resp.Songs, SongDuplicate{
+			ID:             d.ID,
+			Group:          d.GroupName,
+			Song:           d.SongName,
+			DuplicateID:    d.DuplicateID,
+			DuplicateGroup: d.DuplicateGroupName,
+			DuplicateSong:  d.DuplicateSongName,
+			Similarity:     d.Similarity,
+			SameNormalized: d.SameNormalized,
+		})
+	}
+	return resp
+}
+
+// Tag — жанр или тег в ответах API.
+type Tag struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	SongCount int    `json:"song_count"`
+}
+
+// TagCloudItem — элемент облака тегов.
+type TagCloudItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	// Вес относительно самого частого тега (0..1], для выбора размера шрифта
+	Weight float64 `json:"weight" example:"0.5"`
+}
+
+// AttachTagsRequest — тело запроса на привязку жанров или тегов к песне.
+type AttachTagsRequest struct {
+	Names []string `json:"names"`
+}
+
+func newTags(tags []models.Tag) []Tag {
+	dtos := make([]Tag, 0, len(tags))
+	for _, tag := range tags {
+		dtos = append(dtos, Tag{ID: tag.ID, Name: tag.Name, SongCount: tag.SongCount})
+	}
+	return dtos
+}
+
+// newTagCloud рассчитывает веса тегов; tags отсортированы по убыванию числа песен.
+func newTagCloud(tags []models.Tag) []TagCloudItem {
+	items := make([]TagCloudItem, 0, len(tags))
+	for _, tag := range tags {
+		items = append(items, TagCloudItem{
+			Name:   tag.Name,
+			Count:  tag.SongCount,
+			Weight: float64(tag.SongCount) / float64(tags[0].SongCount),
+		})
+	}
+	return items
+}
+
+// Playlist — представление плейлиста в ответах API.
+type Playlist struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	ItemCount   int       `json:"item_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreatePlaylistRequest — тело запроса на создание плейлиста.
+type CreatePlaylistRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// UpdatePlaylistRequest — тело запроса на переименование или изменение описания плейлиста.
+type UpdatePlaylistRequest struct {
+	Name        *string `json:"name,omitempty"`
+	Description *string `json:"description,omitempty"`
+}
+
+// PlaylistItem — песня плейлиста: представление песни с позицией в плейлисте.
+type PlaylistItem struct {
+	Song
+	ItemID   int       `json:"item_id"`
+	Position int       `json:"position"`
+	AddedAt  time.Time `json:"added_at"`
+}
+
+// AddPlaylistItemRequest — тело запроса на добавление песни в плейлист.
+type AddPlaylistItemRequest struct {
+	SongID int `json:"song_id"`
+	// Позиция вставки, начиная с 1; если не задана — в конец
+	Position int `json:"position,omitempty"`
+}
+
+// AddPlaylistItemResponse — ответ на добавление песни в плейлист.
+type AddPlaylistItemResponse struct {
+	ItemID int `json:"item_id"`
+}
+
+// MovePlaylistItemRequest — тело запроса на перемещение песни в плейлисте.
+type MovePlaylistItemRequest struct {
+	Position int `json:"position"`
+}
+
+func newPlaylist(playlist models.Playlist) Playlist {
+	return Playlist{
+		ID:          playlist.ID,
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		ItemCount:   playlist.ItemCount,
+		CreatedAt:   playlist.CreatedAt,
+		UpdatedAt:   playlist.UpdatedAt,
+	}
+}
+
+func newPlaylists(playlists []models.Playlist) []Playlist {
+	dtos := make([]Playlist, 0, len(playlists))
+	for _, playlist := range playlists {
+		dtos = append(dtos, newPlaylist(playlist))
+	}
+	return dtos
+}
+
+func newPlaylistItem(item models.PlaylistItem) PlaylistItem {
+	return PlaylistItem{
+		Song:     newSong(item.Song),
+		ItemID:   item.ID,
+		Position: item.Position,
+		AddedAt:  item.AddedAt,
+	}
+}
+
+func newPlaylistItems(items []models.PlaylistItem) []PlaylistItem {
+	dtos := make([]PlaylistItem, 0, len(items))
+	for _, item := range items {
+		dtos = append(dtos, newPlaylistItem(item))
+	}
+	return dtos
+}
+
+// SmartRule — правило умного плейлиста: условие (field, op, value или values)
+// либо группа вложенных правил (match, rules).
+type SmartRule struct {
+	// Способ объединения вложенных правил группы
+	Match string      `json:"match,omitempty" enums:"all,any" example:"all"`
+	Rules []SmartRule `json:"rules,omitempty"`
+	// Поле песни
+	Field string `json:"field,omitempty" enums:"group,song,album,text,release_date,genre,tag"`
+	// Оператор сравнения
+	Op string `json:"op,omitempty" enums:"eq,contains,not_contains,starts_with,like,before,after,between,is,is_not"`
+	// Значение условия: строка, год или дата YYYY-MM-DD
+	Value string `json:"value,omitempty"`
+	// Значения для оператора between: начало и конец периода
+	Values []string `json:"values,omitempty"`
+}
+
+// SmartPlaylist — представление умного плейлиста в ответах API.
+type SmartPlaylist struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Rules       SmartRule `json:"rules"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateSmartPlaylistRequest — тело запроса на создание умного плейлиста.
+type CreateSmartPlaylistRequest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Rules       SmartRule `json:"rules"`
+}
+
+// UpdateSmartPlaylistRequest — тело запроса на изменение умного плейлиста.
+type UpdateSmartPlaylistRequest struct {
+	Name        *string    `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	Rules       *SmartRule `json:"rules,omitempty"`
+}
+
+func (r SmartRule) model() models.SmartRule {
+	rule := models.SmartRule{Match: r.Match, Field: r.Field, Op: r.Op, Values: r.Values}
+	if r.Value != "" {
+		rule.Values = append([]string{r.Value}, r.Values...)
+	}
+	for _, child := range r.Rules {
+		rule.Rules = append(rule.Rules, child.model())
+	}
+	return rule
+}
+
+func newSmartRule(rule models.SmartRule) SmartRule {
+	dto := SmartRule{Match: rule.Match, Field: rule.Field, Op: rule.Op}
+	if len(rule.Values) == 1 {
+		dto.Value = rule.Values[0]
+	} else {
+		dto.Values = rule.Values
+	}
+	for _, child := range rule.Rules {
+		dto.Rules = append(dto.Rules, newSmartRule(child))
+	}
+	return dto
+}
+
+func newSmartPlaylist(playlist models.SmartPlaylist) SmartPlaylist {
+	return SmartPlaylist{
+		ID:          playlist.ID,
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		Rules:       newSmartRule(playlist.Rules),
+		CreatedAt:   playlist.CreatedAt,
+		UpdatedAt:   playlist.UpdatedAt,
+	}
+}
+
+func newSmartPlaylists(playlists []models.SmartPlaylist) []SmartPlaylist {
+	dtos := make([]SmartPlaylist, 0, len(playlists))
+	for _, playlist := range playlists {
+		dtos = append(dtos, newSmartPlaylist(playlist))
+	}
+	return dtos
+}
+
+// ImportRowResult — итог импорта одной записи файла.
+type ImportRowResult struct {
+	// Номер записи в файле, начиная с 1, без строки заголовка CSV
+	Row    int    `json:"row"`
+	Group  string `json:"group,omitempty"`
+	Song   string `json:"song,omitempty"`
+	Status string `json:"status" enums:"created,skipped,failed"`
+	// ID добавленной песни или уже существующей, из-за которой запись пропущена
+	SongID int `json:"song_id,omitempty"`
+	// Для записи создана (при пробном импорте — будет создана) новая группа
+	NewGroup bool   `json:"new_group,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ImportReport — отчёт о массовом импорте песен.
+type ImportReport struct {
+	// Пробный импорт: created — сколько песен будет добавлено, ничего не сохранено
+	DryRun  bool              `json:"dry_run,omitempty"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+func newImportReport(report models.ImportReport) ImportReport {
+	dto := ImportReport{
+		DryRun:  report.DryRun,
+		Created: report.Created,
+		Skipped: report.Skipped,
+		Failed:  report.Failed,
+		Rows:    make([]ImportRowResult, 0, len(report.Rows)),
+	}
+	for _, row := range report.Rows {
+		dto.Rows = append(dto.Rows, ImportRowResult{
+			Row:      row.Row,
+			Group:    row.Group,
+			Song:     row.Song,
+			Status:   row.Status,
+			SongID:   row.SongID,
+			NewGroup: row.NewGroup,
+			Reason:   row.Reason,
+		})
+	}
+	return dto
+}
+
+// AudioFile — сведения об аудиофайле песни.
+type AudioFile struct {
+	SongID   int    `json:"song_id"`
+	FileName string `json:"file_name,omitempty"`
+	Format   string `json:"format" enums:"mp3,flac,ogg,mp4"`
+	MimeType string `json:"mime_type" example:"audio/mpeg"`
+	// Размер файла в байтах
+	Size int64 `json:"size"`
+	// SHA-256 содержимого
+	Checksum string `json:"checksum"`
+	// Длительность в миллисекундах, 0 — не удалось определить
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func newAudioFile(audio models.AudioFile) AudioFile {
+	return AudioFile{
+		SongID:     audio.SongID,
+		FileName:   audio.FileName,
+		Format:     audio.Format,
+		MimeType:   audio.MimeType,
+		Size:       audio.Size,
+		Checksum:   audio.Checksum,
+		DurationMs: audio.Duration.Milliseconds(),
+		CreatedAt:  audio.CreatedAt,
+		UpdatedAt:  audio.UpdatedAt,
+	}
+}
+
+// AudioTags — теги, прочитанные из аудиофайла.
+type AudioTags struct {
+	Artist string `json:"artist,omitempty"`
+	Title  string `json:"title,omitempty"`
+	Album  string `json:"album,omitempty"`
+	Year   int    `json:"year,omitempty"`
+	// Полная дата выпуска, если она указана в тегах
+	ReleaseDate string `json:"release_date,omitempty" example:"2006-07-16"`
+	TrackNumber int    `json:"track_number,omitempty"`
+	DiscNumber  int    `json:"disc_number,omitempty"`
+	// В файле есть текст песни
+	HasLyrics bool `json:"has_lyrics"`
+	// В файле есть обложка
+	HasCover bool `json:"has_cover"`
+}
+
+// AudioUploadResponse — итог загрузки аудиофайла песни.
+type AudioUploadResponse struct {
+	Audio AudioFile `json:"audio"`
+	Tags  AudioTags `json:"tags"`
+	// Поля песни, заполненные по тегам файла
+	Filled []string `json:"filled"`
+}
+
+func newAudioUploadResponse(result services.AudioUploadResult) AudioUploadResponse {
+	filled := result.Filled
+	if filled == nil {
+		filled = []string{}
+	}
+	return AudioUploadResponse{
+		Audio: newAudioFile(result.Audio),
+		Tags: AudioTags{
+			Artist:      result.Tags.Artist,
+			Title:       result.Tags.Title,
+			Album:       result.Tags.Album,
+			Year:        result.Tags.Year,
+			ReleaseDate: result.Tags.Date,
+			TrackNumber: result.Tags.Track,
+			DiscNumber:  result.Tags.Disc,
+			HasLyrics:   result.Tags.Lyrics != "",
+			HasCover:    result.Tags.Picture != nil,
+		},
+		Filled: filled,
+	}
+}
+
+// Cover — сведения об обложке песни или группы.
+type Cover struct {
+	Kind     string `json:"kind" enums:"song,group"`
+	OwnerID  int    `json:"owner_id"`
+	MimeType string `json:"mime_type" example:"image/jpeg"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	// Размер файла в байтах
+	Size int64 `json:"size"`
+	// SHA-256 исходного изображения
+	Checksum string `json:"checksum"`
+	// Адрес исходного изображения; миниатюра — с параметром size
+	URL       string    `json:"url" example:"/covers/song/1?v=9f86d081"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newCover(cover models.Cover) Cover {
+	return Cover{
+		Kind:      string(cover.Kind),
+		OwnerID:   cover.OwnerID,
+		MimeType:  cover.MimeType,
+		Width:     cover.Width,
+		Height:    cover.Height,
+		Size:      cover.Size,
+		Checksum:  cover.Checksum,
+		URL:       coverURL(cover.Kind, cover.OwnerID, cover.Checksum),
+		CreatedAt: cover.CreatedAt,
+		UpdatedAt: cover.UpdatedAt,
+	}
+}
+
+// coverVersionLength — сколько символов контрольной суммы попадает в параметр v адреса обложки.
+const coverVersionLength = 8
+
+// coverURL возвращает адрес обложки или пустую строку, если обложки нет. Параметр v меняется
+// вместе с обложкой, поэтому ответ по такому адресу можно кешировать бессрочно.
+func coverURL(kind models.CoverKind, ownerID int, checksum string) string {
+	if len(checksum) < coverVersionLength {
+		return ""
+	}
+	return fmt.Sprintf("/covers/%s/%d?v=%s", kind, ownerID, checksum[:coverVersionLength])
+}
+
+// LyricsLine — строка синхронизированного текста.
+type LyricsLine struct {
+	// Момент начала строки в миллисекундах с учётом сдвига [offset:]
+	TimeMs int64  `json:"time_ms" example:"12340"`
+	Line   string `json:"line"`
+}
+
+func newLyricsLines(lyrics lrc.Lyrics) []LyricsLine {
+	dtos := make([]LyricsLine, 0, len(lyrics.Lines))
+	for _, line := range lyrics.Lines {
+		dtos = append(dtos, LyricsLine{TimeMs: line.Time.Milliseconds(), Line: line.Text})
+	}
+	return dtos
+}
+
+// CurrentLyricsLine — строка, которая звучит в заданный момент воспроизведения.
+type CurrentLyricsLine struct {
+	PositionMs int64 `json:"position_ms"`
+	// Номер строки с нуля; -1 — момент раньше первой строки
+	Index  int    `json:"index"`
+	TimeMs *int64 `json:"time_ms,omitempty"`
+	Line   string `json:"line"`
+	// Момент следующей строки; нет у последней
+	NextTimeMs *int64 `json:"next_time_ms,omitempty"`
+}
+
+func newCurrentLyricsLine(lyrics lrc.Lyrics, index int, position time.Duration) CurrentLyricsLine {
+	dto := CurrentLyricsLine{PositionMs: position.Milliseconds(), Index: index}
+	if index >= 0 {
+		line := lyrics.Lines[index]
+		ms := line.Time.Milliseconds()
+		dto.TimeMs, dto.Line = &ms, line.Text
+	}
+	if index+1 < len(lyrics.Lines) {
+		next := lyrics.Lines[index+1].Time.Milliseconds()
+		dto.NextTimeMs = &next
+	}
+	return dto
+}
+
+// LyricsUploadResponse — итог загрузки синхронизированного текста.
+type LyricsUploadResponse struct {
+	SongID    int `json:"song_id"`
+	LineCount int `json:"line_count"`
+	// Теги сведений из текста, например ar, ti, al
+	Meta      map[string]string `json:"meta"`
+	Lines     []LyricsLine      `json:"lines"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func newLyricsUploadResponse(stored models.SongLyrics, lyrics lrc.Lyrics) LyricsUploadResponse {
+	return LyricsUploadResponse{
+		SongID:    stored.SongID,
+		LineCount: stored.LineCount,
+		Meta:      lyrics.Meta,
+		Lines:     newLyricsLines(lyrics),
+		UpdatedAt: stored.UpdatedAt,
+	}
+}
+
+// TextRevision — сведения о версии текста песни без самого текста.
+type TextRevision struct {
+	SongID   int    `json:"song_id"`
+	Revision int    `json:"revision" example:"3"`
+	Author   string `json:"author,omitempty"`
+	// Число строк и символов текста
+	Lines     int       `json:"lines"`
+	Length    int       `json:"length"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newTextRevision(revision models.TextRevision) TextRevision {
+	return TextRevision{
+		SongID:    revision.SongID,
+		Revision:  revision.Revision,
+		Author:    revision.Author,
+		Lines:     len(textdiff.Lines(revision.Text)),
+		Length:    utf8.RuneCountInString(revision.Text),
+		CreatedAt: revision.CreatedAt,
+	}
+}
+
+// TextRevisionDetails — версия текста песни вместе с текстом.
+type TextRevisionDetails struct {
+	TextRevision
+	Text string `json:"text"`
+}
+
+// TextRevisionsResponse — страница версий текста песни, начиная с последней.
+type TextRevisionsResponse struct {
+	SongID    int            `json:"song_id"`
+	Page      int            `json:"page"`
+	Limit     int            `json:"limit"`
+	Revisions []TextRevision `json:"revisions"`
+}
+
+func newTextRevisionsResponse(songID, page, limit int, revisions []models.TextRevision) TextRevisionsResponse {
+	resp := TextRevisionsResponse{SongID: songID, Page: page, Limit: limit, Revisions: make([]TextRevision, 0, len(revisions))}
+	for _, revision := range revisions {
+		resp.Revisions = append(resp.Revisions, newTextRevision(revision))
+	}
+	return resp
+}
+
+// TextDiff — разница между версиями текста песни.
+type TextDiff struct {
+	SongID int `json:"song_id"`
+	// Версия 0 — пустой текст до первой версии
+	From TextRevision `json:"from"`
+	To   TextRevision `json:"to"`
+	// Разница в формате diff -u; пустая, если тексты совпадают
+	Diff string `json:"diff"`
+}
+
+func newTextDiff(diff models.TextDiff) TextDiff {
+	return TextDiff{
+		SongID: diff.SongID,
+		From:   newTextRevision(diff.From),
+		To:     newTextRevision(diff.To),
+		Diff:   diff.Diff,
+	}
+}
+
+// RestoreRevisionRequest — тело запроса на восстановление версии текста.
+type RestoreRevisionRequest struct {
+	Author string `json:"author,omitempty" example:"editor"`
+}
+
+// Health — состояние сервиса и его зависимостей.
+type Health struct {
+	Status      string             `json:"status" enums:"ok,degraded,unavailable"`
+	Database    ComponentHealth    `json:"database"`
+	ExternalAPI *ExternalAPIHealth `json:"external_api,omitempty"`
+	// Кэш ответов внешнего API
+	MetadataCache *MetadataCacheHealth `json:"metadata_cache,omitempty"`
+}
+
+// ComponentHealth — состояние зависимости сервиса.
+type ComponentHealth struct {
+	Status string `json:"status" enums:"ok,unavailable"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExternalAPIHealth — состояние клиента внешнего API.
+type ExternalAPIHealth struct {
+	Name string `json:"name" example:"external-api"`
+	// Состояние выключателя: closed — запросы идут, open — отклоняются сразу, half-open — идёт пробный запрос
+	CircuitState string `json:"circuit_state" enums:"closed,open,half-open"`
+	// Неудачные попытки подряд
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	// Когда разомкнутый выключатель пропустит пробный запрос
+	RetryAt  *time.Time `json:"retry_at,omitempty"`
+	Requests int64      `json:"requests"`
+	Retries  int64      `json:"retries"`
+	// Неудачные попытки за всё время
+	Failures int64 `json:"failures"`
+	// Запросы, отклонённые разомкнутым выключателем
+	Rejected int64 `json:"rejected"`
+	// Попытки, которые ждали ограничителя частоты запросов
+	Throttled   int64      `json:"throttled"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+}
+
+func newExternalAPIHealth(stats httpclient.Stats) *ExternalAPIHealth {
+	optionalTime := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		return &t
+	}
+	return &ExternalAPIHealth{
+		Name:                stats.Name,
+		CircuitState:        string(stats.Breaker.State),
+		ConsecutiveFailures: stats.Breaker.Failures,
+		OpenedAt:            optionalTime(stats.Breaker.OpenedAt),
+		RetryAt:             optionalTime(stats.Breaker.RetryAt),
+		Requests:            stats.Requests,
+		Retries:             stats.Retries,
+		Failures:            stats.Failures,
+		Rejected:            stats.Rejected,
+		Throttled:           stats.Throttled,
+		LastError:           stats.LastError,
+		LastErrorAt:         optionalTime(stats.LastErrorAt),
+	}
+}
+
+// EnrichmentJob — задача очереди обогащения.
+type EnrichmentJob struct {
+	ID     int    `json:"id"`
+	SongID int    `json:"song_id"`
+	Status string `json:"status" example:"queued"`
+	// Число начатых попыток
+	Attempts int `json:"attempts" example:"1"`
+	// Не раньше какого времени задачу возьмёт обработчик
+	RunAt time.Time `json:"run_at"`
+	// Когда обработчик взял задачу; только у выполняющихся задач
+	LockedAt *time.Time `json:"locked_at,omitempty"`
+	// Ошибка последней неудачной попытки
+	LastError string    `json:"last_error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RefreshResponse — ответ на постановку устаревших песен в очередь обогащения.
+type RefreshResponse struct {
+	Queued int `json:"queued" example:"100"`
+}
+
+// RequeueFailedResponse — ответ на перезапуск неудачных задач обогащения.
+type RequeueFailedResponse struct {
+	Requeued int `json:"requeued" example:"3"`
+}
+
+func newEnrichmentJob(job models.EnrichmentJob) EnrichmentJob {
+	return EnrichmentJob{
+		ID:        job.ID,
+		SongID:    job.SongID,
+		Status:    string(job.Status),
+		Attempts:  job.Attempts,
+		RunAt:     job.RunAt,
+		LockedAt:  job.LockedAt,
+		LastError: job.LastError,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
+
+func newEnrichmentJobs(jobs []models.EnrichmentJob) []EnrichmentJob {
+	dtos := make([]EnrichmentJob, 0, len(jobs))
+	for _, job := range jobs {
+		dtos = append(dtos, newEnrichmentJob(job))
+	}
+	return dtos
+}
+
+// MetadataCacheHealth — счётчики кэша ответов внешнего API.
+type MetadataCacheHealth struct {
+	Size      int   `json:"size"`
+	Capacity  int   `json:"capacity"`
+	Evictions int64 `json:"evictions"`
+	// Хранятся ли ответы также в таблице базы данных
+	Store bool  `json:"store"`
+	Hits  int64 `json:"hits"`
+	// Попадания, найденные в таблице, а не в памяти
+	StoreHits int64 `json:"store_hits"`
+	// Попадания с ответом «песня не найдена»
+	NegativeHits int64 `json:"negative_hits"`
+	Misses       int64 `json:"misses"`
+	// Вызовы, дождавшиеся ответа на такой же запрос вместо своего
+	Shared int64 `json:"shared"`
+}
+
+func newMetadataCacheHealth(stats metadata.DetailsCacheStats) *MetadataCacheHealth {
+	return &MetadataCacheHealth{
+		Size:         stats.Size,
+		Capacity:     stats.Capacity,
+		Evictions:    stats.Evictions,
+		Store:        stats.Store,
+		Hits:         stats.Hits,
+		StoreHits:    stats.StoreHits,
+		NegativeHits: stats.NegativeHits,
+		Misses:       stats.Misses,
+		Shared:       stats.Shared,
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+)
+
+// defaultDuplicatesLimit — сколько пар каждого вида возвращать по умолчанию.
+const defaultDuplicatesLimit = 50
+
+type DuplicateHandler struct {
+	DuplicateService *services.DuplicateService
+}
+
+func NewDuplicateHandler(service *services.DuplicateService) *DuplicateHandler {
+	return &DuplicateHandler{DuplicateService: service}
+}
+
+// GetDuplicates godoc
+// @Summary Найти дубликаты
+// @Description Возвращает пары групп и песен, названия которых совпадают без учёта регистра, артикля «The» и алфавита или похожи по триграммам не меньше порога. Песни сравниваются в пределах групп с одинаковым нормализованным названием.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param threshold query number false "Минимальное сходство названий (0..1)" default(0.6)
+// @Param limit query int false "Максимум пар каждого вида" default(50)
+// @Success 200 {object} handlers.DuplicatesResponse "Кандидаты в дубликаты"
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /duplicates [get]
+func (h *DuplicateHandler) GetDuplicates(w http.ResponseWriter, r *http.Request) {
+	threshold := services.DefaultDuplicateThreshold
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		var err error
+		threshold, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "Некорректное значение threshold", http.StatusBadRequest)
+			return
+		}
+	}
+	limit := defaultDuplicatesLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Некорректное значение limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	duplicates, err := h.DuplicateService.FindDuplicates(threshold, limit)
+	if err != nil {
+		http.Error(w, "Ошибка поиска дубликатов: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newDuplicatesResponse(duplicates)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+type EnrichmentHandler struct {
+	EnrichmentService *services.EnrichmentService
+}
+
+func NewEnrichmentHandler(service *services.EnrichmentService) *EnrichmentHandler {
+	return &EnrichmentHandler{EnrichmentService: service}
+}
+
+// GetJobs godoc
+// @Summary Получить задачи обогащения
+// @Description Возвращает задачи очереди обогащения, начиная с недавно изменённых. У каждой песни не больше одной задачи; у неудачных задач в last_error — ошибка последней попытки.
+// @Tags Enrichment
+// @Produce json
+// @Param status query string false "Состояние задачи" Enums(queued, running, done, failed)
+// @Param song_id query int false "ID песни"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество задач на странице" default(10)
+// @Success 200 {array} handlers.EnrichmentJob "Задачи обогащения"
+// @Failure 400 {string} string "Некорректные параметры"
+// @Failure 500 {string} string "Ошибка получения задач"
+// @Router /enrichment/jobs [get]
+func (h *EnrichmentHandler) GetJobs(w http.ResponseWriter, r *http.Request) {
+	filter := models.EnrichmentJobFilter{Status: models.JobStatus(r.URL.Query().Get("status"))}
+	if v := r.URL.Query().Get("song_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			http.Error(w, "Некорректный song_id", http.StatusBadRequest)
+			return
+		}
+		filter.SongID = id
+	}
+	page, limit := parsePagination(r)
+
+	jobs, err := h.EnrichmentService.GetJobs(filter, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения задач обогащения: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newEnrichmentJobs(jobs)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetJob godoc
+// @Summary Получить задачу обогащения
+// @Description Возвращает задачу очереди обогащения по её ID: состояние, число попыток, время следующей попытки и последнюю ошибку.
+// @Tags Enrichment
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 200 {object} handlers.EnrichmentJob "Задача обогащения"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Задача не найдена"
+// @Failure 500 {string} string "Ошибка получения задачи"
+// @Router /enrichment/jobs/{id} [get]
+func (h *EnrichmentHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.EnrichmentService.GetJob(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения задачи обогащения: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newEnrichmentJob(job)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// RequeueJob godoc
+// @Summary Перезапустить задачу обогащения
+// @Description Ставит задачу в очередь заново: сбрасывает число попыток и последнюю ошибку, песня получает статус pending. Выполняющуюся задачу перезапустить нельзя.
+// @Tags Enrichment
+// @Produce json
+// @Param id path int true "ID задачи"
+// @Success 200 {object} handlers.EnrichmentJob "Задача поставлена в очередь"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Задача не найдена"
+// @Failure 409 {string} string "Задача выполняется"
+// @Failure 500 {string} string "Ошибка перезапуска задачи"
+// @Router /enrichment/jobs/{id}/requeue [post]
+func (h *EnrichmentHandler) RequeueJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.EnrichmentService.RequeueJob(id)
+	if err != nil {
+		http.Error(w, "Ошибка перезапуска задачи обогащения: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newEnrichmentJob(job)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// RequeueFailed godoc
+// @Summary Перезапустить неудачные задачи обогащения
+// @Description Ставит в очередь заново все задачи в состоянии failed, сбрасывая число попыток и последнюю ошибку.
+// @Tags Enrichment
+// @Produce json
+// @Success 200 {object} handlers.RequeueFailedResponse "Число перезапущенных задач"
+// @Failure 500 {string} string "Ошибка перезапуска задач"
+// @Router /enrichment/jobs/requeue [post]
+func (h *EnrichmentHandler) RequeueFailed(w http.ResponseWriter, r *http.Request) {
+	n, err := h.EnrichmentService.RequeueFailed()
+	if err != nil {
+		http.Error(w, "Ошибка перезапуска задач обогащения: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RequeueFailedResponse{Requeued: n}); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// RefreshStale godoc
+// @Summary Поставить устаревшие песни в очередь обогащения
+// @Description Делает то же, что очередной проход планировщика: ставит в очередь песни, обогащённые больше REFRESH_MAX_AGE назад, а песни с пустым текстом или ссылкой — обогащённые больше REFRESH_BLANK_AGE назад или ни разу. Полные песни, которые ни разу не обогащались (добавленные до появления очереди или вручную), не выбираются. Песни с неудачным обогащением не выбираются. Заполненные поля обновляются по политикам ENRICH_POLICY_*: keep-manual не трогает поля, введённые вручную, fill-blank только заполняет пустые, overwrite всегда перезаписывает.
+// @Tags Enrichment
+// @Produce json
+// @Param limit query int false "Сколько песен поставить; по умолчанию REFRESH_BATCH"
+// @Success 200 {object} handlers.RefreshResponse "Число поставленных песен"
+// @Failure 400 {string} string "Некорректный limit"
+// @Failure 500 {string} string "Ошибка постановки в очередь"
+// @Router /enrichment/refresh [post]
+func (h *EnrichmentHandler) RefreshStale(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit <= 0 {
+			http.Error(w, "Некорректный limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	n, err := h.EnrichmentService.RefreshStale(limit)
+	if err != nil {
+		http.Error(w, "Ошибка постановки песен в очередь обогащения: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(RefreshResponse{Queued: n}); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// EnrichSong godoc
+// @Summary Поставить песню в очередь обогащения
+// @Description Ставит песню в очередь обогащения или перезапускает её задачу; обработчики заполнят пустые дату выпуска, текст и ссылку, а заполненные обновят по политикам ENRICH_POLICY_*; при keep-manual поля, введённые или очищенные вручную, не меняются. Песню, задача которой выполняется, поставить нельзя.
+// @Tags Enrichment
+// @Produce json
+// @Param id path int true "ID песни"
+// @Success 202 {object} handlers.EnrichmentJob "Песня поставлена в очередь"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 409 {string} string "Задача песни выполняется"
+// @Failure 500 {string} string "Ошибка постановки в очередь"
+// @Router /songs/{id}/enrich [post]
+func (h *EnrichmentHandler) EnrichSong(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	job, err := h.EnrichmentService.EnqueueSong(id)
+	if err != nil {
+		http.Error(w, "Ошибка постановки песни в очередь обогащения: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(newEnrichmentJob(job))
+}
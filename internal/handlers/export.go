@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// Форматы выгрузки списков песен. JSON — обычный ответ API со страницей песен.
+const (
+	formatJSON   = "json"
+	formatM3U    = "m3u"
+	formatXSPF   = "xspf"
+	formatCSV    = "csv"
+	formatNDJSON = "ndjson"
+)
+
+// exportFormats описывает потоковые форматы: тип содержимого, расширение файла и конструктор.
+var exportFormats = map[string]struct {
+	contentType string
+	extension   string
+	newWriter   func(w io.Writer, title string) songWriter
+}{
+	formatM3U:    {"audio/x-mpegurl; charset=utf-8", "m3u8", newM3UWriter},
+	formatXSPF:   {"application/xspf+xml; charset=utf-8", "xspf", newXSPFWriter},
+	formatCSV:    {"text/csv; charset=utf-8", "csv", newCSVWriter},
+	formatNDJSON: {"application/x-ndjson", "ndjson", newNDJSONWriter},
+}
+
+// exportMediaTypes сопоставляет типы из заголовка Accept с форматами выгрузки.
+var exportMediaTypes = map[string]string{
+	"application/json":              formatJSON,
+	"audio/x-mpegurl":               formatM3U,
+	"audio/mpegurl":                 formatM3U,
+	"application/x-mpegurl":         formatM3U,
+	"application/vnd.apple.mpegurl": formatM3U,
+	"application/xspf+xml":          formatXSPF,
+	"text/csv":                      formatCSV,
+	"application/x-ndjson":          formatNDJSON,
+	"application/ndjson":            formatNDJSON,
+}
+
+// exportFormat определяет формат ответа. Параметр format важнее заголовка Accept;
+// если ни один из них не выбирает известный формат, ответ отдаётся в JSON.
+func exportFormat(r *http.Request) (string, error) {
+	if v := strings.ToLower(r.URL.Query().Get("format")); v != "" {
+		if v == "m3u8" {
+			v = formatM3U
+		}
+		if _, ok := exportFormats[v]; !ok && v != formatJSON {
+			return "", fmt.Errorf("неизвестный формат %q", v)
+		}
+		return v, nil
+	}
+
+	format, best := formatJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		f, ok := exportMediaTypes[mediaType]
+		if !ok {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q > best {
+			format, best = f, q
+		}
+	}
+	return format, nil
+}
+
+// exportPagination читает page и limit для выгрузки: если ни один из них не задан,
+// выгружается весь список (limit = -1).
+func exportPagination(r *http.Request) (page, limit int) {
+	query := r.URL.Query()
+	if !query.Has("page") && !query.Has("limit") {
+		return 1, -1
+	}
+	return parsePagination(r)
+}
+
+// songWriter записывает песни в одном из форматов выгрузки. record — объект,
+// который JSON-форматы пишут вместо песни, например элемент плейлиста с позицией.
+type songWriter interface {
+	Begin() error
+	Write(song Song, record any) error
+	End() error
+}
+
+// streamExport отдаёт список песен в потоковом формате: each читает строки из хранилища
+// и передаёт их по одной. Заголовки ответа отправляются с первой строкой, поэтому ошибка
+// до неё возвращается обычным статусом, а после неё выгрузка просто обрывается.
+func streamExport[T any](w http.ResponseWriter, format, title, errPrefix string, song func(T) Song,
+	each func(fn func(T) error) error) {
+	spec := exportFormats[format]
+	var sw songWriter
+	start := func() error {
+		w.Header().Set("Content-Type", spec.contentType)
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment",
+			map[string]string{"filename": title + "." + spec.extension}))
+		w.WriteHeader(http.StatusOK)
+		sw = spec.newWriter(w, title)
+		return sw.Begin()
+	}
+
+	err := each(func(record T) error {
+		if sw == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return sw.Write(song(record), record)
+	})
+	if err != nil && sw == nil {
+		http.Error(w, errPrefix+err.Error(), errorStatus(err))
+		return
+	}
+	if err != nil {
+		log.Errorf("Выгрузка %s прервана: %v", title, err)
+		return
+	}
+	if sw == nil {
+		if err := start(); err != nil {
+			log.Errorf("Ошибка выгрузки %s: %v", title, err)
+			return
+		}
+	}
+	if err := sw.End(); err != nil {
+		log.Errorf("Ошибка завершения выгрузки %s: %v", title, err)
+	}
+}
+
+func songOf(song Song) Song {
+	return song
+}
+
+// m3uWriter пишет расширенный M3U в UTF-8 (M3U8). Песни без ссылки пропускаются:
+// у записи плейлиста обязательно должен быть адрес.
+type m3uWriter struct {
+	w     io.Writer
+	title string
+}
+
+func newM3UWriter(w io.Writer, title string) songWriter {
+	return &m3uWriter{w: w, title: title}
+}
+
+func (m *m3uWriter) Begin() error {
+	_, err := fmt.Fprintf(m.w, "#EXTM3U\n#PLAYLIST:%s\n", m3uEscape(m.title))
+	return err
+}
+
+func (m *m3uWriter) Write(song Song, _ any) error {
+	if song.Link == "" {
+		return nil
+	}
+	_, err := fmt.Fprintf(m.w, "#EXTINF:-1,%s - %s\n%s\n", m3uEscape(song.Group), m3uEscape(song.Song), m3uEscape(song.Link))
+	return err
+}
+
+func (m *m3uWriter) End() error {
+	return nil
+}
+
+// m3uEscape убирает переводы строк, которые разорвали бы запись M3U.
+func m3uEscape(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
+
+// xspfTrack — элемент trackList формата XSPF.
+type xspfTrack struct {
+	XMLName  xml.Name `xml:"track"`
+	Location string   `xml:"location,omitempty"`
+	Title    string   `xml:"title"`
+	Creator  string   `xml:"creator"`
+	Album    string   `xml:"album,omitempty"`
+	TrackNum int      `xml:"trackNum,omitempty"`
+}
+
+// xspfWriter пишет плейлист XSPF, кодируя каждую песню отдельным элементом track.
+type xspfWriter struct {
+	w     io.Writer
+	enc   *xml.Encoder
+	title string
+}
+
+func newXSPFWriter(w io.Writer, title string) songWriter {
+	return &xspfWriter{w: w, enc: xml.NewEncoder(w), title: title}
+}
+
+func (x *xspfWriter) Begin() error {
+	if _, err := io.WriteString(x.w, xml.Header); err != nil {
+		return err
+	}
+	playlist := xml.StartElement{Name: xml.Name{Local: "playlist"}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: "version"}, Value: "1"},
+		{Name: xml.Name{Local: "xmlns"}, Value: "http://xspf.org/ns/0/"},
+	}}
+	if err := x.enc.EncodeToken(playlist); err != nil {
+		return err
+	}
+	if err := x.enc.EncodeElement(x.title, xml.StartElement{Name: xml.Name{Local: "title"}}); err != nil {
+		return err
+	}
+	return x.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "trackList"}})
+}
+
+func (x *xspfWriter) Write(song Song, _ any) error {
+	track := xspfTrack{Location: song.Link, Title: song.Song, Creator: song.Group, Album: song.Album}
+	if song.TrackNumber != nil {
+		track.TrackNum = *song.TrackNumber
+	}
+	if err := x.enc.Encode(track); err != nil {
+		return err
+	}
+	return x.enc.Flush()
+}
+
+func (x *xspfWriter) End() error {
+	if err := x.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "trackList"}}); err != nil {
+		return err
+	}
+	if err := x.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "playlist"}}); err != nil {
+		return err
+	}
+	return x.enc.Flush()
+}
+
+// csvColumns — заголовок выгрузки CSV. Жанры и теги перечисляются через точку с запятой.
+var csvColumns = []string{"id", "group", "song", "album", "track_number", "disc_number",
+	"release_date", "genres", "tags", "link", "text"}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer, _ string) songWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) Begin() error {
+	return c.w.Write(csvColumns)
+}
+
+func (c *csvWriter) Write(song Song, _ any) error {
+	c.w.Write([]string{
+		strconv.Itoa(song.ID),
+		song.Group,
+		song.Song,
+		song.Album,
+		optionalInt(song.TrackNumber),
+		optionalInt(song.DiscNumber),
+		song.ReleaseDate,
+		strings.Join(song.Genres, ";"),
+		strings.Join(song.Tags, ";"),
+		song.Link,
+		song.Text,
+	})
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) End() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func optionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// ndjsonWriter пишет по одному JSON-объекту на строку в том же представлении, что и JSON API.
+type ndjsonWriter struct {
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer, _ string) songWriter {
+	return &ndjsonWriter{enc: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) Begin() error {
+	return nil
+}
+
+func (n *ndjsonWriter) Write(_ Song, record any) error {
+	return n.enc.Encode(record)
+}
+
+func (n *ndjsonWriter) End() error {
+	return nil
+}
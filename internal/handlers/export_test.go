@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+)
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		accept  string
+		want    string
+		wantErr bool
+	}{
+		{name: "по умолчанию JSON", want: formatJSON},
+		{name: "параметр m3u8", query: "format=m3u8", want: formatM3U},
+		{name: "параметр без учёта регистра", query: "format=XSPF", want: formatXSPF},
+		{name: "параметр важнее Accept", query: "format=csv", accept: "application/x-ndjson", want: formatCSV},
+		{name: "неизвестный параметр", query: "format=pdf", wantErr: true},
+		{name: "Accept", accept: "text/csv", want: formatCSV},
+		{name: "Accept с параметрами", accept: "audio/x-mpegurl; charset=utf-8", want: formatM3U},
+		{name: "наибольший q", accept: "application/json;q=0.5, text/csv;q=0.9, application/xspf+xml;q=0.7", want: formatCSV},
+		{name: "q по умолчанию 1", accept: "text/csv;q=0.9, application/x-ndjson", want: formatNDJSON},
+		{name: "при равных q первый", accept: "application/xspf+xml, text/csv", want: formatXSPF},
+		{name: "q=0 — формат не принимается", accept: "text/csv;q=0", want: formatJSON},
+		{name: "неверный q пропускается", accept: "text/csv;q=abc, application/x-ndjson;q=0.1", want: formatNDJSON},
+		{name: "неизвестные типы", accept: "text/html, */*;q=0.8", want: formatJSON},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/songs?"+tt.query, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			got, err := exportFormat(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("exportFormat: ошибка %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("формат %q, ожидался %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// exportRecord — элемент выгрузки, отличный от песни, как элемент плейлиста с позицией.
+type exportRecord struct {
+	Position int  `json:"position"`
+	Song     Song `json:"song"`
+}
+
+// export выгружает records в формате format и возвращает ответ.
+func export(format string, records []exportRecord, failAt int, err error) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	streamExport(w, format, "Любимое", "Ошибка получения песен: ",
+		func(r exportRecord) Song { return r.Song },
+		func(fn func(exportRecord) error) error {
+			for i, record := range records {
+				if i == failAt {
+					return err
+				}
+				if err := fn(record); err != nil {
+					return err
+				}
+			}
+			if failAt == len(records) {
+				return err
+			}
+			return nil
+		})
+	return w
+}
+
+func exportSongs() []exportRecord {
+	track := 3
+	return []exportRecord{
+		{Position: 1, Song: Song{ID: 1, Group: "Muse", Song: "Hysteria", Album: "Absolution", TrackNumber: &track,
+			ReleaseDate: "2003-12-01", Genres: []string{"rock", "alternative"}, Link: "https://example.com/hysteria"}},
+		{Position: 2, Song: Song{ID: 2, Group: "Muse", Song: "Без ссылки", Text: "Куплет"}},
+		{Position: 3, Song: Song{ID: 3, Group: `AC/DC "Live"`, Song: "Rock & Roll <Train>", Text: "строка, с запятой\nи \"кавычками\"",
+			Link: "https://example.com/?a=1&b=2"}},
+	}
+}
+
+func TestStreamExportM3U(t *testing.T) {
+	w := export(formatM3U, exportSongs(), -1, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("статус %d, ожидался 200", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "audio/x-mpegurl; charset=utf-8" {
+		t.Errorf("Content-Type %q", ct)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, ".m3u8") {
+		t.Errorf("Content-Disposition %q без расширения m3u8", cd)
+	}
+	// Песня без ссылки пропускается: у записи M3U обязательно должен быть адрес
+	want := "#EXTM3U\n#PLAYLIST:Любимое\n" +
+		"#EXTINF:-1,Muse - Hysteria\nhttps://example.com/hysteria\n" +
+		"#EXTINF:-1,AC/DC \"Live\" - Rock & Roll <Train>\nhttps://example.com/?a=1&b=2\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("тело ответа:\n%s\nожидалось:\n%s", got, want)
+	}
+}
+
+func TestStreamExportXSPF(t *testing.T) {
+	w := export(formatXSPF, exportSongs(), -1, nil)
+	body := w.Body.String()
+	if !strings.HasPrefix(body, xml.Header) {
+		t.Errorf("ответ без заголовка XML: %.60q", body)
+	}
+	for _, escaped := range []string{"Rock &amp; Roll &lt;Train&gt;", "AC/DC &#34;Live&#34;", "?a=1&amp;b=2"} {
+		if !strings.Contains(body, escaped) {
+			t.Errorf("в ответе нет экранированного %q:\n%s", escaped, body)
+		}
+	}
+
+	var playlist struct {
+		Title  string `xml:"title"`
+		Tracks []struct {
+			Location string `xml:"location"`
+			Title    string `xml:"title"`
+			Creator  string `xml:"creator"`
+			Album    string `xml:"album"`
+			TrackNum int    `xml:"trackNum"`
+		} `xml:"trackList>track"`
+	}
+	if err := xml.Unmarshal(w.Body.Bytes(), &playlist); err != nil {
+		t.Fatalf("ответ не разбирается как XML: %v", err)
+	}
+	if playlist.Title != "Любимое" || len(playlist.Tracks) != 3 {
+		t.Fatalf("плейлист %+v", playlist)
+	}
+	if tr := playlist.Tracks[0]; tr.Album != "Absolution" || tr.TrackNum != 3 || tr.Location != "https://example.com/hysteria" {
+		t.Errorf("первый трек %+v", tr)
+	}
+	if tr := playlist.Tracks[2]; tr.Title != "Rock & Roll <Train>" || tr.Creator != `AC/DC "Live"` || tr.Location != "https://example.com/?a=1&b=2" {
+		t.Errorf("трек со спецсимволами %+v", tr)
+	}
+}
+
+func TestStreamExportCSV(t *testing.T) {
+	w := export(formatCSV, exportSongs(), -1, nil)
+	rows, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("ответ не разбирается как CSV: %v", err)
+	}
+	if len(rows) != 4 || !reflect.DeepEqual(rows[0], csvColumns) {
+		t.Fatalf("строки CSV %q", rows)
+	}
+	want := []string{"1", "Muse", "Hysteria", "Absolution", "3", "", "2003-12-01", "rock;alternative", "", "https://example.com/hysteria", ""}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("строка песни %q, ожидалась %q", rows[1], want)
+	}
+	if got := rows[3][len(csvColumns)-1]; got != "строка, с запятой\nи \"кавычками\"" {
+		t.Errorf("текст песни после разбора CSV %q", got)
+	}
+}
+
+func TestStreamExportNDJSON(t *testing.T) {
+	records := exportSongs()
+	w := export(formatNDJSON, records, -1, nil)
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != len(records) {
+		t.Fatalf("строк %d, ожидалось %d:\n%s", len(lines), len(records), w.Body)
+	}
+	for i, line := range lines {
+		// Пишется сам элемент выгрузки, а не только песня
+		var got exportRecord
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("строка %d не JSON: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(got, records[i]) {
+			t.Errorf("строка %d = %+v, ожидалась %+v", i+1, got, records[i])
+		}
+	}
+}
+
+func TestStreamExportEmpty(t *testing.T) {
+	w := export(formatM3U, nil, -1, nil)
+	if w.Code != http.StatusOK || w.Body.String() != "#EXTM3U\n#PLAYLIST:Любимое\n" {
+		t.Errorf("пустая выгрузка: статус %d, тело %q", w.Code, w.Body)
+	}
+}
+
+func TestStreamExportErrors(t *testing.T) {
+	notFound := fmt.Errorf("%w: id 7", services.ErrPlaylistNotFound)
+
+	// Ошибка до первой строки — обычный ответ с кодом ошибки
+	w := export(formatCSV, exportSongs(), 0, notFound)
+	if w.Code != http.StatusNotFound || !strings.HasPrefix(w.Body.String(), "Ошибка получения песен: ") {
+		t.Errorf("ошибка до первой строки: статус %d, тело %q", w.Code, w.Body)
+	}
+
+	// После первой строки заголовки уже отправлены: выгрузка просто обрывается
+	w = export(formatNDJSON, exportSongs(), 1, errors.New("соединение с базой потеряно"))
+	if w.Code != http.StatusOK || strings.Count(w.Body.String(), "\n") != 1 {
+		t.Errorf("ошибка после первой строки: статус %d, тело %q", w.Code, w.Body)
+	}
+}
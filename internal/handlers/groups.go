@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+type GroupHandler struct {
+	GroupService *services.GroupService
+}
+
+func NewGroupHandler(service *services.GroupService) *GroupHandler {
+	return &GroupHandler{GroupService: service}
+}
+
+// GetGroups godoc
+// @Summary Получить список групп
+// @Description Возвращает группы с поиском по названию, фильтром по стране и поддержкой пагинации.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param q query string false "Поиск по названию группы"
+// @Param country query string false "Страна"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Group "Список групп"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /groups [get]
+func (h *GroupHandler) GetGroups(w http.ResponseWriter, r *http.Request) {
+	filter := models.GroupFilter{
+		Name:    r.URL.Query().Get("q"),
+		Country: r.URL.Query().Get("country"),
+	}
+	page, limit := parsePagination(r)
+
+	groups, err := h.GroupService.GetGroups(filter, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения групп: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newGroups(groups)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetGroup godoc
+// @Summary Получить группу
+// @Description Возвращает группу по ID вместе с числом её песен и прежними названиями слитых в неё дубликатов.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Success 200 {object} handlers.Group "Группа"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /groups/{id} [get]
+func (h *GroupHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.GroupService.GetGroup(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения группы: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newGroup(group))
+}
+
+// CreateGroup godoc
+// @Summary Создать группу
+// @Description Добавляет группу в библиотеку. Название группы должно быть уникальным.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param input body CreateGroupRequest true "Данные группы"
+// @Success 201 {object} handlers.Group "Созданная группа"
+// @Failure 400 {string} string "Некорректные входные данные"
+// @Failure 409 {string} string "Группа уже существует"
+// @Failure 500 {string} string "Ошибка создания группы"
+// @Router /groups [post]
+func (h *GroupHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
+	var input CreateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректные входные данные", http.StatusBadRequest)
+		return
+	}
+
+	group := models.Group{
+		Name:       input.Name,
+		Country:    input.Country,
+		FormedYear: input.FormedYear,
+		Bio:        input.Bio,
+	}
+	created, err := h.GroupService.CreateGroup(group)
+	if err != nil {
+		http.Error(w, "Ошибка создания группы: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newGroup(created))
+}
+
+// UpdateGroup godoc
+// @Summary Обновить группу
+// @Description Обновляет переданные поля группы, в том числе переименовывает её.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param input body UpdateGroupRequest true "Обновляемые данные группы"
+// @Success 200 {string} string "Группа успешно обновлена"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 409 {string} string "Группа с таким названием уже существует"
+// @Failure 500 {string} string "Ошибка обновления группы"
+// @Router /groups/{id} [put]
+func (h *GroupHandler) UpdateGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input UpdateGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	update := models.GroupUpdate{
+		Name:       input.Name,
+		Country:    input.Country,
+		FormedYear: input.FormedYear,
+		Bio:        input.Bio,
+	}
+	if err := h.GroupService.UpdateGroup(id, update); err != nil {
+		http.Error(w, "Ошибка обновления группы: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Группа успешно обновлена"))
+}
+
+// DeleteGroup godoc
+// @Summary Удалить группу
+// @Description Удаляет группу по ID. Группа с песнями или альбомами удаляется только при cascade=true вместе с ними, иначе возвращается 409.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы"
+// @Param cascade query bool false "Удалить песни и альбомы группы" default(false)
+// @Success 204 {string} string "Группа успешно удалена"
+// @Failure 400 {string} string "Некорректный ID или параметр cascade"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 409 {string} string "У группы есть песни или альбомы"
+// @Failure 500 {string} string "Ошибка удаления группы"
+// @Router /groups/{id} [delete]
+func (h *GroupHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var cascade bool
+	if v := r.URL.Query().Get("cascade"); v != "" {
+		cascade, err = strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "Некорректное значение cascade", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if err := h.GroupService.DeleteGroup(id, cascade); err != nil {
+		http.Error(w, "Ошибка удаления группы: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MergeGroup godoc
+// @Summary Слить группу-дубликат
+// @Description Переносит песни и альбомы группы в target_id, сохраняет её название псевдонимом целевой группы (псевдоним с таким названием у другой группы переходит к целевой) и удаляет её.
+// @Tags Groups
+// @Accept json
+// @Produce json
+// @Param id path int true "ID группы-дубликата"
+// @Param input body MergeGroupRequest true "Целевая группа"
+// @Success 200 {object} handlers.Group "Группа после слияния"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Группа не найдена"
+// @Failure 500 {string} string "Ошибка слияния групп"
+// @Router /groups/{id}/merge [post]
+func (h *GroupHandler) MergeGroup(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input MergeGroupRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	group, err := h.GroupService.MergeGroups(id, input.TargetID)
+	if err != nil {
+		http.Error(w, "Ошибка слияния групп: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newGroup(group))
+}
@@ -5,23 +5,14 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/EugeneKrivoshein/music_library/config"
 	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
 	"github.com/EugeneKrivoshein/music_library/internal/services"
 	"github.com/gorilla/mux"
 )
 
-type Song struct {
-	ID          int    `json:"id"`
-	GroupName   string `json:"group_name"`
-	SongName    string `json:"song_name"`
-	ReleaseDate string `json:"release_date"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
-}
-
 type SongHandler struct {
 	SongService *services.SongService
 	dbProvider  *conn.PostgresProvider
@@ -38,12 +29,19 @@ func NewSongHandler(provider *conn.PostgresProvider, service *services.SongServi
 
 // GetSongs godoc
 // @Summary Получить список песен
-// @Description Возвращает список песен с фильтрацией по группе и названию песни, а также поддержкой пагинации.
+// @Description Возвращает список песен с фильтрацией по группе, названию песни, жанрам и тегам, а также поддержкой пагинации. При fuzzy=true поиск ведётся по сходству триграмм с учётом опечаток и транслитерации, результаты упорядочены по score. Параметр format или заголовок Accept переключают ответ на потоковую выгрузку M3U8 (песни со ссылкой), XSPF, CSV или NDJSON; без page и limit выгружается весь список.
 // @Tags Songs
 // @Accept json
-// @Produce json
+// @Produce json,audio/x-mpegurl,application/xspf+xml,text/csv,application/x-ndjson
 // @Param group query string false "Название группы" default()
 // @Param song query string false "Название песни" default()
+// @Param album_id query int false "ID альбома"
+// @Param fuzzy query bool false "Нечёткий поиск по сходству" default(false)
+// @Param min_score query number false "Минимальное сходство для нечёткого поиска (0..1)" default(0.25)
+// @Param genre query string false "Жанр; параметр можно повторять или перечислять жанры через запятую"
+// @Param tag query string false "Тег; параметр можно повторять или перечислять теги через запятую"
+// @Param match query string false "Песня должна иметь хотя бы один (any) или все (all) перечисленные жанры и теги" Enums(any, all) default(any)
+// @Param format query string false "Формат ответа; важнее заголовка Accept" Enums(json, m3u8, xspf, csv, ndjson)
 // @Param page query int false "Номер страницы" default(1)
 // @Param limit query int false "Количество элементов на странице" default(10)
 // @Success 200 {array} handlers.Song "Список песен"
@@ -52,67 +50,119 @@ func NewSongHandler(provider *conn.PostgresProvider, service *services.SongServi
 // @Router /songs [get]
 func (h *SongHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
 	// Извлекаем параметры из запроса
-	group := r.URL.Query().Get("group")
-	song := r.URL.Query().Get("song")
-	pageStr := r.URL.Query().Get("page")
-	limitStr := r.URL.Query().Get("limit")
-
-	// Преобразуем параметры страницы и лимита
-	page, err := strconv.Atoi(pageStr)
-	if err != nil || page <= 0 {
-		page = 1 // если страница некорректна, то начинаем с первой
+	filter := models.SongFilter{
+		Group: r.URL.Query().Get("group"),
+		Song:  r.URL.Query().Get("song"),
 	}
-
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 10 // если лимит некорректен, то устанавливаем значение по умолчанию
+	if v := r.URL.Query().Get("album_id"); v != "" {
+		albumID, err := strconv.Atoi(v)
+		if err != nil || albumID <= 0 {
+			http.Error(w, "Некорректное значение album_id", http.StatusBadRequest)
+			return
+		}
+		filter.AlbumID = &albumID
 	}
+	if v := r.URL.Query().Get("fuzzy"); v != "" {
+		fuzzy, err := strconv.ParseBool(v)
+		if err != nil {
+			http.Error(w, "Некорректное значение fuzzy", http.StatusBadRequest)
+			return
+		}
+		filter.Fuzzy = fuzzy
+	}
+	if v := r.URL.Query().Get("min_score"); v != "" {
+		minScore, err := strconv.ParseFloat(v, 64)
+		if err != nil || minScore < 0 || minScore > 1 {
+			http.Error(w, "Некорректное значение min_score", http.StatusBadRequest)
+			return
+		}
+		filter.MinScore = minScore
+	}
+	filter.Genres = queryList(r, "genre")
+	filter.Tags = queryList(r, "tag")
+	switch r.URL.Query().Get("match") {
+	case "", "any":
+	case "all":
+		filter.MatchAll = true
+	default:
+		http.Error(w, "Некорректное значение match", http.StatusBadRequest)
+		return
+	}
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, "Некорректное значение format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != formatJSON {
+		page, limit := exportPagination(r)
+		streamExport(w, format, "songs", "Ошибка получения песен: ", songOf, func(fn func(Song) error) error {
+			return h.SongService.ExportSongs(filter, page, limit, func(song models.Song) error {
+				return fn(newSong(song))
+			})
+		})
+		return
+	}
+	page, limit := parsePagination(r)
 
 	// Получаем список песен через сервис
-	songs, err := h.SongService.GetSongs(group, song, page, limit)
+	songs, err := h.SongService.GetSongs(filter, page, limit)
 	if err != nil {
-		http.Error(w, "Ошибка получения песен: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Ошибка получения песен: "+err.Error(), errorStatus(err))
 		return
 	}
 
 	// Преобразуем данные в нужный формат
-	var response []map[string]interface{}
-	for _, songData := range songs {
-		// Извлекаем нужные поля из карты
-		text, ok := songData["text"].(string) // здесь мы предполагаем, что поле text будет в карте
-		if !ok {
-			text = "" // если текст не найден, оставляем пустым
-		}
+	response := newSongs(songs)
 
-		// Разделяем текст песни на куплеты, если текст существует
-		verses := []string{}
-		if text != "" {
-			verses = strings.Split(text, "\n")
-		}
-
-		// Пагинация по куплетам: определяем куплеты для текущей страницы
-		startIndex := (page - 1) * limit
-		endIndex := startIndex + limit
+	// Отправляем JSON-ответ
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
 
-		// Проверяем, что индексы не выходят за пределы массива куплетов
-		if startIndex > len(verses) {
-			verses = []string{} // Если страницы не существует, возвращаем пустой список
-		} else if endIndex > len(verses) {
-			verses = verses[startIndex:] // Если конец выходит за пределы, берем только оставшиеся куплеты
-		} else {
-			verses = verses[startIndex:endIndex]
-		}
+// searchLanguages сопоставляет параметр lang с конфигурацией полнотекстового поиска.
+var searchLanguages = map[string]string{
+	"":   models.SearchLanguageAny,
+	"ru": models.SearchLanguageRussian,
+	"en": models.SearchLanguageEnglish,
+}
 
-		// Обновляем текст песни для текущей страницы (по куплетам)
-		songData["text"] = strings.Join(verses, "\n")
+// SearchSongs godoc
+// @Summary Полнотекстовый поиск песен
+// @Description Ищет по текстам, названиям песен и групп с учётом русской и английской морфологии. Результаты упорядочены по релевантности, совпадения в фрагменте выделены тегом <b>.
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param q query string true "Поисковый запрос (поддерживает синтаксис websearch: кавычки, or, -)"
+// @Param lang query string false "Морфология: ru, en или пусто для обеих" Enums(ru, en)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.SearchResult "Найденные песни"
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /songs/search [get]
+func (h *SongHandler) SearchSongs(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		http.Error(w, "Параметр q обязателен", http.StatusBadRequest)
+		return
+	}
+	language, ok := searchLanguages[r.URL.Query().Get("lang")]
+	if !ok {
+		http.Error(w, "Некорректное значение lang", http.StatusBadRequest)
+		return
+	}
+	page, limit := parsePagination(r)
 
-		// Добавляем песню в ответ
-		response = append(response, songData)
+	results, err := h.SongService.SearchSongs(models.SearchQuery{Query: q, Language: language}, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка поиска: "+err.Error(), errorStatus(err))
+		return
 	}
 
-	// Отправляем JSON-ответ
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := json.NewEncoder(w).Encode(newSearchResults(results)); err != nil {
 		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -126,6 +176,7 @@ func (h *SongHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID песни"
 // @Success 200 {string} string "Текст песни"
 // @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня не найдена"
 // @Failure 500 {string} string "Ошибка получения текста песни"
 // @Router /songs/{id} [get]
 func (h *SongHandler) GetSongText(w http.ResponseWriter, r *http.Request) {
@@ -138,7 +189,7 @@ func (h *SongHandler) GetSongText(w http.ResponseWriter, r *http.Request) {
 
 	text, err := h.SongService.GetSongText(id)
 	if err != nil {
-		http.Error(w, "Ошибка получения текста песни: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Ошибка получения текста песни: "+err.Error(), errorStatus(err))
 		return
 	}
 
@@ -146,46 +197,90 @@ func (h *SongHandler) GetSongText(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(text)
 }
 
-// AddSongWithAPI добавляет песню через внешнее API.
+// GetSongVerses godoc
+// @Summary Получить куплеты песни
+// @Description Делит текст песни на куплеты по пустым строкам и возвращает их постранично.
+// @Tags Songs
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество куплетов на странице" default(10)
+// @Success 200 {object} handlers.VersesResponse "Куплеты песни"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Ошибка получения куплетов"
+// @Router /songs/{id}/verses [get]
+func (h *SongHandler) GetSongVerses(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	page, limit := parsePagination(r)
+
+	verses, err := h.SongService.GetSongVerses(id, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения куплетов: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newVersesResponse(verses)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AddSongWithAPI добавляет песню и ставит её в очередь обогащения.
 // @Summary Добавить песню через API
-// @Description Добавляет новую песню, используя данные внешнего API.
+// @Description Сразу добавляет песню со статусом обогащения pending и ставит её в очередь. Обработчики очереди запрашивают дату выпуска, текст и ссылку у первого источника, который знает песню; источники и их порядок задаёт METADATA_PROVIDERS: внешний API (api), каталог файлов JSON/YAML (fixtures) и ручное заполнение (manual). Неудачные попытки повторяются с нарастающей задержкой, итог виден в enrichment_status песни и в GET /enrichment/jobs. Группа ищется по названию без учёта регистра, артикля «The» и алфавита, а также по прежним названиям. Неизвестная группа создаётся только при create_group=true, иначе возвращается 422 с похожими названиями.
 // @Tags Songs
 // @Accept json
 // @Produce json
-// @Param input body Song true "Данные песни"
-// @Success 201 {string} string "Песня успешно добавлена"
+// @Param input body AddSongRequest true "Данные песни"
+// @Success 201 {object} handlers.AddSongResponse "Песня добавлена и поставлена в очередь обогащения"
 // @Failure 400 {string} string "Некорректные входные данные"
+// @Failure 404 {string} string "Альбом не найден"
+// @Failure 422 {string} string "Группа не найдена"
 // @Failure 500 {string} string "Ошибка добавления песни"
 // @Router /songs/add [post]
 func (h *SongHandler) AddSongWithAPI(w http.ResponseWriter, r *http.Request) {
-	var input struct {
-		Group string `json:"group"`
-		Song  string `json:"song"`
-	}
+	var input AddSongRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil || input.Group == "" || input.Song == "" {
 		http.Error(w, "Некорректные входные данные", http.StatusBadRequest)
 		return
 	}
+	track := models.AlbumTrack{AlbumID: input.AlbumID, TrackNumber: input.TrackNumber, DiscNumber: input.DiscNumber}
+	if !validTrack(track) {
+		http.Error(w, "Номер трека и диска должны быть положительными", http.StatusBadRequest)
+		return
+	}
 
-	if err := h.SongService.AddSongWithAPI(h.Config, input.Group, input.Song); err != nil {
-		http.Error(w, "Ошибка добавления песни: "+err.Error(), http.StatusInternalServerError)
+	opts := services.AddSongOptions{Track: track, CreateGroup: input.CreateGroup}
+	id, err := h.SongService.AddSongWithAPI(input.Group, input.Song, opts)
+	if err != nil {
+		http.Error(w, "Ошибка добавления песни: "+err.Error(), errorStatus(err))
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte("Песня успешно добавлена"))
+	json.NewEncoder(w).Encode(AddSongResponse{ID: id, EnrichmentStatus: string(models.EnrichmentPending)})
 }
 
 // UpdateSong обновляет данные песни.
 // @Summary Обновить песню
-// @Description Обновляет данные песни по её ID.
+// @Description Обновляет данные песни по её ID. Изменённый текст сохраняется новой версией в истории текста вместе с автором правки. Изменённые дата выпуска, текст и ссылка отмечаются как введённые вручную, и повторное обогащение с политикой keep-manual их не перезаписывает.
 // @Tags Songs
 // @Accept json
 // @Produce json
 // @Param id path int true "ID песни"
-// @Param input body Song true "Обновляемые данные песни"
+// @Param input body UpdateSongRequest true "Обновляемые данные песни"
 // @Success 200 {string} string "Песня успешно обновлена"
 // @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Песня или альбом не найдены"
+// @Failure 422 {string} string "Группа не найдена"
 // @Failure 500 {string} string "Ошибка обновления песни"
 // @Router /songs/{id} [put]
 func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
@@ -196,13 +291,7 @@ func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var input struct {
-		Group       string  `json:"group,omitempty"`
-		Song        string  `json:"song,omitempty"`
-		ReleaseDate *string `json:"release_date,omitempty"` // Формат даты: "YYYY-MM-DD"
-		Text        *string `json:"text,omitempty"`
-		Link        *string `json:"link,omitempty"`
-	}
+	var input UpdateSongRequest
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
 		return
@@ -219,15 +308,28 @@ func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Проверяем правильность формата даты, если она передана
-	if input.ReleaseDate != nil {
-		if _, err := time.Parse("2006-01-02", *input.ReleaseDate); err != nil {
-			http.Error(w, "Некорректный формат даты", http.StatusBadRequest)
-			return
-		}
+	releaseDate, err := parseDate(input.ReleaseDate)
+	if err != nil {
+		http.Error(w, "Некорректный формат даты", http.StatusBadRequest)
+		return
+	}
+	if !validTrack(models.AlbumTrack{AlbumID: input.AlbumID, TrackNumber: input.TrackNumber, DiscNumber: input.DiscNumber}) {
+		http.Error(w, "Номер трека и диска должны быть положительными", http.StatusBadRequest)
+		return
 	}
 
-	if err := h.SongService.UpdateSong(id, input.Group, input.Song, input.ReleaseDate, input.Text, input.Link); err != nil {
-		http.Error(w, "Ошибка обновления песни: "+err.Error(), http.StatusInternalServerError)
+	update := models.SongUpdate{
+		SongName:    &input.Song,
+		ReleaseDate: releaseDate,
+		Text:        input.Text,
+		Link:        input.Link,
+		AlbumID:     input.AlbumID,
+		TrackNumber: input.TrackNumber,
+		DiscNumber:  input.DiscNumber,
+		Author:      input.Author,
+	}
+	if err := h.SongService.UpdateSong(id, input.Group, input.CreateGroup, update); err != nil {
+		http.Error(w, "Ошибка обновления песни: "+err.Error(), errorStatus(err))
 		return
 	}
 
@@ -244,6 +346,7 @@ func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "ID песни"
 // @Success 204 {string} string "Песня успешно удалена"
 // @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня не найдена"
 // @Failure 500 {string} string "Ошибка удаления песни"
 // @Router /songs/{id} [delete]
 func (h *SongHandler) DeleteSong(w http.ResponseWriter, r *http.Request) {
@@ -255,7 +358,7 @@ func (h *SongHandler) DeleteSong(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.SongService.DeleteSong(id); err != nil {
-		http.Error(w, "Ошибка удаления песни: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Ошибка удаления песни: "+err.Error(), errorStatus(err))
 		return
 	}
 
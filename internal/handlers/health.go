@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/httpclient"
+	"github.com/EugeneKrivoshein/music_library/internal/metadata"
+	"github.com/EugeneKrivoshein/music_library/internal/utils"
+)
+
+// healthPingTimeout ограничивает проверку базы данных, чтобы страница состояния не зависала.
+const healthPingTimeout = 2 * time.Second
+
+// Состояния сервиса на странице состояния.
+const (
+	healthOK          = "ok"
+	healthDegraded    = "degraded"
+	healthUnavailable = "unavailable"
+)
+
+type HealthHandler struct {
+	dbProvider *conn.PostgresProvider
+	api        *utils.APIClient
+	cache      *metadata.DetailsCache
+}
+
+func NewHealthHandler(provider *conn.PostgresProvider, api *utils.APIClient, cache *metadata.DetailsCache) *HealthHandler {
+	return &HealthHandler{dbProvider: provider, api: api, cache: cache}
+}
+
+// GetHealth godoc
+// @Summary Состояние сервиса
+// @Description Проверяет базу данных и показывает состояние клиента внешнего API: выключатель (closed, open, half-open), счётчики запросов, повторов и отказов, последнюю ошибку, а также счётчики кэша его ответов (попадания, промахи, объединённые запросы). Без базы данных сервис недоступен (503); если выключатель внешнего API не замкнут, сервис работает с ограничениями (degraded), но отвечает 200.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} handlers.Health "Сервис работает"
+// @Failure 503 {object} handlers.Health "База данных недоступна"
+// @Router /health [get]
+func (h *HealthHandler) GetHealth(w http.ResponseWriter, r *http.Request) {
+	health := Health{Status: healthOK, Database: ComponentHealth{Status: healthOK}}
+	if h.dbProvider != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), healthPingTimeout)
+		defer cancel()
+		if err := h.dbProvider.DB().PingContext(ctx); err != nil {
+			health.Database = ComponentHealth{Status: healthUnavailable, Error: err.Error()}
+			health.Status = healthUnavailable
+		}
+	}
+	if h.api != nil {
+		stats := h.api.Stats()
+		health.ExternalAPI = newExternalAPIHealth(stats)
+		if stats.Breaker.State != httpclient.StateClosed && health.Status == healthOK {
+			health.Status = healthDegraded
+		}
+	}
+	if h.cache != nil {
+		health.MetadataCache = newMetadataCacheHealth(h.cache.Stats())
+	}
+
+	status := http.StatusOK
+	if health.Status == healthUnavailable {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(health)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/httpclient"
+	"github.com/EugeneKrivoshein/music_library/internal/metadata"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+)
+
+// parsePagination читает параметры page и limit, подставляя значения по умолчанию.
+func parsePagination(r *http.Request) (page, limit int) {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1 // если страница некорректна, то начинаем с первой
+	}
+
+	limit, err = strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10 // если лимит некорректен, то устанавливаем значение по умолчанию
+	}
+	return page, limit
+}
+
+// queryList собирает значения параметра, заданного несколько раз или через запятую.
+func queryList(r *http.Request, key string) []string {
+	var values []string
+	for _, v := range r.URL.Query()[key] {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+	}
+	return values
+}
+
+// errorStatus подбирает HTTP-статус для ошибки сервисного слоя.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrSongNotFound),
+		errors.Is(err, services.ErrGroupNotFound),
+		errors.Is(err, services.ErrAlbumNotFound),
+		errors.Is(err, services.ErrTagNotAttached),
+		errors.Is(err, services.ErrPlaylistNotFound),
+		errors.Is(err, services.ErrPlaylistItemNotFound),
+		errors.Is(err, services.ErrSmartPlaylistNotFound),
+		errors.Is(err, services.ErrAudioNotFound),
+		errors.Is(err, services.ErrCoverNotFound),
+		errors.Is(err, services.ErrLyricsNotFound),
+		errors.Is(err, services.ErrRevisionNotFound),
+		errors.Is(err, services.ErrJobNotFound),
+		errors.Is(err, metadata.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrGroupExists),
+		errors.Is(err, services.ErrGroupNotEmpty),
+		errors.Is(err, services.ErrJobRunning):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrUnknownGroup):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, httpclient.ErrCircuitOpen):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parseDate разбирает необязательную дату в формате YYYY-MM-DD.
+func parseDate(value *string) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	date, err := time.Parse(models.DateLayout, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// validTrack проверяет, что номера трека и диска, если заданы, положительны.
+func validTrack(track models.AlbumTrack) bool {
+	return (track.TrackNumber == nil || *track.TrackNumber > 0) &&
+		(track.DiscNumber == nil || *track.DiscNumber > 0)
+}
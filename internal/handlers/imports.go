@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/importer"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+)
+
+const (
+	// maxImportSize ограничивает размер загружаемого файла.
+	maxImportSize = 64 << 20
+	// importMemory — сколько файла держать в памяти, остальное multipart сохраняет во временный файл.
+	importMemory = 8 << 20
+)
+
+// ImportSongs godoc
+// @Summary Импортировать песни из файла
+// @Description Добавляет песни из CSV (колонки group, song и необязательные release_date, text, link, genres, tags), JSON-массива или NDJSON пакетами в транзакциях. Песни, которые уже есть у группы, пропускаются. Возвращает отчёт по каждой записи.
+// @Tags Songs
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Файл с песнями"
+// @Param format formData string false "Формат файла; по умолчанию определяется по расширению" Enums(csv, json, ndjson)
+// @Param create_groups formData bool false "Создавать группы, которых нет в библиотеке" default(true)
+// @Param enrich formData bool false "Поставить песни с пустыми датой, текстом или ссылкой в очередь обогащения" default(false)
+// @Param batch_size formData int false "Число песен в одной транзакции" default(100)
+// @Success 200 {object} handlers.ImportReport "Отчёт об импорте"
+// @Failure 400 {string} string "Некорректный файл или параметры"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Failure 500 {string} string "Ошибка импорта"
+// @Router /songs/import [post]
+func (h *SongHandler) ImportSongs(w http.ResponseWriter, r *http.Request) {
+	file, header, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	format := r.FormValue("format")
+	if format == "" {
+		format = importer.DetectFormat(header.Filename, header.Header.Get("Content-Type"))
+	}
+	if format == "" {
+		http.Error(w, "Не удалось определить формат файла, укажите format", http.StatusBadRequest)
+		return
+	}
+	var opts services.ImportOptions
+	var err error
+	if opts.CreateGroups, err = formBool(r, "create_groups", true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if opts.Enrich, err = formBool(r, "enrich", false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if v := r.FormValue("batch_size"); v != "" {
+		if opts.BatchSize, err = strconv.Atoi(v); err != nil {
+			http.Error(w, "Некорректное значение batch_size", http.StatusBadRequest)
+			return
+		}
+	}
+
+	rows, err := importer.Read(file, format)
+	if err != nil {
+		http.Error(w, "Ошибка разбора файла: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	report, err := h.SongService.ImportSongs(rows, opts)
+	if err != nil {
+		http.Error(w, "Ошибка импорта: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newImportReport(report)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// ImportPlaylist godoc
+// @Summary Импортировать песни из плейлиста
+// @Description Разбирает плейлист M3U/M3U8 (строки #EXTINF:длительность,Исполнитель - Название или имена файлов) или XSPF и добавляет песни по одной, как POST /songs/add: сведения о них заполнит очередь обогащения. При dry_run=true только показывает, какие песни и группы будут добавлены.
+// @Tags Songs
+// @Accept mpfd
+// @Produce json
+// @Param file formData file true "Плейлист"
+// @Param format formData string false "Формат плейлиста; по умолчанию определяется по расширению" Enums(m3u, xspf)
+// @Param create_groups formData bool false "Создавать группы, которых нет в библиотеке" default(true)
+// @Param dry_run formData bool false "Пробный импорт без сохранения" default(false)
+// @Success 200 {object} handlers.ImportReport "Отчёт об импорте"
+// @Failure 400 {string} string "Некорректный файл или параметры"
+// @Failure 413 {string} string "Файл слишком большой"
+// @Router /songs/import/playlist [post]
+func (h *SongHandler) ImportPlaylist(w http.ResponseWriter, r *http.Request) {
+	file, header, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+	defer file.Close()
+
+	format := r.FormValue("format")
+	if format == "" {
+		format = importer.DetectPlaylistFormat(header.Filename, header.Header.Get("Content-Type"))
+	}
+	if format == "" {
+		http.Error(w, "Не удалось определить формат плейлиста, укажите format", http.StatusBadRequest)
+		return
+	}
+	var opts services.PlaylistImportOptions
+	var err error
+	if opts.CreateGroups, err = formBool(r, "create_groups", true); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if opts.DryRun, err = formBool(r, "dry_run", false); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows, err := importer.ReadPlaylist(file, format)
+	if err != nil {
+		http.Error(w, "Ошибка разбора плейлиста: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	report := h.SongService.ImportPlaylist(rows, opts)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newImportReport(report)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// uploadedFile разбирает multipart-форму размером до maxImportSize и возвращает файл из поля file.
+// При ошибке сам отвечает клиенту и возвращает ok = false.
+func uploadedFile(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, bool) {
+	if !parseUploadForm(w, r, maxImportSize) {
+		return nil, nil, false
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		r.MultipartForm.RemoveAll()
+		http.Error(w, "Не передан файл", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return file, header, true
+}
+
+// parseUploadForm разбирает multipart-форму размером до limit байт.
+// При ошибке сам отвечает клиенту и возвращает false.
+func parseUploadForm(w http.ResponseWriter, r *http.Request, limit int64) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if err := r.ParseMultipartForm(importMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Файл слишком большой", http.StatusRequestEntityTooLarge)
+			return false
+		}
+		http.Error(w, "Некорректная форма: "+err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// formBool читает логическое поле формы, подставляя def, если поле не передано.
+func formBool(r *http.Request, name string, def bool) (bool, error) {
+	v := r.FormValue(name)
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("некорректное значение %s", name)
+	}
+	return b, nil
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/lrc"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+// maxLyricsSize ограничивает размер загружаемого текста LRC.
+const maxLyricsSize = 1 << 20
+
+// Форматы ответа с синхронизированным текстом.
+const (
+	lyricsFormatLRC   = "lrc"
+	lyricsFormatJSON  = "json"
+	lyricsFormatPlain = "plain"
+)
+
+type LyricsHandler struct {
+	LyricsService *services.LyricsService
+}
+
+func NewLyricsHandler(service *services.LyricsService) *LyricsHandler {
+	return &LyricsHandler{LyricsService: service}
+}
+
+// GetSongLyrics godoc
+// @Summary Получить синхронизированный текст песни
+// @Description Возвращает текст LRC как загружен (format=lrc), массив строк с временем в миллисекундах (format=json) или текст без отметок времени (format=plain). С параметром at возвращает только строку, которая звучит в этот момент: в json — объект с номером строки и временем следующей, в lrc — строку с отметкой, в plain — сам текст строки; до первой строки номер равен -1, а в plain и lrc ответ пустой.
+// @Tags Lyrics
+// @Produce json,plain
+// @Param id path int true "ID песни"
+// @Param format query string false "Формат ответа" Enums(lrc, json, plain) default(json)
+// @Param at query string false "Момент воспроизведения: миллисекунды или длительность вида 1m23.5s"
+// @Success 200 {array} handlers.LyricsLine "Строки текста; с at — handlers.CurrentLyricsLine"
+// @Failure 400 {string} string "Некорректный ID, формат или момент"
+// @Failure 404 {string} string "Песня или синхронизированный текст не найдены"
+// @Failure 500 {string} string "Ошибка получения текста"
+// @Router /songs/{id}/lyrics [get]
+func (h *LyricsHandler) GetSongLyrics(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = lyricsFormatJSON
+	case lyricsFormatLRC, lyricsFormatJSON, lyricsFormatPlain:
+	default:
+		http.Error(w, "Некорректный формат: ожидается lrc, json или plain", http.StatusBadRequest)
+		return
+	}
+	var position *time.Duration
+	if value := r.URL.Query().Get("at"); value != "" {
+		at, err := parsePosition(value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		position = &at
+	}
+
+	stored, lyrics, err := h.LyricsService.GetLyrics(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	if position != nil {
+		writeCurrentLine(w, lyrics, *position, format)
+		return
+	}
+	switch format {
+	case lyricsFormatLRC:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": fmt.Sprintf("song-%d.lrc", id)}))
+		io.WriteString(w, stored.LRC+"\n")
+	case lyricsFormatPlain:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		io.WriteString(w, lyrics.Plain()+"\n")
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(newLyricsLines(lyrics)); err != nil {
+			http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// writeCurrentLine отвечает строкой, которая звучит в момент position, в формате format.
+func writeCurrentLine(w http.ResponseWriter, lyrics lrc.Lyrics, position time.Duration, format string) {
+	index := lyrics.At(position)
+	switch format {
+	case lyricsFormatLRC, lyricsFormatPlain:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if index < 0 {
+			return
+		}
+		line := lyrics.Lines[index]
+		if format == lyricsFormatLRC {
+			fmt.Fprintf(w, "[%s]%s\n", lrc.FormatTime(line.Time), line.Text)
+		} else {
+			io.WriteString(w, line.Text+"\n")
+		}
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(newCurrentLyricsLine(lyrics, index, position)); err != nil {
+			http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+// parsePosition разбирает момент воспроизведения: целое число миллисекунд или длительность Go.
+func parsePosition(value string) (time.Duration, error) {
+	if ms, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if ms < 0 {
+			return 0, errors.New("момент воспроизведения не может быть отрицательным")
+		}
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	position, err := time.ParseDuration(value)
+	if err != nil || position < 0 {
+		return 0, fmt.Errorf("некорректный момент воспроизведения %q", value)
+	}
+	return position, nil
+}
+
+// PutSongLyrics godoc
+// @Summary Загрузить синхронизированный текст песни
+// @Description Проверяет текст в формате LRC и сохраняет его, заменяя прежний. Текст передаётся телом запроса или файлом в поле file multipart-формы. Поддерживаются отметки [mm:ss], [mm:ss.xx] и [mm:ss.xxx], несколько отметок у одной строки, теги сведений ([ar:], [ti:], [al:]) и сдвиг [offset:]; отметки слов расширенного LRC (<mm:ss.xx>) отбрасываются. Строка без отметки времени — ошибка с номером строки. Простой текст песни не меняется.
+// @Tags Lyrics
+// @Accept plain,mpfd
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param lyrics body string false "Текст LRC"
+// @Success 200 {object} handlers.LyricsUploadResponse "Текст сохранён"
+// @Failure 400 {string} string "Некорректный ID или ошибка в тексте LRC"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 413 {string} string "Текст слишком большой"
+// @Failure 500 {string} string "Ошибка сохранения текста"
+// @Router /songs/{id}/lyrics [put]
+func (h *LyricsHandler) PutSongLyrics(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	text, ok := readLyricsText(w, r)
+	if !ok {
+		return
+	}
+
+	stored, lyrics, err := h.LyricsService.SaveLyrics(id, text)
+	if err != nil {
+		http.Error(w, "Ошибка сохранения текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newLyricsUploadResponse(stored, lyrics)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// readLyricsText читает текст LRC из поля file multipart-формы или из тела запроса.
+// При ошибке сам отвечает клиенту и возвращает ok = false.
+func readLyricsText(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var src io.Reader
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if !parseUploadForm(w, r, maxLyricsSize) {
+			return "", false
+		}
+		defer r.MultipartForm.RemoveAll()
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Не передан файл", http.StatusBadRequest)
+			return "", false
+		}
+		defer file.Close()
+		src = file
+	} else {
+		src = http.MaxBytesReader(w, r.Body, maxLyricsSize)
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Текст слишком большой", http.StatusRequestEntityTooLarge)
+			return "", false
+		}
+		http.Error(w, "Ошибка чтения текста: "+err.Error(), http.StatusBadRequest)
+		return "", false
+	}
+	return string(data), true
+}
+
+// DeleteSongLyrics godoc
+// @Summary Удалить синхронизированный текст песни
+// @Description Удаляет текст LRC песни; простой текст песни остаётся.
+// @Tags Lyrics
+// @Param id path int true "ID песни"
+// @Success 204 {string} string "Текст удалён"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня или синхронизированный текст не найдены"
+// @Failure 500 {string} string "Ошибка удаления текста"
+// @Router /songs/{id}/lyrics [delete]
+func (h *LyricsHandler) DeleteSongLyrics(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.LyricsService.DeleteLyrics(id); err != nil {
+		http.Error(w, "Ошибка удаления текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/lrc"
+)
+
+func TestParsePosition(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "0", want: 0},
+		{value: "83500", want: 83500 * time.Millisecond},
+		{value: "1m23.5s", want: 83500 * time.Millisecond},
+		{value: "250ms", want: 250 * time.Millisecond},
+		{value: "-1", wantErr: true},
+		{value: "-1s", wantErr: true},
+		{value: "1.5", wantErr: true},
+		{value: "скоро", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePosition(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePosition(%q) = %v, %v, want %v, ошибка %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestWriteCurrentLine(t *testing.T) {
+	lyrics, err := lrc.Parse("[00:01.00]Раз\n[00:02.50]Два")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		name     string
+		position time.Duration
+		format   string
+		want     string
+	}{
+		{name: "lrc", position: 2 * time.Second, format: lyricsFormatLRC, want: "[00:01.00]Раз\n"},
+		{name: "plain", position: 3 * time.Second, format: lyricsFormatPlain, want: "Два\n"},
+		{name: "plain до первой строки", position: 0, format: lyricsFormatPlain, want: ""},
+		{name: "lrc до первой строки", position: 999 * time.Millisecond, format: lyricsFormatLRC, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			writeCurrentLine(w, lyrics, tt.position, tt.format)
+			if got := w.Body.String(); got != tt.want {
+				t.Errorf("ответ = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCurrentLineJSON(t *testing.T) {
+	lyrics, err := lrc.Parse("[00:01.00]Раз\n[00:02.50]Два")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		position time.Duration
+		want     string
+	}{
+		{0, `{"position_ms":0,"index":-1,"line":"","next_time_ms":1000}`},
+		{1500 * time.Millisecond, `{"position_ms":1500,"index":0,"time_ms":1000,"line":"Раз","next_time_ms":2500}`},
+		{time.Minute, `{"position_ms":60000,"index":1,"time_ms":2500,"line":"Два"}`},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		writeCurrentLine(w, lyrics, tt.position, lyricsFormatJSON)
+		var got, want any
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("ответ %q: %v", w.Body.String(), err)
+		}
+		json.Unmarshal([]byte(tt.want), &want)
+		gotJSON, _ := json.Marshal(got)
+		wantJSON, _ := json.Marshal(want)
+		if string(gotJSON) != string(wantJSON) {
+			t.Errorf("at %v: ответ = %s, want %s", tt.position, gotJSON, wantJSON)
+		}
+	}
+}
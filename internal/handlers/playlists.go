@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+type PlaylistHandler struct {
+	PlaylistService *services.PlaylistService
+}
+
+func NewPlaylistHandler(service *services.PlaylistService) *PlaylistHandler {
+	return &PlaylistHandler{PlaylistService: service}
+}
+
+// GetPlaylists godoc
+// @Summary Получить список плейлистов
+// @Description Возвращает плейлисты по названию с числом песен и поддержкой пагинации.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Playlist "Список плейлистов"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /playlists [get]
+func (h *PlaylistHandler) GetPlaylists(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r)
+
+	playlists, err := h.PlaylistService.GetPlaylists(page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения плейлистов: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newPlaylists(playlists)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetPlaylist godoc
+// @Summary Получить плейлист
+// @Description Возвращает плейлист по ID. Песни плейлиста доступны через GET /playlists/{id}/items.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Success 200 {object} handlers.Playlist "Плейлист"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /playlists/{id} [get]
+func (h *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	playlist, err := h.PlaylistService.GetPlaylist(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newPlaylist(playlist))
+}
+
+// CreatePlaylist godoc
+// @Summary Создать плейлист
+// @Description Создаёт пустой плейлист.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param input body CreatePlaylistRequest true "Данные плейлиста"
+// @Success 201 {object} handlers.Playlist "Созданный плейлист"
+// @Failure 400 {string} string "Некорректные входные данные"
+// @Failure 500 {string} string "Ошибка создания плейлиста"
+// @Router /playlists [post]
+func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	var input CreatePlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректные входные данные", http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.PlaylistService.CreatePlaylist(models.Playlist{Name: input.Name, Description: input.Description})
+	if err != nil {
+		http.Error(w, "Ошибка создания плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newPlaylist(created))
+}
+
+// UpdatePlaylist godoc
+// @Summary Обновить плейлист
+// @Description Переименовывает плейлист или меняет его описание.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param input body UpdatePlaylistRequest true "Обновляемые данные плейлиста"
+// @Success 200 {string} string "Плейлист успешно обновлён"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Ошибка обновления плейлиста"
+// @Router /playlists/{id} [put]
+func (h *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input UpdatePlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	update := models.PlaylistUpdate{Name: input.Name, Description: input.Description}
+	if err := h.PlaylistService.UpdatePlaylist(id, update); err != nil {
+		http.Error(w, "Ошибка обновления плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Плейлист успешно обновлён"))
+}
+
+// DeletePlaylist godoc
+// @Summary Удалить плейлист
+// @Description Удаляет плейлист. Песни остаются в библиотеке.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Success 204 {string} string "Плейлист успешно удалён"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Ошибка удаления плейлиста"
+// @Router /playlists/{id} [delete]
+func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.PlaylistService.DeletePlaylist(id); err != nil {
+		http.Error(w, "Ошибка удаления плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetPlaylistItems godoc
+// @Summary Получить песни плейлиста
+// @Description Возвращает песни плейлиста по порядку позиций в том же представлении, что и GET /songs, с ID элемента и позицией. Параметр format или заголовок Accept переключают ответ на потоковую выгрузку M3U8, XSPF, CSV или NDJSON; без page и limit выгружается весь плейлист.
+// @Tags Playlists
+// @Accept json
+// @Produce json,audio/x-mpegurl,application/xspf+xml,text/csv,application/x-ndjson
+// @Param id path int true "ID плейлиста"
+// @Param format query string false "Формат ответа; важнее заголовка Accept" Enums(json, m3u8, xspf, csv, ndjson)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.PlaylistItem "Песни плейлиста"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Плейлист не найден"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /playlists/{id}/items [get]
+func (h *PlaylistHandler) GetPlaylistItems(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, "Некорректное значение format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != formatJSON {
+		page, limit := exportPagination(r)
+		title := "playlist-" + strconv.Itoa(id)
+		streamExport(w, format, title, "Ошибка получения песен плейлиста: ", playlistItemSong, func(fn func(PlaylistItem) error) error {
+			return h.PlaylistService.ExportPlaylistItems(id, page, limit, func(item models.PlaylistItem) error {
+				return fn(newPlaylistItem(item))
+			})
+		})
+		return
+	}
+	page, limit := parsePagination(r)
+
+	items, err := h.PlaylistService.GetPlaylistItems(id, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения песен плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newPlaylistItems(items)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AddPlaylistItem godoc
+// @Summary Добавить песню в плейлист
+// @Description Вставляет песню на указанную позицию, сдвигая последующие, или в конец плейлиста. Одна песня может входить в плейлист несколько раз.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param input body AddPlaylistItemRequest true "Песня и позиция"
+// @Success 201 {object} handlers.AddPlaylistItemResponse "ID нового элемента"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Плейлист или песня не найдены"
+// @Failure 500 {string} string "Ошибка добавления песни в плейлист"
+// @Router /playlists/{id}/items [post]
+func (h *PlaylistHandler) AddPlaylistItem(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input AddPlaylistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	itemID, err := h.PlaylistService.AddPlaylistItem(id, input.SongID, input.Position)
+	if err != nil {
+		http.Error(w, "Ошибка добавления песни в плейлист: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(AddPlaylistItemResponse{ItemID: itemID})
+}
+
+// MovePlaylistItem godoc
+// @Summary Переместить песню в плейлисте
+// @Description Переносит элемент на новую позицию; позиции остальных элементов пересчитываются без пропусков.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param item_id path int true "ID элемента плейлиста"
+// @Param input body MovePlaylistItemRequest true "Новая позиция"
+// @Success 200 {string} string "Песня перемещена"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Плейлист или элемент не найдены"
+// @Failure 500 {string} string "Ошибка перемещения песни"
+// @Router /playlists/{id}/items/{item_id} [put]
+func (h *PlaylistHandler) MovePlaylistItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := parsePlaylistItemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	var input MovePlaylistItemRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.PlaylistService.MovePlaylistItem(id, itemID, input.Position); err != nil {
+		http.Error(w, "Ошибка перемещения песни: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Песня перемещена"))
+}
+
+// RemovePlaylistItem godoc
+// @Summary Убрать песню из плейлиста
+// @Description Удаляет элемент плейлиста; последующие элементы сдвигаются на его место.
+// @Tags Playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID плейлиста"
+// @Param item_id path int true "ID элемента плейлиста"
+// @Success 204 {string} string "Песня убрана из плейлиста"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Плейлист или элемент не найдены"
+// @Failure 500 {string} string "Ошибка удаления песни из плейлиста"
+// @Router /playlists/{id}/items/{item_id} [delete]
+func (h *PlaylistHandler) RemovePlaylistItem(w http.ResponseWriter, r *http.Request) {
+	id, itemID, ok := parsePlaylistItemIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.PlaylistService.RemovePlaylistItem(id, itemID); err != nil {
+		http.Error(w, "Ошибка удаления песни из плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parsePlaylistItemIDs читает ID плейлиста и элемента из пути, отвечая 400 при ошибке.
+func parsePlaylistItemIDs(w http.ResponseWriter, r *http.Request) (id, itemID int, ok bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	itemID, err = strconv.Atoi(vars["item_id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID элемента", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, itemID, true
+}
+
+func playlistItemSong(item PlaylistItem) Song {
+	return item.Song
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+// GetTextRevisions godoc
+// @Summary Получить историю текста песни
+// @Description Возвращает версии текста песни постранично, начиная с последней: номер, автора, время и размер текста. Версия добавляется при каждом изменении текста.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество версий на странице" default(10)
+// @Success 200 {object} handlers.TextRevisionsResponse "Версии текста"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Ошибка получения версий"
+// @Router /songs/{id}/revisions [get]
+func (h *SongHandler) GetTextRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	page, limit := parsePagination(r)
+
+	revisions, err := h.SongService.GetTextRevisions(id, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения версий текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTextRevisionsResponse(id, page, limit, revisions)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetTextRevision godoc
+// @Summary Получить версию текста песни
+// @Description Возвращает версию текста песни по номеру вместе с текстом.
+// @Tags Revisions
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param rev path int true "Номер версии"
+// @Success 200 {object} handlers.TextRevisionDetails "Версия текста"
+// @Failure 400 {string} string "Некорректный ID или номер версии"
+// @Failure 404 {string} string "Песня или версия не найдены"
+// @Failure 500 {string} string "Ошибка получения версии"
+// @Router /songs/{id}/revisions/{rev} [get]
+func (h *SongHandler) GetTextRevision(w http.ResponseWriter, r *http.Request) {
+	id, rev, ok := revisionVars(w, r)
+	if !ok {
+		return
+	}
+
+	revision, err := h.SongService.GetTextRevision(id, rev)
+	if err != nil {
+		http.Error(w, "Ошибка получения версии текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	dto := TextRevisionDetails{TextRevision: newTextRevision(revision), Text: revision.Text}
+	if err := json.NewEncoder(w).Encode(dto); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// DiffTextRevisions godoc
+// @Summary Сравнить версии текста песни
+// @Description Возвращает построчную разницу между версиями from и to в едином формате (diff -u). По умолчанию to — последняя версия, а from — предыдущая перед to; версия 0 — пустой текст. Если тексты совпадают, разница пустая; если им нужно больше 1000 правок, всё между общими началом и концом показывается заменённым целиком. С format=json разница возвращается вместе со сведениями о версиях.
+// @Tags Revisions
+// @Produce plain,json
+// @Param id path int true "ID песни"
+// @Param from query int false "Старая версия; по умолчанию — предыдущая перед to"
+// @Param to query int false "Новая версия; по умолчанию — последняя"
+// @Param context query int false "Число общих строк вокруг изменений" default(3)
+// @Param format query string false "Формат ответа" Enums(diff, json) default(diff)
+// @Success 200 {string} string "Разница в формате diff -u; с format=json — handlers.TextDiff"
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Failure 404 {string} string "Песня или версия не найдены"
+// @Failure 500 {string} string "Ошибка сравнения версий"
+// @Router /songs/{id}/revisions/diff [get]
+func (h *SongHandler) DiffTextRevisions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	from, to, context := -1, 0, services.DefaultDiffContext
+	params := []struct {
+		key   string
+		value *int
+	}{{"from", &from}, {"to", &to}, {"context", &context}}
+	for _, param := range params {
+		if v := r.URL.Query().Get(param.key); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				http.Error(w, "Некорректное значение "+param.key, http.StatusBadRequest)
+				return
+			}
+			*param.value = n
+		}
+	}
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "diff" && format != "json" {
+		http.Error(w, "Некорректный формат: ожидается diff или json", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.SongService.DiffTextRevisions(id, from, to, context)
+	if err != nil {
+		http.Error(w, "Ошибка сравнения версий текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(newTextDiff(diff)); err != nil {
+			http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "text/x-diff; charset=utf-8")
+	io.WriteString(w, diff.Diff)
+}
+
+// RestoreTextRevision godoc
+// @Summary Восстановить версию текста песни
+// @Description Делает текст выбранной версии текущим текстом песни. Откат сохраняется в истории новой версией с указанным автором, так что его тоже можно отменить; если текст уже совпадает, новая версия не создаётся. Возвращает последнюю версию.
+// @Tags Revisions
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param rev path int true "Номер версии"
+// @Param input body RestoreRevisionRequest false "Автор отката"
+// @Success 200 {object} handlers.TextRevision "Текущая версия текста"
+// @Failure 400 {string} string "Некорректный ID, номер версии или формат данных"
+// @Failure 404 {string} string "Песня или версия не найдены"
+// @Failure 500 {string} string "Ошибка восстановления версии"
+// @Router /songs/{id}/revisions/{rev}/restore [post]
+func (h *SongHandler) RestoreTextRevision(w http.ResponseWriter, r *http.Request) {
+	id, rev, ok := revisionVars(w, r)
+	if !ok {
+		return
+	}
+	// Тело необязательно: без него автор отката неизвестен
+	var input RestoreRevisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil && err != io.EOF {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	latest, err := h.SongService.RestoreTextRevision(id, rev, input.Author)
+	if err != nil {
+		http.Error(w, "Ошибка восстановления версии текста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTextRevision(latest)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// revisionVars читает ID песни и номер версии из пути; при ошибке отвечает 400.
+func revisionVars(w http.ResponseWriter, r *http.Request) (id, rev int, ok bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return 0, 0, false
+	}
+	rev, err = strconv.Atoi(vars["rev"])
+	if err != nil || rev <= 0 {
+		http.Error(w, fmt.Sprintf("Некорректный номер версии %q", vars["rev"]), http.StatusBadRequest)
+		return 0, 0, false
+	}
+	return id, rev, true
+}
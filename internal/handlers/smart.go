@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+type SmartPlaylistHandler struct {
+	SmartPlaylistService *services.SmartPlaylistService
+}
+
+func NewSmartPlaylistHandler(service *services.SmartPlaylistService) *SmartPlaylistHandler {
+	return &SmartPlaylistHandler{SmartPlaylistService: service}
+}
+
+// GetSmartPlaylists godoc
+// @Summary Получить список умных плейлистов
+// @Description Возвращает умные плейлисты вместе с их правилами и поддержкой пагинации.
+// @Tags SmartPlaylists
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.SmartPlaylist "Список умных плейлистов"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /smart-playlists [get]
+func (h *SmartPlaylistHandler) GetSmartPlaylists(w http.ResponseWriter, r *http.Request) {
+	page, limit := parsePagination(r)
+
+	playlists, err := h.SmartPlaylistService.GetSmartPlaylists(page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения умных плейлистов: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newSmartPlaylists(playlists)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetSmartPlaylist godoc
+// @Summary Получить умный плейлист
+// @Description Возвращает умный плейлист по ID. Подходящие под правила песни доступны через GET /smart-playlists/{id}/songs.
+// @Tags SmartPlaylists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID умного плейлиста"
+// @Success 200 {object} handlers.SmartPlaylist "Умный плейлист"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Умный плейлист не найден"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /smart-playlists/{id} [get]
+func (h *SmartPlaylistHandler) GetSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	playlist, err := h.SmartPlaylistService.GetSmartPlaylist(id)
+	if err != nil {
+		http.Error(w, "Ошибка получения умного плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newSmartPlaylist(playlist))
+}
+
+// CreateSmartPlaylist godoc
+// @Summary Создать умный плейлист
+// @Description Сохраняет правила отбора песен. Условия задают поле, оператор и значение, группы объединяют вложенные правила через match=all|any. Правила проверяются при сохранении.
+// @Tags SmartPlaylists
+// @Accept json
+// @Produce json
+// @Param input body CreateSmartPlaylistRequest true "Данные умного плейлиста"
+// @Success 201 {object} handlers.SmartPlaylist "Созданный умный плейлист"
+// @Failure 400 {string} string "Некорректные входные данные или правила"
+// @Failure 500 {string} string "Ошибка создания умного плейлиста"
+// @Router /smart-playlists [post]
+func (h *SmartPlaylistHandler) CreateSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	var input CreateSmartPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректные входные данные", http.StatusBadRequest)
+		return
+	}
+
+	playlist := models.SmartPlaylist{
+		Name:        input.Name,
+		Description: input.Description,
+		Rules:       input.Rules.model(),
+	}
+	created, err := h.SmartPlaylistService.CreateSmartPlaylist(playlist)
+	if err != nil {
+		http.Error(w, "Ошибка создания умного плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(newSmartPlaylist(created))
+}
+
+// UpdateSmartPlaylist godoc
+// @Summary Обновить умный плейлист
+// @Description Переименовывает умный плейлист, меняет описание или заменяет правила целиком.
+// @Tags SmartPlaylists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID умного плейлиста"
+// @Param input body UpdateSmartPlaylistRequest true "Обновляемые данные умного плейлиста"
+// @Success 200 {string} string "Умный плейлист успешно обновлён"
+// @Failure 400 {string} string "Некорректный ID, формат данных или правила"
+// @Failure 404 {string} string "Умный плейлист не найден"
+// @Failure 500 {string} string "Ошибка обновления умного плейлиста"
+// @Router /smart-playlists/{id} [put]
+func (h *SmartPlaylistHandler) UpdateSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input UpdateSmartPlaylistRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	update := models.SmartPlaylistUpdate{Name: input.Name, Description: input.Description}
+	if input.Rules != nil {
+		rules := input.Rules.model()
+		update.Rules = &rules
+	}
+	if err := h.SmartPlaylistService.UpdateSmartPlaylist(id, update); err != nil {
+		http.Error(w, "Ошибка обновления умного плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Умный плейлист успешно обновлён"))
+}
+
+// DeleteSmartPlaylist godoc
+// @Summary Удалить умный плейлист
+// @Description Удаляет умный плейлист по ID. Песни библиотеки не затрагиваются.
+// @Tags SmartPlaylists
+// @Accept json
+// @Produce json
+// @Param id path int true "ID умного плейлиста"
+// @Success 204 {string} string "Умный плейлист успешно удалён"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Умный плейлист не найден"
+// @Failure 500 {string} string "Ошибка удаления умного плейлиста"
+// @Router /smart-playlists/{id} [delete]
+func (h *SmartPlaylistHandler) DeleteSmartPlaylist(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.SmartPlaylistService.DeleteSmartPlaylist(id); err != nil {
+		http.Error(w, "Ошибка удаления умного плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSmartPlaylistSongs godoc
+// @Summary Получить песни умного плейлиста
+// @Description Подбирает песни по правилам плейлиста на момент запроса с поддержкой пагинации. Параметр format или заголовок Accept переключают ответ на потоковую выгрузку M3U8, XSPF, CSV или NDJSON; без page и limit выгружаются все подходящие песни.
+// @Tags SmartPlaylists
+// @Accept json
+// @Produce json,audio/x-mpegurl,application/xspf+xml,text/csv,application/x-ndjson
+// @Param id path int true "ID умного плейлиста"
+// @Param format query string false "Формат ответа; важнее заголовка Accept" Enums(json, m3u8, xspf, csv, ndjson)
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Song "Песни, подходящие под правила"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Умный плейлист не найден"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /smart-playlists/{id}/songs [get]
+func (h *SmartPlaylistHandler) GetSmartPlaylistSongs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+	format, err := exportFormat(r)
+	if err != nil {
+		http.Error(w, "Некорректное значение format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if format != formatJSON {
+		page, limit := exportPagination(r)
+		title := "smart-playlist-" + strconv.Itoa(id)
+		streamExport(w, format, title, "Ошибка получения песен умного плейлиста: ", songOf, func(fn func(Song) error) error {
+			return h.SmartPlaylistService.ExportSmartPlaylistSongs(id, page, limit, func(song models.Song) error {
+				return fn(newSong(song))
+			})
+		})
+		return
+	}
+	page, limit := parsePagination(r)
+
+	songs, err := h.SmartPlaylistService.GetSmartPlaylistSongs(id, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения песен умного плейлиста: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newSongs(songs)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
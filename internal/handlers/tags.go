@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/services"
+	"github.com/gorilla/mux"
+)
+
+// defaultTagCloudLimit — число тегов в облаке по умолчанию.
+const defaultTagCloudLimit = 50
+
+type TagHandler struct {
+	TagService *services.TagService
+}
+
+func NewTagHandler(service *services.TagService) *TagHandler {
+	return &TagHandler{TagService: service}
+}
+
+// GetGenres godoc
+// @Summary Получить список жанров
+// @Description Возвращает жанры по алфавиту вместе с числом песен.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Tag "Список жанров"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /genres [get]
+func (h *TagHandler) GetGenres(w http.ResponseWriter, r *http.Request) {
+	h.writeTags(w, r, models.TagKindGenre)
+}
+
+// GetTags godoc
+// @Summary Получить список тегов
+// @Description Возвращает теги по алфавиту вместе с числом песен.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param page query int false "Номер страницы" default(1)
+// @Param limit query int false "Количество элементов на странице" default(10)
+// @Success 200 {array} handlers.Tag "Список тегов"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /tags [get]
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	h.writeTags(w, r, models.TagKindTag)
+}
+
+func (h *TagHandler) writeTags(w http.ResponseWriter, r *http.Request, kind models.TagKind) {
+	page, limit := parsePagination(r)
+
+	tags, err := h.TagService.GetTags(kind, page, limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения меток: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTags(tags)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// GetTagCloud godoc
+// @Summary Получить облако тегов
+// @Description Возвращает самые частые теги с числом песен и относительным весом.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param limit query int false "Максимальное число тегов" default(50)
+// @Success 200 {array} handlers.TagCloudItem "Облако тегов"
+// @Failure 400 {string} string "Некорректные параметры запроса"
+// @Failure 500 {string} string "Ошибка сервера"
+// @Router /tags/cloud [get]
+func (h *TagHandler) GetTagCloud(w http.ResponseWriter, r *http.Request) {
+	limit := defaultTagCloudLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			http.Error(w, "Некорректное значение limit", http.StatusBadRequest)
+			return
+		}
+	}
+
+	tags, err := h.TagService.GetTagCloud(limit)
+	if err != nil {
+		http.Error(w, "Ошибка получения облака тегов: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(newTagCloud(tags)); err != nil {
+		http.Error(w, "Ошибка кодирования ответа: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AttachGenres godoc
+// @Summary Добавить жанры песне
+// @Description Отмечает песню жанрами, создавая недостающие. Названия приводятся к нижнему регистру.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param input body AttachTagsRequest true "Названия жанров"
+// @Success 200 {object} handlers.Song "Песня с обновлёнными жанрами"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Ошибка добавления жанров"
+// @Router /songs/{id}/genres [post]
+func (h *TagHandler) AttachGenres(w http.ResponseWriter, r *http.Request) {
+	h.attach(w, r, models.TagKindGenre)
+}
+
+// AttachTags godoc
+// @Summary Добавить теги песне
+// @Description Отмечает песню тегами, создавая недостающие. Названия приводятся к нижнему регистру.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param input body AttachTagsRequest true "Названия тегов"
+// @Success 200 {object} handlers.Song "Песня с обновлёнными тегами"
+// @Failure 400 {string} string "Некорректный ID или формат данных"
+// @Failure 404 {string} string "Песня не найдена"
+// @Failure 500 {string} string "Ошибка добавления тегов"
+// @Router /songs/{id}/tags [post]
+func (h *TagHandler) AttachTags(w http.ResponseWriter, r *http.Request) {
+	h.attach(w, r, models.TagKindTag)
+}
+
+func (h *TagHandler) attach(w http.ResponseWriter, r *http.Request, kind models.TagKind) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	var input AttachTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, "Некорректный формат данных", http.StatusBadRequest)
+		return
+	}
+
+	song, err := h.TagService.AttachTags(id, kind, input.Names)
+	if err != nil {
+		http.Error(w, "Ошибка добавления меток: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newSong(song))
+}
+
+// DetachGenre godoc
+// @Summary Убрать жанр у песни
+// @Description Снимает жанр с песни. Сам жанр остаётся в справочнике.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param name path string true "Название жанра"
+// @Success 204 {string} string "Жанр снят"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Жанр не привязан к песне"
+// @Failure 500 {string} string "Ошибка снятия жанра"
+// @Router /songs/{id}/genres/{name} [delete]
+func (h *TagHandler) DetachGenre(w http.ResponseWriter, r *http.Request) {
+	h.detach(w, r, models.TagKindGenre)
+}
+
+// DetachTag godoc
+// @Summary Убрать тег у песни
+// @Description Снимает тег с песни. Сам тег остаётся в справочнике.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param id path int true "ID песни"
+// @Param name path string true "Название тега"
+// @Success 204 {string} string "Тег снят"
+// @Failure 400 {string} string "Некорректный ID"
+// @Failure 404 {string} string "Тег не привязан к песне"
+// @Failure 500 {string} string "Ошибка снятия тега"
+// @Router /songs/{id}/tags/{name} [delete]
+func (h *TagHandler) DetachTag(w http.ResponseWriter, r *http.Request) {
+	h.detach(w, r, models.TagKindTag)
+}
+
+func (h *TagHandler) detach(w http.ResponseWriter, r *http.Request, kind models.TagKind) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Некорректный ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.TagService.DetachTag(id, kind, mux.Vars(r)["name"]); err != nil {
+		http.Error(w, "Ошибка снятия метки: "+err.Error(), errorStatus(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,122 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen возвращается без запроса, пока выключатель разомкнут.
+var ErrCircuitOpen = errors.New("внешний сервис недоступен: выключатель разомкнут")
+
+// State — состояние выключателя.
+type State string
+
+const (
+	// StateClosed — запросы идут как обычно.
+	StateClosed State = "closed"
+	// StateOpen — запросы отклоняются сразу, пока не истечёт время ожидания.
+	StateOpen State = "open"
+	// StateHalfOpen — пропускается один пробный запрос; его итог замыкает или снова размыкает выключатель.
+	StateHalfOpen State = "half-open"
+)
+
+// Breaker — автоматический выключатель (circuit breaker): после threshold неудачных попыток
+// подряд он размыкается и отклоняет запросы, а через openTimeout пропускает пробный.
+type Breaker struct {
+	mu          sync.Mutex
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	state    State
+	failures int
+	openedAt time.Time
+	// probing — пробный запрос в полуразомкнутом состоянии уже выполняется.
+	probing bool
+}
+
+// BreakerState — снимок состояния выключателя.
+type BreakerState struct {
+	State State
+	// Failures — неудачные попытки подряд.
+	Failures int
+	// OpenedAt — когда выключатель разомкнулся в последний раз; нулевое, если не размыкался.
+	OpenedAt time.Time
+	// RetryAt — когда будет пропущен пробный запрос; заполняется только в разомкнутом состоянии.
+	RetryAt time.Time
+}
+
+func NewBreaker(threshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{threshold: threshold, openTimeout: openTimeout, now: time.Now, state: StateClosed}
+}
+
+// Allow решает, можно ли выполнить попытку. В разомкнутом состоянии и пока идёт пробный
+// запрос возвращает ErrCircuitOpen; по истечении ожидания переводит выключатель в полуразомкнутое.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Before(b.openedAt.Add(b.openTimeout)) {
+			return ErrCircuitOpen
+		}
+		b.state = StateHalfOpen
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success отмечает удачную попытку: выключатель замыкается, счётчик неудач сбрасывается.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// Failure отмечает неудачную попытку. Неудачный пробный запрос или threshold неудач подряд
+// размыкают выключатель.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		b.state = StateOpen
+		b.openedAt = b.now()
+	}
+	b.probing = false
+}
+
+// Cancel отмечает попытку, прерванную вызывающим: она ничего не говорит о сервисе,
+// но освобождает место пробного запроса.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State возвращает снимок состояния выключателя.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := BreakerState{State: b.state, Failures: b.failures, OpenedAt: b.openedAt}
+	if b.state == StateOpen {
+		state.RetryAt = b.openedAt.Add(b.openTimeout)
+	}
+	return state
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeClock — управляемые часы для выключателя.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(threshold int) (*Breaker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	b := NewBreaker(threshold, time.Minute)
+	b.now = clock.Now
+	return b, clock
+}
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b, clock := newTestBreaker(3)
+
+	for i := 0; i < 2; i++ {
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		b.Failure()
+	}
+	if state := b.State(); state.State != StateClosed || state.Failures != 2 {
+		t.Fatalf("после 2 неудач State = %+v, want closed", state)
+	}
+
+	b.Allow()
+	b.Failure()
+	state := b.State()
+	if state.State != StateOpen || !state.OpenedAt.Equal(clock.now) || !state.RetryAt.Equal(clock.now.Add(time.Minute)) {
+		t.Fatalf("после 3 неудач State = %+v, want open", state)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Allow в разомкнутом = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestBreakerSuccessResetsFailures(t *testing.T) {
+	b, _ := newTestBreaker(2)
+	b.Failure()
+	b.Success()
+	b.Failure()
+	if state := b.State(); state.State != StateClosed || state.Failures != 1 {
+		t.Errorf("State = %+v, want closed с 1 неудачей", state)
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	tests := []struct {
+		name      string
+		probe     func(b *Breaker)
+		wantState State
+	}{
+		{name: "удачная проба замыкает", probe: (*Breaker).Success, wantState: StateClosed},
+		{name: "неудачная проба снова размыкает", probe: (*Breaker).Failure, wantState: StateOpen},
+		{name: "отменённая проба оставляет полуразомкнутым", probe: (*Breaker).Cancel, wantState: StateHalfOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, clock := newTestBreaker(1)
+			b.Failure()
+
+			clock.now = clock.now.Add(time.Minute - time.Nanosecond)
+			if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("Allow до истечения ожидания = %v, want ErrCircuitOpen", err)
+			}
+			clock.now = clock.now.Add(time.Nanosecond)
+			if err := b.Allow(); err != nil {
+				t.Fatalf("пробный Allow: %v", err)
+			}
+			if state := b.State(); state.State != StateHalfOpen || !state.RetryAt.IsZero() {
+				t.Fatalf("State = %+v, want half-open", state)
+			}
+			// Пока идёт проба, остальные попытки отклоняются
+			if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+				t.Fatalf("второй Allow во время пробы = %v, want ErrCircuitOpen", err)
+			}
+
+			tt.probe(b)
+			state := b.State()
+			if state.State != tt.wantState {
+				t.Fatalf("State = %q, want %q", state.State, tt.wantState)
+			}
+			switch tt.wantState {
+			case StateOpen:
+				if !state.OpenedAt.Equal(clock.now) {
+					t.Errorf("OpenedAt = %v, want %v", state.OpenedAt, clock.now)
+				}
+				if err := b.Allow(); !errors.Is(err, ErrCircuitOpen) {
+					t.Errorf("Allow = %v, want ErrCircuitOpen", err)
+				}
+			default:
+				if err := b.Allow(); err != nil {
+					t.Errorf("Allow: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Package httpclient — HTTP-клиент для внешних API: таймаут попытки, повторы
+// с экспоненциальной задержкой и случайным разбросом, учёт Retry-After,
+// ограничение частоты запросов и автоматический выключатель, который перестаёт
+// слать запросы упавшему сервису.
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// Config — параметры клиента.
+type Config struct {
+	// Name — имя сервиса в журнале и на странице состояния.
+	Name string
+	// Timeout ограничивает одну попытку целиком, вместе с чтением ответа.
+	Timeout time.Duration
+	// MaxRetries — число повторов после первой попытки.
+	MaxRetries int
+	// BaseDelay — задержка перед первым повтором; каждая следующая вдвое больше, но не больше MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// FailureThreshold — число неудачных попыток подряд, после которого выключатель размыкается.
+	FailureThreshold int
+	// OpenTimeout — сколько выключатель остаётся разомкнутым до пробного запроса.
+	OpenTimeout time.Duration
+	// RateLimit — не больше стольких попыток в секунду, включая повторы; 0 — без ограничения.
+	// RateBurst — сколько попыток можно сделать подряд без ожидания.
+	RateLimit float64
+	RateBurst int
+}
+
+// DefaultConfig возвращает параметры клиента по умолчанию.
+func DefaultConfig() Config {
+	return Config{
+		Name:             "external-api",
+		Timeout:          10 * time.Second,
+		MaxRetries:       3,
+		BaseDelay:        200 * time.Millisecond,
+		MaxDelay:         5 * time.Second,
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// Client выполняет запросы к одному внешнему сервису. Повторяются сетевые ошибки и ответы
+// 429 и 5xx; задержка между попытками растёт экспоненциально со случайным разбросом,
+// а Retry-After из ответа задаёт её явно. Безопасен для одновременного использования.
+type Client struct {
+	config  Config
+	http    *http.Client
+	breaker *Breaker
+	limiter *Limiter
+
+	requests  atomic.Int64
+	retries   atomic.Int64
+	failures  atomic.Int64
+	rejected  atomic.Int64
+	throttled atomic.Int64
+	lastErr   atomic.Pointer[failure]
+}
+
+// failure — последняя неудачная попытка.
+type failure struct {
+	err string
+	at  time.Time
+}
+
+// Stats — состояние клиента для страницы состояния.
+type Stats struct {
+	Name    string
+	Breaker BreakerState
+	// Requests — вызовы Do, Retries — повторные попытки, Failures — неудачные попытки,
+	// Rejected — вызовы, отклонённые разомкнутым выключателем,
+	// Throttled — попытки, которые ждали ограничителя частоты.
+	Requests  int64
+	Retries   int64
+	Failures  int64
+	Rejected  int64
+	Throttled int64
+	// LastError и LastErrorAt — последняя неудачная попытка; пустые, если неудач не было.
+	LastError   string
+	LastErrorAt time.Time
+}
+
+func NewClient(config Config) *Client {
+	return &Client{
+		config:  config,
+		http:    &http.Client{Timeout: config.Timeout},
+		breaker: NewBreaker(config.FailureThreshold, config.OpenTimeout),
+		limiter: NewLimiter(config.RateLimit, config.RateBurst),
+	}
+}
+
+// Do выполняет запрос с повторами. Запрос отменяется вместе с req.Context(), в том числе
+// во время ожидания перед повтором. Запрос с телом повторяется, только если задан req.GetBody.
+// После исчерпания повторов возвращается последний ответ, поэтому код ответа проверяет вызывающий.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		// Ограничитель проверяется до выключателя, чтобы пробный запрос не ждал своей очереди
+		waited, err := c.limiter.Wait(ctx)
+		if waited {
+			c.throttled.Add(1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := c.breaker.Allow(); err != nil {
+			c.rejected.Add(1)
+			return nil, fmt.Errorf("%s: %w", c.config.Name, err)
+		}
+		if attempt > 0 {
+			c.retries.Add(1)
+			if err := rewind(req); err != nil {
+				c.breaker.Cancel()
+				return nil, err
+			}
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				// Запрос отменил вызывающий — сервис тут ни при чём
+				c.breaker.Cancel()
+				return nil, ctx.Err()
+			}
+			c.fail(err)
+		} else if retryable(resp.StatusCode) {
+			c.fail(fmt.Errorf("ответ %d %s", resp.StatusCode, http.StatusText(resp.StatusCode)))
+		} else {
+			c.breaker.Success()
+			return resp, nil
+		}
+
+		delay, ok := c.delay(attempt, resp)
+		if !ok || attempt >= c.config.MaxRetries || (req.Body != nil && req.GetBody == nil) {
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", c.config.Name, err)
+			}
+			return resp, nil
+		}
+		if resp != nil {
+			// Тело читается до конца, чтобы соединение вернулось в пул
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+		log.Warnf("%s: попытка %d не удалась, повтор через %v", c.config.Name, attempt+1, delay.Round(time.Millisecond))
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Get выполняет GET-запрос с повторами в контексте ctx.
+func (c *Client) Get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка создания запроса: %w", err)
+	}
+	return c.Do(req)
+}
+
+// Stats возвращает счётчики клиента и состояние выключателя.
+func (c *Client) Stats() Stats {
+	stats := Stats{
+		Name:      c.config.Name,
+		Breaker:   c.breaker.State(),
+		Requests:  c.requests.Load(),
+		Retries:   c.retries.Load(),
+		Failures:  c.failures.Load(),
+		Rejected:  c.rejected.Load(),
+		Throttled: c.throttled.Load(),
+	}
+	if last := c.lastErr.Load(); last != nil {
+		stats.LastError, stats.LastErrorAt = last.err, last.at
+	}
+	return stats
+}
+
+func (c *Client) fail(err error) {
+	c.failures.Add(1)
+	c.lastErr.Store(&failure{err: err.Error(), at: time.Now()})
+	c.breaker.Failure()
+}
+
+// delay выбирает задержку перед повтором: Retry-After из ответа или экспоненциальную
+// с полным разбросом (случайную от нуля до BaseDelay·2^attempt, не больше MaxDelay).
+// Если сервис просит ждать дольше MaxDelay, повтора не будет.
+func (c *Client) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return wait, wait <= c.config.MaxDelay
+		}
+	}
+	backoff := c.config.MaxDelay
+	if attempt < 30 {
+		backoff = min(c.config.BaseDelay<<attempt, c.config.MaxDelay)
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+	return rand.N(backoff + 1), true
+}
+
+// retryable сообщает, стоит ли повторить запрос с таким кодом ответа.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter разбирает заголовок Retry-After: число секунд или дату HTTP.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	return max(at.Sub(now), 0), true
+}
+
+// rewind восстанавливает тело запроса перед повтором.
+func rewind(req *http.Request) error {
+	if req.Body == nil || req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("ошибка повтора тела запроса: %w", err)
+	}
+	req.Body = body
+	return nil
+}
+
+// sleep ждёт d или отмены ctx.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,211 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "0", want: 0, wantOK: true},
+		{value: "120", want: 2 * time.Minute, wantOK: true},
+		{value: "-5", wantOK: false},
+		{value: "1.5", wantOK: false},
+		{value: "soon", wantOK: false},
+		{value: "Mon, 01 Jan 2024 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{value: "Monday, 01-Jan-24 12:01:00 GMT", want: time.Minute, wantOK: true},
+		{value: "Mon Jan  1 12:00:10 2024", want: 10 * time.Second, wantOK: true},
+		{value: "Mon, 01 Jan 2024 11:00:00 GMT", want: 0, wantOK: true},
+	}
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	for status, want := range map[int]bool{200: false, 404: false, 400: false, 429: true, 500: true, 503: true} {
+		if got := retryable(status); got != want {
+			t.Errorf("retryable(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestDelayBackoff(t *testing.T) {
+	c := NewClient(Config{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})
+	for attempt, limit := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		limit *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			delay, ok := c.delay(attempt, nil)
+			if !ok || delay < 0 || delay > limit {
+				t.Fatalf("delay(%d) = %v, %v, want не больше %v", attempt, delay, ok, limit)
+			}
+		}
+	}
+	if delay, ok := c.delay(100, nil); !ok || delay > time.Second {
+		t.Errorf("delay(100) = %v, %v: переполнение сдвига", delay, ok)
+	}
+
+	resp := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+	if _, ok := c.delay(0, resp); ok {
+		t.Error("Retry-After дольше MaxDelay не должен повторяться")
+	}
+	resp.Header.Set("Retry-After", "1")
+	if delay, ok := c.delay(0, resp); !ok || delay != time.Second {
+		t.Errorf("delay с Retry-After = %v, %v, want 1s", delay, ok)
+	}
+}
+
+func newTestClient(threshold, retries int) *Client {
+	return NewClient(Config{
+		Name:             "test",
+		Timeout:          time.Second,
+		MaxRetries:       retries,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         10 * time.Millisecond,
+		FailureThreshold: threshold,
+		OpenTimeout:      time.Minute,
+	})
+}
+
+func TestClientRetries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := newTestClient(10, 3)
+	resp, err := c.Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("статус %d после %d попыток, want 200 после 3", resp.StatusCode, calls.Load())
+	}
+	stats := c.Stats()
+	if stats.Requests != 1 || stats.Retries != 2 || stats.Failures != 2 || stats.Breaker.State != StateClosed || stats.Breaker.Failures != 0 {
+		t.Errorf("Stats = %+v", stats)
+	}
+	if !strings.Contains(stats.LastError, "503") {
+		t.Errorf("LastError = %q, want 503", stats.LastError)
+	}
+}
+
+func TestClientReturnsLastResponse(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(10, 2).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError || calls.Load() != 3 {
+		t.Errorf("статус %d после %d попыток, want 500 после 3", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestClientDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	resp, err := newTestClient(10, 3).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+	if calls.Load() != 1 {
+		t.Errorf("попыток = %d, want 1", calls.Load())
+	}
+}
+
+func TestClientBreakerRejects(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := newTestClient(2, 5)
+	if _, err := c.Get(context.Background(), server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("error = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := c.Get(context.Background(), server.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("повторный вызов: error = %v, want ErrCircuitOpen", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("попыток = %d, want 2: разомкнутый выключатель не должен слать запросы", calls.Load())
+	}
+	if stats := c.Stats(); stats.Rejected != 2 || stats.Breaker.State != StateOpen {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestClientCanceledDoesNotTripBreaker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	c := newTestClient(1, 3)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.Get(ctx, server.URL); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want DeadlineExceeded", err)
+	}
+	if stats := c.Stats(); stats.Breaker.State != StateClosed || stats.Failures != 0 {
+		t.Errorf("Stats = %+v: отмена вызывающим засчитана как сбой", stats)
+	}
+}
+
+func TestClientRetriesBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+	resp, err := newTestClient(10, 3).Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	if len(bodies) != 2 || bodies[1] != "payload" {
+		t.Errorf("тела попыток = %q, want два раза payload", bodies)
+	}
+}
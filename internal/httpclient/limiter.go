@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter ограничивает частоту запросов алгоритмом «ведро с токенами»: ведро вмещает
+// burst токенов и пополняется со скоростью rate в секунду, каждый запрос забирает токен.
+// Нулевой *Limiter ничего не ограничивает.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter создаёт ограничитель на rate запросов в секунду с запасом burst;
+// при rate <= 0 возвращает nil — без ограничения.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if rate <= 0 {
+		return nil
+	}
+	burst = max(burst, 1)
+	return &Limiter{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait ждёт свободного токена или отмены ctx. Токен бронируется сразу, поэтому
+// одновременные вызовы выстраиваются в очередь; при отмене бронь возвращается.
+// Возвращает true, если пришлось ждать.
+func (l *Limiter) Wait(ctx context.Context) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return false, nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewLimiterUnlimited(t *testing.T) {
+	for _, rate := range []float64{0, -1} {
+		l := NewLimiter(rate, 10)
+		if l != nil {
+			t.Fatalf("NewLimiter(%v) = %+v, want nil", rate, l)
+		}
+		for i := 0; i < 100; i++ {
+			if waited, err := l.Wait(context.Background()); waited || err != nil {
+				t.Fatalf("Wait = %v, %v", waited, err)
+			}
+		}
+	}
+}
+
+func TestLimiterBurst(t *testing.T) {
+	l := NewLimiter(1, 3)
+	for i := 0; i < 3; i++ {
+		if waited, err := l.Wait(context.Background()); waited || err != nil {
+			t.Fatalf("Wait %d в пределах запаса = %v, %v", i, waited, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	waited, err := l.Wait(ctx)
+	if !waited || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait сверх запаса = %v, %v, want ожидание и DeadlineExceeded", waited, err)
+	}
+	// Отменённое ожидание возвращает бронь: очередь не растёт
+	if l.tokens < -0.1 || l.tokens > 0.1 {
+		t.Errorf("tokens = %v, want около 0", l.tokens)
+	}
+}
+
+func TestLimiterRefill(t *testing.T) {
+	l := NewLimiter(100, 1)
+	l.Wait(context.Background())
+
+	start := time.Now()
+	waited, err := l.Wait(context.Background())
+	if !waited || err != nil {
+		t.Fatalf("Wait = %v, %v", waited, err)
+	}
+	if elapsed := time.Since(start); elapsed < 5*time.Millisecond || elapsed > time.Second {
+		t.Errorf("ожидание = %v, want около 10ms", elapsed)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if waited, _ := l.Wait(context.Background()); waited {
+		t.Error("ведро не пополнилось")
+	}
+}
+
+func TestLimiterBurstAtLeastOne(t *testing.T) {
+	l := NewLimiter(1, 0)
+	if waited, _ := l.Wait(context.Background()); waited {
+		t.Error("первый запрос ждал при burst 0")
+	}
+}
@@ -0,0 +1,153 @@
+// Package imaging распознаёт обложки JPEG и PNG и уменьшает их до миниатюр
+// средствами стандартной библиотеки.
+package imaging
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Форматы изображений.
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+)
+
+// ErrUnsupported возвращается, если содержимое не является изображением JPEG или PNG.
+var ErrUnsupported = errors.New("неподдерживаемый формат изображения")
+
+// jpegQuality — качество сжатия миниатюр JPEG.
+const jpegQuality = 85
+
+// formats описывает поддерживаемые форматы: тип содержимого и расширение файла.
+var formats = map[string]struct {
+	mimeType  string
+	extension string
+}{
+	FormatJPEG: {"image/jpeg", ".jpg"},
+	FormatPNG:  {"image/png", ".png"},
+}
+
+// MimeType возвращает тип содержимого для формата.
+func MimeType(format string) string {
+	return formats[format].mimeType
+}
+
+// Extension возвращает расширение файла для формата вместе с точкой.
+func Extension(format string) string {
+	return formats[format].extension
+}
+
+// DecodeConfig читает заголовок изображения: формат и размеры, не декодируя пиксели.
+func DecodeConfig(r io.Reader) (image.Config, string, error) {
+	config, format, err := image.DecodeConfig(r)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return image.Config{}, "", ErrUnsupported
+		}
+		return image.Config{}, "", fmt.Errorf("ошибка чтения изображения: %w", err)
+	}
+	if _, ok := formats[format]; !ok {
+		return image.Config{}, "", ErrUnsupported
+	}
+	return config, format, nil
+}
+
+// Fit возвращает размеры, до которых нужно уменьшить изображение width×height,
+// чтобы оно поместилось в квадрат size×size с сохранением пропорций. Изображение не увеличивается.
+func Fit(width, height, size int) (int, int) {
+	if width <= size && height <= size {
+		return width, height
+	}
+	if width >= height {
+		return size, max(1, height*size/width)
+	}
+	return max(1, width*size/height), size
+}
+
+// Thumbnail декодирует изображение из r, уменьшает его, чтобы оно поместилось в квадрат
+// size×size, и записывает в w в исходном формате. Возвращает формат изображения.
+func Thumbnail(w io.Writer, r io.Reader, size int) (string, error) {
+	src, format, err := image.Decode(r)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return "", ErrUnsupported
+		}
+		return "", fmt.Errorf("ошибка декодирования изображения: %w", err)
+	}
+	bounds := src.Bounds()
+	width, height := Fit(bounds.Dx(), bounds.Dy(), size)
+	dst := Resize(src, width, height)
+
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(w, dst, &jpeg.Options{Quality: jpegQuality})
+	case FormatPNG:
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(w, dst)
+	default:
+		return "", ErrUnsupported
+	}
+	if err != nil {
+		return "", fmt.Errorf("ошибка кодирования изображения: %w", err)
+	}
+	return format, nil
+}
+
+// Resize уменьшает изображение до width×height усреднением: каждый пиксель результата —
+// среднее покрываемого им прямоугольника исходных пикселей. Для увеличения не подходит.
+func Resize(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	// Перевод в RGBA упрощает и ускоряет чтение пикселей для любых цветовых моделей
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	} else {
+		rgba = rgba.SubImage(bounds).(*image.RGBA)
+	}
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	if srcW == 0 || srcH == 0 {
+		return dst
+	}
+
+	for y := 0; y < height; y++ {
+		y0, y1 := span(y, height, srcH)
+		for x := 0; x < width; x++ {
+			x0, x1 := span(x, width, srcW)
+			var r, g, b, a uint64
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride+x0*4 : sy*rgba.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					r += uint64(row[i])
+					g += uint64(row[i+1])
+					b += uint64(row[i+2])
+					a += uint64(row[i+3])
+				}
+			}
+			n := uint64((x1 - x0) * (y1 - y0))
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8((r + n/2) / n)
+			dst.Pix[i+1] = uint8((g + n/2) / n)
+			dst.Pix[i+2] = uint8((b + n/2) / n)
+			dst.Pix[i+3] = uint8((a + n/2) / n)
+		}
+	}
+	return dst
+}
+
+// span возвращает полуинтервал исходных пикселей, который покрывает пиксель i результата
+// длиной n при исходной длине total; интервал содержит хотя бы один пиксель.
+func span(i, n, total int) (int, int) {
+	from := i * total / n
+	to := (i + 1) * total / n
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
@@ -0,0 +1,172 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name                string
+		width, height, size int
+		wantW, wantH        int
+	}{
+		{name: "меньше квадрата не увеличивается", width: 100, height: 50, size: 256, wantW: 100, wantH: 50},
+		{name: "ровно по размеру", width: 256, height: 256, size: 256, wantW: 256, wantH: 256},
+		{name: "горизонтальное", width: 1000, height: 500, size: 256, wantW: 256, wantH: 128},
+		{name: "вертикальное", width: 500, height: 1000, size: 256, wantW: 128, wantH: 256},
+		{name: "одна сторона больше", width: 300, height: 200, size: 256, wantW: 256, wantH: 170},
+		{name: "узкая полоса не схлопывается", width: 1000, height: 3, size: 64, wantW: 64, wantH: 1},
+		{name: "высокая полоса не схлопывается", width: 3, height: 1000, size: 64, wantW: 1, wantH: 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, h := Fit(tt.width, tt.height, tt.size)
+			if w != tt.wantW || h != tt.wantH {
+				t.Errorf("Fit(%d, %d, %d) = %d×%d, ожидалось %d×%d", tt.width, tt.height, tt.size, w, h, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+// halves возвращает изображение в оттенках серого: левая половина чёрная, правая белая.
+func halves(rect image.Rectangle) *image.Gray {
+	img := image.NewGray(rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if x >= rect.Min.X+rect.Dx()/2 {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+func checkPixel(t *testing.T, img *image.RGBA, x, y int, want color.RGBA) {
+	t.Helper()
+	if got := img.RGBAAt(x, y); got != want {
+		t.Errorf("пиксель (%d, %d) = %v, ожидался %v", x, y, got, want)
+	}
+}
+
+var (
+	black = color.RGBA{0, 0, 0, 255}
+	white = color.RGBA{255, 255, 255, 255}
+	red   = color.RGBA{255, 0, 0, 255}
+	blue  = color.RGBA{0, 0, 255, 255}
+)
+
+func TestResizeNonRGBA(t *testing.T) {
+	dst := Resize(halves(image.Rect(0, 0, 8, 4)), 2, 1)
+	if dst.Bounds() != image.Rect(0, 0, 2, 1) {
+		t.Fatalf("размер результата %v, ожидался 2×1", dst.Bounds())
+	}
+	checkPixel(t, dst, 0, 0, black)
+	checkPixel(t, dst, 1, 0, white)
+}
+
+func TestResizeAverages(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	src.SetRGBA(0, 0, white)
+	src.SetRGBA(1, 1, white)
+	src.SetRGBA(1, 0, black)
+	src.SetRGBA(0, 1, black)
+	dst := Resize(src, 1, 1)
+	checkPixel(t, dst, 0, 0, color.RGBA{128, 128, 128, 255})
+}
+
+func TestResizeSubImage(t *testing.T) {
+	// Четыре квадранта: сверху чёрный и белый, снизу красный и синий
+	src := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			c := []color.RGBA{black, white, red, blue}[y/4*2+x/4]
+			src.SetRGBA(x, y, c)
+		}
+	}
+
+	t.Run("RGBA", func(t *testing.T) {
+		sub := src.SubImage(image.Rect(4, 4, 8, 8))
+		checkPixel(t, Resize(sub, 1, 1), 0, 0, blue)
+		sub = src.SubImage(image.Rect(0, 4, 8, 8))
+		dst := Resize(sub, 2, 1)
+		checkPixel(t, dst, 0, 0, red)
+		checkPixel(t, dst, 1, 0, blue)
+	})
+	t.Run("не RGBA", func(t *testing.T) {
+		gray := halves(image.Rect(0, 0, 8, 8))
+		sub := gray.SubImage(image.Rect(4, 2, 8, 6))
+		checkPixel(t, Resize(sub, 2, 2), 1, 1, white)
+		sub = gray.SubImage(image.Rect(2, 2, 6, 6))
+		dst := Resize(sub, 2, 1)
+		checkPixel(t, dst, 0, 0, black)
+		checkPixel(t, dst, 1, 0, white)
+	})
+}
+
+func encode(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := halves(image.Rect(0, 0, width, height))
+	var buf bytes.Buffer
+	var err error
+	if format == FormatPNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		width, height int
+		wantW, wantH  int
+	}{
+		{name: "PNG", format: FormatPNG, width: 300, height: 150, wantW: 64, wantH: 32},
+		{name: "JPEG", format: FormatJPEG, width: 150, height: 300, wantW: 32, wantH: 64},
+		{name: "маленький PNG не увеличивается", format: FormatPNG, width: 40, height: 20, wantW: 40, wantH: 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			format, err := Thumbnail(&out, bytes.NewReader(encode(t, tt.format, tt.width, tt.height)), 64)
+			if err != nil {
+				t.Fatalf("Thumbnail: %v", err)
+			}
+			if format != tt.format {
+				t.Errorf("формат %q, ожидался %q", format, tt.format)
+			}
+			config, gotFormat, err := DecodeConfig(&out)
+			if err != nil {
+				t.Fatalf("DecodeConfig: %v", err)
+			}
+			if gotFormat != tt.format {
+				t.Errorf("миниатюра записана в формате %q, ожидался %q", gotFormat, tt.format)
+			}
+			if config.Width != tt.wantW || config.Height != tt.wantH {
+				t.Errorf("размер миниатюры %d×%d, ожидался %d×%d", config.Width, config.Height, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+func TestThumbnailUnsupported(t *testing.T) {
+	_, err := Thumbnail(&bytes.Buffer{}, strings.NewReader("не изображение"), 64)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("ошибка %v, ожидалась ErrUnsupported", err)
+	}
+	if _, _, err := DecodeConfig(strings.NewReader("не изображение")); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("DecodeConfig: ошибка %v, ожидалась ErrUnsupported", err)
+	}
+}
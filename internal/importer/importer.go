@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// Поддерживаемые форматы файлов.
+const (
+	FormatCSV    = "csv"
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+)
+
+// utf8BOM — метка порядка байтов, которую добавляют табличные редакторы при сохранении CSV.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// record — запись JSON и NDJSON. Совпадает с представлением песни в API,
+// поэтому выгрузку NDJSON можно импортировать обратно; лишние поля игнорируются.
+type record struct {
+	Group       string   `json:"group"`
+	Song        string   `json:"song"`
+	ReleaseDate string   `json:"release_date"`
+	Text        string   `json:"text"`
+	Link        string   `json:"link"`
+	Genres      []string `json:"genres"`
+	Tags        []string `json:"tags"`
+}
+
+func (r record) row(n int) models.ImportRow {
+	return models.ImportRow{
+		Row:         n,
+		Group:       strings.TrimSpace(r.Group),
+		Song:        strings.TrimSpace(r.Song),
+		ReleaseDate: strings.TrimSpace(r.ReleaseDate),
+		Text:        r.Text,
+		Link:        strings.TrimSpace(r.Link),
+		Genres:      r.Genres,
+		Tags:        r.Tags,
+	}
+}
+
+// DetectFormat определяет формат по расширению файла или типу содержимого.
+// Возвращает пустую строку, если формат не распознан.
+func DetectFormat(filename, contentType string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return FormatCSV
+	case ".json":
+		return FormatJSON
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	}
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return FormatCSV
+	case strings.HasPrefix(contentType, "application/x-ndjson"), strings.HasPrefix(contentType, "application/ndjson"):
+		return FormatNDJSON
+	case strings.HasPrefix(contentType, "application/json"):
+		return FormatJSON
+	}
+	return ""
+}
+
+// Read разбирает файл в формате format. Записи, которые не удалось разобрать,
+// возвращаются с заполненным Err, чтобы попасть в отчёт; ошибка возвращается,
+// только если файл повреждён настолько, что дальше читать его нельзя.
+func Read(r io.Reader, format string) ([]models.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSON:
+		return readJSON(r)
+	case FormatNDJSON:
+		return readNDJSON(r)
+	}
+	return nil, fmt.Errorf("неизвестный формат файла %q", format)
+}
+
+// readCSV читает CSV с заголовком. Обязательны колонки group и song, необязательны
+// release_date, text, link, genres и tags (списки через точку с запятой); остальные
+// колонки, например из выгрузки GET /songs?format=csv, игнорируются.
+func readCSV(r io.Reader) ([]models.ImportRow, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []models.ImportRow{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения заголовка CSV: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"group", "song"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("в заголовке CSV нет колонки %q", required)
+		}
+	}
+
+	rows := []models.ImportRow{}
+	for n := 1; ; n++ {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора CSV в записи %d: %w", n, err)
+		}
+		if len(fields) != len(header) {
+			rows = append(rows, models.ImportRow{Row: n,
+				Err: fmt.Sprintf("ожидается полей: %d, получено %d", len(header), len(fields))})
+			continue
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return fields[i]
+			}
+			return ""
+		}
+		rows = append(rows, record{
+			Group:       field("group"),
+			Song:        field("song"),
+			ReleaseDate: field("release_date"),
+			Text:        field("text"),
+			Link:        field("link"),
+			Genres:      splitList(field("genres")),
+			Tags:        splitList(field("tags")),
+		}.row(n))
+	}
+}
+
+// splitList разбирает список жанров или тегов из колонки CSV.
+func splitList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	return strings.Split(value, ";")
+}
+
+// readJSON читает массив объектов, разбирая элементы по одному.
+func readJSON(r io.Reader) ([]models.ImportRow, error) {
+	dec := json.NewDecoder(r)
+	if token, err := dec.Token(); err != nil || token != json.Delim('[') {
+		return nil, errors.New("ожидается JSON-массив записей")
+	}
+
+	rows := []models.ImportRow{}
+	for n := 1; dec.More(); n++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("ошибка разбора JSON в записи %d: %w", n, err)
+		}
+		rows = append(rows, decodeRecord(raw, n))
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("ошибка разбора JSON: %w", err)
+	}
+	return rows, nil
+}
+
+// readNDJSON читает по одному объекту на строку; пустые строки пропускаются,
+// но учитываются в номерах записей, чтобы номер совпадал со строкой файла.
+func readNDJSON(r io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	// Тексты песен бывают длинными, стандартного буфера в 64 КБ не всегда хватает
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+
+	rows := []models.ImportRow{}
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if n == 1 {
+			line = bytes.TrimPrefix(line, utf8BOM)
+		}
+		if len(line) == 0 {
+			continue
+		}
+		rows = append(rows, decodeRecord(line, n))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения NDJSON: %w", err)
+	}
+	return rows, nil
+}
+
+func decodeRecord(data []byte, n int) models.ImportRow {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return models.ImportRow{Row: n, Err: "некорректная запись: " + err.Error()}
+	}
+	return rec.row(n)
+}
@@ -0,0 +1,157 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		filename, contentType string
+		want                  string
+	}{
+		{"songs.CSV", "", FormatCSV},
+		{"songs.json", "text/plain", FormatJSON},
+		{"songs.jsonl", "", FormatNDJSON},
+		{"songs.ndjson", "application/json", FormatNDJSON},
+		{"", "text/csv; charset=utf-8", FormatCSV},
+		{"upload", "application/x-ndjson", FormatNDJSON},
+		{"upload", "application/json", FormatJSON},
+		{"songs.txt", "text/plain", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectFormat(tt.filename, tt.contentType); got != tt.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, want %q", tt.filename, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	const file = "\uFEFFid, Group ,song,release_date,text,link,genres,tags\n" +
+		"1,Muse, Uprising ,16.07.2009,\"Куплет,\nприпев\",https://example.com,rock;alternative,\n" +
+		"2,Queen,Bohemian Rhapsody\n" +
+		"3,,Без группы,,,,,\n"
+	rows, err := Read(strings.NewReader(file), FormatCSV)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := []models.ImportRow{
+		{Row: 1, Group: "Muse", Song: "Uprising", ReleaseDate: "16.07.2009", Text: "Куплет,\nприпев", Link: "https://example.com", Genres: []string{"rock", "alternative"}},
+		{Row: 2, Err: "ожидается полей: 8, получено 3"},
+		{Row: 3, Song: "Без группы"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows =\n%+v\nwant\n%+v", rows, want)
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "нет колонки song", file: "group,name\nMuse,Uprising\n", wantErr: `нет колонки "song"`},
+		{name: "незакрытая кавычка", file: "group,song\nMuse,\"Uprising\n", wantErr: "в записи 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.file), FormatCSV)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	rows, err := Read(strings.NewReader(""), FormatCSV)
+	if err != nil || rows == nil || len(rows) != 0 {
+		t.Errorf("пустой CSV: rows = %v, %v, want пустой список", rows, err)
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	const file = `[
+		{"group": " Muse ", "song": "Uprising", "genres": ["rock"], "id": 7},
+		{"group": "Queen", "song": 42},
+		{"group": "Queen", "song": "Bohemian Rhapsody", "text": "  Is this the real life?  "}
+	]`
+	rows, err := Read(strings.NewReader(file), FormatJSON)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %+v, want 3 записи", rows)
+	}
+	if rows[0].Group != "Muse" || rows[0].Song != "Uprising" || !reflect.DeepEqual(rows[0].Genres, []string{"rock"}) {
+		t.Errorf("rows[0] = %+v", rows[0])
+	}
+	if rows[1].Row != 2 || !strings.HasPrefix(rows[1].Err, "некорректная запись") {
+		t.Errorf("rows[1] = %+v, want ошибку записи 2", rows[1])
+	}
+	// Текст песни не обрезается
+	if rows[2].Row != 3 || rows[2].Text != "  Is this the real life?  " {
+		t.Errorf("rows[2] = %+v", rows[2])
+	}
+}
+
+func TestReadJSONMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "не массив", file: `{"group": "Muse"}`, wantErr: "ожидается JSON-массив"},
+		{name: "пустой файл", file: "", wantErr: "ожидается JSON-массив"},
+		{name: "обрыв записи", file: `[{"group": "Muse"}, {"group":`, wantErr: "в записи 2"},
+		{name: "нет закрывающей скобки", file: `[{"group": "Muse"}`, wantErr: "ошибка разбора JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.file), FormatJSON)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	const file = "\uFEFF{\"group\":\"Muse\",\"song\":\"Uprising\"}\n" +
+		"\n" +
+		"{\"group\":\"Queen\"\n" +
+		"  {\"group\":\"Queen\",\"song\":\"Bohemian Rhapsody\",\"tags\":[\"classic\"]}  \r\n"
+	rows, err := Read(strings.NewReader(file), FormatNDJSON)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %+v, want 3 записи", rows)
+	}
+	// Номера записей совпадают со строками файла, пустые строки учитываются
+	if rows[0].Row != 1 || rows[0].Group != "Muse" || rows[0].Err != "" {
+		t.Errorf("rows[0] = %+v", rows[0])
+	}
+	if rows[1].Row != 3 || rows[1].Err == "" {
+		t.Errorf("rows[1] = %+v, want ошибку в строке 3", rows[1])
+	}
+	if rows[2].Row != 4 || rows[2].Song != "Bohemian Rhapsody" || !reflect.DeepEqual(rows[2].Tags, []string{"classic"}) {
+		t.Errorf("rows[2] = %+v", rows[2])
+	}
+}
+
+func TestReadNDJSONLongLine(t *testing.T) {
+	text := strings.Repeat("а", 200*1024)
+	rows, err := Read(strings.NewReader(`{"group":"Muse","song":"Long","text":"`+text+`"}`), FormatNDJSON)
+	if err != nil || len(rows) != 1 || rows[0].Text != text {
+		t.Errorf("длинная строка: %d записей, %v", len(rows), err)
+	}
+}
+
+func TestReadUnknownFormat(t *testing.T) {
+	if _, err := Read(strings.NewReader(""), "xml"); err == nil {
+		t.Error("неизвестный формат прочитан без ошибки")
+	}
+}
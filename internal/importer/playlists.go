@@ -0,0 +1,263 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// Форматы плейлистов.
+const (
+	FormatM3U  = "m3u"
+	FormatXSPF = "xspf"
+)
+
+// DetectPlaylistFormat определяет формат плейлиста по расширению файла или типу содержимого.
+// Возвращает пустую строку, если формат не распознан.
+func DetectPlaylistFormat(filename, contentType string) string {
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".m3u", ".m3u8":
+		return FormatM3U
+	case ".xspf":
+		return FormatXSPF
+	}
+	switch {
+	case strings.Contains(contentType, "mpegurl"):
+		return FormatM3U
+	case strings.HasPrefix(contentType, "application/xspf+xml"):
+		return FormatXSPF
+	}
+	return ""
+}
+
+// ReadPlaylist разбирает плейлист M3U или XSPF в пары «группа — песня».
+// Записи, из которых их не удалось выделить, возвращаются с заполненным Err.
+func ReadPlaylist(r io.Reader, format string) ([]models.ImportRow, error) {
+	switch format {
+	case FormatM3U:
+		return readM3U(r)
+	case FormatXSPF:
+		return readXSPF(r)
+	}
+	return nil, fmt.Errorf("неизвестный формат плейлиста %q", format)
+}
+
+// readM3U читает M3U и M3U8. Группа и название берутся из строки
+// «#EXTINF:длительность,Исполнитель - Название», а без неё или недостающие — из имени файла записи.
+// Номер записи — номер строки #EXTINF или, если её нет, строки с адресом.
+func readM3U(r io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	rows := []models.ImportRow{}
+	var pending *models.ImportRow
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, string(utf8BOM))
+		}
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if pending != nil {
+				rows = append(rows, finishRow(*pending))
+			}
+			row := models.ImportRow{Row: n}
+			// Атрибуты вроде tvg-name="..." могут содержать запятые, поэтому название
+			// начинается после первой запятой вне кавычек
+			info := line[len("#EXTINF:"):]
+			if i := titleStart(info); i >= 0 {
+				row.Group, row.Song = splitArtistTitle(info[i:])
+				if row.Song == "" {
+					// Строка без разделителя — только название, группа возьмётся из имени файла
+					row.Group, row.Song = "", row.Group
+				}
+			}
+			pending = &row
+		case strings.HasPrefix(line, "#"):
+			// Прочие директивы (#EXTM3U, #PLAYLIST, #EXTGRP и т. п.) не нужны
+			continue
+		default:
+			row := models.ImportRow{Row: n}
+			if pending != nil {
+				row = *pending
+				pending = nil
+			}
+			row.Group, row.Song = fillFromLocation(row.Group, row.Song, line)
+			rows = append(rows, finishRow(row))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения M3U: %w", err)
+	}
+	if pending != nil {
+		rows = append(rows, finishRow(*pending))
+	}
+	return rows, nil
+}
+
+// titleStart возвращает начало названия в строке #EXTINF — позицию после первой запятой
+// вне кавычек, или -1, если запятой нет.
+func titleStart(info string) int {
+	quoted := false
+	for i, r := range info {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// xspfTrack — элемент track плейлиста XSPF, нужные для импорта поля.
+type xspfTrack struct {
+	Location []string `xml:"location"`
+	Title    string   `xml:"title"`
+	Creator  string   `xml:"creator"`
+}
+
+// readXSPF читает треки XSPF по одному. Если у трека нет creator или title,
+// группа и название берутся из имени файла в location.
+func readXSPF(r io.Reader) ([]models.ImportRow, error) {
+	dec := xml.NewDecoder(r)
+	rows := []models.ImportRow{}
+	for n := 1; ; {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка разбора XSPF: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "track" {
+			continue
+		}
+		var track xspfTrack
+		if err := dec.DecodeElement(&track, &start); err != nil {
+			return nil, fmt.Errorf("ошибка разбора XSPF в записи %d: %w", n, err)
+		}
+		row := models.ImportRow{Row: n, Group: strings.TrimSpace(track.Creator), Song: strings.TrimSpace(track.Title)}
+		if len(track.Location) > 0 {
+			row.Group, row.Song = fillFromLocation(row.Group, row.Song, track.Location[0])
+		}
+		rows = append(rows, finishRow(row))
+		n++
+	}
+}
+
+// FromFileName выделяет группу и название из имени файла вида «01 - Исполнитель - Название.mp3».
+// Если в имени нет разделителя, group пустой, а song — имя файла без расширения.
+func FromFileName(name string) (group, song string) {
+	return fillFromLocation("", "", name)
+}
+
+// fillFromLocation дополняет недостающие группу или название из имени файла:
+// оно может быть и «Исполнитель - Название», и просто «Название».
+func fillFromLocation(group, song, location string) (string, string) {
+	if group != "" && song != "" {
+		return group, song
+	}
+	fileGroup, fileSong := splitArtistTitle(locationTitle(location))
+	switch {
+	case fileSong == "":
+		// Имя без разделителя — это только название
+		return group, firstNonEmpty(song, fileGroup)
+	case group == "" && song == "":
+		return fileGroup, fileSong
+	case group == "":
+		return fileGroup, song
+	default:
+		return group, fileSong
+	}
+}
+
+// finishRow помечает запись, из которой не удалось выделить группу и название.
+func finishRow(row models.ImportRow) models.ImportRow {
+	if row.Err == "" && (row.Group == "" || row.Song == "") {
+		row.Err = "не удалось выделить группу и название песни, ожидается «Исполнитель - Название»"
+	}
+	return row
+}
+
+// titleSeparators — разделители исполнителя и названия; длинное и короткое тире
+// встречаются в плейлистах, сохранённых плеерами.
+var titleSeparators = []string{" - ", " – ", " — "}
+
+// splitArtistTitle делит «Исполнитель - Название» по первому разделителю.
+// Без разделителя вся строка возвращается как group, а song остаётся пустым.
+func splitArtistTitle(s string) (group, song string) {
+	s = strings.TrimSpace(s)
+	best := -1
+	var sep string
+	for _, candidate := range titleSeparators {
+		if i := strings.Index(s, candidate); i >= 0 && (best < 0 || i < best) {
+			best, sep = i, candidate
+		}
+	}
+	if best < 0 {
+		return s, ""
+	}
+	return strings.TrimSpace(s[:best]), strings.TrimSpace(s[best+len(sep):])
+}
+
+// locationTitle извлекает из адреса записи имя файла без расширения. Номер трека в начале
+// имени отбрасывается, только если после него остаются и исполнитель, и название:
+// «/music/01 - Muse - Hysteria.mp3» превращается в «Muse - Hysteria»,
+// а «50 Cent - In Da Club» и «311 - Amber» остаются как есть.
+func locationTitle(location string) string {
+	// Локальные пути не разбираются как URL: «#» и «%» в имени файла там обычные символы
+	if strings.Contains(location, "://") {
+		if u, err := url.Parse(location); err == nil {
+			location = u.Path
+		}
+	}
+	// Плейлисты из Windows используют обратную косую черту
+	location = strings.ReplaceAll(location, `\`, "/")
+	name := path.Base(location)
+	name = strings.TrimSpace(strings.TrimSuffix(name, path.Ext(name)))
+
+	if rest, ok := trimTrackNumber(name); ok {
+		if group, song := splitArtistTitle(rest); group != "" && song != "" {
+			return rest
+		}
+	}
+	return name
+}
+
+// trimTrackNumber отрезает номер трека в начале имени: «01 - », «01. » или «01 ».
+// Число, отделённое только пробелом, считается номером, лишь если начинается с нуля:
+// иначе это скорее часть имени исполнителя, как в «50 Cent» или «30 Seconds to Mars».
+func trimTrackNumber(name string) (string, bool) {
+	rest := strings.TrimLeftFunc(name, unicode.IsDigit)
+	if rest == name {
+		return "", false
+	}
+	for _, prefix := range append([]string{". "}, titleSeparators...) {
+		if strings.HasPrefix(rest, prefix) {
+			return strings.TrimSpace(rest[len(prefix):]), true
+		}
+	}
+	if strings.HasPrefix(rest, " ") && name[0] == '0' {
+		return strings.TrimSpace(rest), true
+	}
+	return "", false
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
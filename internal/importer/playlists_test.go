@@ -0,0 +1,176 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+func TestDetectPlaylistFormat(t *testing.T) {
+	tests := []struct {
+		filename, contentType string
+		want                  string
+	}{
+		{"list.m3u", "", FormatM3U},
+		{"list.M3U8", "", FormatM3U},
+		{"list.xspf", "", FormatXSPF},
+		{"", "audio/x-mpegurl", FormatM3U},
+		{"", "application/vnd.apple.mpegurl", FormatM3U},
+		{"", "application/xspf+xml", FormatXSPF},
+		{"list.txt", "text/plain", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectPlaylistFormat(tt.filename, tt.contentType); got != tt.want {
+			t.Errorf("DetectPlaylistFormat(%q, %q) = %q, want %q", tt.filename, tt.contentType, got, tt.want)
+		}
+	}
+}
+
+// checkRows сравнивает записи по номеру, группе, названию и наличию ошибки.
+func checkRows(t *testing.T, rows []models.ImportRow, want []models.ImportRow) {
+	t.Helper()
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %+v, want %d записей", rows, len(want))
+	}
+	for i, row := range rows {
+		w := want[i]
+		if row.Row != w.Row || row.Group != w.Group || row.Song != w.Song || (row.Err != "") != (w.Err != "") {
+			t.Errorf("rows[%d] = %+v, want %+v", i, row, w)
+		}
+	}
+}
+
+func TestReadM3U(t *testing.T) {
+	const file = "\uFEFF#EXTM3U\n" +
+		"#PLAYLIST:Rock\n" +
+		"#EXTINF:305,Muse - Uprising\n" +
+		"/music/Muse/01 - Uprising.mp3\n" +
+		"\n" +
+		"#EXTINF:-1 tvg-name=\"Queen, live\",Queen – Bohemian Rhapsody\n" +
+		"#EXTGRP:Classic\n" +
+		"http://example.com/stream.mp3\n" +
+		"C:\\Music\\02. Radiohead - Creep.flac\n" +
+		"https://example.com/music/Nirvana%20-%20Lithium.mp3?token=1#t=10\n" +
+		"#EXTINF:200,Только название\n" +
+		"/music/Pink Floyd - Time.mp3\n" +
+		"/music/#1 100% hits.mp3\n" +
+		"#EXTINF:180,Metallica — One\n"
+	rows, err := ReadPlaylist(strings.NewReader(file), FormatM3U)
+	if err != nil {
+		t.Fatalf("ReadPlaylist: %v", err)
+	}
+	checkRows(t, rows, []models.ImportRow{
+		{Row: 3, Group: "Muse", Song: "Uprising"},
+		// Запятая в кавычках атрибута не начинает название
+		{Row: 6, Group: "Queen", Song: "Bohemian Rhapsody"},
+		{Row: 9, Group: "Radiohead", Song: "Creep"},
+		{Row: 10, Group: "Nirvana", Song: "Lithium"},
+		// Группа из имени файла, название — из #EXTINF
+		{Row: 11, Group: "Pink Floyd", Song: "Только название"},
+		// Имя без разделителя — только название
+		{Row: 13, Song: "#1 100% hits", Err: "нет группы"},
+		// #EXTINF без адреса в конце файла
+		{Row: 14, Group: "Metallica", Song: "One"},
+	})
+}
+
+func TestReadM3UWithoutTitle(t *testing.T) {
+	rows, err := ReadPlaylist(strings.NewReader("#EXTINF:100\nstream\n"), FormatM3U)
+	if err != nil {
+		t.Fatalf("ReadPlaylist: %v", err)
+	}
+	checkRows(t, rows, []models.ImportRow{{Row: 1, Song: "stream", Err: "нет группы"}})
+
+	rows, err = ReadPlaylist(strings.NewReader(""), FormatM3U)
+	if err != nil || rows == nil || len(rows) != 0 {
+		t.Errorf("пустой M3U: rows = %v, %v, want пустой список", rows, err)
+	}
+}
+
+func TestReadXSPF(t *testing.T) {
+	const file = `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title>Rock</title>
+  <trackList>
+    <track>
+      <location>file:///music/Muse%20-%20Uprising.mp3</location>
+      <creator>Muse</creator>
+      <title> Uprising </title>
+    </track>
+    <track>
+      <location>file:///music/Queen%20-%20Bohemian%20Rhapsody.flac</location>
+      <location>http://example.com/other.mp3</location>
+    </track>
+    <track>
+      <location>file:///music/03%20Creep.mp3</location>
+      <creator>Radiohead</creator>
+    </track>
+    <track>
+      <title>Без исполнителя</title>
+    </track>
+  </trackList>
+</playlist>`
+	rows, err := ReadPlaylist(strings.NewReader(file), FormatXSPF)
+	if err != nil {
+		t.Fatalf("ReadPlaylist: %v", err)
+	}
+	checkRows(t, rows, []models.ImportRow{
+		{Row: 1, Group: "Muse", Song: "Uprising"},
+		{Row: 2, Group: "Queen", Song: "Bohemian Rhapsody"},
+		// Номер без исполнителя после него не отбрасывается
+		{Row: 3, Group: "Radiohead", Song: "03 Creep"},
+		{Row: 4, Song: "Без исполнителя", Err: "нет группы"},
+	})
+}
+
+func TestReadXSPFMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		wantErr string
+	}{
+		{name: "обрыв документа", file: `<playlist><trackList><track><title>x</title>`, wantErr: "в записи 1"},
+		{name: "неверная разметка", file: `<playlist><trackList></playlist>`, wantErr: "ошибка разбора XSPF"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadPlaylist(strings.NewReader(tt.file), FormatXSPF)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFromFileName(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantGroup string
+		wantSong  string
+	}{
+		{"01 - Muse - Hysteria.mp3", "Muse", "Hysteria"},
+		{"01. Muse - Hysteria.mp3", "Muse", "Hysteria"},
+		{"Muse – Hysteria.flac", "Muse", "Hysteria"},
+		{"Muse - Knights of Cydonia - Live.mp3", "Muse", "Knights of Cydonia - Live"},
+		{"Hysteria.mp3", "", "Hysteria"},
+		{"1979.mp3", "", "1979"},
+		{"AC-DC - Thunderstruck.mp3", "AC-DC", "Thunderstruck"},
+		{"01 Muse - Hysteria.mp3", "Muse", "Hysteria"},
+		{"07 – Muse – Hysteria.mp3", "Muse", "Hysteria"},
+		// Число в начале — часть имени исполнителя, а не номер трека
+		{"50 Cent - In Da Club.mp3", "50 Cent", "In Da Club"},
+		{"30 Seconds to Mars - Kings and Queens.mp3", "30 Seconds to Mars", "Kings and Queens"},
+		{"311 - Amber.mp3", "311", "Amber"},
+		{"10 Years - Wasteland.mp3", "10 Years", "Wasteland"},
+		// После номера нет исполнителя — имя остаётся как есть
+		{"01 - Hysteria.mp3", "01", "Hysteria"},
+		{"02 Hysteria.mp3", "", "02 Hysteria"},
+	}
+	for _, tt := range tests {
+		group, song := FromFileName(tt.name)
+		if group != tt.wantGroup || song != tt.wantSong {
+			t.Errorf("FromFileName(%q) = %q, %q, want %q, %q", tt.name, group, song, tt.wantGroup, tt.wantSong)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// Package lrc разбирает тексты песен в формате LRC: строки с отметками времени
+// вида [mm:ss.xx] и теги сведений вида [ar:Исполнитель].
+package lrc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Line — строка текста и момент, с которого она звучит.
+type Line struct {
+	Time time.Duration
+	Text string
+}
+
+// Lyrics — разобранный текст LRC.
+type Lyrics struct {
+	// Meta — теги сведений ([ar:], [ti:], [al:], [by:], [length:] и т. п.) с названиями в нижнем регистре.
+	Meta map[string]string
+	// Offset — сдвиг из тега [offset:] в миллисекундах; уже учтён во времени строк.
+	Offset time.Duration
+	// Lines — строки по возрастанию времени. Строка с несколькими отметками повторяется для каждой.
+	Lines []Line
+}
+
+var (
+	// timeTag — отметка времени: минуты, секунды и необязательные доли секунды через точку или двоеточие.
+	timeTag = regexp.MustCompile(`^(\d+):(\d{1,2})(?:[.:](\d{1,3}))?$`)
+	// metaTag — тег сведений: название из букв и значение после двоеточия.
+	metaTag = regexp.MustCompile(`^([A-Za-z#]+):(.*)$`)
+	// wordTag — отметка времени слова в расширенном LRC, например <00:12.34>.
+	wordTag = regexp.MustCompile(`<\d+:\d{1,2}(?:[.:]\d{1,3})?>`)
+)
+
+// errNoLines возвращается, если в тексте нет ни одной строки с отметкой времени.
+var errNoLines = errors.New("в тексте нет строк с отметками времени")
+
+// Parse разбирает текст LRC. Пустые строки пропускаются; строка без отметки времени
+// и без тега сведений, а также некорректная отметка — ошибка с номером строки.
+func Parse(text string) (Lyrics, error) {
+	text = strings.TrimPrefix(text, "\uFEFF")
+	lyrics := Lyrics{Meta: map[string]string{}}
+	for i, raw := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		n := i + 1
+		rest := strings.TrimSpace(raw)
+		if rest == "" {
+			continue
+		}
+
+		var times []time.Duration
+		for strings.HasPrefix(rest, "[") {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return Lyrics{}, fmt.Errorf("строка %d: не закрыта квадратная скобка", n)
+			}
+			tag := strings.TrimSpace(rest[1:end])
+			if m := timeTag.FindStringSubmatch(tag); m != nil {
+				t, err := parseTime(m)
+				if err != nil {
+					return Lyrics{}, fmt.Errorf("строка %d: %w", n, err)
+				}
+				times = append(times, t)
+			} else if m := metaTag.FindStringSubmatch(tag); m != nil && len(times) == 0 {
+				key, value := strings.ToLower(m[1]), strings.TrimSpace(m[2])
+				if key == "offset" {
+					ms, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+					if err != nil {
+						return Lyrics{}, fmt.Errorf("строка %d: некорректный сдвиг %q", n, value)
+					}
+					lyrics.Offset = time.Duration(ms) * time.Millisecond
+				}
+				lyrics.Meta[key] = value
+				// Тег сведений занимает всю строку
+				rest = ""
+				break
+			} else {
+				return Lyrics{}, fmt.Errorf("строка %d: некорректная метка [%s]", n, tag)
+			}
+			rest = strings.TrimSpace(rest[end+1:])
+		}
+		if len(times) == 0 {
+			if rest != "" {
+				return Lyrics{}, fmt.Errorf("строка %d: нет отметки времени", n)
+			}
+			continue
+		}
+
+		line := strings.TrimSpace(wordTag.ReplaceAllString(rest, ""))
+		for _, t := range times {
+			lyrics.Lines = append(lyrics.Lines, Line{Time: t, Text: line})
+		}
+	}
+	if len(lyrics.Lines) == 0 {
+		return Lyrics{}, errNoLines
+	}
+
+	// Положительный сдвиг означает, что строки должны появляться раньше
+	for i := range lyrics.Lines {
+		lyrics.Lines[i].Time = max(0, lyrics.Lines[i].Time-lyrics.Offset)
+	}
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].Time < lyrics.Lines[j].Time
+	})
+	return lyrics, nil
+}
+
+// parseTime переводит отметку времени, найденную timeTag, в длительность.
+// Одна цифра долей — десятые, две — сотые, три — тысячные секунды.
+func parseTime(m []string) (time.Duration, error) {
+	minutes, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, fmt.Errorf("некорректные минуты %q", m[1])
+	}
+	seconds, _ := strconv.Atoi(m[2])
+	if seconds >= 60 {
+		return 0, fmt.Errorf("некорректные секунды %q", m[2])
+	}
+	t := time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if fraction := m[3]; fraction != "" {
+		value, _ := strconv.Atoi(fraction)
+		for i := len(fraction); i < 3; i++ {
+			value *= 10
+		}
+		t += time.Duration(value) * time.Millisecond
+	}
+	return t, nil
+}
+
+// At возвращает индекс строки, которая звучит в момент position: последней строки,
+// время которой не позже position. До первой строки возвращает -1.
+func (l Lyrics) At(position time.Duration) int {
+	return sort.Search(len(l.Lines), func(i int) bool {
+		return l.Lines[i].Time > position
+	}) - 1
+}
+
+// Plain возвращает текст без отметок времени: строки по порядку, пустые строки
+// в начале и в конце отбрасываются.
+func (l Lyrics) Plain() string {
+	lines := make([]string, len(l.Lines))
+	for i, line := range l.Lines {
+		lines[i] = line.Text
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// FormatTime записывает момент в виде отметки LRC без скобок: mm:ss.xx.
+func FormatTime(t time.Duration) string {
+	centiseconds := t.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", centiseconds/6000, centiseconds/100%60, centiseconds%100)
+}
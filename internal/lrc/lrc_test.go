@@ -0,0 +1,176 @@
+package lrc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration {
+	return time.Duration(n) * time.Millisecond
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		wantLines  []Line
+		wantMeta   map[string]string
+		wantOffset time.Duration
+	}{
+		{
+			name:      "точность отметок",
+			text:      "[00:01]a\n[00:02.5]b\n[00:03.25]c\n[00:04.125]d\n[01:05:50]e",
+			wantLines: []Line{{ms(1000), "a"}, {ms(2500), "b"}, {ms(3250), "c"}, {ms(4125), "d"}, {ms(65500), "e"}},
+			wantMeta:  map[string]string{},
+		},
+		{
+			name:      "минуты больше часа",
+			text:      "[75:00.00]финал",
+			wantLines: []Line{{75 * time.Minute, "финал"}},
+			wantMeta:  map[string]string{},
+		},
+		{
+			name:      "несколько отметок у строки и сортировка",
+			text:      "[00:10.00][00:01.00]припев\n[00:05.00]куплет",
+			wantLines: []Line{{ms(1000), "припев"}, {ms(5000), "куплет"}, {ms(10000), "припев"}},
+			wantMeta:  map[string]string{},
+		},
+		{
+			name:      "теги сведений, BOM, CRLF и пустые строки",
+			text:      "\uFEFF[ar: Muse ]\r\n[TI:Uprising]\r\n\r\n[00:01.00] Первая строка \r\n[00:02.00]",
+			wantLines: []Line{{ms(1000), "Первая строка"}, {ms(2000), ""}},
+			wantMeta:  map[string]string{"ar": "Muse", "ti": "Uprising"},
+		},
+		{
+			name:       "положительный сдвиг не уводит время в минус",
+			text:       "[offset:+500]\n[00:00.20]a\n[00:01.00]b",
+			wantLines:  []Line{{0, "a"}, {ms(500), "b"}},
+			wantMeta:   map[string]string{"offset": "+500"},
+			wantOffset: ms(500),
+		},
+		{
+			name:       "отрицательный сдвиг",
+			text:       "[offset:-250]\n[00:01.00]a",
+			wantLines:  []Line{{ms(1250), "a"}},
+			wantMeta:   map[string]string{"offset": "-250"},
+			wantOffset: ms(-250),
+		},
+		{
+			name:      "отметки слов расширенного LRC",
+			text:      "[00:01.00]<00:01.00>Раз <00:01.50>два<00:02.00>",
+			wantLines: []Line{{ms(1000), "Раз два"}},
+			wantMeta:  map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lyrics, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(lyrics.Lines, tt.wantLines) {
+				t.Errorf("Lines = %v, want %v", lyrics.Lines, tt.wantLines)
+			}
+			if !reflect.DeepEqual(lyrics.Meta, tt.wantMeta) {
+				t.Errorf("Meta = %v, want %v", lyrics.Meta, tt.wantMeta)
+			}
+			if lyrics.Offset != tt.wantOffset {
+				t.Errorf("Offset = %v, want %v", lyrics.Offset, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestParseMalformed(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr string
+	}{
+		{name: "пустой текст", text: "", wantErr: errNoLines.Error()},
+		{name: "только теги сведений", text: "[ar:Muse]\n[ti:Uprising]", wantErr: errNoLines.Error()},
+		{name: "строка без отметки", text: "[00:01.00]a\nпросто текст", wantErr: "строка 2: нет отметки времени"},
+		{name: "незакрытая скобка", text: "[00:01.00]a\n\n[00:02.00", wantErr: "строка 3: не закрыта"},
+		{name: "секунды за 59", text: "[00:60.00]a", wantErr: "строка 1: некорректные секунды"},
+		{name: "слишком длинные доли", text: "[00:01.1234]a", wantErr: "строка 1: некорректная метка"},
+		{name: "буквы в отметке", text: "[0a:01]a", wantErr: "строка 1: некорректная метка"},
+		{name: "тег сведений после отметки", text: "[00:01.00][ar:Muse]a", wantErr: "строка 1: некорректная метка"},
+		{name: "некорректный сдвиг", text: "[offset:soon]\n[00:01.00]a", wantErr: "строка 1: некорректный сдвиг"},
+		{name: "пустая метка", text: "[]a", wantErr: "строка 1: некорректная метка"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := Parse("\n\n"); !errors.Is(err, errNoLines) {
+		t.Errorf("error = %v, want errNoLines", err)
+	}
+}
+
+func TestAt(t *testing.T) {
+	lyrics, err := Parse("[00:01.00]a\n[00:02.00]b\n[00:02.00]c\n[00:05.00]d")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		position time.Duration
+		want     int
+	}{
+		{0, -1},
+		{ms(999), -1},
+		{ms(1000), 0},
+		{ms(1999), 0},
+		// Из строк с одинаковым временем звучит последняя
+		{ms(2000), 2},
+		{ms(4999), 2},
+		{ms(5000), 3},
+		{time.Hour, 3},
+	}
+	for _, tt := range tests {
+		if got := lyrics.At(tt.position); got != tt.want {
+			t.Errorf("At(%v) = %d, want %d", tt.position, got, tt.want)
+		}
+	}
+	if got := (Lyrics{}).At(time.Second); got != -1 {
+		t.Errorf("At без строк = %d, want -1", got)
+	}
+}
+
+func TestPlain(t *testing.T) {
+	lyrics, err := Parse("[00:00.00]\n[00:01.00]a\n[00:02.00]\n[00:03.00]b\n[00:04.00]")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := lyrics.Plain(); got != "a\n\nb" {
+		t.Errorf("Plain = %q, want %q", got, "a\n\nb")
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		t    time.Duration
+		want string
+	}{
+		{0, "00:00.00"},
+		{ms(1239), "00:01.23"},
+		{ms(65500), "01:05.50"},
+		{75 * time.Minute, "75:00.00"},
+	}
+	for _, tt := range tests {
+		if got := FormatTime(tt.t); got != tt.want {
+			t.Errorf("FormatTime(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+		// Записанная отметка снова разбирается в то же время с точностью до сотых
+		lyrics, err := Parse("[" + FormatTime(tt.t) + "]x")
+		if err != nil || lyrics.Lines[0].Time != tt.t.Truncate(10*time.Millisecond) {
+			t.Errorf("Parse(FormatTime(%v)) = %v, %v", tt.t, lyrics.Lines, err)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/cache"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/utils"
+)
+
+var _ SongDetailsFetcher = (*DetailsCache)(nil)
+
+// DetailsCacheConfig — параметры кэша сведений о песнях.
+type DetailsCacheConfig struct {
+	// Size — сколько ответов держать в памяти; 0 отключает кэш в памяти.
+	Size int
+	// TTL — сколько хранить найденные сведения, NegativeTTL — ответ «песня не найдена».
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+// DetailsCache кэширует ответы внешнего API: сначала в памяти (LRU), затем, если задано
+// хранилище, в таблице, общей для всех процессов. Кэшируются и ответы 404, а ошибки
+// API — нет. Одновременные запросы одной песни объединяются в один запрос к API.
+type DetailsCache struct {
+	api    SongDetailsFetcher
+	store  repository.MetadataCacheRepository
+	config DetailsCacheConfig
+	lru    *cache.LRU[string, detailsEntry]
+	flight cache.Group[detailsEntry]
+
+	hits         atomic.Int64
+	storeHits    atomic.Int64
+	negativeHits atomic.Int64
+	misses       atomic.Int64
+	shared       atomic.Int64
+}
+
+// detailsEntry — ответ API в кэше: сведения или «не найдено» и срок годности.
+type detailsEntry struct {
+	detail    utils.SongDetail
+	notFound  bool
+	expiresAt time.Time
+}
+
+// DetailsCacheStats — счётчики кэша для страницы состояния.
+type DetailsCacheStats struct {
+	// Size и Capacity — занятые и все места в памяти, Evictions — вытесненные ответы.
+	Size      int
+	Capacity  int
+	Evictions int64
+	// Store — включено ли хранение в таблице.
+	Store bool
+	// Hits — ответы из кэша, из них StoreHits — из таблицы, NegativeHits — «не найдено».
+	Hits         int64
+	StoreHits    int64
+	NegativeHits int64
+	// Misses — запросы к API, Shared — вызовы, получившие ответ чужого запроса.
+	Misses int64
+	Shared int64
+}
+
+// NewDetailsCache создаёт кэш перед api; store может быть nil — тогда ответы хранятся только в памяти.
+func NewDetailsCache(api SongDetailsFetcher, store repository.MetadataCacheRepository, config DetailsCacheConfig) *DetailsCache {
+	return &DetailsCache{
+		api:    api,
+		store:  store,
+		config: config,
+		lru:    cache.NewLRU[string, detailsEntry](config.Size),
+	}
+}
+
+// FetchSongDetails возвращает сведения о песне из кэша или запрашивает их у API.
+func (c *DetailsCache) FetchSongDetails(ctx context.Context, group, song string) (*utils.SongDetail, error) {
+	key := detailsKey(group, song)
+	if entry, ok := c.lru.Get(key); ok {
+		if !entry.expired() {
+			c.hit(entry)
+			return result(entry, group, song)
+		}
+		// Пока мы проверяли срок, параллельная загрузка могла положить свежий ответ:
+		// удаляем запись, только если она всё ещё просрочена
+		c.lru.RemoveIf(key, detailsEntry.expired)
+	}
+
+	entry, shared, err := c.flight.Do(ctx, key, func(ctx context.Context) (detailsEntry, error) {
+		return c.load(ctx, key, group, song)
+	})
+	if shared {
+		c.shared.Add(1)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result(entry, group, song)
+}
+
+// load ищет ответ в таблице, а без него запрашивает API и сохраняет ответ в оба уровня.
+func (c *DetailsCache) load(ctx context.Context, key, group, song string) (detailsEntry, error) {
+	if c.store != nil {
+		cached, err := c.store.Get(key)
+		if err == nil {
+			entry := detailsEntry{
+				detail:    utils.SongDetail{ReleaseDate: cached.ReleaseDate, Text: cached.Text, Link: cached.Link},
+				notFound:  cached.NotFound,
+				expiresAt: time.Now().Add(cached.TTL),
+			}
+			c.hit(entry)
+			c.storeHits.Add(1)
+			c.lru.Add(key, entry)
+			return entry, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			log.Warnf("Кэш сведений о песнях в базе недоступен: %v", err)
+		}
+	}
+
+	c.misses.Add(1)
+	detail, err := c.api.FetchSongDetails(ctx, group, song)
+	entry, ttl := detailsEntry{}, c.config.TTL
+	switch {
+	case errors.Is(err, utils.ErrSongNotFound):
+		entry.notFound, ttl = true, c.config.NegativeTTL
+	case err != nil:
+		return detailsEntry{}, err
+	default:
+		entry.detail = *detail
+	}
+	entry.expiresAt = time.Now().Add(ttl)
+	c.lru.Add(key, entry)
+	if c.store != nil {
+		err := c.store.Put(models.CachedSongDetails{
+			Key:         key,
+			ReleaseDate: entry.detail.ReleaseDate,
+			Text:        entry.detail.Text,
+			Link:        entry.detail.Link,
+			NotFound:    entry.notFound,
+			TTL:         ttl,
+		})
+		if err != nil {
+			log.Warnf("Не удалось сохранить сведения о песне в кэш: %v", err)
+		}
+	}
+	return entry, nil
+}
+
+// expired сообщает, что срок годности записи истёк.
+func (e detailsEntry) expired() bool {
+	return !time.Now().Before(e.expiresAt)
+}
+
+func (c *DetailsCache) hit(entry detailsEntry) {
+	c.hits.Add(1)
+	if entry.notFound {
+		c.negativeHits.Add(1)
+	}
+}
+
+// result превращает запись кэша в ответ FetchSongDetails. Каждый вызов получает
+// свою копию сведений, чтобы правки вызвавшего не попали в кэш.
+func result(entry detailsEntry, group, song string) (*utils.SongDetail, error) {
+	if entry.notFound {
+		return nil, fmt.Errorf("%w: %s - %s", utils.ErrSongNotFound, group, song)
+	}
+	detail := entry.detail
+	return &detail, nil
+}
+
+// Stats возвращает счётчики кэша.
+func (c *DetailsCache) Stats() DetailsCacheStats {
+	return DetailsCacheStats{
+		Size:         c.lru.Len(),
+		Capacity:     c.lru.Capacity(),
+		Evictions:    c.lru.Evictions(),
+		Store:        c.store != nil,
+		Hits:         c.hits.Load(),
+		StoreHits:    c.storeHits.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		Misses:       c.misses.Load(),
+		Shared:       c.shared.Load(),
+	}
+}
+
+// detailsKey — ключ кэша: группа и название без учёта регистра и крайних пробелов.
+func detailsKey(group, song string) string {
+	return strings.ToLower(strings.TrimSpace(group)) + "\x00" + strings.ToLower(strings.TrimSpace(song))
+}
@@ -0,0 +1,184 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/repository/memory"
+	"github.com/EugeneKrivoshein/music_library/internal/utils"
+)
+
+// stubFetcher отвечает заданными сведениями или ошибкой и считает запросы.
+type stubFetcher struct {
+	calls  atomic.Int32
+	detail utils.SongDetail
+	err    error
+	// release, если задан, задерживает ответ до закрытия канала.
+	release chan struct{}
+}
+
+func (f *stubFetcher) FetchSongDetails(ctx context.Context, group, song string) (*utils.SongDetail, error) {
+	f.calls.Add(1)
+	if f.release != nil {
+		<-f.release
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	detail := f.detail
+	return &detail, nil
+}
+
+var uprising = utils.SongDetail{ReleaseDate: "16.07.2009", Text: "Куплет", Link: "https://example.com"}
+
+func fetch(t *testing.T, c *DetailsCache, group, song string) (*utils.SongDetail, error) {
+	t.Helper()
+	return c.FetchSongDetails(context.Background(), group, song)
+}
+
+func TestDetailsCacheHit(t *testing.T) {
+	api := &stubFetcher{detail: uprising}
+	c := NewDetailsCache(api, nil, DetailsCacheConfig{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	for _, group := range []string{"Muse", "  muse ", "MUSE"} {
+		detail, err := fetch(t, c, group, "Uprising")
+		if err != nil {
+			t.Fatalf("FetchSongDetails(%q): %v", group, err)
+		}
+		if *detail != uprising {
+			t.Errorf("FetchSongDetails(%q) = %+v", group, detail)
+		}
+	}
+	if api.calls.Load() != 1 {
+		t.Errorf("запросов к API = %d, want 1", api.calls.Load())
+	}
+	stats := c.Stats()
+	if stats.Hits != 2 || stats.Misses != 1 || stats.Size != 1 || stats.Store {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestDetailsCacheReturnsCopy(t *testing.T) {
+	c := NewDetailsCache(&stubFetcher{detail: uprising}, nil, DetailsCacheConfig{Size: 10, TTL: time.Hour})
+	detail, _ := fetch(t, c, "Muse", "Uprising")
+	detail.Text = "Изменено"
+	if again, _ := fetch(t, c, "Muse", "Uprising"); again.Text != uprising.Text {
+		t.Errorf("правка вызвавшего попала в кэш: %q", again.Text)
+	}
+}
+
+func TestDetailsCacheExpiry(t *testing.T) {
+	api := &stubFetcher{detail: uprising}
+	c := NewDetailsCache(api, nil, DetailsCacheConfig{Size: 10, TTL: 20 * time.Millisecond})
+
+	fetch(t, c, "Muse", "Uprising")
+	time.Sleep(30 * time.Millisecond)
+	if _, err := fetch(t, c, "Muse", "Uprising"); err != nil {
+		t.Fatalf("FetchSongDetails: %v", err)
+	}
+	if api.calls.Load() != 2 {
+		t.Errorf("запросов к API = %d, want 2: просроченный ответ не перезапрошен", api.calls.Load())
+	}
+	if c.Stats().Size != 1 {
+		t.Errorf("Size = %d, want 1", c.Stats().Size)
+	}
+}
+
+func TestDetailsCacheNegative(t *testing.T) {
+	api := &stubFetcher{err: fmt.Errorf("%w: 404", utils.ErrSongNotFound)}
+	c := NewDetailsCache(api, nil, DetailsCacheConfig{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	for i := 0; i < 2; i++ {
+		if _, err := fetch(t, c, "Muse", "Unknown"); !errors.Is(err, utils.ErrSongNotFound) {
+			t.Fatalf("error = %v, want ErrSongNotFound", err)
+		}
+	}
+	if api.calls.Load() != 1 {
+		t.Errorf("запросов к API = %d, want 1: 404 не закэширован", api.calls.Load())
+	}
+	if stats := c.Stats(); stats.NegativeHits != 1 {
+		t.Errorf("NegativeHits = %d, want 1", stats.NegativeHits)
+	}
+}
+
+func TestDetailsCacheErrorNotCached(t *testing.T) {
+	boom := errors.New("503 Service Unavailable")
+	api := &stubFetcher{err: boom}
+	c := NewDetailsCache(api, nil, DetailsCacheConfig{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour})
+
+	if _, err := fetch(t, c, "Muse", "Uprising"); !errors.Is(err, boom) {
+		t.Fatalf("error = %v, want сбой API", err)
+	}
+	api.err = nil
+	api.detail = uprising
+	if detail, err := fetch(t, c, "Muse", "Uprising"); err != nil || *detail != uprising {
+		t.Fatalf("после сбоя FetchSongDetails = %+v, %v", detail, err)
+	}
+	if api.calls.Load() != 2 {
+		t.Errorf("запросов к API = %d, want 2", api.calls.Load())
+	}
+}
+
+func TestDetailsCacheZeroSize(t *testing.T) {
+	api := &stubFetcher{detail: uprising}
+	c := NewDetailsCache(api, nil, DetailsCacheConfig{TTL: time.Hour})
+	fetch(t, c, "Muse", "Uprising")
+	fetch(t, c, "Muse", "Uprising")
+	if api.calls.Load() != 2 {
+		t.Errorf("запросов к API = %d, want 2: кэш в памяти отключён", api.calls.Load())
+	}
+}
+
+func TestDetailsCacheStore(t *testing.T) {
+	store := memory.NewMetadataCacheRepository(memory.NewStore())
+	api := &stubFetcher{detail: uprising}
+	config := DetailsCacheConfig{Size: 10, TTL: time.Hour, NegativeTTL: time.Hour}
+
+	first := NewDetailsCache(api, store, config)
+	fetch(t, first, "Muse", "Uprising")
+
+	// Другой процесс с пустой памятью находит ответ в таблице
+	second := NewDetailsCache(api, store, config)
+	detail, err := fetch(t, second, "Muse", "Uprising")
+	if err != nil || *detail != uprising {
+		t.Fatalf("FetchSongDetails = %+v, %v", detail, err)
+	}
+	if api.calls.Load() != 1 {
+		t.Errorf("запросов к API = %d, want 1", api.calls.Load())
+	}
+	if stats := second.Stats(); stats.StoreHits != 1 || stats.Misses != 0 || !stats.Store {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestDetailsCacheCollapsesConcurrentMisses(t *testing.T) {
+	api := &stubFetcher{detail: uprising, release: make(chan struct{})}
+	c := NewDetailsCache(api, nil, DetailsCacheConfig{Size: 10, TTL: time.Hour})
+
+	const n = 5
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			if _, err := fetch(t, c, "Muse", "Uprising"); err != nil {
+				t.Errorf("FetchSongDetails: %v", err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(api.release)
+	wg.Wait()
+
+	if api.calls.Load() != 1 {
+		t.Errorf("запросов к API = %d, want 1", api.calls.Load())
+	}
+	if stats := c.Stats(); stats.Shared != n-1 {
+		t.Errorf("Shared = %d, want %d", stats.Shared, n-1)
+	}
+}
@@ -0,0 +1,49 @@
+package metadata
+
+import (
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/config"
+)
+
+// Имена источников в настройке METADATA_PROVIDERS.
+const (
+	ProviderAPI      = "api"
+	ProviderFixtures = "fixtures"
+	ProviderManual   = "manual"
+)
+
+// NewChainFromConfig собирает цепочку источников в порядке cfg.MetadataProviders.
+// Источник api ходит во внешний API через api, fixtures читает каталог
+// cfg.MetadataFixtureDir.
+func NewChainFromConfig(cfg *config.Config, api SongDetailsFetcher) (*Chain, error) {
+	providers := make([]MetadataProvider, 0, len(cfg.MetadataProviders))
+	seen := map[string]bool{}
+	for _, name := range cfg.MetadataProviders {
+		if seen[name] {
+			return nil, fmt.Errorf("источник сведений %q указан дважды", name)
+		}
+		seen[name] = true
+		switch name {
+		case ProviderAPI:
+			providers = append(providers, NewHTTPProvider(api))
+		case ProviderFixtures:
+			if cfg.MetadataFixtureDir == "" {
+				return nil, fmt.Errorf("для источника %s не задан каталог METADATA_FIXTURE_DIR", ProviderFixtures)
+			}
+			fixtures, err := NewFixtureProvider(cfg.MetadataFixtureDir)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, fixtures)
+		case ProviderManual:
+			providers = append(providers, NewManualProvider())
+		default:
+			return nil, fmt.Errorf("неизвестный источник сведений %q: ожидается %s, %s или %s", name, ProviderAPI, ProviderFixtures, ProviderManual)
+		}
+	}
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("не задан ни один источник сведений о песнях")
+	}
+	return NewChain(providers...), nil
+}
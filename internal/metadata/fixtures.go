@@ -0,0 +1,138 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+	"gopkg.in/yaml.v3"
+)
+
+// fixture — запись о песне в файле каталога. В файле может быть одна запись или список.
+type fixture struct {
+	Group       string `json:"group" yaml:"group"`
+	Song        string `json:"song" yaml:"song"`
+	ReleaseDate string `json:"release_date" yaml:"release_date"`
+	Text        string `json:"text" yaml:"text"`
+	Link        string `json:"link" yaml:"link"`
+	// Confidence — уверенность в записи; без поля — 1.
+	Confidence *float64 `json:"confidence" yaml:"confidence"`
+}
+
+// FixtureProvider ищет сведения в каталоге файлов JSON и YAML (.json, .yaml, .yml).
+// Файлы читаются один раз при создании; группа и песня сравниваются после нормализации,
+// как названия групп в библиотеке.
+type FixtureProvider struct {
+	songs map[string]Details
+}
+
+// NewFixtureProvider читает все файлы каталога dir. Ошибка в любом файле — ошибка
+// создания с именем файла: молча пропущенная запись потом выглядела бы как неизвестная песня.
+func NewFixtureProvider(dir string) (*FixtureProvider, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка чтения каталога сведений о песнях: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	// Файлы читаются по имени, чтобы при повторах побеждала одна и та же запись
+	sort.Strings(names)
+
+	p := &FixtureProvider{songs: map[string]Details{}}
+	for _, name := range names {
+		ext := strings.ToLower(filepath.Ext(name))
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		fixtures, err := readFixtures(filepath.Join(dir, name), ext)
+		if err != nil {
+			return nil, fmt.Errorf("файл %s: %w", name, err)
+		}
+		for i, f := range fixtures {
+			if f.Group == "" || f.Song == "" {
+				return nil, fmt.Errorf("файл %s, запись %d: не указаны группа или название песни", name, i+1)
+			}
+			confidence := 1.0
+			if f.Confidence != nil {
+				confidence = *f.Confidence
+			}
+			if confidence < 0 || confidence > 1 {
+				return nil, fmt.Errorf("файл %s, запись %d: уверенность должна быть от 0 до 1", name, i+1)
+			}
+			key := fixtureKey(f.Group, f.Song)
+			if _, ok := p.songs[key]; ok {
+				log.Warnf("Каталог сведений %s: песня %s - %s повторяется в %s, используется первая запись", dir, f.Group, f.Song, name)
+				continue
+			}
+			p.songs[key] = Details{ReleaseDate: f.ReleaseDate, Text: f.Text, Link: f.Link, Confidence: confidence}
+		}
+	}
+	log.Infof("Каталог сведений %s загружен, песен: %d", dir, len(p.songs))
+	return p, nil
+}
+
+var _ MetadataProvider = (*FixtureProvider)(nil)
+
+func (p *FixtureProvider) Name() string {
+	return ProviderFixtures
+}
+
+func (p *FixtureProvider) Lookup(ctx context.Context, group, song string) (Details, error) {
+	details, ok := p.songs[fixtureKey(group, song)]
+	if !ok {
+		return Details{}, ErrNotFound
+	}
+	return details, nil
+}
+
+func fixtureKey(group, song string) string {
+	return normalize.Name(group) + "\x00" + normalize.Name(song)
+}
+
+// readFixtures разбирает файл с одной записью или списком записей.
+func readFixtures(path, ext string) ([]fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if ext == ".json" {
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+			var list []fixture
+			err = json.Unmarshal(data, &list)
+			return list, err
+		}
+		var single fixture
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, err
+		}
+		return []fixture{single}, nil
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	if len(node.Content) == 0 {
+		return nil, nil
+	}
+	if node.Content[0].Kind == yaml.SequenceNode {
+		var list []fixture
+		err = node.Decode(&list)
+		return list, err
+	}
+	var single fixture
+	if err := node.Decode(&single); err != nil {
+		return nil, err
+	}
+	return []fixture{single}, nil
+}
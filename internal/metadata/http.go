@@ -0,0 +1,49 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+
+	"github.com/EugeneKrivoshein/music_library/internal/utils"
+)
+
+// apiConfidence — уверенность в сведениях внешнего API: он ищет песню по точному названию.
+const apiConfidence = 1
+
+// SongDetailsFetcher получает сведения о песне по группе и названию из внешнего API.
+type SongDetailsFetcher interface {
+	FetchSongDetails(ctx context.Context, group, song string) (*utils.SongDetail, error)
+}
+
+var _ SongDetailsFetcher = (*utils.APIClient)(nil)
+
+// HTTPProvider запрашивает сведения у внешнего API (GET /info), обычно через кэш.
+type HTTPProvider struct {
+	api SongDetailsFetcher
+}
+
+func NewHTTPProvider(api SongDetailsFetcher) *HTTPProvider {
+	return &HTTPProvider{api: api}
+}
+
+var _ MetadataProvider = (*HTTPProvider)(nil)
+
+func (p *HTTPProvider) Name() string {
+	return ProviderAPI
+}
+
+func (p *HTTPProvider) Lookup(ctx context.Context, group, song string) (Details, error) {
+	detail, err := p.api.FetchSongDetails(ctx, group, song)
+	if errors.Is(err, utils.ErrSongNotFound) {
+		return Details{}, ErrNotFound
+	}
+	if err != nil {
+		return Details{}, err
+	}
+	return Details{
+		ReleaseDate: detail.ReleaseDate,
+		Text:        detail.Text,
+		Link:        detail.Link,
+		Confidence:  apiConfidence,
+	}, nil
+}
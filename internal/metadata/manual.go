@@ -0,0 +1,22 @@
+package metadata
+
+import "context"
+
+// ManualProvider ничего не ищет и отвечает пустыми сведениями с нулевой уверенностью.
+// Последним в цепочке он позволяет добавить песню, которую не знает ни один источник,
+// и заполнить её поля вручную.
+type ManualProvider struct{}
+
+func NewManualProvider() *ManualProvider {
+	return &ManualProvider{}
+}
+
+var _ MetadataProvider = (*ManualProvider)(nil)
+
+func (p *ManualProvider) Name() string {
+	return ProviderManual
+}
+
+func (p *ManualProvider) Lookup(ctx context.Context, group, song string) (Details, error) {
+	return Details{}, nil
+}
@@ -0,0 +1,91 @@
+// Package metadata ищет сведения о песнях — дату выпуска, текст и ссылку — в нескольких
+// источниках: внешнем API, каталоге локальных файлов или нигде (ручное заполнение).
+// Источники объединяются в цепочку, которая опрашивает их по порядку, а ответы внешнего
+// API кэшируются (DetailsCache).
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// ErrNotFound возвращается, если источник не знает песню.
+var ErrNotFound = errors.New("сведения о песне не найдены")
+
+// Details — сведения о песне из источника. Пустые поля источнику неизвестны.
+type Details struct {
+	// ReleaseDate — дата выпуска как её отдаёт источник: «16.07.2006» или «2006-07-16».
+	ReleaseDate string
+	Text        string
+	Link        string
+	// Confidence — уверенность источника в сведениях от 0 до 1.
+	Confidence float64
+	// Source — имя источника, который нашёл песню.
+	Source string
+}
+
+// MetadataProvider — источник сведений о песнях.
+type MetadataProvider interface {
+	// Name возвращает имя источника для журнала и настроек.
+	Name() string
+	// Lookup ищет песню группы. Если источник её не знает, возвращает ErrNotFound.
+	Lookup(ctx context.Context, group, song string) (Details, error)
+}
+
+// Chain опрашивает источники по порядку и возвращает сведения первого, кто знает песню.
+// Ошибка источника (например, недоступный API) не прерывает поиск: опрашивается следующий.
+// Но если после ошибки ответил только источник с нулевой уверенностью (manual), цепочка
+// возвращает ошибку: пустые сведения не должны выдавать временный сбой за успех.
+type Chain struct {
+	providers []MetadataProvider
+}
+
+func NewChain(providers ...MetadataProvider) *Chain {
+	return &Chain{providers: providers}
+}
+
+var _ MetadataProvider = (*Chain)(nil)
+
+func (c *Chain) Name() string {
+	return "chain"
+}
+
+func (c *Chain) Lookup(ctx context.Context, group, song string) (Details, error) {
+	var errs []error
+	for _, provider := range c.providers {
+		details, err := provider.Lookup(ctx, group, song)
+		if err == nil {
+			if details.Confidence == 0 && len(errs) > 0 {
+				break
+			}
+			details.Source = provider.Name()
+			return details, nil
+		}
+		if ctx.Err() != nil {
+			return Details{}, ctx.Err()
+		}
+		if !errors.Is(err, ErrNotFound) {
+			log.Warnf("Источник %s не ответил для %s - %s: %v", provider.Name(), group, song, err)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+		}
+	}
+	// Песню не нашёл никто: если кто-то из источников ещё и сломался, это важнее
+	if len(errs) > 0 {
+		return Details{}, errors.Join(errs...)
+	}
+	return Details{}, fmt.Errorf("%w: %s - %s", ErrNotFound, group, song)
+}
+
+// Providers возвращает имена источников цепочки по порядку.
+func (c *Chain) Providers() []string {
+	names := make([]string, 0, len(c.providers))
+	for _, provider := range c.providers {
+		names = append(names, provider.Name())
+	}
+	return names
+}
@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// stubProvider отвечает заданными сведениями или ошибкой.
+type stubProvider struct {
+	name    string
+	details Details
+	err     error
+}
+
+func (p stubProvider) Name() string {
+	return p.name
+}
+
+func (p stubProvider) Lookup(ctx context.Context, group, song string) (Details, error) {
+	return p.details, p.err
+}
+
+func TestChainLookup(t *testing.T) {
+	found := Details{Text: "Куплет", Confidence: 1}
+	notFound := stubProvider{name: "api", err: ErrNotFound}
+	broken := stubProvider{name: "api", err: errors.New("503 Service Unavailable")}
+	fixtures := stubProvider{name: "fixtures", details: found}
+
+	tests := []struct {
+		name       string
+		providers  []MetadataProvider
+		wantSource string
+		wantErr    error
+		wantInErr  string
+	}{
+		{
+			name:       "первый источник знает песню",
+			providers:  []MetadataProvider{stubProvider{name: "api", details: found}, fixtures},
+			wantSource: "api",
+		},
+		{
+			name:       "после 404 отвечает следующий",
+			providers:  []MetadataProvider{notFound, fixtures},
+			wantSource: "fixtures",
+		},
+		{
+			name:       "после сбоя отвечает уверенный источник",
+			providers:  []MetadataProvider{broken, fixtures},
+			wantSource: "fixtures",
+		},
+		{
+			name:       "после 404 manual отвечает пустыми сведениями",
+			providers:  []MetadataProvider{notFound, NewManualProvider()},
+			wantSource: ProviderManual,
+		},
+		{
+			name:      "сбой не прячется за manual",
+			providers: []MetadataProvider{broken, NewManualProvider()},
+			wantInErr: "api: 503 Service Unavailable",
+		},
+		{
+			name:      "никто не знает песню",
+			providers: []MetadataProvider{notFound, stubProvider{name: "fixtures", err: ErrNotFound}},
+			wantErr:   ErrNotFound,
+		},
+		{
+			name:      "сбой важнее 404",
+			providers: []MetadataProvider{broken, stubProvider{name: "fixtures", err: ErrNotFound}},
+			wantInErr: "503",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details, err := NewChain(tt.providers...).Lookup(context.Background(), "Muse", "Uprising")
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantInErr != "":
+				if err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), tt.wantInErr) {
+					t.Fatalf("error = %v, want сбой с %q", err, tt.wantInErr)
+				}
+			default:
+				if err != nil {
+					t.Fatalf("Lookup: %v", err)
+				}
+				if details.Source != tt.wantSource {
+					t.Errorf("Source = %q, want %q", details.Source, tt.wantSource)
+				}
+			}
+		})
+	}
+}
+
+func TestChainLookupCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	chain := NewChain(stubProvider{name: "api", err: context.Canceled}, NewManualProvider())
+	if _, err := chain.Lookup(ctx, "Muse", "Uprising"); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Типы альбомов.
+const (
+	AlbumTypeLP     = "LP"
+	AlbumTypeEP     = "EP"
+	AlbumTypeSingle = "single"
+)
+
+// ValidAlbumType сообщает, поддерживается ли тип альбома.
+func ValidAlbumType(t string) bool {
+	switch t {
+	case AlbumTypeLP, AlbumTypeEP, AlbumTypeSingle:
+		return true
+	}
+	return false
+}
+
+// Album — альбом группы.
+type Album struct {
+	ID          int        `db:"id"`
+	GroupID     int        `db:"group_id"`
+	GroupName   string     `db:"group_name"`
+	Title       string     `db:"title"`
+	ReleaseDate *time.Time `db:"release_date"`
+	Type        string     `db:"album_type"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+}
+
+// AlbumFilter задаёт условия отбора альбомов.
+type AlbumFilter struct {
+	// GroupID ограничивает выборку альбомами группы, 0 — все группы.
+	GroupID int
+	Title   string
+}
+
+// AlbumUpdate содержит изменяемые поля альбома, nil означает «не менять».
+type AlbumUpdate struct {
+	GroupID     *int
+	Title       *string
+	ReleaseDate *time.Time
+	Type        *string
+}
+
+// AlbumTrack — необязательная привязка песни к альбому.
+type AlbumTrack struct {
+	AlbumID     *int
+	TrackNumber *int
+	DiscNumber  *int
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// AudioFile — аудиофайл песни. Сам файл лежит в хранилище объектов под ключом StorageKey.
+type AudioFile struct {
+	SongID     int    `db:"song_id"`
+	StorageKey string `db:"storage_key"`
+	// FileName — имя файла, под которым его загрузили.
+	FileName string `db:"file_name"`
+	Format   string `db:"format"`
+	MimeType string `db:"mime_type"`
+	Size     int64  `db:"size_bytes"`
+	// Checksum — SHA-256 содержимого в шестнадцатеричном виде.
+	Checksum string `db:"checksum"`
+	// Duration — длительность по данным файла, 0 — не удалось определить.
+	Duration  time.Duration `db:"duration_ms"`
+	CreatedAt time.Time     `db:"created_at"`
+	UpdatedAt time.Time     `db:"updated_at"`
+}
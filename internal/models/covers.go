@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// CoverKind — владелец обложки: песня или группа.
+type CoverKind string
+
+const (
+	CoverKindSong  CoverKind = "song"
+	CoverKindGroup CoverKind = "group"
+)
+
+// Cover — обложка песни или группы. Исходное изображение лежит в хранилище объектов
+// под ключом StorageKey, миниатюры создаются по запросу рядом с ним.
+type Cover struct {
+	Kind       CoverKind `db:"-"`
+	OwnerID    int       `db:"-"`
+	StorageKey string    `db:"storage_key"`
+	MimeType   string    `db:"mime_type"`
+	Width      int       `db:"width"`
+	Height     int       `db:"height"`
+	Size       int64     `db:"size_bytes"`
+	// Checksum — SHA-256 исходного изображения в шестнадцатеричном виде.
+	Checksum  string    `db:"checksum"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+// GroupDuplicate — пара групп, похожих на дубликаты друг друга.
+type GroupDuplicate struct {
+	ID            int
+	Name          string
+	DuplicateID   int
+	DuplicateName string
+	// Similarity — сходство названий по триграммам (0..1).
+	Similarity float64
+	// SameNormalized — названия совпадают после нормализации (normalize.Name).
+	SameNormalized bool
+}
+
+// SongDuplicate — пара песен одной группы (или её дубликата), похожих на дубликаты.
+type SongDuplicate struct {
+	ID                 int
+	GroupName          string
+	SongName           string
+	DuplicateID        int
+	DuplicateGroupName string
+	DuplicateSongName  string
+	// Similarity — сходство названий песен по триграммам (0..1).
+	Similarity float64
+	// SameNormalized — названия песен совпадают без учёта регистра.
+	SameNormalized bool
+}
+
+// Duplicates — отчёт о найденных кандидатах в дубликаты.
+type Duplicates struct {
+	Groups []GroupDuplicate
+	Songs  []SongDuplicate
+}
@@ -0,0 +1,145 @@
+package models
+
+import (
+	"slices"
+	"time"
+)
+
+// EnrichmentStatus — состояние обогащения песни сведениями из внешних источников.
+type EnrichmentStatus string
+
+const (
+	// EnrichmentPending — песня ждёт задачи в очереди обогащения.
+	EnrichmentPending EnrichmentStatus = "pending"
+	// EnrichmentDone — сведения получены или песня не нуждается в обогащении.
+	EnrichmentDone EnrichmentStatus = "done"
+	// EnrichmentFailed — попытки обогащения исчерпаны.
+	EnrichmentFailed EnrichmentStatus = "failed"
+)
+
+// JobStatus — состояние задачи в очереди обогащения.
+type JobStatus string
+
+const (
+	// JobQueued — задача ждёт времени запуска RunAt.
+	JobQueued JobStatus = "queued"
+	// JobRunning — задачу выполняет обработчик.
+	JobRunning JobStatus = "running"
+	JobDone    JobStatus = "done"
+	// JobFailed — попытки исчерпаны или ошибка не исправится повтором.
+	JobFailed JobStatus = "failed"
+)
+
+// ValidJobStatus сообщает, известно ли состояние задачи.
+func ValidJobStatus(status JobStatus) bool {
+	switch status {
+	case JobQueued, JobRunning, JobDone, JobFailed:
+		return true
+	}
+	return false
+}
+
+// EnrichmentJob — задача обогащения песни. У песни не больше одной задачи:
+// повторная постановка в очередь перезапускает прежнюю.
+type EnrichmentJob struct {
+	ID     int       `db:"id"`
+	SongID int       `db:"song_id"`
+	Status JobStatus `db:"status"`
+	// Attempts — число начатых попыток.
+	Attempts int `db:"attempts"`
+	// RunAt — не раньше какого времени задачу можно брать.
+	RunAt time.Time `db:"run_at"`
+	// LockedAt — когда обработчик взял задачу; nil, если задача не выполняется.
+	LockedAt *time.Time `db:"locked_at"`
+	// LastError — ошибка последней неудачной попытки.
+	LastError string    `db:"last_error"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// EnrichmentJobFilter задаёт условия отбора задач обогащения; пустые поля не ограничивают выборку.
+type EnrichmentJobFilter struct {
+	Status JobStatus
+	SongID int
+}
+
+// Поля песни, которые заполняет обогащение.
+const (
+	SongFieldReleaseDate = "release_date"
+	SongFieldText        = "text"
+	SongFieldLink        = "link"
+)
+
+// SongFields перечисляет поля, которые заполняет обогащение.
+var SongFields = []string{SongFieldReleaseDate, SongFieldText, SongFieldLink}
+
+// FilledFields возвращает заполненные поля песни из SongFields.
+func (s Song) FilledFields() []string {
+	var fields []string
+	if s.ReleaseDate != nil {
+		fields = append(fields, SongFieldReleaseDate)
+	}
+	if s.Text != "" {
+		fields = append(fields, SongFieldText)
+	}
+	if s.Link != "" {
+		fields = append(fields, SongFieldLink)
+	}
+	return fields
+}
+
+// IsManual сообщает, введено ли поле вручную.
+func (s Song) IsManual(field string) bool {
+	return slices.Contains(s.ManualFields, field)
+}
+
+// ChangedFields возвращает поля из SongFields, которые меняет правка.
+func (u SongUpdate) ChangedFields() []string {
+	var fields []string
+	if u.ReleaseDate != nil {
+		fields = append(fields, SongFieldReleaseDate)
+	}
+	if u.Text != nil {
+		fields = append(fields, SongFieldText)
+	}
+	if u.Link != nil {
+		fields = append(fields, SongFieldLink)
+	}
+	return fields
+}
+
+// MergePolicy — как повторное обогащение поступает с уже заполненным полем песни.
+// Пустые поля заполняются при любой политике, кроме keep-manual для поля, очищенного вручную;
+// пустое значение источника поле не стирает.
+type MergePolicy string
+
+const (
+	// MergeKeepManual перезаписывает и заполняет поле, если оно не введено (или не очищено) вручную.
+	MergeKeepManual MergePolicy = "keep-manual"
+	// MergeFillBlank только заполняет пустое поле.
+	MergeFillBlank MergePolicy = "fill-blank"
+	// MergeOverwrite всегда перезаписывает поле значением источника.
+	MergeOverwrite MergePolicy = "overwrite"
+)
+
+// ValidMergePolicy сообщает, известна ли политика слияния.
+func ValidMergePolicy(policy MergePolicy) bool {
+	switch policy {
+	case MergeKeepManual, MergeFillBlank, MergeOverwrite:
+		return true
+	}
+	return false
+}
+
+// CachedSongDetails — ответ внешнего API о песне, сохранённый в кэше.
+type CachedSongDetails struct {
+	// Key — группа и название песни в нижнем регистре через нулевой байт.
+	Key         string `db:"key"`
+	ReleaseDate string `db:"release_date"`
+	Text        string `db:"text"`
+	Link        string `db:"link"`
+	// NotFound — API ответил, что песни нет (404); такой ответ тоже кэшируется.
+	NotFound bool `db:"not_found"`
+	// TTL — сколько запись ещё действительна.
+	TTL time.Duration
+}
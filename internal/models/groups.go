@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Group — музыкальная группа (исполнитель).
+type Group struct {
+	ID         int       `db:"id"`
+	Name       string    `db:"name"`
+	Country    string    `db:"country"`
+	FormedYear *int      `db:"formed_year"`
+	Bio        string    `db:"bio"`
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+	// SongCount — число песен группы, заполняется при чтении.
+	SongCount int `db:"song_count"`
+	// Aliases — прежние названия слитых в группу дубликатов, заполняется при чтении по ID.
+	Aliases []string `db:"-"`
+	// CoverChecksum — контрольная сумма обложки группы, пустая, если обложки нет.
+	CoverChecksum string `db:"cover_checksum"`
+}
+
+// GroupFilter задаёт условия отбора групп.
+type GroupFilter struct {
+	Name    string
+	Country string
+}
+
+// GroupUpdate содержит изменяемые поля группы, nil означает «не менять».
+type GroupUpdate struct {
+	Name       *string
+	Country    *string
+	FormedYear *int
+	Bio        *string
+}
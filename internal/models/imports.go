@@ -0,0 +1,69 @@
+package models
+
+// ImportRow — запись импортируемого файла. Поля, кроме Group и Song, необязательны.
+type ImportRow struct {
+	// Row — номер записи в файле, начиная с 1, без строки заголовка CSV.
+	Row         int
+	Group       string
+	Song        string
+	ReleaseDate string
+	Text        string
+	Link        string
+	Genres      []string
+	Tags        []string
+	// Err — причина, по которой запись не удалось разобрать; такая запись не импортируется.
+	Err string
+}
+
+// Итоги импорта отдельной записи.
+const (
+	ImportCreated = "created"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// ImportResult — итог импорта одной записи.
+type ImportResult struct {
+	Row    int
+	Group  string
+	Song   string
+	Status string
+	// SongID — ID добавленной песни или уже существующей, из-за которой запись пропущена.
+	SongID int
+	// NewGroup — для записи создана (или при пробном импорте будет создана) новая группа.
+	NewGroup bool
+	Reason   string
+}
+
+// ImportReport — отчёт об импорте файла по каждой записи.
+type ImportReport struct {
+	// DryRun — пробный импорт: Created означает «будет добавлена», ничего не сохранено.
+	DryRun  bool
+	Created int
+	Skipped int
+	Failed  int
+	Rows    []ImportResult
+}
+
+// Add учитывает итог записи в отчёте.
+func (r *ImportReport) Add(result ImportResult) {
+	switch result.Status {
+	case ImportCreated:
+		r.Created++
+	case ImportSkipped:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Rows = append(r.Rows, result)
+}
+
+// BatchResult — итог сохранения одной песни из пакета.
+type BatchResult struct {
+	// ID — ID добавленной песни или уже существующей, если Duplicate.
+	ID int
+	// Duplicate — у группы уже есть песня с таким названием, новая не добавлена.
+	Duplicate bool
+	// Err — ошибка сохранения этой песни; остальные песни пакета она не затрагивает.
+	Err error
+}
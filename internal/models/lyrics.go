@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// SongLyrics — синхронизированный текст песни в формате LRC, сохранённый как загружен.
+type SongLyrics struct {
+	SongID int    `db:"song_id"`
+	LRC    string `db:"lrc"`
+	// LineCount — число строк с отметками времени.
+	LineCount int       `db:"line_count"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
@@ -1,12 +1,113 @@
 package models
 
+import "time"
+
 // Song represents a song in the library.
 // @Description Представляет песню в библиотеке
 type Song struct {
-	ID          int    `db:"id"`
-	GroupName   string `db:"group_name"`
-	SongName    string `db:"song_name"`
-	ReleaseDate string `db:"release_date"`
-	Text        string `db:"text"`
-	Link        string `db:"link"`
+	ID          int        `db:"id"`
+	GroupID     int        `db:"group_id"`
+	GroupName   string     `db:"group_name"`
+	SongName    string     `db:"song_name"`
+	ReleaseDate *time.Time `db:"release_date"`
+	Text        string     `db:"text"`
+	Link        string     `db:"link"`
+	AlbumID     *int       `db:"album_id"`
+	AlbumTitle  string     `db:"album_title"`
+	TrackNumber *int       `db:"track_number"`
+	DiscNumber  *int       `db:"disc_number"`
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+	// Genres и Tags — названия жанров и тегов песни по алфавиту.
+	Genres []string `db:"-"`
+	Tags   []string `db:"-"`
+	// Score — степень сходства с запросом при нечётком поиске (0..1).
+	Score float64 `db:"score"`
+	// CoverChecksum и GroupCoverChecksum — контрольные суммы обложек песни и её группы,
+	// пустые, если обложки нет; заполняются при чтении.
+	CoverChecksum      string `db:"cover_checksum"`
+	GroupCoverChecksum string `db:"group_cover_checksum"`
+	// EnrichmentStatus — состояние обогащения; при создании пустое значение означает done,
+	// а pending сразу ставит песню в очередь обогащения.
+	EnrichmentStatus EnrichmentStatus `db:"enrichment_status"`
+	// EnrichedAt — когда песня последний раз получила сведения из источников; nil — ни разу.
+	EnrichedAt *time.Time `db:"enriched_at"`
+	// ManualFields — поля из SongFields, введённые вручную, по алфавиту.
+	ManualFields []string `db:"manual_fields"`
+}
+
+// SongFilter задаёт условия отбора песен.
+type SongFilter struct {
+	Group string
+	Song  string
+	// Fuzzy включает поиск по сходству триграмм вместо ILIKE.
+	Fuzzy bool
+	// MinScore — минимальное сходство для нечёткого поиска.
+	MinScore float64
+	// AlbumID ограничивает выборку песнями альбома; песни идут в порядке диска и трека.
+	AlbumID *int
+	// Genres и Tags ограничивают выборку песнями с этими жанрами и тегами.
+	Genres []string
+	Tags   []string
+	// MatchAll требует у песни все перечисленные жанры (и все теги), а не хотя бы один.
+	MatchAll bool
+	// Rules — правила умного плейлиста, проверенные при сохранении; nil — без правил.
+	Rules *SmartRule
+}
+
+// SongUpdate содержит изменяемые поля песни, nil означает «не менять».
+type SongUpdate struct {
+	GroupID     *int
+	SongName    *string
+	ReleaseDate *time.Time
+	Text        *string
+	Link        *string
+	AlbumID     *int
+	TrackNumber *int
+	DiscNumber  *int
+	// Author — автор правки текста, попадает в историю версий текста.
+	Author string
+	// Enrichment — правку сделало обогащение: изменённые поля перестают считаться
+	// введёнными вручную. Любая другая правка отмечает их как ручные.
+	Enrichment bool
+}
+
+// DateLayout — формат даты выпуска в API и базе данных.
+const DateLayout = "2006-01-02"
+
+// Verse — куплет песни с порядковым номером (начиная с 1).
+type Verse struct {
+	Index int
+	Text  string
+}
+
+// VersePage — страница куплетов песни.
+type VersePage struct {
+	SongID     int
+	Page       int
+	Limit      int
+	Total      int
+	TotalPages int
+	Verses     []Verse
+}
+
+// Конфигурации полнотекстового поиска PostgreSQL.
+const (
+	SearchLanguageAny     = ""
+	SearchLanguageRussian = "russian"
+	SearchLanguageEnglish = "english"
+)
+
+// SearchQuery — параметры полнотекстового поиска по песням.
+type SearchQuery struct {
+	Query string
+	// Language — конфигурация морфологии; пустое значение означает поиск сразу по обеим.
+	Language string
+}
+
+// SearchResult — найденная песня с релевантностью и фрагментом с подсветкой совпадений.
+type SearchResult struct {
+	Song     Song
+	Rank     float64
+	Headline string
 }
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Playlist — пользовательский плейлист.
+type Playlist struct {
+	ID          int       `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	// ItemCount — число песен в плейлисте, заполняется при чтении.
+	ItemCount int `db:"item_count"`
+}
+
+// PlaylistUpdate содержит изменяемые поля плейлиста, nil означает «не менять».
+type PlaylistUpdate struct {
+	Name        *string
+	Description *string
+}
+
+// PlaylistItem — песня на определённой позиции плейлиста.
+type PlaylistItem struct {
+	ID int `db:"id"`
+	// Position — номер элемента в плейлисте, начиная с 1, без пропусков.
+	Position int       `db:"position"`
+	AddedAt  time.Time `db:"added_at"`
+	Song     Song
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// TextRevision — сохранённая версия текста песни. Версия добавляется при каждом изменении текста,
+// поэтому последняя совпадает с текущим текстом песни.
+type TextRevision struct {
+	SongID int `db:"song_id"`
+	// Revision — номер версии у песни, начиная с 1.
+	Revision int    `db:"revision"`
+	Text     string `db:"text"`
+	// Author — автор правки, пустой — неизвестен (импорт, теги аудиофайла, старые тексты).
+	Author    string    `db:"author"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// TextDiff — разница между двумя версиями текста песни в едином формате.
+// Версия с номером 0 — пустой текст до первой версии.
+type TextDiff struct {
+	SongID int
+	From   TextRevision
+	To     TextRevision
+	// Diff — разница в формате diff -u; пустая, если тексты совпадают.
+	Diff string
+}
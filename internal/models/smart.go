@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Способы объединения правил в группе.
+const (
+	RuleMatchAll = "all"
+	RuleMatchAny = "any"
+)
+
+// Поля песни, доступные в правилах умных плейлистов.
+const (
+	RuleFieldGroup       = "group"
+	RuleFieldSong        = "song"
+	RuleFieldAlbum       = "album"
+	RuleFieldText        = "text"
+	RuleFieldReleaseDate = "release_date"
+	RuleFieldGenre       = "genre"
+	RuleFieldTag         = "tag"
+)
+
+// Операторы условий. Строки сравниваются без учёта регистра.
+const (
+	RuleOpEquals      = "eq"
+	RuleOpContains    = "contains"
+	RuleOpNotContains = "not_contains"
+	RuleOpStartsWith  = "starts_with"
+	// RuleOpLike — шаблон в синтаксисе ILIKE: % — любая подстрока, _ — один символ.
+	RuleOpLike    = "like"
+	RuleOpBefore  = "before"
+	RuleOpAfter   = "after"
+	RuleOpBetween = "between"
+	RuleOpIs      = "is"
+	RuleOpIsNot   = "is_not"
+)
+
+// SmartRule — правило умного плейлиста: либо условие на поле песни (Field, Op, Values),
+// либо группа вложенных правил (Match, Rules). Хранится в JSON.
+type SmartRule struct {
+	Match  string      `json:"match,omitempty"`
+	Rules  []SmartRule `json:"rules,omitempty"`
+	Field  string      `json:"field,omitempty"`
+	Op     string      `json:"op,omitempty"`
+	Values []string    `json:"values,omitempty"`
+}
+
+// IsGroup сообщает, что правило — группа вложенных правил, а не условие.
+func (r SmartRule) IsGroup() bool {
+	return r.Field == ""
+}
+
+// RuleDateRange разбирает дату из правила: год (1990) задаёт весь год,
+// дата в формате YYYY-MM-DD — один день. Возвращает первый и последний день.
+func RuleDateRange(value string) (from, to time.Time, err error) {
+	if year, err := time.Parse("2006", value); err == nil {
+		return year, year.AddDate(1, 0, -1), nil
+	}
+	day, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("ожидается год или дата в формате YYYY-MM-DD, получено %q", value)
+	}
+	return day, day, nil
+}
+
+// SmartPlaylist — плейлист, песни которого подбираются по правилам при чтении.
+type SmartPlaylist struct {
+	ID          int       `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	Rules       SmartRule `db:"rules"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+}
+
+// SmartPlaylistUpdate содержит изменяемые поля умного плейлиста, nil означает «не менять».
+type SmartPlaylistUpdate struct {
+	Name        *string
+	Description *string
+	Rules       *SmartRule
+}
@@ -0,0 +1,17 @@
+package models
+
+// TagKind — вид метки песни: жанр или произвольный тег.
+type TagKind string
+
+const (
+	TagKindGenre TagKind = "genre"
+	TagKindTag   TagKind = "tag"
+)
+
+// Tag — жанр или тег вместе с числом отмеченных им песен.
+type Tag struct {
+	ID        int     `db:"id"`
+	Kind      TagKind `db:"-"`
+	Name      string  `db:"name"`
+	SongCount int     `db:"song_count"`
+}
@@ -0,0 +1,22 @@
+package normalize
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Name приводит название к ключу для сравнения: Unicode NFC, свёртка регистра,
+// транслитерация кириллицы в латиницу, схлопнутые пробелы и без артикля «The» в начале.
+// Так «The Beatles» и « beatles», «Сплин» и «Splin» дают одинаковые ключи.
+func Name(s string) string {
+	s = norm.NFC.String(s)
+	s = cases.Fold().String(s)
+	s = ToLatin(s)
+	s = strings.Join(strings.Fields(s), " ")
+	if rest, ok := strings.CutPrefix(s, "the "); ok && rest != "" {
+		s = rest
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+package normalize
+
+import "testing"
+
+func TestName(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "артикль и регистр", a: "The Beatles", b: "beatles"},
+		{name: "пробелы", a: "  The   Rolling  Stones ", b: "rolling stones"},
+		{name: "NFD и NFC", a: "Bjo\u0308rk", b: "Bj\u00f6rk"},
+		{name: "свёртка регистра", a: "STRASSE", b: "straße"},
+		{name: "кириллица и латиница", a: "Сплин", b: "Splin"},
+		{name: "ё и е", a: "Ёлка", b: "елка"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a, b := Name(tt.a), Name(tt.b); a != b {
+				t.Errorf("Name(%q) = %q, Name(%q) = %q, ожидались одинаковые ключи", tt.a, a, tt.b, b)
+			}
+		})
+	}
+}
+
+func TestNameKeepsDistinct(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		// Название из одного артикля не сокращается до пустой строки
+		{in: "The", want: "the"},
+		{in: "The The", want: "the"},
+		{in: "Theatre of Tragedy", want: "theatre of tragedy"},
+		{in: "Кино", want: "kino"},
+	}
+	for _, tt := range tests {
+		if got := Name(tt.in); got != tt.want {
+			t.Errorf("Name(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// cyrToLat — упрощённая транслитерация кириллицы в латиницу.
+var cyrToLat = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye",
+}
+
+// latDigraphs — сочетания латинских букв, которые передают одну кириллическую.
+// Порядок важен: более длинные сочетания проверяются первыми.
+var latDigraphs = []struct {
+	lat string
+	cyr string
+}{
+	{"shch", "щ"}, {"sch", "щ"}, {"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"},
+	{"sh", "ш"}, {"yu", "ю"}, {"ya", "я"}, {"yo", "ё"}, {"ye", "е"},
+}
+
+// latToCyr — обратная транслитерация одиночных латинских букв.
+var latToCyr = map[rune]string{
+	'a': "а", 'b': "б", 'c': "к", 'd': "д", 'e': "е", 'f': "ф", 'g': "г", 'h': "х",
+	'i': "и", 'j': "й", 'k': "к", 'l': "л", 'm': "м", 'n': "н", 'o': "о", 'p': "п",
+	'q': "к", 'r': "р", 's': "с", 't': "т", 'u': "у", 'v': "в", 'w': "в", 'x': "кс",
+	'y': "ы", 'z': "з",
+}
+
+// ToLatin транслитерирует кириллицу в латиницу, остальные символы оставляет как есть.
+// Результат приводится к нижнему регистру.
+func ToLatin(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if lat, ok := cyrToLat[r]; ok {
+			b.WriteString(lat)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ToCyrillic транслитерирует латиницу в кириллицу, остальные символы оставляет как есть.
+// Результат приводится к нижнему регистру.
+func ToCyrillic(s string) string {
+	s = strings.ToLower(s)
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		matched := false
+		for _, d := range latDigraphs {
+			if strings.HasPrefix(s[i:], d.lat) {
+				b.WriteString(d.cyr)
+				i += len(d.lat)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if cyr, ok := latToCyr[r]; ok {
+			b.WriteString(cyr)
+		} else {
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// HasCyrillic сообщает, содержит ли строка кириллические буквы.
+func HasCyrillic(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Transliterate возвращает написание строки в другом алфавите: кириллицу переводит
+// в латиницу, всё остальное — в кириллицу. Нужна для поиска «Splin» по «Сплин».
+func Transliterate(s string) string {
+	if HasCyrillic(s) {
+		return ToLatin(s)
+	}
+	return ToCyrillic(s)
+}
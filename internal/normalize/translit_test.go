@@ -0,0 +1,53 @@
+package normalize
+
+import "testing"
+
+func TestToLatin(t *testing.T) {
+	tests := map[string]string{
+		"Сплин":        "splin",
+		"Щит и Жук":    "shchit i zhuk",
+		"Чайф":         "chayf",
+		"Мумий Тролль": "mumiy troll",
+		"Muse":         "muse",
+		"ДДТ 2000":     "ddt 2000",
+	}
+	for in, want := range tests {
+		if got := ToLatin(in); got != want {
+			t.Errorf("ToLatin(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestToCyrillic(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{name: "shch раньше sh и ch", in: "shchuka", want: "щука"},
+		{name: "sch раньше sh", in: "borsch", want: "борщ"},
+		{name: "sh", in: "shar", want: "шар"},
+		{name: "ch", in: "chaif", want: "чаиф"},
+		{name: "zh и kh", in: "zhukhov", want: "жухов"},
+		{name: "ts", in: "tsoi", want: "цои"},
+		{name: "ya и yu", in: "yablochko yula", want: "яблочко юла"},
+		{name: "регистр", in: "Splin", want: "сплин"},
+		{name: "x — две буквы", in: "Max", want: "макс"},
+		{name: "прочие символы", in: "DDT-2000!", want: "ддт-2000!"},
+		{name: "кириллица не меняется", in: "Кино", want: "кино"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToCyrillic(tt.in); got != tt.want {
+				t.Errorf("ToCyrillic(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransliterate(t *testing.T) {
+	if got := Transliterate("Сплин"); got != "splin" {
+		t.Errorf("Transliterate(Сплин) = %q", got)
+	}
+	if got := Transliterate("Splin"); got != "сплин" {
+		t.Errorf("Transliterate(Splin) = %q", got)
+	}
+}
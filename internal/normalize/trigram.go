@@ -0,0 +1,38 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Trigrams разбивает строку на триграммы так же, как pg_trgm: слова из букв и цифр
+// в нижнем регистре дополняются двумя пробелами в начале и одним в конце.
+func Trigrams(s string) map[string]struct{} {
+	set := map[string]struct{}{}
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Similarity повторяет функцию similarity из pg_trgm: отношение числа общих
+// триграмм к числу уникальных триграмм обеих строк.
+func Similarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	common := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
@@ -0,0 +1,46 @@
+package normalize
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTrigrams(t *testing.T) {
+	got := Trigrams("Muse!")
+	want := []string{"  m", " mu", "mus", "use", "se "}
+	if len(got) != len(want) {
+		t.Fatalf("Trigrams(Muse!) = %v, want %v", got, want)
+	}
+	for _, tr := range want {
+		if _, ok := got[tr]; !ok {
+			t.Errorf("нет триграммы %q в %v", tr, got)
+		}
+	}
+	if got := Trigrams(" !? "); len(got) != 0 {
+		t.Errorf("Trigrams без слов = %v, want пусто", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "Muse", b: "MUSE", want: 1},
+		{a: "AC/DC", b: "ac dc", want: 1},
+		// Общих триграмм 8 из 11 уникальных
+		{a: "Metallica", b: "Metalica", want: 8.0 / 11},
+		{a: "Сплин", b: "сплин", want: 1},
+		{a: "Muse", b: "Queen", want: 0},
+		{a: "", b: "Muse", want: 0},
+		{a: "...", b: "...", want: 0},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got, back := Similarity(tt.a, tt.b), Similarity(tt.b, tt.a); got != back {
+			t.Errorf("Similarity несимметрична: %v и %v", got, back)
+		}
+	}
+}
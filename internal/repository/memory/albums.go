@@ -0,0 +1,141 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// AlbumRepository хранит альбомы в памяти.
+type AlbumRepository struct {
+	store *Store
+}
+
+func NewAlbumRepository(store *Store) *AlbumRepository {
+	return &AlbumRepository{store: store}
+}
+
+var _ repository.AlbumRepository = (*AlbumRepository)(nil)
+
+func (r *AlbumRepository) List(filter models.AlbumFilter, limit, offset int) ([]models.Album, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	albums := []models.Album{}
+	for _, row := range r.store.albums {
+		if filter.GroupID != 0 && row.groupID != filter.GroupID {
+			continue
+		}
+		if !containsFold(row.title, filter.Title) {
+			continue
+		}
+		albums = append(albums, r.store.albumModel(row))
+	}
+	// Порядок как в PostgreSQL: по дате выпуска (без даты — в конце), затем по ID
+	sort.Slice(albums, func(i, j int) bool {
+		di, dj := albums[i].ReleaseDate, albums[j].ReleaseDate
+		if (di == nil) != (dj == nil) {
+			return dj == nil
+		}
+		if di != nil && !di.Equal(*dj) {
+			return di.Before(*dj)
+		}
+		return albums[i].ID < albums[j].ID
+	})
+	return paginate(albums, limit, offset), nil
+}
+
+func (r *AlbumRepository) Get(id int) (models.Album, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.albums[id]
+	if !ok {
+		return models.Album{}, repository.ErrNotFound
+	}
+	return r.store.albumModel(row), nil
+}
+
+func (r *AlbumRepository) Create(album models.Album) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.groups[album.GroupID]; !ok {
+		return 0, repository.ErrNotFound
+	}
+	now := time.Now()
+	r.store.nextAlbumID++
+	r.store.albums[r.store.nextAlbumID] = &albumRow{
+		id:          r.store.nextAlbumID,
+		groupID:     album.GroupID,
+		title:       album.Title,
+		releaseDate: copyTime(album.ReleaseDate),
+		albumType:   album.Type,
+		createdAt:   now,
+		updatedAt:   now,
+	}
+	return r.store.nextAlbumID, nil
+}
+
+func (r *AlbumRepository) Update(id int, update models.AlbumUpdate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.albums[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if update.GroupID != nil {
+		if _, ok := r.store.groups[*update.GroupID]; !ok {
+			return repository.ErrNotFound
+		}
+		row.groupID = *update.GroupID
+	}
+	if update.Title != nil && *update.Title != "" {
+		row.title = *update.Title
+	}
+	if update.ReleaseDate != nil {
+		row.releaseDate = copyTime(update.ReleaseDate)
+	}
+	if update.Type != nil {
+		row.albumType = *update.Type
+	}
+	row.updatedAt = time.Now()
+	return nil
+}
+
+func (r *AlbumRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.albums[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.albums, id)
+	// ON DELETE SET NULL
+	for _, song := range r.store.songs {
+		if song.albumID != nil && *song.albumID == id {
+			song.albumID = nil
+		}
+	}
+	return nil
+}
+
+// albumModel собирает модель альбома вместе с названием группы. Вызывается под блокировкой.
+func (s *Store) albumModel(row *albumRow) models.Album {
+	album := models.Album{
+		ID:          row.id,
+		GroupID:     row.groupID,
+		Title:       row.title,
+		ReleaseDate: copyTime(row.releaseDate),
+		Type:        row.albumType,
+		CreatedAt:   row.createdAt,
+		UpdatedAt:   row.updatedAt,
+	}
+	if group, ok := s.groups[row.groupID]; ok {
+		album.GroupName = group.name
+	}
+	return album
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// AudioRepository хранит сведения об аудиофайлах песен в памяти.
+type AudioRepository struct {
+	store *Store
+}
+
+func NewAudioRepository(store *Store) *AudioRepository {
+	return &AudioRepository{store: store}
+}
+
+var _ repository.AudioRepository = (*AudioRepository)(nil)
+
+func (r *AudioRepository) Get(songID int) (models.AudioFile, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	audio, ok := r.store.audio[songID]
+	if !ok {
+		return models.AudioFile{}, repository.ErrNotFound
+	}
+	return audio, nil
+}
+
+func (r *AudioRepository) Save(audio models.AudioFile) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.songs[audio.SongID]; !ok {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	audio.CreatedAt = now
+	if prev, ok := r.store.audio[audio.SongID]; ok {
+		audio.CreatedAt = prev.CreatedAt
+	}
+	audio.UpdatedAt = now
+	r.store.audio[audio.SongID] = audio
+	return nil
+}
+
+func (r *AudioRepository) Delete(songID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.audio[songID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.audio, songID)
+	return nil
+}
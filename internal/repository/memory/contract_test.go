@@ -0,0 +1,20 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/repository/repotest"
+)
+
+func TestContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		store := NewStore()
+		return repotest.Repos{
+			Songs:      NewSongRepository(store),
+			Groups:     NewGroupRepository(store),
+			Enrichment: NewEnrichmentRepository(store),
+			Tags:       NewTagRepository(store),
+			Playlists:  NewPlaylistRepository(store),
+		}
+	})
+}
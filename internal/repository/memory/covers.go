@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// CoverRepository хранит сведения об обложках в памяти.
+type CoverRepository struct {
+	store *Store
+}
+
+func NewCoverRepository(store *Store) *CoverRepository {
+	return &CoverRepository{store: store}
+}
+
+var _ repository.CoverRepository = (*CoverRepository)(nil)
+
+func (r *CoverRepository) Get(kind models.CoverKind, ownerID int) (models.Cover, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	cover, ok := r.store.covers[kind][ownerID]
+	if !ok {
+		return models.Cover{}, repository.ErrNotFound
+	}
+	return cover, nil
+}
+
+func (r *CoverRepository) Save(cover models.Cover) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.coverOwnerExists(cover.Kind, cover.OwnerID) {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	cover.CreatedAt = now
+	if prev, ok := r.store.covers[cover.Kind][cover.OwnerID]; ok {
+		cover.CreatedAt = prev.CreatedAt
+	}
+	cover.UpdatedAt = now
+	r.store.covers[cover.Kind][cover.OwnerID] = cover
+	return nil
+}
+
+func (r *CoverRepository) Delete(kind models.CoverKind, ownerID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.covers[kind][ownerID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.covers[kind], ownerID)
+	return nil
+}
+
+// coverOwnerExists сообщает, есть ли владелец обложки. Вызывается под блокировкой.
+func (s *Store) coverOwnerExists(kind models.CoverKind, ownerID int) bool {
+	switch kind {
+	case models.CoverKindSong:
+		_, ok := s.songs[ownerID]
+		return ok
+	case models.CoverKindGroup:
+		_, ok := s.groups[ownerID]
+		return ok
+	}
+	return false
+}
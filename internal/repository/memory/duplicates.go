@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+)
+
+func (r *GroupRepository) FindDuplicates(threshold float64, limit int) ([]models.GroupDuplicate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows := make([]*groupRow, 0, len(r.store.groups))
+	for _, row := range r.store.groups {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].id < rows[j].id })
+
+	duplicates := []models.GroupDuplicate{}
+	for i, a := range rows {
+		for _, b := range rows[i+1:] {
+			same := normalize.Name(a.name) == normalize.Name(b.name)
+			score := normalize.Similarity(a.name, b.name)
+			if !same && score < threshold {
+				continue
+			}
+			duplicates = append(duplicates, models.GroupDuplicate{
+				ID:             a.id,
+				Name:           a.name,
+				DuplicateID:    b.id,
+				DuplicateName:  b.name,
+				Similarity:     score,
+				SameNormalized: same,
+			})
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].SameNormalized != duplicates[j].SameNormalized {
+			return duplicates[i].SameNormalized
+		}
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+	return paginate(duplicates, limit, 0), nil
+}
+
+func (r *SongRepository) FindDuplicates(threshold float64, limit int) ([]models.SongDuplicate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	songs := make([]models.Song, 0, len(r.store.songs))
+	for _, row := range r.store.songs {
+		songs = append(songs, r.store.songModel(row))
+	}
+	sort.Slice(songs, func(i, j int) bool { return songs[i].ID < songs[j].ID })
+
+	duplicates := []models.SongDuplicate{}
+	for i, a := range songs {
+		for _, b := range songs[i+1:] {
+			if normalize.Name(a.GroupName) != normalize.Name(b.GroupName) {
+				continue
+			}
+			same := strings.ToLower(strings.TrimSpace(a.SongName)) == strings.ToLower(strings.TrimSpace(b.SongName))
+			score := normalize.Similarity(a.SongName, b.SongName)
+			if !same && score < threshold {
+				continue
+			}
+			duplicates = append(duplicates, models.SongDuplicate{
+				ID:                 a.ID,
+				GroupName:          a.GroupName,
+				SongName:           a.SongName,
+				DuplicateID:        b.ID,
+				DuplicateGroupName: b.GroupName,
+				DuplicateSongName:  b.SongName,
+				Similarity:         score,
+				SameNormalized:     same,
+			})
+		}
+	}
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		if duplicates[i].SameNormalized != duplicates[j].SameNormalized {
+			return duplicates[i].SameNormalized
+		}
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+	return paginate(duplicates, limit, 0), nil
+}
@@ -0,0 +1,249 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// EnrichmentRepository хранит очередь обогащения в памяти.
+type EnrichmentRepository struct {
+	store *Store
+}
+
+func NewEnrichmentRepository(store *Store) *EnrichmentRepository {
+	return &EnrichmentRepository{store: store}
+}
+
+var _ repository.EnrichmentRepository = (*EnrichmentRepository)(nil)
+
+// songJob возвращает задачу песни или nil. Вызывается под блокировкой.
+func (s *Store) songJob(songID int) *models.EnrichmentJob {
+	for _, job := range s.jobs {
+		if job.SongID == songID {
+			return job
+		}
+	}
+	return nil
+}
+
+// enqueueJob создаёт задачу песни или перезапускает прежнюю и ставит песне статус pending.
+// Вызывается под блокировкой.
+func (s *Store) enqueueJob(songID int) *models.EnrichmentJob {
+	now := time.Now()
+	job := s.songJob(songID)
+	if job == nil {
+		s.nextJobID++
+		job = &models.EnrichmentJob{ID: s.nextJobID, SongID: songID, CreatedAt: now}
+		s.jobs[job.ID] = job
+	}
+	job.Status = models.JobQueued
+	job.Attempts = 0
+	job.RunAt = now
+	job.LockedAt = nil
+	job.LastError = ""
+	job.UpdatedAt = now
+	s.songs[songID].enrichmentStatus = models.EnrichmentPending
+	return job
+}
+
+// finishJob завершает задачу со статусом status и переносит итог на песню. Вызывается под блокировкой.
+func (s *Store) finishJob(jobID int, status models.JobStatus, lastError string) (*models.EnrichmentJob, error) {
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	job.Status = status
+	job.LockedAt = nil
+	job.LastError = lastError
+	job.UpdatedAt = time.Now()
+	return job, nil
+}
+
+func (r *EnrichmentRepository) Enqueue(songID int) (models.EnrichmentJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.songs[songID]; !ok {
+		return models.EnrichmentJob{}, repository.ErrNotFound
+	}
+	if job := r.store.songJob(songID); job != nil && job.Status == models.JobRunning {
+		return models.EnrichmentJob{}, repository.ErrConflict
+	}
+	return copyJob(r.store.enqueueJob(songID)), nil
+}
+
+func (r *EnrichmentRepository) Claim(limit int, lease time.Duration) ([]models.EnrichmentJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	ready := []*models.EnrichmentJob{}
+	for _, job := range r.store.jobs {
+		if (job.Status == models.JobQueued && !job.RunAt.After(now)) ||
+			(job.Status == models.JobRunning && job.LockedAt != nil && job.LockedAt.Before(now.Add(-lease))) {
+			ready = append(ready, job)
+		}
+	}
+	sort.Slice(ready, func(i, j int) bool {
+		if !ready[i].RunAt.Equal(ready[j].RunAt) {
+			return ready[i].RunAt.Before(ready[j].RunAt)
+		}
+		return ready[i].ID < ready[j].ID
+	})
+
+	claimed := []models.EnrichmentJob{}
+	for _, job := range ready {
+		if len(claimed) == limit {
+			break
+		}
+		lockedAt := now
+		job.Status = models.JobRunning
+		job.Attempts++
+		job.LockedAt = &lockedAt
+		job.UpdatedAt = now
+		claimed = append(claimed, copyJob(job))
+	}
+	return claimed, nil
+}
+
+func (r *EnrichmentRepository) Complete(jobID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, err := r.store.finishJob(jobID, models.JobDone, "")
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	song := r.store.songs[job.SongID]
+	song.enrichmentStatus = models.EnrichmentDone
+	song.enrichedAt = &now
+	return nil
+}
+
+func (r *EnrichmentRepository) Retry(jobID int, delay time.Duration, lastError string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, err := r.store.finishJob(jobID, models.JobQueued, lastError)
+	if err != nil {
+		return err
+	}
+	job.RunAt = time.Now().Add(delay)
+	return nil
+}
+
+func (r *EnrichmentRepository) Fail(jobID int, lastError string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	job, err := r.store.finishJob(jobID, models.JobFailed, lastError)
+	if err != nil {
+		return err
+	}
+	r.store.songs[job.SongID].enrichmentStatus = models.EnrichmentFailed
+	return nil
+}
+
+func (r *EnrichmentRepository) Get(jobID int) (models.EnrichmentJob, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, ok := r.store.jobs[jobID]
+	if !ok {
+		return models.EnrichmentJob{}, repository.ErrNotFound
+	}
+	return copyJob(job), nil
+}
+
+func (r *EnrichmentRepository) List(filter models.EnrichmentJobFilter, limit, offset int) ([]models.EnrichmentJob, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	jobs := []models.EnrichmentJob{}
+	for _, job := range r.store.jobs {
+		if (filter.Status == "" || job.Status == filter.Status) && (filter.SongID == 0 || job.SongID == filter.SongID) {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if !jobs[i].UpdatedAt.Equal(jobs[j].UpdatedAt) {
+			return jobs[i].UpdatedAt.After(jobs[j].UpdatedAt)
+		}
+		return jobs[i].ID > jobs[j].ID
+	})
+	return paginate(jobs, limit, offset), nil
+}
+
+func (r *EnrichmentRepository) RequeueFailed() (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	n := 0
+	for _, job := range r.store.jobs {
+		if job.Status == models.JobFailed {
+			r.store.enqueueJob(job.SongID)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *EnrichmentRepository) EnqueueStale(maxAge, blankAge time.Duration, limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	stale := []*songRow{}
+	for _, row := range r.store.songs {
+		if row.enrichmentStatus != models.EnrichmentDone {
+			continue
+		}
+		blank := row.text == "" || row.link == ""
+		if row.enrichedAt == nil {
+			if blank {
+				stale = append(stale, row)
+			}
+			continue
+		}
+		if row.enrichedAt.Before(now.Add(-maxAge)) || (blank && row.enrichedAt.Before(now.Add(-blankAge))) {
+			stale = append(stale, row)
+		}
+	}
+	// Сначала неполные песни, которые ни разу не обогащались, затем по давности
+	sort.Slice(stale, func(i, j int) bool {
+		a, b := stale[i].enrichedAt, stale[j].enrichedAt
+		switch {
+		case a == nil || b == nil:
+			if (a == nil) != (b == nil) {
+				return a == nil
+			}
+		case !a.Equal(*b):
+			return a.Before(*b)
+		}
+		return stale[i].id < stale[j].id
+	})
+
+	n := 0
+	for _, row := range stale {
+		if n == limit {
+			break
+		}
+		if job := r.store.songJob(row.id); job != nil && job.Status == models.JobRunning {
+			continue
+		}
+		r.store.enqueueJob(row.id)
+		n++
+	}
+	return n, nil
+}
+
+// copyJob копирует задачу, чтобы вызывающий не менял данные хранилища.
+func copyJob(job *models.EnrichmentJob) models.EnrichmentJob {
+	result := *job
+	result.LockedAt = copyTime(job.LockedAt)
+	return result
+}
@@ -0,0 +1,313 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// similarityThreshold — порог оператора % в pg_trgm по умолчанию.
+const similarityThreshold = 0.3
+
+// GroupRepository хранит группы в памяти.
+type GroupRepository struct {
+	store *Store
+}
+
+func NewGroupRepository(store *Store) *GroupRepository {
+	return &GroupRepository{store: store}
+}
+
+var _ repository.GroupRepository = (*GroupRepository)(nil)
+
+func (r *GroupRepository) List(filter models.GroupFilter, limit, offset int) ([]models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	groups := []models.Group{}
+	for _, row := range r.store.groups {
+		if !containsFold(row.name, filter.Name) {
+			continue
+		}
+		if filter.Country != "" && !strings.EqualFold(row.country, filter.Country) {
+			continue
+		}
+		groups = append(groups, r.store.groupModel(row))
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		return groups[i].ID < groups[j].ID
+	})
+	return paginate(groups, limit, offset), nil
+}
+
+func (r *GroupRepository) Get(id int) (models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.groups[id]
+	if !ok {
+		return models.Group{}, repository.ErrNotFound
+	}
+	group := r.store.groupModel(row)
+	group.Aliases = []string{}
+	for alias, groupID := range r.store.aliases {
+		if groupID == id {
+			group.Aliases = append(group.Aliases, alias)
+		}
+	}
+	sort.Strings(group.Aliases)
+	return group, nil
+}
+
+func (r *GroupRepository) FindByName(name string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	// Приоритеты те же, что в PostgreSQL: точное совпадение, нормализованное название, псевдоним
+	for _, row := range r.store.groups {
+		if row.name == name {
+			return row.id, nil
+		}
+	}
+	key := normalize.Name(name)
+	match := 0
+	for _, row := range r.store.groups {
+		if normalize.Name(row.name) == key && (match == 0 || row.id < match) {
+			match = row.id
+		}
+	}
+	if match != 0 {
+		return match, nil
+	}
+	for alias, groupID := range r.store.aliases {
+		if normalize.Name(alias) == key && (match == 0 || groupID < match) {
+			match = groupID
+		}
+	}
+	if match != 0 {
+		return match, nil
+	}
+	return 0, repository.ErrNotFound
+}
+
+func (r *GroupRepository) FindSimilar(name string, limit int) ([]models.Group, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type scored struct {
+		group models.Group
+		score float64
+	}
+	candidates := []scored{}
+	for _, row := range r.store.groups {
+		if score := normalize.Similarity(row.name, name); score >= similarityThreshold {
+			candidates = append(candidates, scored{group: r.store.groupModel(row), score: score})
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].score != candidates[j].score {
+			return candidates[i].score > candidates[j].score
+		}
+		return candidates[i].group.ID < candidates[j].group.ID
+	})
+
+	groups := []models.Group{}
+	for _, c := range paginate(candidates, limit, 0) {
+		groups = append(groups, c.group)
+	}
+	return groups, nil
+}
+
+func (r *GroupRepository) Create(group models.Group) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, row := range r.store.groups {
+		if row.name == group.Name {
+			return 0, repository.ErrConflict
+		}
+	}
+	now := time.Now()
+	r.store.nextGroupID++
+	r.store.groups[r.store.nextGroupID] = &groupRow{
+		id:         r.store.nextGroupID,
+		name:       group.Name,
+		country:    group.Country,
+		formedYear: copyInt(group.FormedYear),
+		bio:        group.Bio,
+		createdAt:  now,
+		updatedAt:  now,
+	}
+	return r.store.nextGroupID, nil
+}
+
+func (r *GroupRepository) Update(id int, update models.GroupUpdate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.groups[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if update.Name != nil && *update.Name != "" {
+		for _, other := range r.store.groups {
+			if other.id != id && other.name == *update.Name {
+				return repository.ErrConflict
+			}
+		}
+		row.name = *update.Name
+	}
+	if update.Country != nil {
+		row.country = *update.Country
+	}
+	if update.FormedYear != nil {
+		row.formedYear = copyInt(update.FormedYear)
+	}
+	if update.Bio != nil {
+		row.bio = *update.Bio
+	}
+	row.updatedAt = time.Now()
+	return nil
+}
+
+func (r *GroupRepository) SongIDs(groupID int) ([]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	ids := []int{}
+	for id, song := range r.store.songs {
+		if song.groupID == groupID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (r *GroupRepository) Delete(id int, cascade bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.groups[id]; !ok {
+		return repository.ErrNotFound
+	}
+	if !cascade && r.store.groupHasDependents(id) {
+		return repository.ErrConflict
+	}
+	// ON DELETE CASCADE для песен и альбомов
+	for songID, song := range r.store.songs {
+		if song.groupID == id {
+			r.store.deleteSong(songID)
+		}
+	}
+	for albumID, album := range r.store.albums {
+		if album.groupID == id {
+			delete(r.store.albums, albumID)
+		}
+	}
+	for alias, groupID := range r.store.aliases {
+		if groupID == id {
+			delete(r.store.aliases, alias)
+		}
+	}
+	delete(r.store.covers[models.CoverKindGroup], id)
+	delete(r.store.groups, id)
+	return nil
+}
+
+func (r *GroupRepository) Merge(sourceID, targetID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	source, ok := r.store.groups[sourceID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	target, ok := r.store.groups[targetID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+
+	now := time.Now()
+	for _, song := range r.store.songs {
+		if song.groupID == sourceID {
+			song.groupID = targetID
+			song.updatedAt = now
+		}
+	}
+	for _, album := range r.store.albums {
+		if album.groupID == sourceID {
+			album.groupID = targetID
+			album.updatedAt = now
+		}
+	}
+	for alias, groupID := range r.store.aliases {
+		if groupID == sourceID {
+			r.store.aliases[alias] = targetID
+		}
+	}
+	r.store.aliases[source.name] = targetID
+
+	// Незаполненные сведения о группе берём у источника
+	if target.country == "" {
+		target.country = source.country
+	}
+	if target.formedYear == nil {
+		target.formedYear = copyInt(source.formedYear)
+	}
+	if target.bio == "" {
+		target.bio = source.bio
+	}
+	target.updatedAt = now
+
+	delete(r.store.covers[models.CoverKindGroup], sourceID)
+	delete(r.store.groups, sourceID)
+	return nil
+}
+
+// BackfillNormalized ничего не делает: в памяти нормализованные названия вычисляются при поиске.
+func (r *GroupRepository) BackfillNormalized() (int, error) {
+	return 0, nil
+}
+
+// groupHasDependents сообщает, есть ли у группы песни или альбомы. Вызывается под блокировкой.
+func (s *Store) groupHasDependents(id int) bool {
+	for _, song := range s.songs {
+		if song.groupID == id {
+			return true
+		}
+	}
+	for _, album := range s.albums {
+		if album.groupID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// groupModel собирает модель группы с числом песен. Вызывается под блокировкой.
+func (s *Store) groupModel(row *groupRow) models.Group {
+	group := models.Group{
+		ID:            row.id,
+		Name:          row.name,
+		Country:       row.country,
+		FormedYear:    copyInt(row.formedYear),
+		Bio:           row.bio,
+		CreatedAt:     row.createdAt,
+		UpdatedAt:     row.updatedAt,
+		CoverChecksum: s.covers[models.CoverKindGroup][row.id].Checksum,
+	}
+	for _, song := range s.songs {
+		if song.groupID == row.id {
+			group.SongCount++
+		}
+	}
+	return group
+}
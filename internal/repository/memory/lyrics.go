@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// LyricsRepository хранит синхронизированные тексты песен в памяти.
+type LyricsRepository struct {
+	store *Store
+}
+
+func NewLyricsRepository(store *Store) *LyricsRepository {
+	return &LyricsRepository{store: store}
+}
+
+var _ repository.LyricsRepository = (*LyricsRepository)(nil)
+
+func (r *LyricsRepository) Get(songID int) (models.SongLyrics, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	lyrics, ok := r.store.lyrics[songID]
+	if !ok {
+		return models.SongLyrics{}, repository.ErrNotFound
+	}
+	return lyrics, nil
+}
+
+func (r *LyricsRepository) Save(lyrics models.SongLyrics) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.songs[lyrics.SongID]; !ok {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	lyrics.CreatedAt = now
+	if prev, ok := r.store.lyrics[lyrics.SongID]; ok {
+		lyrics.CreatedAt = prev.CreatedAt
+	}
+	lyrics.UpdatedAt = now
+	r.store.lyrics[lyrics.SongID] = lyrics
+	return nil
+}
+
+func (r *LyricsRepository) Delete(songID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.lyrics[songID]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.lyrics, songID)
+	return nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// MetadataCacheRepository хранит кэш ответов внешнего API в памяти.
+type MetadataCacheRepository struct {
+	store *Store
+}
+
+func NewMetadataCacheRepository(store *Store) *MetadataCacheRepository {
+	return &MetadataCacheRepository{store: store}
+}
+
+var _ repository.MetadataCacheRepository = (*MetadataCacheRepository)(nil)
+
+// cachedDetails — запись кэша и момент, когда она устареет.
+type cachedDetails struct {
+	entry     models.CachedSongDetails
+	expiresAt time.Time
+}
+
+func (r *MetadataCacheRepository) Get(key string) (models.CachedSongDetails, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	cached, ok := r.store.metadataCache[key]
+	ttl := time.Until(cached.expiresAt)
+	if !ok || ttl <= 0 {
+		return models.CachedSongDetails{}, repository.ErrNotFound
+	}
+	entry := cached.entry
+	entry.TTL = ttl
+	return entry, nil
+}
+
+func (r *MetadataCacheRepository) Put(entry models.CachedSongDetails) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.metadataCache[entry.Key] = cachedDetails{entry: entry, expiresAt: time.Now().Add(entry.TTL)}
+	return nil
+}
+
+func (r *MetadataCacheRepository) DeleteExpired() (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	n := 0
+	for key, cached := range r.store.metadataCache {
+		if !cached.expiresAt.After(now) {
+			delete(r.store.metadataCache, key)
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,212 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// PlaylistRepository хранит плейлисты в памяти.
+type PlaylistRepository struct {
+	store *Store
+}
+
+func NewPlaylistRepository(store *Store) *PlaylistRepository {
+	return &PlaylistRepository{store: store}
+}
+
+var _ repository.PlaylistRepository = (*PlaylistRepository)(nil)
+
+func (r *PlaylistRepository) List(limit, offset int) ([]models.Playlist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	playlists := make([]models.Playlist, 0, len(r.store.playlists))
+	for _, row := range r.store.playlists {
+		playlists = append(playlists, playlistModel(row))
+	}
+	sort.Slice(playlists, func(i, j int) bool {
+		if playlists[i].Name != playlists[j].Name {
+			return playlists[i].Name < playlists[j].Name
+		}
+		return playlists[i].ID < playlists[j].ID
+	})
+	return paginate(playlists, limit, offset), nil
+}
+
+func (r *PlaylistRepository) Get(id int) (models.Playlist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.playlists[id]
+	if !ok {
+		return models.Playlist{}, repository.ErrNotFound
+	}
+	return playlistModel(row), nil
+}
+
+func (r *PlaylistRepository) Create(playlist models.Playlist) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	r.store.nextPlaylistID++
+	r.store.playlists[r.store.nextPlaylistID] = &playlistRow{
+		id:          r.store.nextPlaylistID,
+		name:        playlist.Name,
+		description: playlist.Description,
+		createdAt:   now,
+		updatedAt:   now,
+	}
+	return r.store.nextPlaylistID, nil
+}
+
+func (r *PlaylistRepository) Update(id int, update models.PlaylistUpdate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.playlists[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if update.Name != nil && *update.Name != "" {
+		row.name = *update.Name
+	}
+	if update.Description != nil {
+		row.description = *update.Description
+	}
+	row.updatedAt = time.Now()
+	return nil
+}
+
+func (r *PlaylistRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.playlists[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.playlists, id)
+	return nil
+}
+
+func (r *PlaylistRepository) Items(playlistID, limit, offset int) ([]models.PlaylistItem, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.playlists[playlistID]
+	if !ok {
+		return []models.PlaylistItem{}, nil
+	}
+	items := make([]models.PlaylistItem, 0, len(row.items))
+	for i, item := range row.items {
+		items = append(items, models.PlaylistItem{
+			ID:       item.id,
+			Position: i + 1,
+			AddedAt:  item.addedAt,
+			Song:     r.store.songModel(r.store.songs[item.songID]),
+		})
+	}
+	return paginate(items, limit, offset), nil
+}
+
+// EachItem выбирает элементы под блокировкой и передаёт их fn уже после её снятия,
+// чтобы медленный получатель не задерживал запись.
+func (r *PlaylistRepository) EachItem(playlistID, limit, offset int, fn func(models.PlaylistItem) error) error {
+	items, err := r.Items(playlistID, limit, offset)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *PlaylistRepository) AddItem(playlistID, songID, position int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.playlists[playlistID]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+	if _, ok := r.store.songs[songID]; !ok {
+		return 0, repository.ErrNotFound
+	}
+	r.store.nextPlaylistItemID++
+	item := &playlistItemRow{id: r.store.nextPlaylistItemID, songID: songID, addedAt: time.Now()}
+	row.items = insertItem(row.items, item, position)
+	row.updatedAt = time.Now()
+	return item.id, nil
+}
+
+func (r *PlaylistRepository) RemoveItem(playlistID, itemID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.playlists[playlistID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	items, _, ok := takeItem(row.items, itemID)
+	if !ok {
+		return repository.ErrNotFound
+	}
+	row.items = items
+	row.updatedAt = time.Now()
+	return nil
+}
+
+func (r *PlaylistRepository) MoveItem(playlistID, itemID, position int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.playlists[playlistID]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	items, item, ok := takeItem(row.items, itemID)
+	if !ok {
+		return repository.ErrNotFound
+	}
+	row.items = insertItem(items, item, position)
+	row.updatedAt = time.Now()
+	return nil
+}
+
+// insertItem вставляет элемент на позицию position (с 1); 0 или позиция за концом — в конец.
+func insertItem(items []*playlistItemRow, item *playlistItemRow, position int) []*playlistItemRow {
+	if position <= 0 || position > len(items) {
+		return append(items, item)
+	}
+	items = append(items, nil)
+	copy(items[position:], items[position-1:])
+	items[position-1] = item
+	return items
+}
+
+// takeItem возвращает элементы без itemID и сам элемент.
+func takeItem(items []*playlistItemRow, itemID int) ([]*playlistItemRow, *playlistItemRow, bool) {
+	for i, item := range items {
+		if item.id == itemID {
+			return append(items[:i:i], items[i+1:]...), item, true
+		}
+	}
+	return items, nil, false
+}
+
+func playlistModel(row *playlistRow) models.Playlist {
+	return models.Playlist{
+		ID:          row.id,
+		Name:        row.name,
+		Description: row.description,
+		CreatedAt:   row.createdAt,
+		UpdatedAt:   row.updatedAt,
+		ItemCount:   len(row.items),
+	}
+}
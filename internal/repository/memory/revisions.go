@@ -0,0 +1,43 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// addTextRevision записывает текст песни следующей версией. Вызывается под блокировкой.
+func (s *Store) addTextRevision(songID int, text, author string) {
+	revisions := s.revisions[songID]
+	s.revisions[songID] = append(revisions, models.TextRevision{
+		SongID:    songID,
+		Revision:  len(revisions) + 1,
+		Text:      text,
+		Author:    author,
+		CreatedAt: time.Now(),
+	})
+}
+
+func (r *SongRepository) TextRevisions(songID, limit, offset int) ([]models.TextRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	revisions := r.store.revisions[songID]
+	result := []models.TextRevision{}
+	for i := len(revisions) - 1 - offset; i >= 0 && len(result) < limit; i-- {
+		result = append(result, revisions[i])
+	}
+	return result, nil
+}
+
+func (r *SongRepository) TextRevision(songID, revision int) (models.TextRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	revisions := r.store.revisions[songID]
+	if revision < 1 || revision > len(revisions) {
+		return models.TextRevision{}, repository.ErrNotFound
+	}
+	return revisions[revision-1], nil
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// matchRule проверяет песню по правилу умного плейлиста с той же семантикой,
+// что и условие, которое строит PostgreSQL-реализация.
+func matchRule(song models.Song, rule models.SmartRule) bool {
+	if rule.IsGroup() {
+		for _, child := range rule.Rules {
+			ok := matchRule(song, child)
+			if rule.Match == models.RuleMatchAny && ok {
+				return true
+			}
+			if rule.Match != models.RuleMatchAny && !ok {
+				return false
+			}
+		}
+		return rule.Match != models.RuleMatchAny || len(rule.Rules) == 0
+	}
+
+	var value string
+	switch rule.Field {
+	case models.RuleFieldGroup:
+		value = song.GroupName
+	case models.RuleFieldSong:
+		value = song.SongName
+	case models.RuleFieldAlbum:
+		value = song.AlbumTitle
+	case models.RuleFieldText:
+		value = song.Text
+	case models.RuleFieldReleaseDate:
+		return matchRuleDate(song, rule)
+	case models.RuleFieldGenre, models.RuleFieldTag:
+		names := song.Genres
+		if rule.Field == models.RuleFieldTag {
+			names = song.Tags
+		}
+		has := false
+		for _, name := range names {
+			has = has || name == rule.Values[0]
+		}
+		return has == (rule.Op == models.RuleOpIs)
+	default:
+		return false
+	}
+
+	switch rule.Op {
+	case models.RuleOpEquals:
+		return strings.EqualFold(value, rule.Values[0])
+	case models.RuleOpContains:
+		return containsFold(value, rule.Values[0])
+	case models.RuleOpNotContains:
+		return !containsFold(value, rule.Values[0])
+	case models.RuleOpStartsWith:
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(rule.Values[0]))
+	case models.RuleOpLike:
+		return likePattern(rule.Values[0]).MatchString(value)
+	}
+	return false
+}
+
+// matchRuleDate сравнивает дату выпуска песни; песни без даты не подходят.
+func matchRuleDate(song models.Song, rule models.SmartRule) bool {
+	if song.ReleaseDate == nil {
+		return false
+	}
+	from, to, err := models.RuleDateRange(rule.Values[0])
+	if err != nil {
+		return false
+	}
+	date := *song.ReleaseDate
+	switch rule.Op {
+	case models.RuleOpBefore:
+		return date.Before(from)
+	case models.RuleOpAfter:
+		return date.After(to)
+	case models.RuleOpEquals:
+		return !date.Before(from) && !date.After(to)
+	case models.RuleOpBetween:
+		_, end, err := models.RuleDateRange(rule.Values[1])
+		return err == nil && !date.Before(from) && !date.After(end)
+	}
+	return false
+}
+
+// likePattern переводит шаблон ILIKE в регулярное выражение: % — любая подстрока,
+// _ — один символ, обратная косая черта экранирует следующий символ.
+func likePattern(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString(`(?is)^`)
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			b.WriteString(`.*`)
+		case r == '_':
+			b.WriteString(`.`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(`$`)
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// Веса полей повторяют setweight из миграции 004: название — A, группа — B, текст — C.
+const (
+	weightTitle = 1.0
+	weightGroup = 0.4
+	weightText  = 0.2
+)
+
+// Search — упрощённый аналог поиска PostgreSQL: без морфологии, все слова запроса
+// должны встретиться (как подстроки) в названии, группе или тексте песни.
+func (r *SongRepository) Search(query models.SearchQuery, limit, offset int) ([]models.SearchResult, error) {
+	terms := searchTerms(query.Query)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	results := []models.SearchResult{}
+	for _, row := range r.store.songs {
+		song := r.store.songModel(row)
+		title, group, text := strings.ToLower(song.SongName), strings.ToLower(song.GroupName), strings.ToLower(song.Text)
+
+		var rank float64
+		matched := true
+		for _, term := range terms {
+			score := weightTitle*float64(strings.Count(title, term)) +
+				weightGroup*float64(strings.Count(group, term)) +
+				weightText*float64(strings.Count(text, term))
+			if score == 0 {
+				matched = false
+				break
+			}
+			rank += score
+		}
+		if !matched {
+			continue
+		}
+		results = append(results, models.SearchResult{Song: song, Rank: rank, Headline: headline(song, terms)})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].Song.ID < results[j].Song.ID
+	})
+	return paginate(results, limit, offset), nil
+}
+
+// searchTerms разбивает запрос на слова в нижнем регистре.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// headline возвращает первую строку текста (или название песни) с найденным словом,
+// выделяя совпадения тегом <b>, как это делает ts_headline.
+func headline(song models.Song, terms []string) string {
+	source := song.Text
+	if source == "" {
+		source = song.SongName
+	}
+	lines := strings.Split(source, "\n")
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				return highlight(line, terms)
+			}
+		}
+	}
+	return highlight(lines[0], terms)
+}
+
+func highlight(line string, terms []string) string {
+	lower := strings.ToLower(line)
+	// Байтовые смещения в нижнем регистре совпадают с исходными только для
+	// строк без смены длины символов, поэтому при расхождении не подсвечиваем.
+	if len(lower) != len(line) {
+		return line
+	}
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		matched := ""
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:], term) && len(term) > len(matched) {
+				matched = term
+			}
+		}
+		if matched == "" {
+			b.WriteByte(line[i])
+			i++
+			continue
+		}
+		b.WriteString("<b>" + line[i:i+len(matched)] + "</b>")
+		i += len(matched)
+	}
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// SmartPlaylistRepository хранит умные плейлисты в памяти.
+type SmartPlaylistRepository struct {
+	store *Store
+}
+
+func NewSmartPlaylistRepository(store *Store) *SmartPlaylistRepository {
+	return &SmartPlaylistRepository{store: store}
+}
+
+var _ repository.SmartPlaylistRepository = (*SmartPlaylistRepository)(nil)
+
+func (r *SmartPlaylistRepository) List(limit, offset int) ([]models.SmartPlaylist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	playlists := make([]models.SmartPlaylist, 0, len(r.store.smartPlaylists))
+	for _, playlist := range r.store.smartPlaylists {
+		playlists = append(playlists, *playlist)
+	}
+	sort.Slice(playlists, func(i, j int) bool {
+		if playlists[i].Name != playlists[j].Name {
+			return playlists[i].Name < playlists[j].Name
+		}
+		return playlists[i].ID < playlists[j].ID
+	})
+	return paginate(playlists, limit, offset), nil
+}
+
+func (r *SmartPlaylistRepository) Get(id int) (models.SmartPlaylist, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	playlist, ok := r.store.smartPlaylists[id]
+	if !ok {
+		return models.SmartPlaylist{}, repository.ErrNotFound
+	}
+	return *playlist, nil
+}
+
+func (r *SmartPlaylistRepository) Create(playlist models.SmartPlaylist) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	r.store.nextSmartPlaylistID++
+	playlist.ID = r.store.nextSmartPlaylistID
+	playlist.CreatedAt = now
+	playlist.UpdatedAt = now
+	r.store.smartPlaylists[playlist.ID] = &playlist
+	return playlist.ID, nil
+}
+
+func (r *SmartPlaylistRepository) Update(id int, update models.SmartPlaylistUpdate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	playlist, ok := r.store.smartPlaylists[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	if update.Name != nil && *update.Name != "" {
+		playlist.Name = *update.Name
+	}
+	if update.Description != nil {
+		playlist.Description = *update.Description
+	}
+	if update.Rules != nil {
+		playlist.Rules = *update.Rules
+	}
+	playlist.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *SmartPlaylistRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.smartPlaylists[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.store.smartPlaylists, id)
+	return nil
+}
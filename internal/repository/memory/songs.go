@@ -0,0 +1,401 @@
+package memory
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// SongRepository хранит песни в памяти.
+type SongRepository struct {
+	store *Store
+}
+
+func NewSongRepository(store *Store) *SongRepository {
+	return &SongRepository{store: store}
+}
+
+var _ repository.SongRepository = (*SongRepository)(nil)
+
+func (r *SongRepository) List(filter models.SongFilter, limit, offset int) ([]models.Song, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	songs := []models.Song{}
+	for _, row := range r.store.songs {
+		song := r.store.songModel(row)
+		if filter.Fuzzy {
+			score, ok := fuzzyScore(song, filter)
+			if !ok {
+				continue
+			}
+			song.Score = score
+		} else if !containsFold(song.GroupName, filter.Group) || !containsFold(song.SongName, filter.Song) {
+			continue
+		}
+		if filter.AlbumID != nil && (song.AlbumID == nil || *song.AlbumID != *filter.AlbumID) {
+			continue
+		}
+		if !r.store.matchTags(models.TagKindGenre, row.id, filter.Genres, filter.MatchAll) ||
+			!r.store.matchTags(models.TagKindTag, row.id, filter.Tags, filter.MatchAll) {
+			continue
+		}
+		if filter.Rules != nil && !matchRule(song, *filter.Rules) {
+			continue
+		}
+		songs = append(songs, song)
+	}
+	sort.Slice(songs, func(i, j int) bool {
+		if songs[i].Score != songs[j].Score {
+			return songs[i].Score > songs[j].Score
+		}
+		if filter.AlbumID != nil {
+			if di, dj := intOr(songs[i].DiscNumber, 1), intOr(songs[j].DiscNumber, 1); di != dj {
+				return di < dj
+			}
+			// Песни без номера трека идут в конце, как NULLS LAST в PostgreSQL
+			ti, tj := songs[i].TrackNumber, songs[j].TrackNumber
+			if (ti == nil) != (tj == nil) {
+				return tj == nil
+			}
+			if ti != nil && *ti != *tj {
+				return *ti < *tj
+			}
+		}
+		return songs[i].ID < songs[j].ID
+	})
+	return paginate(songs, limit, offset), nil
+}
+
+// Each выбирает песни под блокировкой и передаёт их fn уже после её снятия,
+// чтобы медленный получатель не задерживал запись.
+func (r *SongRepository) Each(filter models.SongFilter, limit, offset int, fn func(models.Song) error) error {
+	songs, err := r.List(filter, limit, offset)
+	if err != nil {
+		return err
+	}
+	for _, song := range songs {
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fuzzyScore повторяет логику PostgreSQL-реализации: каждое непустое поле фильтра
+// должно набрать сходство не ниже порога с исходным написанием или транслитерацией.
+func fuzzyScore(song models.Song, filter models.SongFilter) (float64, bool) {
+	var total float64
+	fields := 0
+	for _, f := range []struct{ value, query string }{
+		{song.GroupName, filter.Group},
+		{song.SongName, filter.Song},
+	} {
+		if f.query == "" {
+			continue
+		}
+		score := max(normalize.Similarity(f.value, f.query), normalize.Similarity(f.value, normalize.Transliterate(f.query)))
+		if score < filter.MinScore {
+			return 0, false
+		}
+		total += score
+		fields++
+	}
+	if fields == 0 {
+		return 0, true
+	}
+	return total / float64(fields), true
+}
+
+func (r *SongRepository) Get(id int) (models.Song, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.songs[id]
+	if !ok {
+		return models.Song{}, repository.ErrNotFound
+	}
+	return r.store.songModel(row), nil
+}
+
+func (r *SongRepository) GetText(id int) (string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	row, ok := r.store.songs[id]
+	if !ok {
+		return "", repository.ErrNotFound
+	}
+	return row.text, nil
+}
+
+func (r *SongRepository) FindByName(groupID int, name string) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	id, ok := r.store.findSong(groupID, name)
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+	return id, nil
+}
+
+func (r *SongRepository) Create(song models.Song) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.createSong(song)
+}
+
+func (r *SongRepository) CreateBatch(songs []models.Song) ([]models.BatchResult, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	results := make([]models.BatchResult, len(songs))
+	for i, song := range songs {
+		if id, ok := r.store.findSong(song.GroupID, song.SongName); ok {
+			results[i] = models.BatchResult{ID: id, Duplicate: true}
+			continue
+		}
+		id, err := r.store.createSong(song)
+		if err != nil {
+			results[i] = models.BatchResult{Err: err}
+			continue
+		}
+		r.store.attachTags(models.TagKindGenre, id, song.Genres)
+		r.store.attachTags(models.TagKindTag, id, song.Tags)
+		results[i] = models.BatchResult{ID: id}
+	}
+	return results, nil
+}
+
+// createSong добавляет песню и возвращает её ID. Вызывается под блокировкой.
+func (s *Store) createSong(song models.Song) (int, error) {
+	if _, ok := s.groups[song.GroupID]; !ok {
+		return 0, repository.ErrNotFound
+	}
+	if song.AlbumID != nil {
+		if _, ok := s.albums[*song.AlbumID]; !ok {
+			return 0, repository.ErrNotFound
+		}
+	}
+	now := time.Now()
+	s.nextSongID++
+	s.songs[s.nextSongID] = &songRow{
+		id:          s.nextSongID,
+		groupID:     song.GroupID,
+		songName:    song.SongName,
+		releaseDate: copyTime(song.ReleaseDate),
+		text:        song.Text,
+		link:        song.Link,
+		albumID:     copyInt(song.AlbumID),
+		trackNumber: copyInt(song.TrackNumber),
+		discNumber:  copyInt(song.DiscNumber),
+		createdAt:   now,
+		updatedAt:   now,
+
+		enrichmentStatus: song.EnrichmentStatus,
+		manualFields:     mergeFields(nil, song.FilledFields(), nil),
+	}
+	if song.Text != "" {
+		s.addTextRevision(s.nextSongID, song.Text, "")
+	}
+	switch song.EnrichmentStatus {
+	case models.EnrichmentPending:
+		s.enqueueJob(s.nextSongID)
+	case "":
+		s.songs[s.nextSongID].enrichmentStatus = models.EnrichmentDone
+	}
+	return s.nextSongID, nil
+}
+
+// findSong ищет песню группы по названию без учёта регистра. Вызывается под блокировкой.
+func (s *Store) findSong(groupID int, name string) (int, bool) {
+	id := 0
+	for _, row := range s.songs {
+		if row.groupID == groupID && strings.EqualFold(row.songName, name) && (id == 0 || row.id < id) {
+			id = row.id
+		}
+	}
+	return id, id != 0
+}
+
+func (r *SongRepository) Update(id int, update models.SongUpdate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.songs[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	text := row.text
+	if update.GroupID != nil {
+		if _, ok := r.store.groups[*update.GroupID]; !ok {
+			return repository.ErrNotFound
+		}
+		row.groupID = *update.GroupID
+	}
+	// Пустое название, как и в PostgreSQL-реализации, не затирает текущее
+	if update.SongName != nil && *update.SongName != "" {
+		row.songName = *update.SongName
+	}
+	if update.ReleaseDate != nil {
+		row.releaseDate = copyTime(update.ReleaseDate)
+	}
+	if update.Text != nil {
+		row.text = *update.Text
+	}
+	if update.Link != nil {
+		row.link = *update.Link
+	}
+	if update.AlbumID != nil {
+		if _, ok := r.store.albums[*update.AlbumID]; !ok {
+			return repository.ErrNotFound
+		}
+		row.albumID = copyInt(update.AlbumID)
+	}
+	if update.TrackNumber != nil {
+		row.trackNumber = copyInt(update.TrackNumber)
+	}
+	if update.DiscNumber != nil {
+		row.discNumber = copyInt(update.DiscNumber)
+	}
+	if update.Enrichment {
+		row.manualFields = mergeFields(row.manualFields, nil, update.ChangedFields())
+	} else {
+		row.manualFields = mergeFields(row.manualFields, update.ChangedFields(), nil)
+	}
+	row.updatedAt = time.Now()
+	if row.text != text {
+		r.store.addTextRevision(id, row.text, update.Author)
+	}
+	return nil
+}
+
+func (r *SongRepository) Delete(id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.songs[id]; !ok {
+		return repository.ErrNotFound
+	}
+	r.store.deleteSong(id)
+	return nil
+}
+
+// deleteSong удаляет песню вместе со связями с метками, элементами плейлистов и аудиофайлом,
+// как ON DELETE CASCADE. Вызывается под блокировкой.
+func (s *Store) deleteSong(id int) {
+	for _, songTags := range s.songTags {
+		delete(songTags, id)
+	}
+	for _, playlist := range s.playlists {
+		items := playlist.items[:0]
+		for _, item := range playlist.items {
+			if item.songID != id {
+				items = append(items, item)
+			}
+		}
+		playlist.items = items
+	}
+	delete(s.audio, id)
+	delete(s.lyrics, id)
+	delete(s.revisions, id)
+	if job := s.songJob(id); job != nil {
+		delete(s.jobs, job.ID)
+	}
+	delete(s.covers[models.CoverKindSong], id)
+	delete(s.songs, id)
+}
+
+// songModel собирает модель песни вместе с названием группы и метками. Вызывается под блокировкой.
+func (s *Store) songModel(row *songRow) models.Song {
+	song := models.Song{
+		ID:          row.id,
+		GroupID:     row.groupID,
+		SongName:    row.songName,
+		ReleaseDate: copyTime(row.releaseDate),
+		Text:        row.text,
+		Link:        row.link,
+		AlbumID:     copyInt(row.albumID),
+		TrackNumber: copyInt(row.trackNumber),
+		DiscNumber:  copyInt(row.discNumber),
+		CreatedAt:   row.createdAt,
+		UpdatedAt:   row.updatedAt,
+		Genres:      s.songTagNames(models.TagKindGenre, row.id),
+		Tags:        s.songTagNames(models.TagKindTag, row.id),
+
+		EnrichmentStatus: row.enrichmentStatus,
+		EnrichedAt:       copyTime(row.enrichedAt),
+		ManualFields:     slices.Clone(row.manualFields),
+	}
+	if group, ok := s.groups[row.groupID]; ok {
+		song.GroupName = group.name
+	}
+	song.CoverChecksum = s.covers[models.CoverKindSong][row.id].Checksum
+	song.GroupCoverChecksum = s.covers[models.CoverKindGroup][row.groupID].Checksum
+	if row.albumID != nil {
+		if album, ok := s.albums[*row.albumID]; ok {
+			song.AlbumTitle = album.title
+		}
+	}
+	return song
+}
+
+// containsFold повторяет семантику ILIKE '%substr%': пустая подстрока подходит всегда.
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// mergeFields добавляет к полям fields поля add и убирает remove; результат — новый
+// срез по алфавиту, как manual_fields в PostgreSQL.
+func mergeFields(fields, add, remove []string) []string {
+	merged := []string{}
+	for _, field := range append(slices.Clone(fields), add...) {
+		if !slices.Contains(remove, field) && !slices.Contains(merged, field) {
+			merged = append(merged, field)
+		}
+	}
+	slices.Sort(merged)
+	return merged
+}
+
+// copyTime копирует дату, чтобы хранилище не делило указатель с вызывающим кодом.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func copyInt(n *int) *int {
+	if n == nil {
+		return nil
+	}
+	c := *n
+	return &c
+}
+
+func intOr(n *int, def int) int {
+	if n == nil {
+		return def
+	}
+	return *n
+}
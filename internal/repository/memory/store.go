@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// Store — общее in-memory хранилище, на котором работают репозитории пакета.
+// Используется в тестах и при запуске без базы данных.
+type Store struct {
+	mu sync.RWMutex
+
+	groups      map[int]*groupRow
+	nextGroupID int
+	// aliases сопоставляет прежнее название группы с ID группы, в которую её слили.
+	aliases map[string]int
+
+	songs      map[int]*songRow
+	nextSongID int
+
+	albums      map[int]*albumRow
+	nextAlbumID int
+
+	// tags хранит жанры и теги по видам, songTags — ID меток каждой песни.
+	tags      map[models.TagKind]map[int]*tagRow
+	songTags  map[models.TagKind]map[int]map[int]struct{}
+	nextTagID int
+
+	playlists          map[int]*playlistRow
+	nextPlaylistID     int
+	nextPlaylistItemID int
+
+	smartPlaylists      map[int]*models.SmartPlaylist
+	nextSmartPlaylistID int
+
+	// audio — аудиофайлы по ID песни.
+	audio map[int]models.AudioFile
+
+	// lyrics — синхронизированные тексты по ID песни.
+	lyrics map[int]models.SongLyrics
+
+	// revisions — версии текста по ID песни в порядке номеров.
+	revisions map[int][]models.TextRevision
+
+	// covers — обложки по виду владельца и его ID.
+	covers map[models.CoverKind]map[int]models.Cover
+
+	// jobs — задачи обогащения по ID задачи; у песни не больше одной задачи.
+	jobs      map[int]*models.EnrichmentJob
+	nextJobID int
+
+	// metadataCache — кэш ответов внешнего API по ключу вместе со сроком годности.
+	metadataCache map[string]cachedDetails
+}
+
+type groupRow struct {
+	id         int
+	name       string
+	country    string
+	formedYear *int
+	bio        string
+	createdAt  time.Time
+	updatedAt  time.Time
+}
+
+type songRow struct {
+	id          int
+	groupID     int
+	songName    string
+	releaseDate *time.Time
+	text        string
+	link        string
+	albumID     *int
+	trackNumber *int
+	discNumber  *int
+	createdAt   time.Time
+	updatedAt   time.Time
+
+	enrichmentStatus models.EnrichmentStatus
+	enrichedAt       *time.Time
+	manualFields     []string
+}
+
+type tagRow struct {
+	id        int
+	name      string
+	createdAt time.Time
+}
+
+type playlistRow struct {
+	id          int
+	name        string
+	description string
+	// items — элементы в порядке позиций, позиция элемента — индекс + 1
+	items     []*playlistItemRow
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+type playlistItemRow struct {
+	id      int
+	songID  int
+	addedAt time.Time
+}
+
+type albumRow struct {
+	id          int
+	groupID     int
+	title       string
+	releaseDate *time.Time
+	albumType   string
+	createdAt   time.Time
+	updatedAt   time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		groups:  map[int]*groupRow{},
+		aliases: map[string]int{},
+		songs:   map[int]*songRow{},
+		albums:  map[int]*albumRow{},
+		tags: map[models.TagKind]map[int]*tagRow{
+			models.TagKindGenre: {},
+			models.TagKindTag:   {},
+		},
+		songTags: map[models.TagKind]map[int]map[int]struct{}{
+			models.TagKindGenre: {},
+			models.TagKindTag:   {},
+		},
+		playlists:      map[int]*playlistRow{},
+		smartPlaylists: map[int]*models.SmartPlaylist{},
+		audio:          map[int]models.AudioFile{},
+		lyrics:         map[int]models.SongLyrics{},
+		revisions:      map[int][]models.TextRevision{},
+		jobs:           map[int]*models.EnrichmentJob{},
+		metadataCache:  map[string]cachedDetails{},
+		covers: map[models.CoverKind]map[int]models.Cover{
+			models.CoverKindSong:  {},
+			models.CoverKindGroup: {},
+		},
+	}
+}
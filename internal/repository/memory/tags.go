@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// TagRepository хранит жанры и теги песен в памяти.
+type TagRepository struct {
+	store *Store
+}
+
+func NewTagRepository(store *Store) *TagRepository {
+	return &TagRepository{store: store}
+}
+
+var _ repository.TagRepository = (*TagRepository)(nil)
+
+func (r *TagRepository) List(kind models.TagKind, limit, offset int) ([]models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tags, err := r.store.tagModels(kind)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return paginate(tags, limit, offset), nil
+}
+
+func (r *TagRepository) Popular(kind models.TagKind, limit int) ([]models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	all, err := r.store.tagModels(kind)
+	if err != nil {
+		return nil, err
+	}
+	tags := []models.Tag{}
+	for _, tag := range all {
+		if tag.SongCount > 0 {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].SongCount != tags[j].SongCount {
+			return tags[i].SongCount > tags[j].SongCount
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	return paginate(tags, limit, 0), nil
+}
+
+func (r *TagRepository) Attach(songID int, kind models.TagKind, names []string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tags[kind]; !ok {
+		return fmt.Errorf("неизвестный вид метки %q", kind)
+	}
+	if _, ok := r.store.songs[songID]; !ok {
+		return repository.ErrNotFound
+	}
+	r.store.attachTags(kind, songID, names)
+	return nil
+}
+
+// attachTags привязывает к песне метки с названиями names, добавляя недостающие.
+// Вызывается под блокировкой.
+func (s *Store) attachTags(kind models.TagKind, songID int, names []string) {
+	linked := s.songTags[kind][songID]
+	if linked == nil {
+		linked = map[int]struct{}{}
+		s.songTags[kind][songID] = linked
+	}
+	for _, name := range names {
+		tag := s.findTag(kind, name)
+		if tag == nil {
+			s.nextTagID++
+			tag = &tagRow{id: s.nextTagID, name: name, createdAt: time.Now()}
+			s.tags[kind][tag.id] = tag
+		}
+		linked[tag.id] = struct{}{}
+	}
+}
+
+func (r *TagRepository) Detach(songID int, kind models.TagKind, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.tags[kind]; !ok {
+		return fmt.Errorf("неизвестный вид метки %q", kind)
+	}
+	tag := r.store.findTag(kind, name)
+	if tag == nil {
+		return repository.ErrNotFound
+	}
+	linked := r.store.songTags[kind][songID]
+	if _, ok := linked[tag.id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(linked, tag.id)
+	return nil
+}
+
+// findTag ищет метку по названию. Вызывается под блокировкой.
+func (s *Store) findTag(kind models.TagKind, name string) *tagRow {
+	for _, tag := range s.tags[kind] {
+		if tag.name == name {
+			return tag
+		}
+	}
+	return nil
+}
+
+// tagModels собирает все метки вида kind с числом песен. Вызывается под блокировкой.
+func (s *Store) tagModels(kind models.TagKind) ([]models.Tag, error) {
+	rows, ok := s.tags[kind]
+	if !ok {
+		return nil, fmt.Errorf("неизвестный вид метки %q", kind)
+	}
+	counts := map[int]int{}
+	for _, linked := range s.songTags[kind] {
+		for tagID := range linked {
+			counts[tagID]++
+		}
+	}
+	tags := make([]models.Tag, 0, len(rows))
+	for _, row := range rows {
+		tags = append(tags, models.Tag{ID: row.id, Kind: kind, Name: row.name, SongCount: counts[row.id]})
+	}
+	return tags, nil
+}
+
+// songTagNames возвращает названия меток песни по алфавиту. Вызывается под блокировкой.
+func (s *Store) songTagNames(kind models.TagKind, songID int) []string {
+	names := []string{}
+	for tagID := range s.songTags[kind][songID] {
+		names = append(names, s.tags[kind][tagID].name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// matchTags проверяет метки песни: хотя бы одна из names или, при all, все.
+// Пустой список подходит всегда. Вызывается под блокировкой.
+func (s *Store) matchTags(kind models.TagKind, songID int, names []string, all bool) bool {
+	if len(names) == 0 {
+		return true
+	}
+	has := map[string]bool{}
+	for _, name := range s.songTagNames(kind, songID) {
+		has[name] = true
+	}
+	for _, name := range names {
+		if all && !has[name] {
+			return false
+		}
+		if !all && has[name] {
+			return true
+		}
+	}
+	return all
+}
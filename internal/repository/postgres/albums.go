@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// AlbumRepository хранит альбомы в PostgreSQL.
+type AlbumRepository struct {
+	db *sql.DB
+}
+
+func NewAlbumRepository(provider *conn.PostgresProvider) *AlbumRepository {
+	return &AlbumRepository{db: provider.DB()}
+}
+
+var _ repository.AlbumRepository = (*AlbumRepository)(nil)
+
+const albumColumns = `a.id, a.group_id, g.name, a.title, a.release_date, a.album_type, a.created_at, a.updated_at`
+
+func scanAlbum(row scanner) (models.Album, error) {
+	var album models.Album
+	var releaseDate, createdAt, updatedAt sql.NullTime
+	if err := row.Scan(&album.ID, &album.GroupID, &album.GroupName, &album.Title, &releaseDate,
+		&album.Type, &createdAt, &updatedAt); err != nil {
+		return models.Album{}, err
+	}
+	album.ReleaseDate = nullTimePtr(releaseDate)
+	album.CreatedAt = createdAt.Time
+	album.UpdatedAt = updatedAt.Time
+	return album, nil
+}
+
+func (r *AlbumRepository) List(filter models.AlbumFilter, limit, offset int) ([]models.Album, error) {
+	query := `
+		SELECT ` + albumColumns + `
+		FROM albums a
+		JOIN groups g ON a.group_id = g.id
+		WHERE ($1 = 0 OR a.group_id = $1)
+		AND ($2 = '' OR a.title ILIKE '%' || $2 || '%')
+		ORDER BY a.release_date NULLS LAST, a.id LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, filter.GroupID, filter.Title, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса альбомов: %w", err)
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		album, err := scanAlbum(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		albums = append(albums, album)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return albums, nil
+}
+
+func (r *AlbumRepository) Get(id int) (models.Album, error) {
+	query := `
+		SELECT ` + albumColumns + `
+		FROM albums a
+		JOIN groups g ON a.group_id = g.id
+		WHERE a.id = $1`
+	album, err := scanAlbum(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Album{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.Album{}, fmt.Errorf("ошибка получения альбома: %w", err)
+	}
+	return album, nil
+}
+
+func (r *AlbumRepository) Create(album models.Album) (int, error) {
+	query := `
+		INSERT INTO albums (group_id, title, release_date, album_type)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+	var id int
+	err := r.db.QueryRow(query, album.GroupID, album.Title, album.ReleaseDate, album.Type).Scan(&id)
+	if isForeignKeyViolation(err) {
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка добавления альбома: %w", err)
+	}
+	return id, nil
+}
+
+func (r *AlbumRepository) Update(id int, update models.AlbumUpdate) error {
+	query := `
+		UPDATE albums
+		SET group_id = COALESCE($1, group_id),
+		    title = COALESCE(NULLIF($2, ''), title),
+		    release_date = COALESCE($3, release_date),
+		    album_type = COALESCE($4, album_type),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $5`
+	res, err := r.db.Exec(query, update.GroupID, update.Title, update.ReleaseDate, update.Type, id)
+	if isForeignKeyViolation(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка обновления альбома: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *AlbumRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM albums WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления альбома: %w", err)
+	}
+	return checkAffected(res)
+}
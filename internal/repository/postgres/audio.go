@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// AudioRepository хранит сведения об аудиофайлах песен в PostgreSQL.
+type AudioRepository struct {
+	db *sql.DB
+}
+
+func NewAudioRepository(provider *conn.PostgresProvider) *AudioRepository {
+	return &AudioRepository{db: provider.DB()}
+}
+
+var _ repository.AudioRepository = (*AudioRepository)(nil)
+
+func (r *AudioRepository) Get(songID int) (models.AudioFile, error) {
+	query := `
+		SELECT song_id, storage_key, COALESCE(file_name, ''), format, mime_type, size_bytes, checksum,
+		       COALESCE(duration_ms, 0), created_at, updated_at
+		FROM song_audio
+		WHERE song_id = $1`
+	var audio models.AudioFile
+	var durationMs int64
+	var createdAt, updatedAt sql.NullTime
+	err := r.db.QueryRow(query, songID).Scan(&audio.SongID, &audio.StorageKey, &audio.FileName, &audio.Format,
+		&audio.MimeType, &audio.Size, &audio.Checksum, &durationMs, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.AudioFile{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.AudioFile{}, fmt.Errorf("ошибка получения аудиофайла: %w", err)
+	}
+	audio.Duration = time.Duration(durationMs) * time.Millisecond
+	audio.CreatedAt = createdAt.Time
+	audio.UpdatedAt = updatedAt.Time
+	return audio, nil
+}
+
+func (r *AudioRepository) Save(audio models.AudioFile) error {
+	query := `
+		INSERT INTO song_audio (song_id, storage_key, file_name, format, mime_type, size_bytes, checksum, duration_ms)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, NULLIF($8, 0))
+		ON CONFLICT (song_id) DO UPDATE
+		SET storage_key = EXCLUDED.storage_key,
+		    file_name = EXCLUDED.file_name,
+		    format = EXCLUDED.format,
+		    mime_type = EXCLUDED.mime_type,
+		    size_bytes = EXCLUDED.size_bytes,
+		    checksum = EXCLUDED.checksum,
+		    duration_ms = EXCLUDED.duration_ms,
+		    updated_at = CURRENT_TIMESTAMP`
+	_, err := r.db.Exec(query, audio.SongID, audio.StorageKey, audio.FileName, audio.Format, audio.MimeType,
+		audio.Size, audio.Checksum, audio.Duration.Milliseconds())
+	if isForeignKeyViolation(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения аудиофайла: %w", err)
+	}
+	return nil
+}
+
+func (r *AudioRepository) Delete(songID int) error {
+	res, err := r.db.Exec(`DELETE FROM song_audio WHERE song_id = $1`, songID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления аудиофайла: %w", err)
+	}
+	return checkAffected(res)
+}
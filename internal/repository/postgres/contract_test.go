@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/db/migrations"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/repotest"
+)
+
+// testDSNEnv — переменная со строкой подключения к отдельной тестовой базе данных.
+// Тесты очищают её таблицы, поэтому рабочую базу указывать нельзя.
+const testDSNEnv = "MUSIC_LIBRARY_TEST_DSN"
+
+func TestContract(t *testing.T) {
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s не задана, тесты PostgreSQL пропущены", testDSNEnv)
+	}
+	provider, err := conn.Open(dsn)
+	if err != nil {
+		t.Fatalf("подключение к тестовой базе: %v", err)
+	}
+	t.Cleanup(func() { provider.Close() })
+	if err := migrations.RunMigrations(provider, "../../db/migrations"); err != nil {
+		t.Fatalf("миграции: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		// Таблицы, ссылающиеся на группы и песни, очищаются каскадно
+		if _, err := provider.DB().Exec(`TRUNCATE groups, songs, genres, tags, playlists RESTART IDENTITY CASCADE`); err != nil {
+			t.Fatalf("очистка таблиц: %v", err)
+		}
+		return repotest.Repos{
+			Songs:      NewSongRepository(provider),
+			Groups:     NewGroupRepository(provider),
+			Enrichment: NewEnrichmentRepository(provider),
+			Tags:       NewTagRepository(provider),
+			Playlists:  NewPlaylistRepository(provider),
+		}
+	})
+}
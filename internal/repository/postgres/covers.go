@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// CoverRepository хранит сведения об обложках в PostgreSQL.
+type CoverRepository struct {
+	db *sql.DB
+}
+
+func NewCoverRepository(provider *conn.PostgresProvider) *CoverRepository {
+	return &CoverRepository{db: provider.DB()}
+}
+
+var _ repository.CoverRepository = (*CoverRepository)(nil)
+
+// coverOwners — колонка таблицы covers со ссылкой на владельца каждого вида.
+var coverOwners = map[models.CoverKind]string{
+	models.CoverKindSong:  "song_id",
+	models.CoverKindGroup: "group_id",
+}
+
+func coverOwner(kind models.CoverKind) (string, error) {
+	column, ok := coverOwners[kind]
+	if !ok {
+		return "", fmt.Errorf("неизвестный вид обложки %q", kind)
+	}
+	return column, nil
+}
+
+func (r *CoverRepository) Get(kind models.CoverKind, ownerID int) (models.Cover, error) {
+	column, err := coverOwner(kind)
+	if err != nil {
+		return models.Cover{}, err
+	}
+	query := `
+		SELECT storage_key, mime_type, width, height, size_bytes, checksum, created_at, updated_at
+		FROM covers
+		WHERE ` + column + ` = $1`
+	cover := models.Cover{Kind: kind, OwnerID: ownerID}
+	var createdAt, updatedAt sql.NullTime
+	err = r.db.QueryRow(query, ownerID).Scan(&cover.StorageKey, &cover.MimeType, &cover.Width, &cover.Height,
+		&cover.Size, &cover.Checksum, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Cover{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.Cover{}, fmt.Errorf("ошибка получения обложки: %w", err)
+	}
+	cover.CreatedAt = createdAt.Time
+	cover.UpdatedAt = updatedAt.Time
+	return cover, nil
+}
+
+func (r *CoverRepository) Save(cover models.Cover) error {
+	column, err := coverOwner(cover.Kind)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO covers (` + column + `, storage_key, mime_type, width, height, size_bytes, checksum)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (` + column + `) DO UPDATE
+		SET storage_key = EXCLUDED.storage_key,
+		    mime_type = EXCLUDED.mime_type,
+		    width = EXCLUDED.width,
+		    height = EXCLUDED.height,
+		    size_bytes = EXCLUDED.size_bytes,
+		    checksum = EXCLUDED.checksum,
+		    updated_at = CURRENT_TIMESTAMP`
+	_, err = r.db.Exec(query, cover.OwnerID, cover.StorageKey, cover.MimeType, cover.Width, cover.Height,
+		cover.Size, cover.Checksum)
+	if isForeignKeyViolation(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения обложки: %w", err)
+	}
+	return nil
+}
+
+func (r *CoverRepository) Delete(kind models.CoverKind, ownerID int) error {
+	column, err := coverOwner(kind)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.Exec(`DELETE FROM covers WHERE `+column+` = $1`, ownerID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления обложки: %w", err)
+	}
+	return checkAffected(res)
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+func (r *GroupRepository) FindDuplicates(threshold float64, limit int) ([]models.GroupDuplicate, error) {
+	// Пары ищутся по индексам: одинаковые нормализованные названия — по B-дереву,
+	// похожие — оператором %, который использует триграммный GIN-индекс;
+	// similarity() считается только для найденных пар, чтобы их упорядочить
+	query := `
+		WITH pairs AS (
+			SELECT a.id AS a_id, b.id AS b_id
+			FROM groups a
+			JOIN groups b ON b.normalized_name = a.normalized_name AND a.id < b.id
+			UNION
+			SELECT a.id, b.id
+			FROM groups a
+			JOIN groups b ON b.name % a.name AND a.id < b.id
+		)
+		SELECT a.id, a.name, b.id, b.name,
+		       similarity(a.name, b.name) AS score, a.normalized_name = b.normalized_name AS same
+		FROM pairs p
+		JOIN groups a ON a.id = p.a_id
+		JOIN groups b ON b.id = p.b_id
+		ORDER BY same DESC, score DESC, a.id, b.id
+		LIMIT $1`
+
+	// Порог оператора % задаётся на время транзакции, как при нечётком поиске песен
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', $1, true)`,
+		strconv.FormatFloat(threshold, 'f', -1, 64)); err != nil {
+		return nil, fmt.Errorf("ошибка установки порога сходства: %w", err)
+	}
+
+	rows, err := tx.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска дубликатов групп: %w", err)
+	}
+	defer rows.Close()
+
+	duplicates := []models.GroupDuplicate{}
+	for rows.Next() {
+		var d models.GroupDuplicate
+		if err := rows.Scan(&d.ID, &d.Name, &d.DuplicateID, &d.DuplicateName, &d.Similarity, &d.SameNormalized); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		duplicates = append(duplicates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return duplicates, nil
+}
+
+func (r *SongRepository) FindDuplicates(threshold float64, limit int) ([]models.SongDuplicate, error) {
+	// Сравниваем песни внутри групп с одинаковым нормализованным названием,
+	// чтобы находить и повторы в ещё не слитых дубликатах групп
+	query := `
+		WITH s AS (
+			SELECT s.id, g.name AS group_name, s.song_name,
+			       g.normalized_name AS group_key,
+			       lower(btrim(s.song_name)) AS song_key
+			FROM songs s
+			JOIN groups g ON g.id = s.group_id
+		)
+		SELECT a.id, a.group_name, a.song_name, b.id, b.group_name, b.song_name,
+		       similarity(a.song_name, b.song_name) AS score, a.song_key = b.song_key AS same
+		FROM s a
+		JOIN s b ON a.id < b.id AND a.group_key = b.group_key
+		WHERE a.song_key = b.song_key OR similarity(a.song_name, b.song_name) >= $1
+		ORDER BY same DESC, score DESC, a.id, b.id
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, threshold, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска дубликатов песен: %w", err)
+	}
+	defer rows.Close()
+
+	duplicates := []models.SongDuplicate{}
+	for rows.Next() {
+		var d models.SongDuplicate
+		if err := rows.Scan(&d.ID, &d.GroupName, &d.SongName, &d.DuplicateID, &d.DuplicateGroupName,
+			&d.DuplicateSongName, &d.Similarity, &d.SameNormalized); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		duplicates = append(duplicates, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return duplicates, nil
+}
@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// EnrichmentRepository хранит очередь обогащения в PostgreSQL. Задачи забираются
+// через FOR UPDATE SKIP LOCKED, поэтому обработчики в разных процессах не мешают друг другу.
+type EnrichmentRepository struct {
+	db *sql.DB
+}
+
+func NewEnrichmentRepository(provider *conn.PostgresProvider) *EnrichmentRepository {
+	return &EnrichmentRepository{db: provider.DB()}
+}
+
+var _ repository.EnrichmentRepository = (*EnrichmentRepository)(nil)
+
+// jobColumns — список колонок, который читает scanJob.
+const jobColumns = `id, song_id, status, attempts, run_at, locked_at, COALESCE(last_error, ''), created_at, updated_at`
+
+func scanJob(row scanner) (models.EnrichmentJob, error) {
+	var job models.EnrichmentJob
+	var runAt, lockedAt, createdAt, updatedAt sql.NullTime
+	err := row.Scan(&job.ID, &job.SongID, &job.Status, &job.Attempts, &runAt, &lockedAt, &job.LastError, &createdAt, &updatedAt)
+	if err != nil {
+		return models.EnrichmentJob{}, err
+	}
+	job.RunAt = runAt.Time
+	job.LockedAt = nullTimePtr(lockedAt)
+	job.CreatedAt = createdAt.Time
+	job.UpdatedAt = updatedAt.Time
+	return job, nil
+}
+
+// insertEnrichmentJob ставит в очередь только что добавленную песню.
+func insertEnrichmentJob(tx *sql.Tx, songID int) error {
+	if _, err := tx.Exec(`INSERT INTO enrichment_jobs (song_id) VALUES ($1)`, songID); err != nil {
+		return fmt.Errorf("ошибка постановки песни в очередь обогащения: %w", err)
+	}
+	return nil
+}
+
+func (r *EnrichmentRepository) Enqueue(songID int) (models.EnrichmentJob, error) {
+	// Выполняющуюся задачу не трогаем: обработчик всё равно запишет её итог
+	query := `
+		WITH job AS (
+			INSERT INTO enrichment_jobs (song_id) VALUES ($1)
+			ON CONFLICT (song_id) DO UPDATE
+			SET status = 'queued',
+			    attempts = 0,
+			    run_at = CURRENT_TIMESTAMP,
+			    locked_at = NULL,
+			    last_error = NULL,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE enrichment_jobs.status <> 'running'
+			RETURNING *
+		), song AS (
+			UPDATE songs SET enrichment_status = 'pending'
+			WHERE id IN (SELECT song_id FROM job)
+		)
+		SELECT ` + jobColumns + ` FROM job`
+	job, err := scanJob(r.db.QueryRow(query, songID))
+	if isForeignKeyViolation(err) {
+		return models.EnrichmentJob{}, repository.ErrNotFound
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.EnrichmentJob{}, repository.ErrConflict
+	}
+	if err != nil {
+		return models.EnrichmentJob{}, fmt.Errorf("ошибка постановки песни в очередь обогащения: %w", err)
+	}
+	return job, nil
+}
+
+func (r *EnrichmentRepository) Claim(limit int, lease time.Duration) ([]models.EnrichmentJob, error) {
+	query := `
+		UPDATE enrichment_jobs j
+		SET status = 'running',
+		    attempts = j.attempts + 1,
+		    locked_at = CURRENT_TIMESTAMP,
+		    updated_at = CURRENT_TIMESTAMP
+		FROM (
+			SELECT id FROM enrichment_jobs
+			WHERE (status = 'queued' AND run_at <= CURRENT_TIMESTAMP)
+			   OR (status = 'running' AND locked_at < CURRENT_TIMESTAMP - make_interval(secs => $2))
+			ORDER BY run_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		) ready
+		WHERE j.id = ready.id
+		RETURNING j.id, j.song_id, j.status, j.attempts, j.run_at, j.locked_at,
+		          COALESCE(j.last_error, ''), j.created_at, j.updated_at`
+	rows, err := r.db.Query(query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения задач обогащения: %w", err)
+	}
+	return scanJobs(rows)
+}
+
+func (r *EnrichmentRepository) Complete(jobID int) error {
+	query := `
+		WITH job AS (
+			UPDATE enrichment_jobs
+			SET status = 'done', locked_at = NULL, last_error = NULL, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING song_id
+		)
+		UPDATE songs SET enrichment_status = 'done', enriched_at = CURRENT_TIMESTAMP
+		WHERE id IN (SELECT song_id FROM job)`
+	res, err := r.db.Exec(query, jobID)
+	if err != nil {
+		return fmt.Errorf("ошибка завершения задачи обогащения: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *EnrichmentRepository) Retry(jobID int, delay time.Duration, lastError string) error {
+	query := `
+		UPDATE enrichment_jobs
+		SET status = 'queued',
+		    run_at = CURRENT_TIMESTAMP + make_interval(secs => $2),
+		    locked_at = NULL,
+		    last_error = $3,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	res, err := r.db.Exec(query, jobID, delay.Seconds(), lastError)
+	if err != nil {
+		return fmt.Errorf("ошибка возврата задачи обогащения в очередь: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *EnrichmentRepository) Fail(jobID int, lastError string) error {
+	query := `
+		WITH job AS (
+			UPDATE enrichment_jobs
+			SET status = 'failed', locked_at = NULL, last_error = $2, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING song_id
+		)
+		UPDATE songs SET enrichment_status = 'failed'
+		WHERE id IN (SELECT song_id FROM job)`
+	res, err := r.db.Exec(query, jobID, lastError)
+	if err != nil {
+		return fmt.Errorf("ошибка завершения задачи обогащения: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *EnrichmentRepository) Get(jobID int) (models.EnrichmentJob, error) {
+	job, err := scanJob(r.db.QueryRow(`SELECT `+jobColumns+` FROM enrichment_jobs WHERE id = $1`, jobID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.EnrichmentJob{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.EnrichmentJob{}, fmt.Errorf("ошибка получения задачи обогащения: %w", err)
+	}
+	return job, nil
+}
+
+func (r *EnrichmentRepository) List(filter models.EnrichmentJobFilter, limit, offset int) ([]models.EnrichmentJob, error) {
+	var b queryBuilder
+	if filter.Status != "" {
+		b.where(`status = ` + b.arg(filter.Status))
+	}
+	if filter.SongID != 0 {
+		b.where(`song_id = ` + b.arg(filter.SongID))
+	}
+	query := `
+		SELECT ` + jobColumns + `
+		FROM enrichment_jobs
+		` + b.whereClause() + `
+		ORDER BY updated_at DESC, id DESC ` + b.page(limit, offset)
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения задач обогащения: %w", err)
+	}
+	return scanJobs(rows)
+}
+
+func (r *EnrichmentRepository) RequeueFailed() (int, error) {
+	query := `
+		WITH job AS (
+			UPDATE enrichment_jobs
+			SET status = 'queued', attempts = 0, run_at = CURRENT_TIMESTAMP, last_error = NULL,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE status = 'failed'
+			RETURNING song_id
+		)
+		UPDATE songs SET enrichment_status = 'pending'
+		WHERE id IN (SELECT song_id FROM job)`
+	res, err := r.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка перезапуска задач обогащения: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка перезапуска задач обогащения: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *EnrichmentRepository) EnqueueStale(maxAge, blankAge time.Duration, limit int) (int, error) {
+	// SKIP LOCKED и смена статуса на pending не дают двум процессам поставить одну песню дважды
+	query := `
+		WITH stale AS (
+			SELECT id FROM songs
+			WHERE enrichment_status = 'done'
+			  AND (enriched_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+			       OR (COALESCE(text, '') = '' OR COALESCE(link, '') = '')
+			          AND (enriched_at IS NULL OR enriched_at < CURRENT_TIMESTAMP - make_interval(secs => $2)))
+			ORDER BY enriched_at NULLS FIRST, id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), job AS (
+			INSERT INTO enrichment_jobs (song_id) SELECT id FROM stale
+			ON CONFLICT (song_id) DO UPDATE
+			SET status = 'queued',
+			    attempts = 0,
+			    run_at = CURRENT_TIMESTAMP,
+			    locked_at = NULL,
+			    last_error = NULL,
+			    updated_at = CURRENT_TIMESTAMP
+			WHERE enrichment_jobs.status <> 'running'
+			RETURNING song_id
+		)
+		UPDATE songs SET enrichment_status = 'pending'
+		WHERE id IN (SELECT song_id FROM job)`
+	res, err := r.db.Exec(query, maxAge.Seconds(), blankAge.Seconds(), limit)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка постановки устаревших песен в очередь обогащения: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка постановки устаревших песен в очередь обогащения: %w", err)
+	}
+	return int(n), nil
+}
+
+// scanJobs читает строки, выбранные по jobColumns, и закрывает rows.
+func scanJobs(rows *sql.Rows) ([]models.EnrichmentJob, error) {
+	defer rows.Close()
+
+	jobs := []models.EnrichmentJob{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка чтения задачи обогащения: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения задач обогащения: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+)
+
+// eachFuzzy ищет песни по сходству триграмм (pg_trgm). Каждое непустое поле фильтра
+// сравнивается с исходным написанием и его транслитерацией, итоговый score —
+// среднее лучших значений по полям.
+func (r *SongRepository) eachFuzzy(filter models.SongFilter, limit, offset int, fn func(models.Song) error) error {
+	// $1..$4 — исходные написания и транслитерации, они же нужны для расчёта score
+	b := queryBuilder{args: []any{
+		filter.Group, normalize.Transliterate(filter.Group),
+		filter.Song, normalize.Transliterate(filter.Song),
+	}}
+	b.where(`($1 = '' OR g.name % $1 OR g.name % $2)`)
+	b.where(`($3 = '' OR s.song_name % $3 OR s.song_name % $4)`)
+	whereSong(&b, filter)
+
+	query := `
+		SELECT ` + songColumns + `,
+		       COALESCE((
+		           CASE WHEN $1 = '' THEN 0 ELSE GREATEST(similarity(g.name, $1), similarity(g.name, $2)) END +
+		           CASE WHEN $3 = '' THEN 0 ELSE GREATEST(similarity(s.song_name, $3), similarity(s.song_name, $4)) END
+		       ) / NULLIF((CASE WHEN $1 = '' THEN 0 ELSE 1 END) + (CASE WHEN $3 = '' THEN 0 ELSE 1 END), 0), 0) AS score` + songFrom + `
+		` + b.whereClause() + `
+		ORDER BY score DESC, s.id ` + b.page(limit, offset)
+
+	// Порог оператора % задаётся на время транзакции, чтобы не влиять на другие соединения пула
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	threshold := strconv.FormatFloat(filter.MinScore, 'f', -1, 64)
+	if _, err := tx.Exec(`SELECT set_config('pg_trgm.similarity_threshold', $1, true)`, threshold); err != nil {
+		return fmt.Errorf("ошибка установки порога сходства: %w", err)
+	}
+
+	rows, err := tx.Query(query, b.args...)
+	if err != nil {
+		return fmt.Errorf("ошибка запроса: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var score float64
+		song, err := scanSong(rows, &score)
+		if err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		song.Score = score
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,389 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// GroupRepository хранит группы в PostgreSQL.
+type GroupRepository struct {
+	db *sql.DB
+}
+
+func NewGroupRepository(provider *conn.PostgresProvider) *GroupRepository {
+	return &GroupRepository{db: provider.DB()}
+}
+
+var _ repository.GroupRepository = (*GroupRepository)(nil)
+
+// groupColumns — список колонок, который читает scanGroup. Ожидает алиас g (groups).
+const groupColumns = `g.id, g.name, COALESCE(g.country, ''), g.formed_year, COALESCE(g.bio, ''),
+		g.created_at, g.updated_at, (SELECT COUNT(*) FROM songs s WHERE s.group_id = g.id),
+		COALESCE((SELECT c.checksum FROM covers c WHERE c.group_id = g.id), '')`
+
+func scanGroup(row scanner, extra ...any) (models.Group, error) {
+	var group models.Group
+	var formedYear sql.NullInt64
+	var createdAt, updatedAt sql.NullTime
+	dest := append([]any{&group.ID, &group.Name, &group.Country, &formedYear, &group.Bio,
+		&createdAt, &updatedAt, &group.SongCount, &group.CoverChecksum}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return models.Group{}, err
+	}
+	group.FormedYear = nullIntPtr(formedYear)
+	group.CreatedAt = createdAt.Time
+	group.UpdatedAt = updatedAt.Time
+	return group, nil
+}
+
+func collectGroups(rows *sql.Rows) ([]models.Group, error) {
+	defer rows.Close()
+
+	groups := []models.Group{}
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return groups, nil
+}
+
+func (r *GroupRepository) List(filter models.GroupFilter, limit, offset int) ([]models.Group, error) {
+	query := `
+		SELECT ` + groupColumns + `
+		FROM groups g
+		WHERE ($1 = '' OR g.name ILIKE '%' || $1 || '%')
+		AND ($2 = '' OR g.country ILIKE $2)
+		ORDER BY g.name, g.id LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(query, filter.Name, filter.Country, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса групп: %w", err)
+	}
+	return collectGroups(rows)
+}
+
+func (r *GroupRepository) Get(id int) (models.Group, error) {
+	query := `
+		SELECT ` + groupColumns + `
+		FROM groups g
+		WHERE g.id = $1`
+	group, err := scanGroup(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Group{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.Group{}, fmt.Errorf("ошибка получения группы: %w", err)
+	}
+
+	group.Aliases, err = r.aliases(id)
+	if err != nil {
+		return models.Group{}, err
+	}
+	return group, nil
+}
+
+func (r *GroupRepository) aliases(groupID int) ([]string, error) {
+	rows, err := r.db.Query(`SELECT alias FROM group_aliases WHERE group_id = $1 ORDER BY alias`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса псевдонимов группы: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := []string{}
+	for rows.Next() {
+		var alias string
+		if err := rows.Scan(&alias); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return aliases, nil
+}
+
+func (r *GroupRepository) FindByName(name string) (int, error) {
+	// Точное совпадение важнее нормализованного: после нормализации названия
+	// разных групп, ещё не слитых в одну, могут совпасть
+	query := `
+		SELECT id FROM (
+			SELECT id, 0 AS priority FROM groups WHERE name = $1
+			UNION ALL
+			SELECT id, 1 FROM groups WHERE normalized_name = $2
+			UNION ALL
+			SELECT group_id, 2 FROM group_aliases WHERE normalized_alias = $2
+		) matches
+		ORDER BY priority, id
+		LIMIT 1`
+	var id int
+	err := r.db.QueryRow(query, name, normalize.Name(name)).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка проверки группы: %w", err)
+	}
+	return id, nil
+}
+
+func (r *GroupRepository) FindSimilar(name string, limit int) ([]models.Group, error) {
+	// Оператор % использует триграммный индекс и стандартный порог pg_trgm
+	query := `
+		SELECT ` + groupColumns + `
+		FROM groups g
+		WHERE g.name % $1
+		ORDER BY similarity(g.name, $1) DESC, g.id
+		LIMIT $2`
+
+	rows, err := r.db.Query(query, name, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска похожих групп: %w", err)
+	}
+	return collectGroups(rows)
+}
+
+func (r *GroupRepository) Create(group models.Group) (int, error) {
+	query := `
+		INSERT INTO groups (name, normalized_name, country, formed_year, bio)
+		VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, ''))
+		RETURNING id`
+	var id int
+	err := r.db.QueryRow(query, group.Name, normalize.Name(group.Name),
+		group.Country, group.FormedYear, group.Bio).Scan(&id)
+	if isUniqueViolation(err) {
+		return 0, repository.ErrConflict
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка добавления группы: %w", err)
+	}
+	return id, nil
+}
+
+func (r *GroupRepository) Update(id int, update models.GroupUpdate) error {
+	query := `
+		UPDATE groups
+		SET name = COALESCE(NULLIF($1, ''), name),
+		    normalized_name = COALESCE(NULLIF($2, ''), normalized_name),
+		    country = COALESCE($3, country),
+		    formed_year = COALESCE($4, formed_year),
+		    bio = COALESCE($5, bio),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6`
+	var normalized string
+	if update.Name != nil {
+		normalized = normalize.Name(*update.Name)
+	}
+	res, err := r.db.Exec(query, update.Name, normalized, update.Country, update.FormedYear, update.Bio, id)
+	if isUniqueViolation(err) {
+		return repository.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка обновления группы: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *GroupRepository) SongIDs(groupID int) ([]int, error) {
+	rows, err := r.db.Query(`SELECT id FROM songs WHERE group_id = $1 ORDER BY id`, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса песен группы: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения песен группы: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *GroupRepository) Delete(id int, cascade bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем группу, чтобы к ней не успели добавить песню между проверкой и удалением
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM groups WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка блокировки группы: %w", err)
+	}
+
+	if !cascade {
+		var hasDependents bool
+		err := tx.QueryRow(`
+			SELECT EXISTS (SELECT 1 FROM songs WHERE group_id = $1)
+			    OR EXISTS (SELECT 1 FROM albums WHERE group_id = $1)`, id).Scan(&hasDependents)
+		if err != nil {
+			return fmt.Errorf("ошибка проверки песен группы: %w", err)
+		}
+		if hasDependents {
+			return repository.ErrConflict
+		}
+	}
+
+	// Песни и альбомы удаляются каскадно внешними ключами
+	if _, err := tx.Exec(`DELETE FROM groups WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("ошибка удаления группы: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return nil
+}
+
+func (r *GroupRepository) Merge(sourceID, targetID int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Блокируем обе группы в порядке ID, чтобы встречные слияния не взаимоблокировались
+	rows, err := tx.Query(`SELECT id, name FROM groups WHERE id IN ($1, $2) ORDER BY id FOR UPDATE`, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("ошибка блокировки групп: %w", err)
+	}
+	var sourceName string
+	locked := 0
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		if id == sourceID {
+			sourceName = name
+		}
+		locked++
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	rows.Close()
+	if locked < 2 {
+		return repository.ErrNotFound
+	}
+
+	statements := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE songs SET group_id = $2, updated_at = CURRENT_TIMESTAMP WHERE group_id = $1`, []any{sourceID, targetID}},
+		{`UPDATE albums SET group_id = $2, updated_at = CURRENT_TIMESTAMP WHERE group_id = $1`, []any{sourceID, targetID}},
+		{`UPDATE group_aliases SET group_id = $2 WHERE group_id = $1`, []any{sourceID, targetID}},
+		{`
+			INSERT INTO group_aliases (group_id, alias, normalized_alias) VALUES ($1, $2, $3)
+			ON CONFLICT (alias) DO UPDATE SET group_id = EXCLUDED.group_id`, []any{targetID, sourceName, normalize.Name(sourceName)}},
+		// Незаполненные сведения о группе берём у источника
+		{`
+			UPDATE groups t
+			SET country = COALESCE(t.country, s.country),
+			    formed_year = COALESCE(t.formed_year, s.formed_year),
+			    bio = COALESCE(t.bio, s.bio),
+			    updated_at = CURRENT_TIMESTAMP
+			FROM groups s
+			WHERE s.id = $1 AND t.id = $2`, []any{sourceID, targetID}},
+		{`DELETE FROM groups WHERE id = $1`, []any{sourceID}},
+	}
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt.query, stmt.args...); err != nil {
+			return fmt.Errorf("ошибка слияния групп: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return nil
+}
+
+func (r *GroupRepository) BackfillNormalized() (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Нормализация выполняется в Go, поэтому сначала читаем строки, затем обновляем их
+	tables := []struct {
+		selectQuery string
+		updateQuery string
+	}{
+		{
+			`SELECT id, name FROM groups WHERE normalized_name IS NULL FOR UPDATE`,
+			`UPDATE groups SET normalized_name = $1 WHERE id = $2`,
+		},
+		{
+			`SELECT id, alias FROM group_aliases WHERE normalized_alias IS NULL FOR UPDATE`,
+			`UPDATE group_aliases SET normalized_alias = $1 WHERE id = $2`,
+		},
+	}
+
+	updated := 0
+	for _, table := range tables {
+		names, err := readNames(tx, table.selectQuery)
+		if err != nil {
+			return 0, err
+		}
+		for id, name := range names {
+			if _, err := tx.Exec(table.updateQuery, normalize.Name(name), id); err != nil {
+				return 0, fmt.Errorf("ошибка заполнения нормализованного названия: %w", err)
+			}
+			updated++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return updated, nil
+}
+
+// readNames читает пары (id, название), возвращаемые запросом.
+func readNames(tx *sql.Tx, query string) (map[int]string, error) {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса названий: %w", err)
+	}
+	defer rows.Close()
+
+	names := map[int]string{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		names[id] = name
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return names, nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// LyricsRepository хранит синхронизированные тексты песен в PostgreSQL.
+type LyricsRepository struct {
+	db *sql.DB
+}
+
+func NewLyricsRepository(provider *conn.PostgresProvider) *LyricsRepository {
+	return &LyricsRepository{db: provider.DB()}
+}
+
+var _ repository.LyricsRepository = (*LyricsRepository)(nil)
+
+func (r *LyricsRepository) Get(songID int) (models.SongLyrics, error) {
+	query := `
+		SELECT song_id, lrc, line_count, created_at, updated_at
+		FROM song_lyrics
+		WHERE song_id = $1`
+	var lyrics models.SongLyrics
+	var createdAt, updatedAt sql.NullTime
+	err := r.db.QueryRow(query, songID).Scan(&lyrics.SongID, &lyrics.LRC, &lyrics.LineCount, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SongLyrics{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.SongLyrics{}, fmt.Errorf("ошибка получения текста LRC: %w", err)
+	}
+	lyrics.CreatedAt = createdAt.Time
+	lyrics.UpdatedAt = updatedAt.Time
+	return lyrics, nil
+}
+
+func (r *LyricsRepository) Save(lyrics models.SongLyrics) error {
+	query := `
+		INSERT INTO song_lyrics (song_id, lrc, line_count)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (song_id) DO UPDATE
+		SET lrc = EXCLUDED.lrc,
+		    line_count = EXCLUDED.line_count,
+		    updated_at = CURRENT_TIMESTAMP`
+	_, err := r.db.Exec(query, lyrics.SongID, lyrics.LRC, lyrics.LineCount)
+	if isForeignKeyViolation(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения текста LRC: %w", err)
+	}
+	return nil
+}
+
+func (r *LyricsRepository) Delete(songID int) error {
+	res, err := r.db.Exec(`DELETE FROM song_lyrics WHERE song_id = $1`, songID)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления текста LRC: %w", err)
+	}
+	return checkAffected(res)
+}
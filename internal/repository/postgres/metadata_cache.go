@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// MetadataCacheRepository хранит кэш ответов внешнего API в PostgreSQL.
+type MetadataCacheRepository struct {
+	db *sql.DB
+}
+
+func NewMetadataCacheRepository(provider *conn.PostgresProvider) *MetadataCacheRepository {
+	return &MetadataCacheRepository{db: provider.DB()}
+}
+
+var _ repository.MetadataCacheRepository = (*MetadataCacheRepository)(nil)
+
+func (r *MetadataCacheRepository) Get(key string) (models.CachedSongDetails, error) {
+	// Остаток срока считает база, чтобы не сравнивать её время с временем процесса
+	query := `
+		SELECT key, COALESCE(release_date, ''), COALESCE(text, ''), COALESCE(link, ''), not_found,
+		       EXTRACT(EPOCH FROM expires_at - CURRENT_TIMESTAMP)
+		FROM metadata_cache
+		WHERE key = $1 AND expires_at > CURRENT_TIMESTAMP`
+	var entry models.CachedSongDetails
+	var ttl float64
+	err := r.db.QueryRow(query, key).Scan(&entry.Key, &entry.ReleaseDate, &entry.Text, &entry.Link, &entry.NotFound, &ttl)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.CachedSongDetails{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.CachedSongDetails{}, fmt.Errorf("ошибка чтения кэша сведений о песне: %w", err)
+	}
+	entry.TTL = time.Duration(ttl * float64(time.Second))
+	return entry, nil
+}
+
+func (r *MetadataCacheRepository) Put(entry models.CachedSongDetails) error {
+	query := `
+		INSERT INTO metadata_cache (key, release_date, text, link, not_found, expires_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), $5, CURRENT_TIMESTAMP + make_interval(secs => $6))
+		ON CONFLICT (key) DO UPDATE
+		SET release_date = EXCLUDED.release_date,
+		    text = EXCLUDED.text,
+		    link = EXCLUDED.link,
+		    not_found = EXCLUDED.not_found,
+		    expires_at = EXCLUDED.expires_at,
+		    updated_at = CURRENT_TIMESTAMP`
+	_, err := r.db.Exec(query, entry.Key, entry.ReleaseDate, entry.Text, entry.Link, entry.NotFound, entry.TTL.Seconds())
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения кэша сведений о песне: %w", err)
+	}
+	return nil
+}
+
+func (r *MetadataCacheRepository) DeleteExpired() (int, error) {
+	res, err := r.db.Exec(`DELETE FROM metadata_cache WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка очистки кэша сведений о песнях: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка очистки кэша сведений о песнях: %w", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// PlaylistRepository хранит плейлисты в PostgreSQL.
+type PlaylistRepository struct {
+	db *sql.DB
+}
+
+func NewPlaylistRepository(provider *conn.PostgresProvider) *PlaylistRepository {
+	return &PlaylistRepository{db: provider.DB()}
+}
+
+var _ repository.PlaylistRepository = (*PlaylistRepository)(nil)
+
+const playlistColumns = `p.id, p.name, COALESCE(p.description, ''), p.created_at, p.updated_at,
+		(SELECT COUNT(*) FROM playlist_items i WHERE i.playlist_id = p.id)`
+
+func scanPlaylist(row scanner) (models.Playlist, error) {
+	var playlist models.Playlist
+	var createdAt, updatedAt sql.NullTime
+	if err := row.Scan(&playlist.ID, &playlist.Name, &playlist.Description, &createdAt, &updatedAt,
+		&playlist.ItemCount); err != nil {
+		return models.Playlist{}, err
+	}
+	playlist.CreatedAt = createdAt.Time
+	playlist.UpdatedAt = updatedAt.Time
+	return playlist, nil
+}
+
+func (r *PlaylistRepository) List(limit, offset int) ([]models.Playlist, error) {
+	query := `
+		SELECT ` + playlistColumns + `
+		FROM playlists p
+		ORDER BY p.name, p.id LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса плейлистов: %w", err)
+	}
+	defer rows.Close()
+
+	playlists := []models.Playlist{}
+	for rows.Next() {
+		playlist, err := scanPlaylist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		playlists = append(playlists, playlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return playlists, nil
+}
+
+func (r *PlaylistRepository) Get(id int) (models.Playlist, error) {
+	query := `
+		SELECT ` + playlistColumns + `
+		FROM playlists p
+		WHERE p.id = $1`
+	playlist, err := scanPlaylist(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Playlist{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.Playlist{}, fmt.Errorf("ошибка получения плейлиста: %w", err)
+	}
+	return playlist, nil
+}
+
+func (r *PlaylistRepository) Create(playlist models.Playlist) (int, error) {
+	query := `
+		INSERT INTO playlists (name, description)
+		VALUES ($1, NULLIF($2, ''))
+		RETURNING id`
+	var id int
+	if err := r.db.QueryRow(query, playlist.Name, playlist.Description).Scan(&id); err != nil {
+		return 0, fmt.Errorf("ошибка добавления плейлиста: %w", err)
+	}
+	return id, nil
+}
+
+func (r *PlaylistRepository) Update(id int, update models.PlaylistUpdate) error {
+	query := `
+		UPDATE playlists
+		SET name = COALESCE(NULLIF($1, ''), name),
+		    description = COALESCE($2, description),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $3`
+	res, err := r.db.Exec(query, update.Name, update.Description, id)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления плейлиста: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *PlaylistRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM playlists WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления плейлиста: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *PlaylistRepository) Items(playlistID, limit, offset int) ([]models.PlaylistItem, error) {
+	items := []models.PlaylistItem{}
+	err := r.EachItem(playlistID, limit, offset, func(item models.PlaylistItem) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *PlaylistRepository) EachItem(playlistID, limit, offset int, fn func(models.PlaylistItem) error) error {
+	// Позиция считается заново при чтении: после каскадного удаления песни
+	// в сохранённых позициях может остаться пропуск
+	b := queryBuilder{args: []any{playlistID}}
+	query := `
+		WITH i AS (
+			SELECT id, song_id, added_at, ROW_NUMBER() OVER (ORDER BY position, id) AS position
+			FROM playlist_items
+			WHERE playlist_id = $1
+		)
+		SELECT ` + songColumns + `, i.id, i.position, i.added_at` + songFrom + `
+		JOIN i ON i.song_id = s.id
+		ORDER BY i.position ` + b.page(limit, offset)
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return fmt.Errorf("ошибка запроса элементов плейлиста: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.PlaylistItem
+		var addedAt sql.NullTime
+		item.Song, err = scanSong(rows, &item.ID, &item.Position, &addedAt)
+		if err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		item.AddedAt = addedAt.Time
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return nil
+}
+
+func (r *PlaylistRepository) AddItem(playlistID, songID, position int) (int, error) {
+	var itemID int
+	err := r.reorder(playlistID, func(tx *sql.Tx, ids []int) ([]int, error) {
+		// Временная позиция в конце, итоговая проставится при перенумерации
+		err := tx.QueryRow(`
+			INSERT INTO playlist_items (playlist_id, song_id, position)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(position), 0) + 1 FROM playlist_items WHERE playlist_id = $1))
+			RETURNING id`, playlistID, songID).Scan(&itemID)
+		if isForeignKeyViolation(err) {
+			return nil, repository.ErrNotFound
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ошибка добавления элемента плейлиста: %w", err)
+		}
+		return insertAt(ids, itemID, position), nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return itemID, nil
+}
+
+func (r *PlaylistRepository) RemoveItem(playlistID, itemID int) error {
+	return r.reorder(playlistID, func(tx *sql.Tx, ids []int) ([]int, error) {
+		rest, ok := without(ids, itemID)
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		if _, err := tx.Exec(`DELETE FROM playlist_items WHERE id = $1`, itemID); err != nil {
+			return nil, fmt.Errorf("ошибка удаления элемента плейлиста: %w", err)
+		}
+		return rest, nil
+	})
+}
+
+func (r *PlaylistRepository) MoveItem(playlistID, itemID, position int) error {
+	return r.reorder(playlistID, func(tx *sql.Tx, ids []int) ([]int, error) {
+		rest, ok := without(ids, itemID)
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return insertAt(rest, itemID, position), nil
+	})
+}
+
+// reorder выполняет изменение элементов плейлиста в транзакции: блокирует плейлист,
+// передаёт change ID элементов по порядку и проставляет позиции 1..n по возвращённому
+// порядку. Уникальность позиций проверяется при COMMIT (ограничение DEFERRABLE).
+func (r *PlaylistRepository) reorder(playlistID int, change func(tx *sql.Tx, ids []int) ([]int, error)) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRow(`SELECT id FROM playlists WHERE id = $1 FOR UPDATE`, playlistID).Scan(&locked)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка блокировки плейлиста: %w", err)
+	}
+
+	rows, err := tx.Query(`SELECT id FROM playlist_items WHERE playlist_id = $1 ORDER BY position, id`, playlistID)
+	if err != nil {
+		return fmt.Errorf("ошибка запроса элементов плейлиста: %w", err)
+	}
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	rows.Close()
+
+	ids, err = change(tx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range ids {
+		if _, err := tx.Exec(`UPDATE playlist_items SET position = $1 WHERE id = $2 AND position <> $1`, i+1, id); err != nil {
+			return fmt.Errorf("ошибка перенумерации плейлиста: %w", err)
+		}
+	}
+	if _, err := tx.Exec(`UPDATE playlists SET updated_at = CURRENT_TIMESTAMP WHERE id = $1`, playlistID); err != nil {
+		return fmt.Errorf("ошибка обновления плейлиста: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return nil
+}
+
+// insertAt вставляет id на позицию position (с 1); 0 или позиция за концом — в конец.
+func insertAt(ids []int, id, position int) []int {
+	if position <= 0 || position > len(ids) {
+		return append(ids, id)
+	}
+	ids = append(ids, 0)
+	copy(ids[position:], ids[position-1:])
+	ids[position-1] = id
+	return ids
+}
+
+// without возвращает ids без id и сообщает, был ли он в списке.
+func without(ids []int, id int) ([]int, bool) {
+	for i, v := range ids {
+		if v == id {
+			return append(ids[:i:i], ids[i+1:]...), true
+		}
+	}
+	return ids, false
+}
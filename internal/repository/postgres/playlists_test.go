@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInsertAt(t *testing.T) {
+	tests := []struct {
+		name     string
+		ids      []int
+		id       int
+		position int
+		want     []int
+	}{
+		{name: "в пустой", ids: []int{}, id: 7, position: 1, want: []int{7}},
+		{name: "в начало", ids: []int{1, 2, 3}, id: 7, position: 1, want: []int{7, 1, 2, 3}},
+		{name: "в середину", ids: []int{1, 2, 3}, id: 7, position: 2, want: []int{1, 7, 2, 3}},
+		{name: "на последнюю позицию", ids: []int{1, 2, 3}, id: 7, position: 3, want: []int{1, 2, 7, 3}},
+		{name: "ноль — в конец", ids: []int{1, 2, 3}, id: 7, position: 0, want: []int{1, 2, 3, 7}},
+		{name: "за концом — в конец", ids: []int{1, 2, 3}, id: 7, position: 4, want: []int{1, 2, 3, 7}},
+		{name: "повтор", ids: []int{1, 2}, id: 1, position: 2, want: []int{1, 1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := insertAt(slices.Clone(tt.ids), tt.id, tt.position); !slices.Equal(got, tt.want) {
+				t.Errorf("insertAt(%v, %d, %d) = %v, want %v", tt.ids, tt.id, tt.position, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithout(t *testing.T) {
+	ids := []int{1, 2, 3}
+	if got, ok := without(ids, 2); !ok || !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("without(2) = %v, %v", got, ok)
+	}
+	if !slices.Equal(ids, []int{1, 2, 3}) {
+		t.Errorf("without изменил исходный срез: %v", ids)
+	}
+	if got, ok := without(ids, 5); ok || !slices.Equal(got, ids) {
+		t.Errorf("without(5) = %v, %v, want исходный срез и false", got, ok)
+	}
+}
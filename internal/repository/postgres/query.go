@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"strconv"
+	"strings"
+)
+
+// queryBuilder собирает условия WHERE и нумерует их параметры ($1, $2, ...),
+// чтобы не перечислять все фильтры в одном статическом запросе.
+type queryBuilder struct {
+	conds []string
+	args  []any
+}
+
+// arg добавляет параметр запроса и возвращает его плейсхолдер.
+func (b *queryBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// where добавляет условие; условия объединяются через AND.
+func (b *queryBuilder) where(cond string) {
+	b.conds = append(b.conds, cond)
+}
+
+// whereClause возвращает WHERE со всеми условиями или пустую строку.
+func (b *queryBuilder) whereClause() string {
+	if len(b.conds) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conds, "\n\t\tAND ")
+}
+
+// page возвращает LIMIT и OFFSET; отрицательный limit снимает ограничение на число строк.
+func (b *queryBuilder) page(limit, offset int) string {
+	if limit < 0 {
+		return "OFFSET " + b.arg(offset)
+	}
+	return "LIMIT " + b.arg(limit) + " OFFSET " + b.arg(offset)
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// addTextRevision записывает текст песни следующей версией. Номер берётся из истории
+// песни, поэтому строку песни в транзакции нужно заблокировать или только что создать.
+func addTextRevision(tx *sql.Tx, songID int, text, author string) error {
+	_, err := tx.Exec(`
+		INSERT INTO song_text_revisions (song_id, revision, text, author)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, NULLIF($3, '')
+		FROM song_text_revisions
+		WHERE song_id = $1`, songID, text, author)
+	if err != nil {
+		return fmt.Errorf("ошибка сохранения версии текста: %w", err)
+	}
+	return nil
+}
+
+func (r *SongRepository) TextRevisions(songID, limit, offset int) ([]models.TextRevision, error) {
+	query := `
+		SELECT song_id, revision, text, COALESCE(author, ''), created_at
+		FROM song_text_revisions
+		WHERE song_id = $1
+		ORDER BY revision DESC
+		LIMIT $2 OFFSET $3`
+	rows, err := r.db.Query(query, songID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка получения версий текста: %w", err)
+	}
+	defer rows.Close()
+
+	revisions := []models.TextRevision{}
+	for rows.Next() {
+		revision, err := scanTextRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения версий текста: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *SongRepository) TextRevision(songID, revision int) (models.TextRevision, error) {
+	query := `
+		SELECT song_id, revision, text, COALESCE(author, ''), created_at
+		FROM song_text_revisions
+		WHERE song_id = $1 AND revision = $2`
+	result, err := scanTextRevision(r.db.QueryRow(query, songID, revision))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TextRevision{}, repository.ErrNotFound
+	}
+	return result, err
+}
+
+func scanTextRevision(row scanner) (models.TextRevision, error) {
+	var revision models.TextRevision
+	var createdAt sql.NullTime
+	err := row.Scan(&revision.SongID, &revision.Revision, &revision.Text, &revision.Author, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TextRevision{}, err
+	}
+	if err != nil {
+		return models.TextRevision{}, fmt.Errorf("ошибка чтения версии текста: %w", err)
+	}
+	revision.CreatedAt = createdAt.Time
+	return revision, nil
+}
@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// ruleColumns — выражения для строковых полей правил. Ожидают таблицы из songFrom.
+var ruleColumns = map[string]string{
+	models.RuleFieldGroup: "g.name",
+	models.RuleFieldSong:  "s.song_name",
+	models.RuleFieldAlbum: "COALESCE(a.title, '')",
+	models.RuleFieldText:  "COALESCE(s.text, '')",
+}
+
+// whereRule переводит правило умного плейлиста в условие SQL. Правила проверяются
+// сервисом при сохранении, поэтому неизвестные поля и операторы дают FALSE.
+func whereRule(b *queryBuilder, rule models.SmartRule) string {
+	if rule.IsGroup() {
+		if len(rule.Rules) == 0 {
+			return "TRUE"
+		}
+		conds := make([]string, 0, len(rule.Rules))
+		for _, child := range rule.Rules {
+			conds = append(conds, whereRule(b, child))
+		}
+		sep := " AND "
+		if rule.Match == models.RuleMatchAny {
+			sep = " OR "
+		}
+		return "(" + strings.Join(conds, sep) + ")"
+	}
+
+	switch rule.Field {
+	case models.RuleFieldReleaseDate:
+		return whereRuleDate(b, rule)
+	case models.RuleFieldGenre, models.RuleFieldTag:
+		kind := models.TagKindGenre
+		if rule.Field == models.RuleFieldTag {
+			kind = models.TagKindTag
+		}
+		cond := tagTables[kind].songHas(b.arg(rule.Values[0]))
+		if rule.Op == models.RuleOpIsNot {
+			return "NOT " + cond
+		}
+		return cond
+	}
+
+	column, ok := ruleColumns[rule.Field]
+	if !ok {
+		return "FALSE"
+	}
+	switch rule.Op {
+	case models.RuleOpEquals:
+		return "lower(" + column + ") = lower(" + b.arg(rule.Values[0]) + ")"
+	case models.RuleOpContains:
+		return column + " ILIKE '%' || " + b.arg(likeEscape(rule.Values[0])) + " || '%'"
+	case models.RuleOpNotContains:
+		return column + " NOT ILIKE '%' || " + b.arg(likeEscape(rule.Values[0])) + " || '%'"
+	case models.RuleOpStartsWith:
+		return column + " ILIKE " + b.arg(likeEscape(rule.Values[0])) + " || '%'"
+	case models.RuleOpLike:
+		return column + " ILIKE " + b.arg(rule.Values[0])
+	}
+	return "FALSE"
+}
+
+// whereRuleDate строит условие на дату выпуска; песни без даты ему не удовлетворяют.
+func whereRuleDate(b *queryBuilder, rule models.SmartRule) string {
+	from, to, err := models.RuleDateRange(rule.Values[0])
+	if err != nil {
+		return "FALSE"
+	}
+	switch rule.Op {
+	case models.RuleOpBefore:
+		return "s.release_date < " + b.arg(from)
+	case models.RuleOpAfter:
+		return "s.release_date > " + b.arg(to)
+	case models.RuleOpEquals:
+		return "s.release_date BETWEEN " + b.arg(from) + " AND " + b.arg(to)
+	case models.RuleOpBetween:
+		_, end, err := models.RuleDateRange(rule.Values[1])
+		if err != nil {
+			return "FALSE"
+		}
+		return "s.release_date BETWEEN " + b.arg(from) + " AND " + b.arg(end)
+	}
+	return "FALSE"
+}
+
+// likeEscape экранирует спецсимволы ILIKE, чтобы значение искалось буквально.
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
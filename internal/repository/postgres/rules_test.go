@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+func cond(field, op string, values ...string) models.SmartRule {
+	return models.SmartRule{Field: field, Op: op, Values: values}
+}
+
+func day(value string) time.Time {
+	t, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestWhereRule(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     models.SmartRule
+		wantSQL  string
+		wantArgs []any
+	}{
+		{
+			name:    "пустая группа",
+			rule:    models.SmartRule{Match: models.RuleMatchAll},
+			wantSQL: "TRUE",
+		},
+		{
+			name:     "равенство без учёта регистра",
+			rule:     cond(models.RuleFieldGroup, models.RuleOpEquals, "Muse"),
+			wantSQL:  "lower(g.name) = lower($1)",
+			wantArgs: []any{"Muse"},
+		},
+		{
+			name:     "подстрока экранируется",
+			rule:     cond(models.RuleFieldSong, models.RuleOpContains, `100%_a\b`),
+			wantSQL:  "s.song_name ILIKE '%' || $1 || '%'",
+			wantArgs: []any{`100\%\_a\\b`},
+		},
+		{
+			name:     "без подстроки",
+			rule:     cond(models.RuleFieldText, models.RuleOpNotContains, "love"),
+			wantSQL:  "COALESCE(s.text, '') NOT ILIKE '%' || $1 || '%'",
+			wantArgs: []any{"love"},
+		},
+		{
+			name:     "начало экранируется",
+			rule:     cond(models.RuleFieldAlbum, models.RuleOpStartsWith, "50%"),
+			wantSQL:  "COALESCE(a.title, '') ILIKE $1 || '%'",
+			wantArgs: []any{`50\%`},
+		},
+		{
+			name:     "шаблон передаётся как есть",
+			rule:     cond(models.RuleFieldSong, models.RuleOpLike, "Hyst%_a"),
+			wantSQL:  "s.song_name ILIKE $1",
+			wantArgs: []any{"Hyst%_a"},
+		},
+		{
+			name:     "год",
+			rule:     cond(models.RuleFieldReleaseDate, models.RuleOpEquals, "2009"),
+			wantSQL:  "s.release_date BETWEEN $1 AND $2",
+			wantArgs: []any{day("2009-01-01"), day("2009-12-31")},
+		},
+		{
+			name:     "до года",
+			rule:     cond(models.RuleFieldReleaseDate, models.RuleOpBefore, "2000"),
+			wantSQL:  "s.release_date < $1",
+			wantArgs: []any{day("2000-01-01")},
+		},
+		{
+			name:     "после года",
+			rule:     cond(models.RuleFieldReleaseDate, models.RuleOpAfter, "2000"),
+			wantSQL:  "s.release_date > $1",
+			wantArgs: []any{day("2000-12-31")},
+		},
+		{
+			name:     "период",
+			rule:     cond(models.RuleFieldReleaseDate, models.RuleOpBetween, "1990", "1999-06-15"),
+			wantSQL:  "s.release_date BETWEEN $1 AND $2",
+			wantArgs: []any{day("1990-01-01"), day("1999-06-15")},
+		},
+		{
+			name:     "нет жанра",
+			rule:     cond(models.RuleFieldGenre, models.RuleOpIsNot, "rock"),
+			wantSQL:  "NOT " + tagTables[models.TagKindGenre].songHas("$1"),
+			wantArgs: []any{"rock"},
+		},
+		{
+			name:    "неизвестное поле",
+			rule:    cond("rating", models.RuleOpEquals, "5"),
+			wantSQL: "FALSE",
+		},
+		{
+			name: "вложенные группы",
+			rule: models.SmartRule{Match: models.RuleMatchAny, Rules: []models.SmartRule{
+				cond(models.RuleFieldGroup, models.RuleOpEquals, "Muse"),
+				{Match: models.RuleMatchAll, Rules: []models.SmartRule{
+					cond(models.RuleFieldTag, models.RuleOpIs, "live"),
+					cond(models.RuleFieldSong, models.RuleOpStartsWith, "a_b"),
+				}},
+			}},
+			wantSQL: "(lower(g.name) = lower($1) OR (" + tagTables[models.TagKindTag].songHas("$2") +
+				" AND s.song_name ILIKE $3 || '%'))",
+			wantArgs: []any{"Muse", "live", `a\_b`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b queryBuilder
+			if got := whereRule(&b, tt.rule); got != tt.wantSQL {
+				t.Errorf("SQL = %s\nwant  %s", got, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(b.args, tt.wantArgs) {
+				t.Errorf("args = %#v, want %#v", b.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestLikeEscape(t *testing.T) {
+	tests := map[string]string{
+		"Muse":      "Muse",
+		"100%":      `100\%`,
+		"a_b":       `a\_b`,
+		`C:\music`:  `C:\\music`,
+		`\%_`:       `\\\%\_`,
+		"Сплин 50%": `Сплин 50\%`,
+	}
+	for in, want := range tests {
+		if got := likeEscape(in); got != want {
+			t.Errorf("likeEscape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// headlineOptions — параметры ts_headline: совпадения выделяются тегом <b>.
+const headlineOptions = "StartSel=<b>, StopSel=</b>, MaxWords=35, MinWords=10, MaxFragments=2"
+
+func (r *SongRepository) Search(query models.SearchQuery, limit, offset int) ([]models.SearchResult, error) {
+	// Пустой язык — ищем одновременно по английской и русской морфологии.
+	// Для подсветки берём конфигурацию russian: латиницу она обрабатывает english_stem.
+	sqlQuery := `
+		WITH q AS (
+			SELECT CASE $2
+				WHEN 'english' THEN websearch_to_tsquery('english', $1)
+				WHEN 'russian' THEN websearch_to_tsquery('russian', $1)
+				ELSE websearch_to_tsquery('english', $1) || websearch_to_tsquery('russian', $1)
+			END AS query
+		)
+		SELECT ` + songColumns + `,
+		       ts_rank(s.search_vector || g.search_vector, q.query) AS rank,
+		       ts_headline(
+		           CASE $2 WHEN 'english' THEN 'english' ELSE 'russian' END::regconfig,
+		           COALESCE(NULLIF(s.text, ''), s.song_name), q.query, $5
+		       ) AS headline` + songFrom + `
+		CROSS JOIN q
+		WHERE s.search_vector @@ q.query OR g.search_vector @@ q.query
+		ORDER BY rank DESC, s.id
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.Query(sqlQuery, query.Query, query.Language, limit, offset, headlineOptions)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка поиска: %w", err)
+	}
+	defer rows.Close()
+
+	results := []models.SearchResult{}
+	for rows.Next() {
+		var result models.SearchResult
+		result.Song, err = scanSong(rows, &result.Rank, &result.Headline)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return results, nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// SmartPlaylistRepository хранит умные плейлисты в PostgreSQL.
+type SmartPlaylistRepository struct {
+	db *sql.DB
+}
+
+func NewSmartPlaylistRepository(provider *conn.PostgresProvider) *SmartPlaylistRepository {
+	return &SmartPlaylistRepository{db: provider.DB()}
+}
+
+var _ repository.SmartPlaylistRepository = (*SmartPlaylistRepository)(nil)
+
+const smartPlaylistColumns = `id, name, COALESCE(description, ''), rules, created_at, updated_at`
+
+func scanSmartPlaylist(row scanner) (models.SmartPlaylist, error) {
+	var playlist models.SmartPlaylist
+	var rules []byte
+	var createdAt, updatedAt sql.NullTime
+	if err := row.Scan(&playlist.ID, &playlist.Name, &playlist.Description, &rules, &createdAt, &updatedAt); err != nil {
+		return models.SmartPlaylist{}, err
+	}
+	if err := json.Unmarshal(rules, &playlist.Rules); err != nil {
+		return models.SmartPlaylist{}, fmt.Errorf("ошибка разбора правил плейлиста %d: %w", playlist.ID, err)
+	}
+	playlist.CreatedAt = createdAt.Time
+	playlist.UpdatedAt = updatedAt.Time
+	return playlist, nil
+}
+
+func (r *SmartPlaylistRepository) List(limit, offset int) ([]models.SmartPlaylist, error) {
+	query := `
+		SELECT ` + smartPlaylistColumns + `
+		FROM smart_playlists
+		ORDER BY name, id LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса умных плейлистов: %w", err)
+	}
+	defer rows.Close()
+
+	playlists := []models.SmartPlaylist{}
+	for rows.Next() {
+		playlist, err := scanSmartPlaylist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		playlists = append(playlists, playlist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return playlists, nil
+}
+
+func (r *SmartPlaylistRepository) Get(id int) (models.SmartPlaylist, error) {
+	query := `
+		SELECT ` + smartPlaylistColumns + `
+		FROM smart_playlists
+		WHERE id = $1`
+	playlist, err := scanSmartPlaylist(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SmartPlaylist{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.SmartPlaylist{}, fmt.Errorf("ошибка получения умного плейлиста: %w", err)
+	}
+	return playlist, nil
+}
+
+func (r *SmartPlaylistRepository) Create(playlist models.SmartPlaylist) (int, error) {
+	rules, err := json.Marshal(playlist.Rules)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка кодирования правил: %w", err)
+	}
+	query := `
+		INSERT INTO smart_playlists (name, description, rules)
+		VALUES ($1, NULLIF($2, ''), $3)
+		RETURNING id`
+	var id int
+	if err := r.db.QueryRow(query, playlist.Name, playlist.Description, rules).Scan(&id); err != nil {
+		return 0, fmt.Errorf("ошибка добавления умного плейлиста: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SmartPlaylistRepository) Update(id int, update models.SmartPlaylistUpdate) error {
+	var rules []byte
+	if update.Rules != nil {
+		var err error
+		if rules, err = json.Marshal(update.Rules); err != nil {
+			return fmt.Errorf("ошибка кодирования правил: %w", err)
+		}
+	}
+	query := `
+		UPDATE smart_playlists
+		SET name = COALESCE(NULLIF($1, ''), name),
+		    description = COALESCE($2, description),
+		    rules = COALESCE($3, rules),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4`
+	res, err := r.db.Exec(query, update.Name, update.Description, rules, id)
+	if err != nil {
+		return fmt.Errorf("ошибка обновления умного плейлиста: %w", err)
+	}
+	return checkAffected(res)
+}
+
+func (r *SmartPlaylistRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM smart_playlists WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления умного плейлиста: %w", err)
+	}
+	return checkAffected(res)
+}
@@ -0,0 +1,393 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// SongRepository хранит песни в PostgreSQL.
+type SongRepository struct {
+	db *sql.DB
+}
+
+func NewSongRepository(provider *conn.PostgresProvider) *SongRepository {
+	return &SongRepository{db: provider.DB()}
+}
+
+var _ repository.SongRepository = (*SongRepository)(nil)
+
+// songColumns — список колонок, который читает scanSong. Ожидает таблицы из songFrom.
+var songColumns = `s.id, s.group_id, g.name, s.song_name, s.release_date,
+		COALESCE(s.text, ''), COALESCE(s.link, ''), s.album_id, COALESCE(a.title, ''),
+		s.track_number, s.disc_number, s.created_at, s.updated_at,
+		` + tagTables[models.TagKindGenre].songNames() + `,
+		` + tagTables[models.TagKindTag].songNames() + `,
+		COALESCE((SELECT c.checksum FROM covers c WHERE c.song_id = s.id), ''),
+		COALESCE((SELECT c.checksum FROM covers c WHERE c.group_id = s.group_id), ''),
+		s.enrichment_status, s.enriched_at, s.manual_fields`
+
+// songFrom — источник строк для songColumns: песни вместе с группой и альбомом.
+const songFrom = `
+		FROM songs s
+		JOIN groups g ON s.group_id = g.id
+		LEFT JOIN albums a ON s.album_id = a.id`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanSong читает строку, выбранную по songColumns.
+func scanSong(row scanner, extra ...any) (models.Song, error) {
+	var song models.Song
+	var releaseDate, createdAt, updatedAt, enrichedAt sql.NullTime
+	var albumID, trackNumber, discNumber sql.NullInt64
+	dest := append([]any{&song.ID, &song.GroupID, &song.GroupName, &song.SongName, &releaseDate,
+		&song.Text, &song.Link, &albumID, &song.AlbumTitle, &trackNumber, &discNumber,
+		&createdAt, &updatedAt, pq.Array(&song.Genres), pq.Array(&song.Tags),
+		&song.CoverChecksum, &song.GroupCoverChecksum, &song.EnrichmentStatus, &enrichedAt,
+		pq.Array(&song.ManualFields)}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return models.Song{}, err
+	}
+	song.ReleaseDate = nullTimePtr(releaseDate)
+	song.AlbumID = nullIntPtr(albumID)
+	song.TrackNumber = nullIntPtr(trackNumber)
+	song.DiscNumber = nullIntPtr(discNumber)
+	song.CreatedAt = createdAt.Time
+	song.UpdatedAt = updatedAt.Time
+	song.EnrichedAt = nullTimePtr(enrichedAt)
+	return song, nil
+}
+
+func (r *SongRepository) List(filter models.SongFilter, limit, offset int) ([]models.Song, error) {
+	songs := []models.Song{}
+	err := r.Each(filter, limit, offset, func(song models.Song) error {
+		songs = append(songs, song)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return songs, nil
+}
+
+// Each читает песни курсором и передаёт их fn по одной, не собирая выборку в памяти.
+func (r *SongRepository) Each(filter models.SongFilter, limit, offset int, fn func(models.Song) error) error {
+	if filter.Fuzzy {
+		return r.eachFuzzy(filter, limit, offset, fn)
+	}
+
+	var b queryBuilder
+	if filter.Group != "" {
+		b.where(`g.name ILIKE '%' || ` + b.arg(filter.Group) + ` || '%'`)
+	}
+	if filter.Song != "" {
+		b.where(`s.song_name ILIKE '%' || ` + b.arg(filter.Song) + ` || '%'`)
+	}
+	whereSong(&b, filter)
+
+	query := `
+		SELECT ` + songColumns + songFrom + `
+		` + b.whereClause() + `
+		ORDER BY ` + songOrder(filter) + ` ` + b.page(limit, offset)
+
+	rows, err := r.db.Query(query, b.args...)
+	if err != nil {
+		return fmt.Errorf("ошибка запроса: %w", err)
+	}
+	return eachSong(rows, fn)
+}
+
+// whereSong добавляет условия фильтра, общие для обычного и нечёткого поиска:
+// альбом, жанры, теги и правила умного плейлиста.
+func whereSong(b *queryBuilder, filter models.SongFilter) {
+	if filter.AlbumID != nil {
+		b.where(`s.album_id = ` + b.arg(*filter.AlbumID))
+	}
+	whereTags(b, models.TagKindGenre, filter.Genres, filter.MatchAll)
+	whereTags(b, models.TagKindTag, filter.Tags, filter.MatchAll)
+	if filter.Rules != nil {
+		b.where(whereRule(b, *filter.Rules))
+	}
+}
+
+// songOrder возвращает порядок сортировки: внутри альбома — по диску и номеру трека.
+func songOrder(filter models.SongFilter) string {
+	if filter.AlbumID != nil {
+		return "COALESCE(s.disc_number, 1), s.track_number NULLS LAST, s.id"
+	}
+	return "s.id"
+}
+
+// eachSong передаёт fn строки, выбранные по songColumns, и закрывает rows.
+func eachSong(rows *sql.Rows, fn func(models.Song) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		song, err := scanSong(rows)
+		if err != nil {
+			return fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		if err := fn(song); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return nil
+}
+
+func (r *SongRepository) Get(id int) (models.Song, error) {
+	query := `
+		SELECT ` + songColumns + songFrom + `
+		WHERE s.id = $1`
+	song, err := scanSong(r.db.QueryRow(query, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Song{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return models.Song{}, fmt.Errorf("ошибка получения песни: %w", err)
+	}
+	return song, nil
+}
+
+func (r *SongRepository) GetText(id int) (string, error) {
+	var text sql.NullString
+	err := r.db.QueryRow(`SELECT text FROM songs WHERE id = $1`, id).Scan(&text)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", repository.ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("ошибка получения текста песни: %w", err)
+	}
+	return text.String, nil
+}
+
+func (r *SongRepository) FindByName(groupID int, name string) (int, error) {
+	var id int
+	err := r.db.QueryRow(`
+		SELECT id FROM songs
+		WHERE group_id = $1 AND lower(song_name) = lower($2)
+		ORDER BY id LIMIT 1`, groupID, name).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка поиска песни: %w", err)
+	}
+	return id, nil
+}
+
+// insertSong добавляет песню; пустой статус обогащения означает done,
+// а заполненные поля считаются введёнными вручную.
+const insertSong = `
+		INSERT INTO songs (group_id, song_name, release_date, text, link, album_id, track_number, disc_number,
+		                   enrichment_status, manual_fields)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE(NULLIF($9, ''), 'done'),
+		        ARRAY(SELECT unnest($10::text[]) ORDER BY 1))
+		RETURNING id`
+
+func (r *SongRepository) Create(song models.Song) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRow(insertSong, song.GroupID, song.SongName, song.ReleaseDate, song.Text, song.Link,
+		song.AlbumID, song.TrackNumber, song.DiscNumber, song.EnrichmentStatus, pq.Array(nonNil(song.FilledFields()))).Scan(&id)
+	if isForeignKeyViolation(err) {
+		// Группы или альбома нет
+		return 0, repository.ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("ошибка сохранения песни: %w", err)
+	}
+	if song.Text != "" {
+		if err := addTextRevision(tx, id, song.Text, ""); err != nil {
+			return 0, err
+		}
+	}
+	if song.EnrichmentStatus == models.EnrichmentPending {
+		if err := insertEnrichmentJob(tx, id); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return id, nil
+}
+
+func (r *SongRepository) CreateBatch(songs []models.Song) ([]models.BatchResult, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	results := make([]models.BatchResult, len(songs))
+	for i, song := range songs {
+		// Точка сохранения откатывает только неудачную песню, а не весь пакет
+		if _, err := tx.Exec(`SAVEPOINT batch_song`); err != nil {
+			return nil, fmt.Errorf("ошибка создания точки сохранения: %w", err)
+		}
+		results[i] = createInTx(tx, song)
+		release := `RELEASE SAVEPOINT batch_song`
+		if results[i].Err != nil {
+			release = `ROLLBACK TO SAVEPOINT batch_song`
+		}
+		if _, err := tx.Exec(release); err != nil {
+			return nil, fmt.Errorf("ошибка завершения точки сохранения: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return results, nil
+}
+
+// createInTx добавляет песню пакета с жанрами и тегами, если у группы ещё нет песни с таким названием.
+func createInTx(tx *sql.Tx, song models.Song) models.BatchResult {
+	var id int
+	err := tx.QueryRow(`
+		SELECT id FROM songs
+		WHERE group_id = $1 AND lower(song_name) = lower($2)
+		ORDER BY id LIMIT 1`, song.GroupID, song.SongName).Scan(&id)
+	if err == nil {
+		return models.BatchResult{ID: id, Duplicate: true}
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return models.BatchResult{Err: fmt.Errorf("ошибка проверки дубликата: %w", err)}
+	}
+
+	err = tx.QueryRow(insertSong, song.GroupID, song.SongName, song.ReleaseDate, song.Text, song.Link,
+		song.AlbumID, song.TrackNumber, song.DiscNumber, song.EnrichmentStatus, pq.Array(nonNil(song.FilledFields()))).Scan(&id)
+	if err != nil {
+		return models.BatchResult{Err: fmt.Errorf("ошибка сохранения песни: %w", err)}
+	}
+	if song.Text != "" {
+		if err := addTextRevision(tx, id, song.Text, ""); err != nil {
+			return models.BatchResult{Err: err}
+		}
+	}
+	if song.EnrichmentStatus == models.EnrichmentPending {
+		if err := insertEnrichmentJob(tx, id); err != nil {
+			return models.BatchResult{Err: err}
+		}
+	}
+	if err := attachTags(tx, tagTables[models.TagKindGenre], id, song.Genres); err != nil {
+		return models.BatchResult{Err: err}
+	}
+	if err := attachTags(tx, tagTables[models.TagKindTag], id, song.Tags); err != nil {
+		return models.BatchResult{Err: err}
+	}
+	return models.BatchResult{ID: id}
+}
+
+// updateSong — изменение песни, в котором nil-параметры оставляют поле как есть.
+// $10 — поля, которые становятся ручными, $11 — перестают ими быть.
+const updateSong = `
+		UPDATE songs
+		SET group_id = COALESCE($1, group_id),
+		    song_name = COALESCE(NULLIF($2, ''), song_name),
+		    release_date = COALESCE($3, release_date),
+		    text = COALESCE($4, text),
+		    link = COALESCE($5, link),
+		    album_id = COALESCE($7, album_id),
+		    track_number = COALESCE($8, track_number),
+		    disc_number = COALESCE($9, disc_number),
+		    manual_fields = ARRAY(
+		        SELECT f FROM unnest(manual_fields) f WHERE f <> ALL($11::text[])
+		        UNION SELECT unnest($10::text[])
+		        ORDER BY 1),
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $6`
+
+func (r *SongRepository) Update(id int, update models.SongUpdate) error {
+	manual, enriched := pq.Array(nonNil(update.ChangedFields())), pq.Array([]string{})
+	if update.Enrichment {
+		manual, enriched = enriched, manual
+	}
+	if update.Text == nil {
+		res, err := r.db.Exec(updateSong, update.GroupID, update.SongName, update.ReleaseDate, update.Text, update.Link, id,
+			update.AlbumID, update.TrackNumber, update.DiscNumber, manual, enriched)
+		if isForeignKeyViolation(err) {
+			return repository.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка обновления песни: %w", err)
+		}
+		return checkAffected(res)
+	}
+
+	// Текст меняется вместе с историей в одной транзакции; блокировка строки
+	// не даёт двум правкам получить один номер версии
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	var current string
+	err = tx.QueryRow(`SELECT COALESCE(text, '') FROM songs WHERE id = $1 FOR UPDATE`, id).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка блокировки песни: %w", err)
+	}
+	_, err = tx.Exec(updateSong, update.GroupID, update.SongName, update.ReleaseDate, update.Text, update.Link, id,
+		update.AlbumID, update.TrackNumber, update.DiscNumber, manual, enriched)
+	if isForeignKeyViolation(err) {
+		return repository.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ошибка обновления песни: %w", err)
+	}
+	if *update.Text != current {
+		if err := addTextRevision(tx, id, *update.Text, update.Author); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return nil
+}
+
+func (r *SongRepository) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM songs WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("ошибка удаления песни: %w", err)
+	}
+	return checkAffected(res)
+}
+
+// nonNil заменяет nil пустым срезом: pq.Array передаёт nil как NULL, а не пустой массив.
+func nonNil(items []string) []string {
+	if items == nil {
+		return []string{}
+	}
+	return items
+}
+
+// checkAffected возвращает repository.ErrNotFound, если запрос не затронул ни одной строки.
+func checkAffected(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("ошибка получения числа изменённых строк: %w", err)
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
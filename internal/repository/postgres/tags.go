@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/lib/pq"
+)
+
+// TagRepository хранит жанры и теги песен в PostgreSQL.
+type TagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(provider *conn.PostgresProvider) *TagRepository {
+	return &TagRepository{db: provider.DB()}
+}
+
+var _ repository.TagRepository = (*TagRepository)(nil)
+
+// tagTable описывает таблицу меток одного вида и её связь с песнями.
+type tagTable struct {
+	table  string // таблица меток
+	join   string // таблица связей с песнями
+	column string // колонка связи, ссылающаяся на метку
+}
+
+var tagTables = map[models.TagKind]tagTable{
+	models.TagKindGenre: {table: "genres", join: "song_genres", column: "genre_id"},
+	models.TagKindTag:   {table: "tags", join: "song_tags", column: "tag_id"},
+}
+
+func lookupTagTable(kind models.TagKind) (tagTable, error) {
+	t, ok := tagTables[kind]
+	if !ok {
+		return tagTable{}, fmt.Errorf("неизвестный вид метки %q", kind)
+	}
+	return t, nil
+}
+
+// songNames возвращает подзапрос с названиями меток песни s по алфавиту.
+func (t tagTable) songNames() string {
+	return `ARRAY(SELECT l.name FROM ` + t.join + ` sl JOIN ` + t.table + ` l ON l.id = sl.` + t.column + `
+		WHERE sl.song_id = s.id ORDER BY l.name)`
+}
+
+// songHas возвращает условие «песня s отмечена меткой с названием из плейсхолдера name».
+func (t tagTable) songHas(name string) string {
+	return `EXISTS (SELECT 1 FROM ` + t.join + ` sl JOIN ` + t.table + ` l ON l.id = sl.` + t.column + `
+		WHERE sl.song_id = s.id AND l.name = ` + name + `)`
+}
+
+// whereTags добавляет условие на метки песни s: хотя бы одна из names или, при all, все.
+func whereTags(b *queryBuilder, kind models.TagKind, names []string, all bool) {
+	if len(names) == 0 {
+		return
+	}
+	t := tagTables[kind]
+	matched := `
+		FROM ` + t.join + ` sl JOIN ` + t.table + ` l ON l.id = sl.` + t.column + `
+		WHERE sl.song_id = s.id AND l.name = ANY(` + b.arg(pq.Array(names)) + `)`
+	if !all {
+		b.where(`EXISTS (SELECT 1 ` + matched + `)`)
+		return
+	}
+	distinct := map[string]struct{}{}
+	for _, name := range names {
+		distinct[name] = struct{}{}
+	}
+	b.where(`(SELECT COUNT(DISTINCT l.name) ` + matched + `) = ` + b.arg(len(distinct)))
+}
+
+func (r *TagRepository) List(kind models.TagKind, limit, offset int) ([]models.Tag, error) {
+	t, err := lookupTagTable(kind)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT l.id, l.name, COUNT(sl.song_id)
+		FROM ` + t.table + ` l
+		LEFT JOIN ` + t.join + ` sl ON sl.` + t.column + ` = l.id
+		GROUP BY l.id
+		ORDER BY l.name LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса меток: %w", err)
+	}
+	return collectTags(rows, kind)
+}
+
+func (r *TagRepository) Popular(kind models.TagKind, limit int) ([]models.Tag, error) {
+	t, err := lookupTagTable(kind)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT l.id, l.name, COUNT(*) AS song_count
+		FROM ` + t.table + ` l
+		JOIN ` + t.join + ` sl ON sl.` + t.column + ` = l.id
+		GROUP BY l.id
+		ORDER BY song_count DESC, l.name LIMIT $1`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса популярных меток: %w", err)
+	}
+	return collectTags(rows, kind)
+}
+
+func collectTags(rows *sql.Rows, kind models.TagKind) ([]models.Tag, error) {
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		tag := models.Tag{Kind: kind}
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.SongCount); err != nil {
+			return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка чтения строк: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *TagRepository) Attach(songID int, kind models.TagKind, names []string) error {
+	t, err := lookupTagTable(kind)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := attachTags(tx, t, songID, names); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка завершения транзакции: %w", err)
+	}
+	return nil
+}
+
+// attachTags привязывает к песне метки с названиями names, добавляя недостающие.
+func attachTags(tx *sql.Tx, t tagTable, songID int, names []string) error {
+	// DO UPDATE вместо DO NOTHING, чтобы RETURNING вернул ID и уже существующей метки
+	upsert := `
+		INSERT INTO ` + t.table + ` (name) VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id`
+	link := `
+		INSERT INTO ` + t.join + ` (song_id, ` + t.column + `) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+	for _, name := range names {
+		var tagID int
+		if err := tx.QueryRow(upsert, name).Scan(&tagID); err != nil {
+			return fmt.Errorf("ошибка добавления метки: %w", err)
+		}
+		_, err := tx.Exec(link, songID, tagID)
+		if isForeignKeyViolation(err) {
+			return repository.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка привязки метки к песне: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *TagRepository) Detach(songID int, kind models.TagKind, name string) error {
+	t, err := lookupTagTable(kind)
+	if err != nil {
+		return err
+	}
+	query := `
+		DELETE FROM ` + t.join + ` sl
+		USING ` + t.table + ` l
+		WHERE sl.` + t.column + ` = l.id AND sl.song_id = $1 AND l.name = $2`
+	res, err := r.db.Exec(query, songID, name)
+	if err != nil {
+		return fmt.Errorf("ошибка снятия метки с песни: %w", err)
+	}
+	return checkAffected(res)
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+var (
+	// ErrNotFound возвращается, когда запрошенная запись отсутствует в хранилище.
+	ErrNotFound = errors.New("запись не найдена")
+	// ErrConflict возвращается при нарушении уникальности.
+	ErrConflict = errors.New("запись уже существует")
+)
+
+// SongRepository описывает хранилище песен.
+type SongRepository interface {
+	// List возвращает песни, подходящие под фильтр, с учётом лимита и смещения.
+	List(filter models.SongFilter, limit, offset int) ([]models.Song, error)
+	// Each передаёт fn песни, подходящие под фильтр, по одной в порядке List и прекращает
+	// чтение при первой ошибке fn. Отрицательный limit снимает ограничение на число песен.
+	Each(filter models.SongFilter, limit, offset int, fn func(models.Song) error) error
+	// Search выполняет полнотекстовый поиск по текстам, названиям песен и групп.
+	Search(query models.SearchQuery, limit, offset int) ([]models.SearchResult, error)
+	// Get возвращает песню по ID.
+	Get(id int) (models.Song, error)
+	// GetText возвращает текст песни по ID.
+	GetText(id int) (string, error)
+	// FindByName возвращает ID песни группы с названием name без учёта регистра.
+	FindByName(groupID int, name string) (int, error)
+	// Create добавляет песню и возвращает её ID. Непустой текст становится первой версией в истории,
+	// а песня со статусом обогащения pending в той же транзакции ставится в очередь обогащения.
+	Create(song models.Song) (int, error)
+	// CreateBatch добавляет песни вместе с их жанрами и тегами в одной транзакции.
+	// Песня, название которой без учёта регистра уже есть у группы, не добавляется.
+	// Результаты идут в порядке songs; ошибка одной песни не отменяет остальные,
+	// а возвращаемая ошибка означает, что не сохранена ни одна.
+	CreateBatch(songs []models.Song) ([]models.BatchResult, error)
+	// Update изменяет только переданные (не nil) поля песни. Изменённый текст
+	// сохраняется новой версией в истории вместе с update.Author.
+	Update(id int, update models.SongUpdate) error
+	// Delete удаляет песню по ID.
+	Delete(id int) error
+	// TextRevisions возвращает версии текста песни, начиная с последней.
+	TextRevisions(songID, limit, offset int) ([]models.TextRevision, error)
+	// TextRevision возвращает версию текста песни по номеру.
+	TextRevision(songID, revision int) (models.TextRevision, error)
+	// FindDuplicates возвращает до limit пар песен групп с одинаковым нормализованным названием,
+	// у которых названия совпадают после нормализации или похожи не меньше чем на threshold.
+	FindDuplicates(threshold float64, limit int) ([]models.SongDuplicate, error)
+}
+
+// GroupRepository описывает хранилище групп.
+type GroupRepository interface {
+	// List возвращает группы с числом песен, подходящие под фильтр.
+	List(filter models.GroupFilter, limit, offset int) ([]models.Group, error)
+	// Get возвращает группу по ID вместе с числом песен и псевдонимами.
+	Get(id int) (models.Group, error)
+	// FindByName возвращает ID группы по названию: сначала по точному совпадению,
+	// затем по нормализованному названию (normalize.Name) и псевдонимам.
+	FindByName(name string) (int, error)
+	// FindSimilar возвращает до limit групп с похожими названиями, самые похожие первыми.
+	FindSimilar(name string, limit int) ([]models.Group, error)
+	// Create добавляет группу и возвращает её ID.
+	Create(group models.Group) (int, error)
+	// Update изменяет только переданные (не nil) поля группы.
+	Update(id int, update models.GroupUpdate) error
+	// SongIDs возвращает ID песен группы по возрастанию.
+	SongIDs(groupID int) ([]int, error)
+	// Delete удаляет группу. Без cascade возвращает ErrConflict, если у группы есть песни или альбомы.
+	Delete(id int, cascade bool) error
+	// Merge переносит песни, альбомы и псевдонимы группы sourceID в targetID,
+	// сохраняет название источника псевдонимом и удаляет источник. Если такой псевдоним
+	// уже был у другой группы, он переходит к targetID: название источника ведёт туда же, куда его песни.
+	Merge(sourceID, targetID int) error
+	// FindDuplicates возвращает до limit пар групп, названия которых совпадают
+	// после нормализации (normalize.Name) или похожи не меньше чем на threshold.
+	FindDuplicates(threshold float64, limit int) ([]models.GroupDuplicate, error)
+	// BackfillNormalized заполняет нормализованные названия групп и псевдонимов,
+	// у которых их ещё нет, и возвращает число обновлённых записей.
+	BackfillNormalized() (int, error)
+}
+
+// AlbumRepository описывает хранилище альбомов.
+type AlbumRepository interface {
+	// List возвращает альбомы, подходящие под фильтр, с учётом лимита и смещения.
+	List(filter models.AlbumFilter, limit, offset int) ([]models.Album, error)
+	// Get возвращает альбом по ID.
+	Get(id int) (models.Album, error)
+	// Create добавляет альбом и возвращает его ID.
+	Create(album models.Album) (int, error)
+	// Update изменяет только переданные (не nil) поля альбома.
+	Update(id int, update models.AlbumUpdate) error
+	// Delete удаляет альбом; песни альбома остаются без привязки к нему.
+	Delete(id int) error
+}
+
+// TagRepository описывает хранилище жанров и тегов песен.
+type TagRepository interface {
+	// List возвращает метки вида kind по алфавиту вместе с числом песен.
+	List(kind models.TagKind, limit, offset int) ([]models.Tag, error)
+	// Popular возвращает до limit меток вида kind, которыми отмечена хотя бы одна песня,
+	// самые частые первыми.
+	Popular(kind models.TagKind, limit int) ([]models.Tag, error)
+	// Attach отмечает песню метками, создавая недостающие. Уже привязанные метки пропускаются.
+	Attach(songID int, kind models.TagKind, names []string) error
+	// Detach снимает метку с песни. Возвращает ErrNotFound, если метка не была привязана.
+	Detach(songID int, kind models.TagKind, name string) error
+}
+
+// PlaylistRepository описывает хранилище плейлистов и их элементов.
+// Позиции элементов начинаются с 1 и идут без пропусков.
+type PlaylistRepository interface {
+	// List возвращает плейлисты с числом песен, с учётом лимита и смещения.
+	List(limit, offset int) ([]models.Playlist, error)
+	// Get возвращает плейлист по ID вместе с числом песен.
+	Get(id int) (models.Playlist, error)
+	// Create добавляет плейлист и возвращает его ID.
+	Create(playlist models.Playlist) (int, error)
+	// Update изменяет только переданные (не nil) поля плейлиста.
+	Update(id int, update models.PlaylistUpdate) error
+	// Delete удаляет плейлист вместе с его элементами.
+	Delete(id int) error
+	// Items возвращает элементы плейлиста по порядку позиций.
+	Items(playlistID, limit, offset int) ([]models.PlaylistItem, error)
+	// EachItem передаёт fn элементы плейлиста по одной в порядке позиций.
+	// Отрицательный limit снимает ограничение на число элементов.
+	EachItem(playlistID, limit, offset int, fn func(models.PlaylistItem) error) error
+	// AddItem вставляет песню на позицию position (0 или больше длины — в конец),
+	// сдвигая последующие элементы, и возвращает ID элемента.
+	AddItem(playlistID, songID, position int) (int, error)
+	// RemoveItem удаляет элемент и закрывает образовавшийся пропуск в позициях.
+	RemoveItem(playlistID, itemID int) error
+	// MoveItem переносит элемент на позицию position (больше длины — в конец).
+	MoveItem(playlistID, itemID, position int) error
+}
+
+// SmartPlaylistRepository описывает хранилище умных плейлистов. Песни плейлиста
+// подбираются через SongRepository.List с правилами в фильтре.
+type SmartPlaylistRepository interface {
+	// List возвращает умные плейлисты с учётом лимита и смещения.
+	List(limit, offset int) ([]models.SmartPlaylist, error)
+	// Get возвращает умный плейлист по ID.
+	Get(id int) (models.SmartPlaylist, error)
+	// Create добавляет умный плейлист и возвращает его ID.
+	Create(playlist models.SmartPlaylist) (int, error)
+	// Update изменяет только переданные (не nil) поля умного плейлиста.
+	Update(id int, update models.SmartPlaylistUpdate) error
+	// Delete удаляет умный плейлист.
+	Delete(id int) error
+}
+
+// AudioRepository хранит сведения об аудиофайлах песен; у песни не больше одного файла.
+type AudioRepository interface {
+	// Get возвращает аудиофайл песни.
+	Get(songID int) (models.AudioFile, error)
+	// Save сохраняет аудиофайл песни, заменяя прежний. Возвращает ErrNotFound, если песни нет.
+	Save(audio models.AudioFile) error
+	// Delete удаляет сведения об аудиофайле песни.
+	Delete(songID int) error
+}
+
+// LyricsRepository хранит синхронизированные тексты песен; у песни не больше одного текста.
+type LyricsRepository interface {
+	// Get возвращает синхронизированный текст песни.
+	Get(songID int) (models.SongLyrics, error)
+	// Save сохраняет текст песни, заменяя прежний. Возвращает ErrNotFound, если песни нет.
+	Save(lyrics models.SongLyrics) error
+	// Delete удаляет синхронизированный текст песни.
+	Delete(songID int) error
+}
+
+// CoverRepository хранит сведения об обложках песен и групп; у владельца не больше одной обложки.
+type CoverRepository interface {
+	// Get возвращает обложку владельца.
+	Get(kind models.CoverKind, ownerID int) (models.Cover, error)
+	// Save сохраняет обложку, заменяя прежнюю. Возвращает ErrNotFound, если владельца нет.
+	Save(cover models.Cover) error
+	// Delete удаляет сведения об обложке владельца.
+	Delete(kind models.CoverKind, ownerID int) error
+}
+
+// EnrichmentRepository — очередь задач обогащения песен. Задачу одновременно выполняет
+// не больше одного обработчика; статус песни меняется вместе со статусом её задачи.
+type EnrichmentRepository interface {
+	// Enqueue ставит песню в очередь: создаёт задачу или перезапускает прежнюю с нуля попыток,
+	// а песне ставит статус pending. Возвращает ErrNotFound, если песни нет, и ErrConflict,
+	// если задача сейчас выполняется.
+	Enqueue(songID int) (models.EnrichmentJob, error)
+	// Claim забирает до limit готовых задач и переводит их в running, увеличивая счётчик попыток.
+	// Задачи, взятые другими обработчиками, пропускаются; задача running, взятая раньше
+	// чем lease назад, считается брошенной и забирается снова.
+	Claim(limit int, lease time.Duration) ([]models.EnrichmentJob, error)
+	// Complete завершает задачу: задача и песня получают статус done, у песни обновляется enriched_at.
+	Complete(jobID int) error
+	// Retry возвращает задачу в очередь с ошибкой попытки; задачу можно будет взять через delay.
+	Retry(jobID int, delay time.Duration, lastError string) error
+	// Fail завершает задачу ошибкой: задача и песня получают статус failed.
+	Fail(jobID int, lastError string) error
+	// Get возвращает задачу по ID.
+	Get(jobID int) (models.EnrichmentJob, error)
+	// List возвращает задачи по фильтру, начиная с последних изменённых.
+	List(filter models.EnrichmentJobFilter, limit, offset int) ([]models.EnrichmentJob, error)
+	// RequeueFailed перезапускает все задачи со статусом failed и возвращает их число.
+	RequeueFailed() (int, error)
+	// EnqueueStale ставит в очередь до limit обогащённых песен (статус done), начиная с давних:
+	// обогащённые больше maxAge назад, а с пустым текстом или ссылкой — больше blankAge назад
+	// или ни разу. Полные песни, которые ни разу не обогащались (добавленные до очереди
+	// обогащения или вручную), не выбираются. Возвращает число поставленных песен.
+	EnqueueStale(maxAge, blankAge time.Duration, limit int) (int, error)
+}
+
+// MetadataCacheRepository — долговременный уровень кэша ответов внешнего API,
+// общий для всех процессов сервиса.
+type MetadataCacheRepository interface {
+	// Get возвращает действительную запись; ErrNotFound, если записи нет или она устарела.
+	Get(key string) (models.CachedSongDetails, error)
+	// Put сохраняет запись на entry.TTL, заменяя прежнюю.
+	Put(entry models.CachedSongDetails) error
+	// DeleteExpired удаляет устаревшие записи и возвращает их число.
+	DeleteExpired() (int, error)
+}
@@ -0,0 +1,800 @@
+// Package repotest содержит общий набор тестов контракта хранилищ: его проходят
+// и PostgreSQL-реализация, и реализация в памяти.
+package repotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// Repos — проверяемые хранилища. Каждый вызов фабрики в Run должен возвращать
+// хранилища с пустыми данными.
+type Repos struct {
+	Songs      repository.SongRepository
+	Groups     repository.GroupRepository
+	Enrichment repository.EnrichmentRepository
+	Tags       repository.TagRepository
+	Playlists  repository.PlaylistRepository
+}
+
+// Run проверяет контракт SongRepository, GroupRepository, EnrichmentRepository,
+// TagRepository и PlaylistRepository; newRepos вызывается для каждого подтеста.
+func Run(t *testing.T, newRepos func(t *testing.T) Repos) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, r Repos)
+	}{
+		{"GroupCreateGet", testGroupCreateGet},
+		{"GroupCreateConflict", testGroupCreateConflict},
+		{"GroupFindByName", testGroupFindByName},
+		{"GroupUpdatePartial", testGroupUpdatePartial},
+		{"GroupListPagination", testGroupListPagination},
+		{"GroupDelete", testGroupDelete},
+		{"GroupMerge", testGroupMerge},
+		{"GroupMergeTakesOverAlias", testGroupMergeTakesOverAlias},
+		{"GroupFindDuplicates", testGroupFindDuplicates},
+		{"SongCreateGet", testSongCreateGet},
+		{"SongCreateUnknownGroup", testSongCreateUnknownGroup},
+		{"SongUpdatePartial", testSongUpdatePartial},
+		{"SongUpdateMissing", testSongUpdateMissing},
+		{"SongListFilter", testSongListFilter},
+		{"SongListPagination", testSongListPagination},
+		{"SongFindByName", testSongFindByName},
+		{"SongDelete", testSongDelete},
+		{"SongListTags", testSongListTags},
+		{"EnqueueStale", testEnqueueStale},
+		{"PlaylistItems", testPlaylistItems},
+		{"PlaylistItemsMissing", testPlaylistItemsMissing},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newRepos(t))
+		})
+	}
+}
+
+func createGroup(t *testing.T, r Repos, name string) int {
+	t.Helper()
+	id, err := r.Groups.Create(models.Group{Name: name})
+	if err != nil {
+		t.Fatalf("Groups.Create(%q): %v", name, err)
+	}
+	return id
+}
+
+func createSong(t *testing.T, r Repos, song models.Song) int {
+	t.Helper()
+	id, err := r.Songs.Create(song)
+	if err != nil {
+		t.Fatalf("Songs.Create(%q): %v", song.SongName, err)
+	}
+	return id
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func date(value string) *time.Time {
+	d, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		panic(err)
+	}
+	return &d
+}
+
+func formatDate(d *time.Time) string {
+	if d == nil {
+		return ""
+	}
+	return d.Format(models.DateLayout)
+}
+
+func songNames(songs []models.Song) []string {
+	names := make([]string, 0, len(songs))
+	for _, song := range songs {
+		names = append(names, song.SongName)
+	}
+	return names
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func testGroupCreateGet(t *testing.T, r Repos) {
+	id, err := r.Groups.Create(models.Group{Name: "Muse", Country: "UK", FormedYear: ptr(1994), Bio: "Рок-группа"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	createSong(t, r, models.Song{GroupID: id, SongName: "Uprising"})
+
+	group, err := r.Groups.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if group.Name != "Muse" || group.Country != "UK" || group.Bio != "Рок-группа" {
+		t.Errorf("Get = %+v", group)
+	}
+	if group.FormedYear == nil || *group.FormedYear != 1994 {
+		t.Errorf("FormedYear = %v, want 1994", group.FormedYear)
+	}
+	if group.SongCount != 1 {
+		t.Errorf("SongCount = %d, want 1", group.SongCount)
+	}
+	if _, err := r.Groups.Get(id + 1000); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testGroupCreateConflict(t *testing.T, r Repos) {
+	createGroup(t, r, "Muse")
+	if _, err := r.Groups.Create(models.Group{Name: "Muse"}); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Create(duplicate) error = %v, want ErrConflict", err)
+	}
+}
+
+func testGroupFindByName(t *testing.T, r Repos) {
+	id := createGroup(t, r, "The Beatles")
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"The Beatles", nil},
+		{"the beatles", nil},
+		{"  THE   BEATLES ", nil},
+		{"Beatles Tribute", repository.ErrNotFound},
+	}
+	for _, tt := range tests {
+		got, err := r.Groups.FindByName(tt.name)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("FindByName(%q) error = %v, want %v", tt.name, err, tt.want)
+			continue
+		}
+		if tt.want == nil && got != id {
+			t.Errorf("FindByName(%q) = %d, want %d", tt.name, got, id)
+		}
+	}
+}
+
+func testGroupUpdatePartial(t *testing.T, r Repos) {
+	id, err := r.Groups.Create(models.Group{Name: "Muse", Country: "UK", Bio: "Рок-группа"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	createGroup(t, r, "Queen")
+
+	if err := r.Groups.Update(id, models.GroupUpdate{FormedYear: ptr(1994)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	group, err := r.Groups.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if group.Name != "Muse" || group.Country != "UK" || group.Bio != "Рок-группа" {
+		t.Errorf("Update изменил непереданные поля: %+v", group)
+	}
+	if group.FormedYear == nil || *group.FormedYear != 1994 {
+		t.Errorf("FormedYear = %v, want 1994", group.FormedYear)
+	}
+
+	if err := r.Groups.Update(id, models.GroupUpdate{Name: ptr("Queen")}); !errors.Is(err, repository.ErrConflict) {
+		t.Errorf("Update(занятое название) error = %v, want ErrConflict", err)
+	}
+	if err := r.Groups.Update(id+1000, models.GroupUpdate{Bio: ptr("")}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testGroupListPagination(t *testing.T, r Repos) {
+	for _, name := range []string{"Queen", "ABBA", "Muse", "Metallica"} {
+		createGroup(t, r, name)
+	}
+
+	tests := []struct {
+		filter        models.GroupFilter
+		limit, offset int
+		want          []string
+	}{
+		{models.GroupFilter{}, 10, 0, []string{"ABBA", "Metallica", "Muse", "Queen"}},
+		{models.GroupFilter{}, 2, 0, []string{"ABBA", "Metallica"}},
+		{models.GroupFilter{}, 2, 2, []string{"Muse", "Queen"}},
+		{models.GroupFilter{}, 2, 4, []string{}},
+		{models.GroupFilter{Name: "m"}, 10, 0, []string{"Metallica", "Muse"}},
+	}
+	for _, tt := range tests {
+		groups, err := r.Groups.List(tt.filter, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		got := make([]string, 0, len(groups))
+		for _, group := range groups {
+			got = append(got, group.Name)
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("List(%+v, %d, %d) = %v, want %v", tt.filter, tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func testGroupDelete(t *testing.T, r Repos) {
+	id := createGroup(t, r, "Muse")
+	songID := createSong(t, r, models.Song{GroupID: id, SongName: "Uprising"})
+
+	if err := r.Groups.Delete(id, false); !errors.Is(err, repository.ErrConflict) {
+		t.Fatalf("Delete(с песнями) error = %v, want ErrConflict", err)
+	}
+	if err := r.Groups.Delete(id, true); err != nil {
+		t.Fatalf("Delete(cascade): %v", err)
+	}
+	if _, err := r.Groups.Get(id); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Get после удаления: error = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Songs.Get(songID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("песня группы не удалена каскадно: error = %v", err)
+	}
+	if err := r.Groups.Delete(id, true); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testSongCreateGet(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	id := createSong(t, r, models.Song{
+		GroupID:     groupID,
+		SongName:    "Uprising",
+		ReleaseDate: date("2009-09-07"),
+		Text:        "Paranoia is in bloom",
+		Link:        "https://example.com/uprising",
+	})
+
+	song, err := r.Songs.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if song.ID != id || song.GroupID != groupID || song.GroupName != "Muse" || song.SongName != "Uprising" {
+		t.Errorf("Get = %+v", song)
+	}
+	if got := formatDate(song.ReleaseDate); got != "2009-09-07" {
+		t.Errorf("ReleaseDate = %q, want 2009-09-07", got)
+	}
+	if song.Text != "Paranoia is in bloom" || song.Link != "https://example.com/uprising" {
+		t.Errorf("Text, Link = %q, %q", song.Text, song.Link)
+	}
+	if song.EnrichmentStatus != models.EnrichmentDone {
+		t.Errorf("EnrichmentStatus = %q, want %q", song.EnrichmentStatus, models.EnrichmentDone)
+	}
+
+	text, err := r.Songs.GetText(id)
+	if err != nil || text != "Paranoia is in bloom" {
+		t.Errorf("GetText = %q, %v", text, err)
+	}
+	if _, err := r.Songs.Get(id + 1000); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Songs.GetText(id + 1000); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("GetText(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testSongCreateUnknownGroup(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	_, err := r.Songs.Create(models.Song{GroupID: groupID + 1000, SongName: "Uprising"})
+	if !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Create(неизвестная группа) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testSongUpdatePartial(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	otherID := createGroup(t, r, "Queen")
+	id := createSong(t, r, models.Song{
+		GroupID:     groupID,
+		SongName:    "Uprising",
+		ReleaseDate: date("2009-09-07"),
+		Text:        "Paranoia is in bloom",
+		Link:        "https://example.com/uprising",
+	})
+
+	tests := []struct {
+		name   string
+		update models.SongUpdate
+		check  func(models.Song) bool
+	}{
+		{
+			name:   "только текст",
+			update: models.SongUpdate{Text: ptr("The PR transmissions will resume")},
+			check: func(s models.Song) bool {
+				return s.Text == "The PR transmissions will resume" && s.SongName == "Uprising" &&
+					s.Link == "https://example.com/uprising" && formatDate(s.ReleaseDate) == "2009-09-07"
+			},
+		},
+		{
+			name:   "только дата",
+			update: models.SongUpdate{ReleaseDate: date("2009-08-03")},
+			check: func(s models.Song) bool {
+				return formatDate(s.ReleaseDate) == "2009-08-03" && s.Text == "The PR transmissions will resume"
+			},
+		},
+		{
+			name:   "пустое название не затирает текущее",
+			update: models.SongUpdate{SongName: ptr("")},
+			check:  func(s models.Song) bool { return s.SongName == "Uprising" },
+		},
+		{
+			name:   "пустая ссылка очищает поле",
+			update: models.SongUpdate{Link: ptr("")},
+			check:  func(s models.Song) bool { return s.Link == "" && s.SongName == "Uprising" },
+		},
+		{
+			name:   "смена группы",
+			update: models.SongUpdate{GroupID: &otherID},
+			check:  func(s models.Song) bool { return s.GroupID == otherID && s.GroupName == "Queen" },
+		},
+		{
+			name:   "пустое изменение",
+			update: models.SongUpdate{},
+			check:  func(s models.Song) bool { return s.SongName == "Uprising" && s.GroupID == otherID },
+		},
+	}
+	for _, tt := range tests {
+		if err := r.Songs.Update(id, tt.update); err != nil {
+			t.Fatalf("%s: Update: %v", tt.name, err)
+		}
+		song, err := r.Songs.Get(id)
+		if err != nil {
+			t.Fatalf("%s: Get: %v", tt.name, err)
+		}
+		if !tt.check(song) {
+			t.Errorf("%s: после Update песня = %+v", tt.name, song)
+		}
+	}
+
+	if err := r.Songs.Update(id, models.SongUpdate{GroupID: ptr(otherID + 1000)}); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Update(неизвестная группа) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testSongUpdateMissing(t *testing.T, r Repos) {
+	for _, update := range []models.SongUpdate{{Link: ptr("x")}, {Text: ptr("x")}} {
+		if err := r.Songs.Update(1000, update); !errors.Is(err, repository.ErrNotFound) {
+			t.Errorf("Update(missing, %+v) error = %v, want ErrNotFound", update, err)
+		}
+	}
+}
+
+func testSongListFilter(t *testing.T, r Repos) {
+	muse := createGroup(t, r, "Muse")
+	queen := createGroup(t, r, "Queen")
+	createSong(t, r, models.Song{GroupID: muse, SongName: "Uprising"})
+	createSong(t, r, models.Song{GroupID: muse, SongName: "Starlight"})
+	createSong(t, r, models.Song{GroupID: queen, SongName: "Bohemian Rhapsody"})
+
+	tests := []struct {
+		filter models.SongFilter
+		want   []string
+	}{
+		{models.SongFilter{}, []string{"Uprising", "Starlight", "Bohemian Rhapsody"}},
+		{models.SongFilter{Group: "muse"}, []string{"Uprising", "Starlight"}},
+		{models.SongFilter{Song: "RISING"}, []string{"Uprising"}},
+		{models.SongFilter{Group: "Queen", Song: "star"}, []string{}},
+	}
+	for _, tt := range tests {
+		songs, err := r.Songs.List(tt.filter, 10, 0)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if got := songNames(songs); !equalStrings(got, tt.want) {
+			t.Errorf("List(%+v) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func testSongListPagination(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	for _, name := range []string{"A", "B", "C", "D", "E"} {
+		createSong(t, r, models.Song{GroupID: groupID, SongName: name})
+	}
+
+	tests := []struct {
+		limit, offset int
+		want          []string
+	}{
+		{2, 0, []string{"A", "B"}},
+		{2, 2, []string{"C", "D"}},
+		{2, 4, []string{"E"}},
+		{2, 6, []string{}},
+		{-1, 1, []string{"B", "C", "D", "E"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		err := r.Songs.Each(models.SongFilter{}, tt.limit, tt.offset, func(song models.Song) error {
+			got = append(got, song.SongName)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Each: %v", err)
+		}
+		if got == nil {
+			got = []string{}
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("Each(limit %d, offset %d) = %v, want %v", tt.limit, tt.offset, got, tt.want)
+		}
+		if tt.limit < 0 {
+			continue
+		}
+		songs, err := r.Songs.List(models.SongFilter{}, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if names := songNames(songs); !equalStrings(names, tt.want) {
+			t.Errorf("List(limit %d, offset %d) = %v, want %v", tt.limit, tt.offset, names, tt.want)
+		}
+	}
+}
+
+func testSongFindByName(t *testing.T, r Repos) {
+	muse := createGroup(t, r, "Muse")
+	queen := createGroup(t, r, "Queen")
+	id := createSong(t, r, models.Song{GroupID: muse, SongName: "Uprising"})
+
+	if got, err := r.Songs.FindByName(muse, "UPRISING"); err != nil || got != id {
+		t.Errorf("FindByName = %d, %v, want %d", got, err, id)
+	}
+	if _, err := r.Songs.FindByName(queen, "Uprising"); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("FindByName(другая группа) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testSongDelete(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	id := createSong(t, r, models.Song{GroupID: groupID, SongName: "Uprising"})
+
+	if err := r.Songs.Delete(id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := r.Songs.Get(id); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Get после удаления: error = %v, want ErrNotFound", err)
+	}
+	if err := r.Songs.Delete(id); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+	group, err := r.Groups.Get(groupID)
+	if err != nil || group.SongCount != 0 {
+		t.Errorf("группа после удаления песни = %+v, %v", group, err)
+	}
+}
+
+func testSongListTags(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	songs := []struct {
+		name         string
+		genres, tags []string
+	}{
+		{"Hysteria", []string{"rock", "alternative"}, []string{"live"}},
+		{"Starlight", []string{"rock"}, []string{"live", "acoustic"}},
+		// Тег с тем же названием, что и жанр, жанром не считается
+		{"Madness", []string{"pop"}, []string{"rock"}},
+		{"Uprising", nil, nil},
+	}
+	for _, song := range songs {
+		id := createSong(t, r, models.Song{GroupID: groupID, SongName: song.name})
+		if err := r.Tags.Attach(id, models.TagKindGenre, song.genres); err != nil {
+			t.Fatalf("Attach: %v", err)
+		}
+		if err := r.Tags.Attach(id, models.TagKindTag, song.tags); err != nil {
+			t.Fatalf("Attach: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter models.SongFilter
+		want   []string
+	}{
+		{"жанр", models.SongFilter{Genres: []string{"rock"}}, []string{"Hysteria", "Starlight"}},
+		{"любой из жанров", models.SongFilter{Genres: []string{"rock", "pop"}}, []string{"Hysteria", "Starlight", "Madness"}},
+		{"все жанры", models.SongFilter{Genres: []string{"rock", "alternative"}, MatchAll: true}, []string{"Hysteria"}},
+		{"все жанры, таких песен нет", models.SongFilter{Genres: []string{"rock", "pop"}, MatchAll: true}, []string{}},
+		{"повтор жанра", models.SongFilter{Genres: []string{"rock", "rock"}, MatchAll: true}, []string{"Hysteria", "Starlight"}},
+		{"тег", models.SongFilter{Tags: []string{"rock"}}, []string{"Madness"}},
+		{"все теги", models.SongFilter{Tags: []string{"live", "acoustic"}, MatchAll: true}, []string{"Starlight"}},
+		// Условия на жанры и на теги объединяются через AND и без MatchAll
+		{"жанр и тег", models.SongFilter{Genres: []string{"rock", "pop"}, Tags: []string{"live"}}, []string{"Hysteria", "Starlight"}},
+		{"жанр и тег, нет пересечения", models.SongFilter{Genres: []string{"pop"}, Tags: []string{"live"}}, []string{}},
+		{"все жанры и все теги", models.SongFilter{Genres: []string{"rock"}, Tags: []string{"live", "acoustic"}, MatchAll: true}, []string{"Starlight"}},
+		{"неизвестный жанр", models.SongFilter{Genres: []string{"jazz"}}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songs, err := r.Songs.List(tt.filter, 10, 0)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if got := songNames(songs); !equalStrings(got, tt.want) {
+				t.Errorf("List(%+v) = %v, want %v", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func testEnqueueStale(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	full := models.Song{GroupID: groupID, Text: "Куплет", Link: "https://example.com"}
+
+	// Песни без статуса обогащения (как добавленные до очереди) считаются обогащёнными, но enriched_at у них нет
+	legacyFull := full
+	legacyFull.SongName = "Полная без обогащения"
+	createSong(t, r, legacyFull)
+	legacyBlankID := createSong(t, r, models.Song{GroupID: groupID, SongName: "Пустая без обогащения"})
+
+	enriched := full
+	enriched.SongName = "Обогащённая"
+	enriched.EnrichmentStatus = models.EnrichmentPending
+	enrichedID := createSong(t, r, enriched)
+	claimed, err := r.Enrichment.Claim(10, time.Minute)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+	if err := r.Enrichment.Complete(claimed[0].ID); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	queued := func() map[int]bool {
+		t.Helper()
+		jobs, err := r.Enrichment.List(models.EnrichmentJobFilter{Status: models.JobQueued}, 100, 0)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		ids := map[int]bool{}
+		for _, job := range jobs {
+			ids[job.SongID] = true
+		}
+		return ids
+	}
+
+	// Свежая обогащённая песня не устарела, а полная необогащённая не выбирается вовсе
+	n, err := r.Enrichment.EnqueueStale(time.Hour, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("EnqueueStale: %v", err)
+	}
+	if ids := queued(); n != 1 || len(ids) != 1 || !ids[legacyBlankID] {
+		t.Errorf("EnqueueStale(1h) = %d, в очереди %v, want только песню %d", n, ids, legacyBlankID)
+	}
+
+	n, err = r.Enrichment.EnqueueStale(0, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("EnqueueStale: %v", err)
+	}
+	if ids := queued(); n != 1 || len(ids) != 2 || !ids[enrichedID] {
+		t.Errorf("EnqueueStale(0) = %d, в очереди %v, want ещё песню %d", n, ids, enrichedID)
+	}
+	song, err := r.Songs.Get(enrichedID)
+	if err != nil || song.EnrichmentStatus != models.EnrichmentPending {
+		t.Errorf("песня после постановки в очередь = %+v, %v, want pending", song, err)
+	}
+}
+
+func testGroupMerge(t *testing.T, r Repos) {
+	sourceID, err := r.Groups.Create(models.Group{Name: "Muse UK", Country: "UK"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	targetID := createGroup(t, r, "Muse")
+	songID := createSong(t, r, models.Song{GroupID: sourceID, SongName: "Uprising"})
+
+	if err := r.Groups.Merge(sourceID, targetID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if _, err := r.Groups.Get(sourceID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("источник после слияния: error = %v, want ErrNotFound", err)
+	}
+	target, err := r.Groups.Get(targetID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if target.SongCount != 1 || target.Country != "UK" || !equalStrings(target.Aliases, []string{"Muse UK"}) {
+		t.Errorf("цель после слияния = %+v", target)
+	}
+	if song, err := r.Songs.Get(songID); err != nil || song.GroupID != targetID {
+		t.Errorf("песня после слияния = %+v, %v", song, err)
+	}
+	if id, err := r.Groups.FindByName("muse uk"); err != nil || id != targetID {
+		t.Errorf("FindByName(псевдоним) = %d, %v, want %d", id, err, targetID)
+	}
+	if err := r.Groups.Merge(sourceID, targetID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("Merge(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func testGroupMergeTakesOverAlias(t *testing.T, r Repos) {
+	// «Queen II» сливается в «Queen» и становится его псевдонимом, затем группу с тем же
+	// названием создают снова и сливают уже в «Queen + Adam Lambert»
+	queenID := createGroup(t, r, "Queen")
+	if err := r.Groups.Merge(createGroup(t, r, "Queen II"), queenID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	lambertID := createGroup(t, r, "Queen + Adam Lambert")
+	sourceID := createGroup(t, r, "Queen II")
+	createSong(t, r, models.Song{GroupID: sourceID, SongName: "Radio Ga Ga"})
+
+	if err := r.Groups.Merge(sourceID, lambertID); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if id, err := r.Groups.FindByName("Queen II"); err != nil || id != lambertID {
+		t.Errorf("FindByName(Queen II) = %d, %v, want %d", id, err, lambertID)
+	}
+	queen, err := r.Groups.Get(queenID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(queen.Aliases) != 0 {
+		t.Errorf("псевдонимы Queen = %v, want нет", queen.Aliases)
+	}
+	lambert, err := r.Groups.Get(lambertID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !equalStrings(lambert.Aliases, []string{"Queen II"}) {
+		t.Errorf("псевдонимы Queen + Adam Lambert = %v, want [Queen II]", lambert.Aliases)
+	}
+}
+
+func testGroupFindDuplicates(t *testing.T, r Repos) {
+	beatles := createGroup(t, r, "The Beatles")
+	beatles2 := createGroup(t, r, "beatles")
+	metallica := createGroup(t, r, "Metallica")
+	metallica2 := createGroup(t, r, "Metalica")
+	createGroup(t, r, "ABBA")
+
+	duplicates, err := r.Groups.FindDuplicates(0.5, 10)
+	if err != nil {
+		t.Fatalf("FindDuplicates: %v", err)
+	}
+	if len(duplicates) != 2 {
+		t.Fatalf("FindDuplicates = %+v, want 2 пары", duplicates)
+	}
+	if d := duplicates[0]; d.ID != beatles || d.DuplicateID != beatles2 || !d.SameNormalized {
+		t.Errorf("первая пара = %+v, want совпадение нормализованных названий", d)
+	}
+	if d := duplicates[1]; d.ID != metallica || d.DuplicateID != metallica2 || d.SameNormalized || d.Similarity < 0.5 {
+		t.Errorf("вторая пара = %+v, want похожие названия", d)
+	}
+
+	if duplicates, err = r.Groups.FindDuplicates(0.5, 1); err != nil || len(duplicates) != 1 {
+		t.Errorf("FindDuplicates(limit 1) = %+v, %v", duplicates, err)
+	}
+}
+
+// checkPlaylist сверяет песни плейлиста по порядку и проверяет, что позиции идут 1..n без пропусков.
+func checkPlaylist(t *testing.T, r Repos, playlistID int, wantSongs ...int) []models.PlaylistItem {
+	t.Helper()
+	items, err := r.Playlists.Items(playlistID, 100, 0)
+	if err != nil {
+		t.Fatalf("Items: %v", err)
+	}
+	songs := make([]int, len(items))
+	for i, item := range items {
+		songs[i] = item.Song.ID
+		if item.Position != i+1 {
+			t.Errorf("позиция элемента %d = %d, want %d", item.ID, item.Position, i+1)
+		}
+	}
+	if len(songs) != len(wantSongs) {
+		t.Fatalf("песни плейлиста = %v, want %v", songs, wantSongs)
+	}
+	for i := range songs {
+		if songs[i] != wantSongs[i] {
+			t.Fatalf("песни плейлиста = %v, want %v", songs, wantSongs)
+		}
+	}
+	playlist, err := r.Playlists.Get(playlistID)
+	if err != nil || playlist.ItemCount != len(wantSongs) {
+		t.Errorf("плейлист = %+v, %v, want %d песен", playlist, err, len(wantSongs))
+	}
+	return items
+}
+
+func addItem(t *testing.T, r Repos, playlistID, songID, position int) int {
+	t.Helper()
+	id, err := r.Playlists.AddItem(playlistID, songID, position)
+	if err != nil {
+		t.Fatalf("AddItem(%d, %d): %v", songID, position, err)
+	}
+	return id
+}
+
+func testPlaylistItems(t *testing.T, r Repos) {
+	groupID := createGroup(t, r, "Muse")
+	a := createSong(t, r, models.Song{GroupID: groupID, SongName: "Uprising"})
+	b := createSong(t, r, models.Song{GroupID: groupID, SongName: "Hysteria"})
+	c := createSong(t, r, models.Song{GroupID: groupID, SongName: "Starlight"})
+	playlistID, err := r.Playlists.Create(models.Playlist{Name: "Любимое"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	// Вставка: 0 и позиция за концом — в конец, остальные сдвигают последующие элементы
+	itemA := addItem(t, r, playlistID, a, 0)
+	addItem(t, r, playlistID, b, 10)
+	itemC := addItem(t, r, playlistID, c, 1)
+	checkPlaylist(t, r, playlistID, c, a, b)
+
+	// Одна песня может стоять в плейлисте несколько раз
+	itemA2 := addItem(t, r, playlistID, a, 3)
+	checkPlaylist(t, r, playlistID, c, a, a, b)
+
+	if err := r.Playlists.MoveItem(playlistID, itemC, 3); err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	checkPlaylist(t, r, playlistID, a, a, c, b)
+	if err := r.Playlists.MoveItem(playlistID, itemA, 0); err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	checkPlaylist(t, r, playlistID, a, c, b, a)
+	if err := r.Playlists.MoveItem(playlistID, itemA, 1); err != nil {
+		t.Fatalf("MoveItem: %v", err)
+	}
+	items := checkPlaylist(t, r, playlistID, a, a, c, b)
+	if items[0].ID != itemA || items[1].ID != itemA2 {
+		t.Errorf("повторы песни = %d, %d, want %d, %d", items[0].ID, items[1].ID, itemA, itemA2)
+	}
+
+	// Удаление закрывает пропуск в позициях
+	if err := r.Playlists.RemoveItem(playlistID, itemA2); err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+	checkPlaylist(t, r, playlistID, a, c, b)
+	if err := r.Playlists.RemoveItem(playlistID, itemA); err != nil {
+		t.Fatalf("RemoveItem: %v", err)
+	}
+	checkPlaylist(t, r, playlistID, c, b)
+
+	if items, err := r.Playlists.Items(playlistID, 1, 1); err != nil || len(items) != 1 || items[0].Song.ID != b || items[0].Position != 2 {
+		t.Errorf("Items(limit 1, offset 1) = %+v, %v", items, err)
+	}
+}
+
+func testPlaylistItemsMissing(t *testing.T, r Repos) {
+	songID := createSong(t, r, models.Song{GroupID: createGroup(t, r, "Muse"), SongName: "Uprising"})
+	playlistID, err := r.Playlists.Create(models.Playlist{Name: "Любимое"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	otherID, err := r.Playlists.Create(models.Playlist{Name: "Другой"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	itemID := addItem(t, r, playlistID, songID, 0)
+
+	if _, err := r.Playlists.AddItem(playlistID, songID+100, 0); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("AddItem(нет песни) error = %v, want ErrNotFound", err)
+	}
+	if _, err := r.Playlists.AddItem(otherID+100, songID, 0); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("AddItem(нет плейлиста) error = %v, want ErrNotFound", err)
+	}
+	// Элемент чужого плейлиста не удаляется и не переносится
+	if err := r.Playlists.RemoveItem(otherID, itemID); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("RemoveItem(чужой элемент) error = %v, want ErrNotFound", err)
+	}
+	if err := r.Playlists.MoveItem(otherID, itemID, 1); !errors.Is(err, repository.ErrNotFound) {
+		t.Errorf("MoveItem(чужой элемент) error = %v, want ErrNotFound", err)
+	}
+	checkPlaylist(t, r, playlistID, songID)
+	checkPlaylist(t, r, otherID)
+}
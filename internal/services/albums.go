@@ -0,0 +1,119 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+type AlbumService struct {
+	albums repository.AlbumRepository
+	groups repository.GroupRepository
+}
+
+func NewAlbumService(albums repository.AlbumRepository, groups repository.GroupRepository) *AlbumService {
+	return &AlbumService{
+		albums: albums,
+		groups: groups,
+	}
+}
+
+// GetAlbums возвращает альбомы с фильтрацией по группе и названию.
+func (s *AlbumService) GetAlbums(filter models.AlbumFilter, page, limit int) ([]models.Album, error) {
+	if filter.GroupID != 0 {
+		if err := s.checkGroup(filter.GroupID); err != nil {
+			return nil, err
+		}
+	}
+
+	albums, err := s.albums.List(filter, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения альбомов: %v", err)
+		return nil, err
+	}
+	log.Infof("Найдено %d альбомов", len(albums))
+	return albums, nil
+}
+
+func (s *AlbumService) GetAlbum(id int) (models.Album, error) {
+	album, err := s.albums.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Album{}, fmt.Errorf("%w: id %d", ErrAlbumNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения альбома с ID %d: %v", id, err)
+		return models.Album{}, err
+	}
+	return album, nil
+}
+
+// CreateAlbum добавляет альбом и возвращает его с заполненными ID и названием группы.
+func (s *AlbumService) CreateAlbum(album models.Album) (models.Album, error) {
+	if album.Title == "" {
+		return models.Album{}, fmt.Errorf("%w: название альбома не может быть пустым", ErrInvalidInput)
+	}
+	if album.Type == "" {
+		album.Type = models.AlbumTypeLP
+	}
+	if !models.ValidAlbumType(album.Type) {
+		return models.Album{}, fmt.Errorf("%w: неизвестный тип альбома %q", ErrInvalidInput, album.Type)
+	}
+	if err := s.checkGroup(album.GroupID); err != nil {
+		return models.Album{}, err
+	}
+
+	id, err := s.albums.Create(album)
+	if err != nil {
+		log.Errorf("Ошибка добавления альбома: %v", err)
+		return models.Album{}, err
+	}
+	log.Infof("Альбом %q добавлен с ID %d", album.Title, id)
+	return s.GetAlbum(id)
+}
+
+func (s *AlbumService) UpdateAlbum(id int, update models.AlbumUpdate) error {
+	if update.Type != nil && !models.ValidAlbumType(*update.Type) {
+		return fmt.Errorf("%w: неизвестный тип альбома %q", ErrInvalidInput, *update.Type)
+	}
+	if update.GroupID != nil {
+		if err := s.checkGroup(*update.GroupID); err != nil {
+			return err
+		}
+	}
+
+	if err := s.albums.Update(id, update); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: id %d", ErrAlbumNotFound, id)
+		}
+		log.Errorf("Ошибка обновления альбома с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Альбом с ID %d успешно обновлён", id)
+	return nil
+}
+
+func (s *AlbumService) DeleteAlbum(id int) error {
+	if err := s.albums.Delete(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: id %d", ErrAlbumNotFound, id)
+		}
+		log.Errorf("Ошибка удаления альбома с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Альбом с ID %d успешно удалён", id)
+	return nil
+}
+
+func (s *AlbumService) checkGroup(id int) error {
+	_, err := s.groups.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrGroupNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения группы с ID %d: %v", id, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/audiotag"
+	"github.com/EugeneKrivoshein/music_library/internal/importer"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+)
+
+// ErrAudioNotFound возвращается, если у песни нет аудиофайла.
+var ErrAudioNotFound = errors.New("аудиофайл не найден")
+
+// albumLookupLimit — сколько альбомов группы с похожим названием просматривать при поиске по тегам.
+const albumLookupLimit = 100
+
+// AudioService хранит аудиофайлы песен и заполняет песни по их тегам.
+type AudioService struct {
+	audio repository.AudioRepository
+	blobs storage.BlobStorage
+	songs *SongService
+}
+
+func NewAudioService(audio repository.AudioRepository, blobs storage.BlobStorage, songs *SongService) *AudioService {
+	return &AudioService{audio: audio, blobs: blobs, songs: songs}
+}
+
+// AudioUpload — загружаемый аудиофайл. File читается несколько раз, поэтому должен поддерживать Seek.
+type AudioUpload struct {
+	FileName string
+	File     io.ReadSeeker
+}
+
+// AudioUploadResult — итог загрузки аудиофайла песни.
+type AudioUploadResult struct {
+	Audio models.AudioFile
+	Tags  audiotag.Tags
+	// Filled — поля песни, заполненные по тегам файла.
+	Filled []string
+}
+
+// AudioImportOptions — параметры массовой загрузки аудиофайлов.
+type AudioImportOptions struct {
+	// CreateGroups разрешает создавать группы, которых нет в библиотеке.
+	CreateGroups bool
+}
+
+// songBlobPrefix — префикс ключей всех файлов песни в хранилище.
+func songBlobPrefix(songID int) string {
+	return fmt.Sprintf("songs/%d", songID)
+}
+
+// UploadSongAudio сохраняет аудиофайл песни, заменяя прежний, и заполняет по тегам файла
+// пустые поля песни: текст, дату выпуска, альбом, номера трека и диска.
+// Группа и название песни не меняются.
+func (s *AudioService) UploadSongAudio(songID int, upload AudioUpload) (AudioUploadResult, error) {
+	song, err := s.songs.songs.Get(songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return AudioUploadResult{}, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения песни с ID %d: %v", songID, err)
+		return AudioUploadResult{}, err
+	}
+	tags, err := readAudioTags(upload)
+	if err != nil {
+		return AudioUploadResult{}, err
+	}
+
+	audio, err := s.store(songID, upload, tags)
+	if err != nil {
+		return AudioUploadResult{}, err
+	}
+	filled, err := s.fillSong(song, tags)
+	if err != nil {
+		// Файл уже сохранён, незаполненные поля можно поправить вручную
+		log.Warnf("Не удалось заполнить песню %d по тегам файла: %v", songID, err)
+	}
+	log.Infof("Аудиофайл песни %d сохранён (%s, %d байт), заполнено полей: %d", songID, audio.Format, audio.Size, len(filled))
+	return AudioUploadResult{Audio: audio, Tags: tags, Filled: filled}, nil
+}
+
+// ImportAudio добавляет песни по тегам аудиофайлов: группа и название берутся из тегов,
+// а если их там нет — из имени файла «Исполнитель - Название». Файл песни, которая уже есть
+// у группы без аудио, привязывается к ней; песни с аудиофайлом пропускаются.
+// Номер записи в отчёте — номер файла в запросе, начиная с 1.
+func (s *AudioService) ImportAudio(uploads []AudioUpload, opts AudioImportOptions) models.ImportReport {
+	report := models.ImportReport{Rows: make([]models.ImportResult, 0, len(uploads))}
+	newGroups := map[string]bool{}
+	for i, upload := range uploads {
+		result := models.ImportResult{Row: i + 1}
+		tags, err := readAudioTags(upload)
+		// Файл с нераспознанными тегами в отчёте узнаётся по имени
+		result.Group, result.Song = fillFromFileName(tags.Artist, tags.Title, upload.FileName)
+		if err == nil {
+			err = s.importFile(upload, tags, opts, newGroups, &result)
+		}
+		if err != nil {
+			result.Status, result.Reason = models.ImportFailed, err.Error()
+		}
+		report.Add(result)
+	}
+	log.Infof("Загрузка аудиофайлов завершена: добавлено %d, пропущено %d, с ошибками %d",
+		report.Created, report.Skipped, report.Failed)
+	return report
+}
+
+// importFile добавляет песню по тегам одного файла или привязывает файл к существующей песне.
+func (s *AudioService) importFile(upload AudioUpload, tags audiotag.Tags, opts AudioImportOptions,
+	newGroups map[string]bool, result *models.ImportResult) error {
+	if result.Group == "" || result.Song == "" {
+		return fmt.Errorf("%w: в тегах и имени файла нет исполнителя и названия", ErrInvalidInput)
+	}
+
+	_, err := s.songs.groups.FindByName(result.Group)
+	groupExists := err == nil
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Errorf("Ошибка проверки группы: %v", err)
+		return err
+	}
+	groupID, err := s.songs.resolveGroup(result.Group, opts.CreateGroups)
+	if err != nil {
+		return err
+	}
+	if key := normalize.Name(result.Group); !groupExists && !newGroups[key] {
+		result.NewGroup, newGroups[key] = true, true
+	}
+
+	songID, err := s.songs.songs.FindByName(groupID, result.Song)
+	switch {
+	case err == nil:
+		if _, err := s.audio.Get(songID); err == nil {
+			result.Status, result.SongID = models.ImportSkipped, songID
+			result.Reason = "у песни уже есть аудиофайл"
+			return nil
+		} else if !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+		attached, err := s.UploadSongAudio(songID, upload)
+		if err != nil {
+			return err
+		}
+		result.Status, result.SongID = models.ImportCreated, songID
+		result.Reason = "аудиофайл привязан к существующей песне"
+		if len(attached.Filled) > 0 {
+			result.Reason += ", заполнены поля: " + strings.Join(attached.Filled, ", ")
+		}
+		return nil
+	case !errors.Is(err, repository.ErrNotFound):
+		log.Errorf("Ошибка поиска песни: %v", err)
+		return err
+	}
+
+	// Поля из тегов заполняет fillSong, как при загрузке файла: они не считаются введёнными вручную
+	song := models.Song{GroupID: groupID, SongName: result.Song}
+	songID, err = s.songs.songs.Create(song)
+	if err != nil {
+		log.Errorf("Ошибка сохранения песни в базу: %v", err)
+		return err
+	}
+	if _, err := s.store(songID, upload, tags); err != nil {
+		// Песня без файла не нужна: её создали только ради него
+		if delErr := s.songs.songs.Delete(songID); delErr != nil {
+			log.Errorf("Ошибка удаления песни %d после неудачной загрузки файла: %v", songID, delErr)
+		}
+		return err
+	}
+	song.ID = songID
+	if _, err := s.fillSong(song, tags); err != nil {
+		log.Warnf("Не удалось заполнить песню %d по тегам файла: %v", songID, err)
+	}
+	result.Status, result.SongID = models.ImportCreated, songID
+	log.Infof("Песня %s - %s добавлена по аудиофайлу с ID %d", result.Group, result.Song, songID)
+	return nil
+}
+
+// GetSongAudio возвращает сведения об аудиофайле песни.
+func (s *AudioService) GetSongAudio(songID int) (models.AudioFile, error) {
+	audio, err := s.audio.Get(songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.AudioFile{}, s.missingAudio(songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения аудиофайла песни %d: %v", songID, err)
+		return models.AudioFile{}, err
+	}
+	return audio, nil
+}
+
+// OpenSongAudio открывает аудиофайл песни для чтения; закрыть его должен вызывающий.
+func (s *AudioService) OpenSongAudio(songID int) (models.AudioFile, storage.Blob, error) {
+	audio, err := s.GetSongAudio(songID)
+	if err != nil {
+		return models.AudioFile{}, nil, err
+	}
+	blob, err := s.blobs.Open(audio.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		// Запись есть, а файла нет: хранилище потеряло его или его удалили вручную
+		log.Errorf("Файл %s песни %d отсутствует в хранилище", audio.StorageKey, songID)
+		return models.AudioFile{}, nil, fmt.Errorf("%w: файл песни %d отсутствует в хранилище", ErrAudioNotFound, songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка открытия файла %s: %v", audio.StorageKey, err)
+		return models.AudioFile{}, nil, err
+	}
+	return audio, blob, nil
+}
+
+// DeleteSongAudio удаляет аудиофайл песни; сама песня остаётся.
+func (s *AudioService) DeleteSongAudio(songID int) error {
+	audio, err := s.GetSongAudio(songID)
+	if err != nil {
+		return err
+	}
+	if err := s.audio.Delete(songID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return s.missingAudio(songID)
+		}
+		log.Errorf("Ошибка удаления аудиофайла песни %d: %v", songID, err)
+		return err
+	}
+	if err := s.blobs.Delete(audio.StorageKey); err != nil {
+		log.Warnf("Не удалось удалить файл %s из хранилища: %v", audio.StorageKey, err)
+	}
+	log.Infof("Аудиофайл песни %d удалён", songID)
+	return nil
+}
+
+// missingAudio различает отсутствие песни и отсутствие у неё аудиофайла.
+func (s *AudioService) missingAudio(songID int) error {
+	if _, err := s.songs.songs.Get(songID); errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	return fmt.Errorf("%w: у песни %d", ErrAudioNotFound, songID)
+}
+
+// store сохраняет файл в хранилище под ключом с контрольной суммой и записывает сведения о нём.
+// Прежний файл песни удаляется из хранилища после того, как запись указывает на новый.
+func (s *AudioService) store(songID int, upload AudioUpload, tags audiotag.Tags) (models.AudioFile, error) {
+	checksum, err := checksumOf(upload.File)
+	if err != nil {
+		return models.AudioFile{}, err
+	}
+
+	audio := models.AudioFile{
+		SongID:     songID,
+		StorageKey: fmt.Sprintf("%s/audio-%s%s", songBlobPrefix(songID), checksum[:16], audiotag.Extension(tags.Format)),
+		FileName:   upload.FileName,
+		Format:     tags.Format,
+		MimeType:   audiotag.MimeType(tags.Format),
+		Checksum:   checksum,
+		Duration:   tags.Duration,
+	}
+	previous, err := s.audio.Get(songID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Errorf("Ошибка получения аудиофайла песни %d: %v", songID, err)
+		return models.AudioFile{}, err
+	}
+
+	if audio.Size, err = s.blobs.Put(audio.StorageKey, upload.File); err != nil {
+		log.Errorf("Ошибка сохранения файла %s: %v", audio.StorageKey, err)
+		return models.AudioFile{}, fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+	if err := s.audio.Save(audio); err != nil {
+		if previous.StorageKey != audio.StorageKey {
+			s.blobs.Delete(audio.StorageKey)
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.AudioFile{}, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+		}
+		log.Errorf("Ошибка сохранения аудиофайла песни %d: %v", songID, err)
+		return models.AudioFile{}, err
+	}
+	if previous.StorageKey != "" && previous.StorageKey != audio.StorageKey {
+		if err := s.blobs.Delete(previous.StorageKey); err != nil {
+			log.Warnf("Не удалось удалить прежний файл %s: %v", previous.StorageKey, err)
+		}
+	}
+	return s.audio.Get(songID)
+}
+
+// checksumOf возвращает SHA-256 содержимого r в шестнадцатеричном виде
+// и возвращает r в начало, чтобы его можно было прочитать снова.
+func checksumOf(r io.ReadSeeker) (string, error) {
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// fillSong заполняет пустые поля песни по тегам и возвращает названия заполненных полей.
+func (s *AudioService) fillSong(song models.Song, tags audiotag.Tags) ([]string, error) {
+	var update models.SongUpdate
+	var filled []string
+	if song.Text == "" && tags.Lyrics != "" {
+		update.Text = &tags.Lyrics
+		filled = append(filled, "text")
+	}
+	if song.ReleaseDate == nil {
+		if date := tagReleaseDate(tags); date != nil {
+			update.ReleaseDate = date
+			filled = append(filled, "release_date")
+		}
+	}
+	albumID := song.AlbumID
+	if albumID == nil && tags.Album != "" {
+		id, err := s.albumByTitle(song.GroupID, tags.Album, update.ReleaseDate)
+		if err != nil {
+			return nil, err
+		}
+		albumID, update.AlbumID = &id, &id
+		filled = append(filled, "album")
+	}
+	// Номера трека и диска имеют смысл только внутри альбома
+	if albumID != nil && song.TrackNumber == nil && tags.Track > 0 {
+		update.TrackNumber = &tags.Track
+		filled = append(filled, "track_number")
+	}
+	if albumID != nil && song.DiscNumber == nil && tags.Disc > 0 {
+		update.DiscNumber = &tags.Disc
+		filled = append(filled, "disc_number")
+	}
+	if len(filled) == 0 {
+		return nil, nil
+	}
+	if err := s.songs.FillSongFields(song.ID, tagsAuthor, update); err != nil {
+		return nil, fmt.Errorf("ошибка обновления песни: %w", err)
+	}
+	return filled, nil
+}
+
+// tagsAuthor — автор правок, сделанных по тегам аудиофайла, в истории версий текста.
+const tagsAuthor = "audio-tags"
+
+// tagReleaseDate возвращает дату выпуска из тегов. Если в тегах указан только год,
+// датой считается 1 января этого года.
+func tagReleaseDate(tags audiotag.Tags) *time.Time {
+	if tags.Date != "" {
+		if date, err := time.Parse(models.DateLayout, tags.Date); err == nil {
+			return &date
+		}
+	}
+	if tags.Year > 0 {
+		date := time.Date(tags.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	return nil
+}
+
+// albumByTitle находит альбом группы с названием title без учёта регистра или создаёт его.
+func (s *AudioService) albumByTitle(groupID int, title string, releaseDate *time.Time) (int, error) {
+	albums, err := s.songs.albums.List(models.AlbumFilter{GroupID: groupID, Title: title}, albumLookupLimit, 0)
+	if err != nil {
+		log.Errorf("Ошибка поиска альбома: %v", err)
+		return 0, err
+	}
+	for _, album := range albums {
+		if strings.EqualFold(album.Title, title) {
+			return album.ID, nil
+		}
+	}
+	id, err := s.songs.albums.Create(models.Album{GroupID: groupID, Title: title, ReleaseDate: releaseDate, Type: models.AlbumTypeLP})
+	if err != nil {
+		log.Errorf("Ошибка добавления альбома: %v", err)
+		return 0, err
+	}
+	log.Infof("Добавлен альбом %q с ID %d по тегам аудиофайла", title, id)
+	return id, nil
+}
+
+// readAudioTags разбирает теги загруженного файла; нераспознанный формат — ошибка клиента.
+func readAudioTags(upload AudioUpload) (audiotag.Tags, error) {
+	tags, err := audiotag.Read(upload.File)
+	if err != nil {
+		return audiotag.Tags{}, fmt.Errorf("%w: %s: %v", ErrInvalidInput, upload.FileName, err)
+	}
+	return tags, nil
+}
+
+// fillFromFileName дополняет недостающие группу или название из имени файла.
+func fillFromFileName(group, song, fileName string) (string, string) {
+	fileGroup, fileSong := importer.FromFileName(fileName)
+	if group == "" {
+		group = fileGroup
+	}
+	if song == "" {
+		song = fileSong
+	}
+	return group, song
+}
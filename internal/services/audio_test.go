@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/memory"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+)
+
+// audioFixture — AudioService и SongService на общем хранилище в памяти.
+type audioFixture struct {
+	songFixture
+	audio *AudioService
+}
+
+func newAudioFixture(t *testing.T) audioFixture {
+	t.Helper()
+	store := memory.NewStore()
+	blobs, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	f := audioFixture{songFixture: songFixture{
+		songs:  memory.NewSongRepository(store),
+		groups: memory.NewGroupRepository(store),
+		albums: memory.NewAlbumRepository(store),
+	}}
+	f.service = NewSongService(f.songs, f.groups, f.albums, blobs)
+	f.audio = NewAudioService(memory.NewAudioRepository(store), blobs, f.service)
+	return f
+}
+
+// id3Frame собирает кадр ID3v2.3: размер кадра в нём — обычное 32-битное число.
+func id3Frame(id string, data []byte) []byte {
+	frame := append([]byte(id), 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(frame[4:8], uint32(len(data)))
+	return append(frame, data...)
+}
+
+// id3Text — данные текстового кадра ID3 в UTF-8.
+func id3Text(s string) []byte {
+	return append([]byte{3}, s...)
+}
+
+// testMP3 — тег ID3v2.3 из frames и три кадра MPEG-1 Layer III.
+func testMP3(frames ...[]byte) []byte {
+	body := bytes.Join(frames, nil)
+	size := len(body)
+	file := []byte{'I', 'D', '3', 3, 0, 0,
+		byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	file = append(file, body...)
+	frame := make([]byte, 417)
+	copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+	for i := 0; i < 3; i++ {
+		file = append(file, frame...)
+	}
+	return file
+}
+
+// taggedMP3 — MP3 с альбомом, номером трека, годом выпуска без даты и текстом песни.
+func taggedMP3(artist, title string) []byte {
+	return testMP3(
+		id3Frame("TPE1", id3Text(artist)),
+		id3Frame("TIT2", id3Text(title)),
+		id3Frame("TALB", id3Text("Absolution")),
+		id3Frame("TRCK", id3Text("3/14")),
+		id3Frame("TYER", id3Text("2003")),
+		id3Frame("USLT", append([]byte{3, 'e', 'n', 'g', 0}, "Crossing the line"...)),
+	)
+}
+
+func TestUploadSongAudioFillsEmptyFields(t *testing.T) {
+	f := newAudioFixture(t)
+	id, err := f.service.AddSongWithAPI("Muse", "Hysteria", AddSongOptions{CreateGroup: true})
+	if err != nil {
+		t.Fatalf("AddSongWithAPI: %v", err)
+	}
+
+	result, err := f.audio.UploadSongAudio(id, AudioUpload{FileName: "hysteria.mp3", File: bytes.NewReader(taggedMP3("Muse", "Hysteria"))})
+	if err != nil {
+		t.Fatalf("UploadSongAudio: %v", err)
+	}
+	wantFilled := []string{"text", "release_date", "album", "track_number"}
+	if !slices.Equal(result.Filled, wantFilled) {
+		t.Errorf("заполнены поля %v, ожидались %v", result.Filled, wantFilled)
+	}
+
+	song, err := f.songs.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if song.ReleaseDate == nil || !song.ReleaseDate.Equal(time.Date(2003, time.January, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("дата выпуска по году из тегов: %v, ожидалось 2003-01-01", song.ReleaseDate)
+	}
+	if song.Text != "Crossing the line" || song.AlbumID == nil || song.TrackNumber == nil || *song.TrackNumber != 3 {
+		t.Errorf("поля из тегов заполнены неверно: %+v", song)
+	}
+	// Поля из тегов, как и поля обогащения, не считаются введёнными вручную
+	if len(song.ManualFields) != 0 {
+		t.Errorf("поля из тегов отмечены как ручные: %v", song.ManualFields)
+	}
+}
+
+func TestUploadSongAudioKeepsFilledFields(t *testing.T) {
+	f := newAudioFixture(t)
+	id, err := f.service.AddSongWithAPI("Muse", "Hysteria", AddSongOptions{CreateGroup: true})
+	if err != nil {
+		t.Fatalf("AddSongWithAPI: %v", err)
+	}
+	if err := f.service.UpdateSong(id, "", false, models.SongUpdate{Text: ptr("Свой текст")}); err != nil {
+		t.Fatalf("UpdateSong: %v", err)
+	}
+
+	result, err := f.audio.UploadSongAudio(id, AudioUpload{FileName: "hysteria.mp3", File: bytes.NewReader(taggedMP3("Muse", "Hysteria"))})
+	if err != nil {
+		t.Fatalf("UploadSongAudio: %v", err)
+	}
+	if slices.Contains(result.Filled, "text") {
+		t.Errorf("заполнен уже введённый текст: %v", result.Filled)
+	}
+	song, err := f.songs.Get(id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if song.Text != "Свой текст" {
+		t.Errorf("текст песни %q заменён тегами", song.Text)
+	}
+	if !slices.Equal(song.ManualFields, []string{models.SongFieldText}) {
+		t.Errorf("ручные поля %v, ожидался только текст", song.ManualFields)
+	}
+}
+
+func TestImportAudioFillsFromTags(t *testing.T) {
+	f := newAudioFixture(t)
+	report := f.audio.ImportAudio([]AudioUpload{
+		{FileName: "03 - Hysteria.mp3", File: bytes.NewReader(taggedMP3("Muse", "Hysteria"))},
+	}, AudioImportOptions{CreateGroups: true})
+	if len(report.Rows) != 1 || report.Rows[0].Status != models.ImportCreated {
+		t.Fatalf("отчёт импорта %+v, ожидалась одна созданная песня", report.Rows)
+	}
+
+	song, err := f.songs.Get(report.Rows[0].SongID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if song.SongName != "Hysteria" || song.Text != "Crossing the line" || song.AlbumID == nil {
+		t.Errorf("песня по тегам заполнена неверно: %+v", song)
+	}
+	if song.ReleaseDate == nil || song.ReleaseDate.Year() != 2003 {
+		t.Errorf("дата выпуска %v, ожидался 2003 год", song.ReleaseDate)
+	}
+	if len(song.ManualFields) != 0 {
+		t.Errorf("поля из тегов отмечены как ручные: %v", song.ManualFields)
+	}
+}
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/audiotag"
+	"github.com/EugeneKrivoshein/music_library/internal/imaging"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+)
+
+// ErrCoverNotFound возвращается, если у песни или группы нет обложки.
+var ErrCoverNotFound = errors.New("обложка не найдена")
+
+// CoverSizes — допустимые размеры миниатюр: длина большей стороны в пикселях.
+var CoverSizes = []int{64, 128, 256, 512}
+
+// maxCoverPixels ограничивает площадь обложки, чтобы декодирование не съело память.
+const maxCoverPixels = 50_000_000
+
+// CoverService хранит обложки песен и групп и создаёт их миниатюры.
+type CoverService struct {
+	covers repository.CoverRepository
+	blobs  storage.BlobStorage
+	songs  repository.SongRepository
+	groups repository.GroupRepository
+}
+
+func NewCoverService(covers repository.CoverRepository, blobs storage.BlobStorage,
+	songs repository.SongRepository, groups repository.GroupRepository) *CoverService {
+	return &CoverService{covers: covers, blobs: blobs, songs: songs, groups: groups}
+}
+
+// CoverUpload — загружаемое изображение или аудиофайл со встроенной обложкой.
+type CoverUpload struct {
+	FileName string
+	File     io.ReadSeeker
+}
+
+// coverBlobPrefix — префикс ключей файлов владельца обложки в хранилище.
+func coverBlobPrefix(kind models.CoverKind, ownerID int) string {
+	if kind == models.CoverKindGroup {
+		return groupBlobPrefix(ownerID)
+	}
+	return songBlobPrefix(ownerID)
+}
+
+// coverThumbPrefix — каталог миниатюр обложки: ключ исходного изображения без расширения.
+// Ключ содержит контрольную сумму, поэтому миниатюры прежней обложки не путаются с новыми.
+func coverThumbPrefix(cover models.Cover) string {
+	return strings.TrimSuffix(cover.StorageKey, imaging.Extension(coverFormat(cover)))
+}
+
+// coverFormat возвращает формат обложки по её типу содержимого.
+func coverFormat(cover models.Cover) string {
+	if cover.MimeType == imaging.MimeType(imaging.FormatPNG) {
+		return imaging.FormatPNG
+	}
+	return imaging.FormatJPEG
+}
+
+// UploadCover сохраняет обложку песни или группы, заменяя прежнюю. Вместо изображения JPEG
+// или PNG можно передать аудиофайл: обложка берётся из его тегов.
+func (s *CoverService) UploadCover(kind models.CoverKind, ownerID int, upload CoverUpload) (models.Cover, error) {
+	if err := s.checkOwner(kind, ownerID); err != nil {
+		return models.Cover{}, err
+	}
+	image, err := coverImage(upload)
+	if err != nil {
+		return models.Cover{}, err
+	}
+	if _, err := image.Seek(0, io.SeekStart); err != nil {
+		return models.Cover{}, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	config, format, err := imaging.DecodeConfig(image)
+	if err != nil {
+		return models.Cover{}, fmt.Errorf("%w: %s: %v", ErrInvalidInput, upload.FileName, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxCoverPixels {
+		return models.Cover{}, fmt.Errorf("%w: недопустимый размер изображения %d×%d", ErrInvalidInput, config.Width, config.Height)
+	}
+	checksum, err := checksumOf(image)
+	if err != nil {
+		return models.Cover{}, err
+	}
+
+	cover := models.Cover{
+		Kind:       kind,
+		OwnerID:    ownerID,
+		StorageKey: fmt.Sprintf("%s/cover-%s%s", coverBlobPrefix(kind, ownerID), checksum[:16], imaging.Extension(format)),
+		MimeType:   imaging.MimeType(format),
+		Width:      config.Width,
+		Height:     config.Height,
+		Checksum:   checksum,
+	}
+	previous, err := s.covers.Get(kind, ownerID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Errorf("Ошибка получения обложки %s: %v", ownerName(kind, ownerID), err)
+		return models.Cover{}, err
+	}
+
+	if cover.Size, err = s.blobs.Put(cover.StorageKey, image); err != nil {
+		log.Errorf("Ошибка сохранения файла %s: %v", cover.StorageKey, err)
+		return models.Cover{}, fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+	if err := s.covers.Save(cover); err != nil {
+		if previous.StorageKey != cover.StorageKey {
+			s.blobs.Delete(cover.StorageKey)
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.Cover{}, s.ownerNotFound(kind, ownerID)
+		}
+		log.Errorf("Ошибка сохранения обложки %s: %v", ownerName(kind, ownerID), err)
+		return models.Cover{}, err
+	}
+	if previous.StorageKey != "" && previous.StorageKey != cover.StorageKey {
+		s.deleteFiles(previous)
+	}
+	log.Infof("Обложка %s сохранена (%s, %d×%d, %d байт)", ownerName(kind, ownerID), format, cover.Width, cover.Height, cover.Size)
+	return s.covers.Get(kind, ownerID)
+}
+
+// GetCover возвращает сведения об обложке песни или группы.
+func (s *CoverService) GetCover(kind models.CoverKind, ownerID int) (models.Cover, error) {
+	if _, ok := coverKinds[kind]; !ok {
+		return models.Cover{}, fmt.Errorf("%w: неизвестный вид обложки %q", ErrInvalidInput, kind)
+	}
+	cover, err := s.covers.Get(kind, ownerID)
+	if errors.Is(err, repository.ErrNotFound) {
+		if err := s.checkOwner(kind, ownerID); err != nil {
+			return models.Cover{}, err
+		}
+		return models.Cover{}, fmt.Errorf("%w: у %s", ErrCoverNotFound, ownerName(kind, ownerID))
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения обложки %s: %v", ownerName(kind, ownerID), err)
+		return models.Cover{}, err
+	}
+	return cover, nil
+}
+
+// OpenCover открывает обложку для чтения: исходное изображение при size = 0 или миниатюру,
+// большая сторона которой не больше size. Миниатюра создаётся при первом запросе и сохраняется
+// в хранилище; изображение меньше запрошенного размера отдаётся как есть.
+// Закрыть файл должен вызывающий.
+func (s *CoverService) OpenCover(kind models.CoverKind, ownerID, size int) (models.Cover, storage.Blob, error) {
+	if size != 0 && !slices.Contains(CoverSizes, size) {
+		return models.Cover{}, nil, fmt.Errorf("%w: размер миниатюры должен быть одним из %v", ErrInvalidInput, CoverSizes)
+	}
+	cover, err := s.GetCover(kind, ownerID)
+	if err != nil {
+		return models.Cover{}, nil, err
+	}
+	if size == 0 || size >= max(cover.Width, cover.Height) {
+		blob, err := s.open(cover, cover.StorageKey)
+		return cover, blob, err
+	}
+
+	key := fmt.Sprintf("%s/%d%s", coverThumbPrefix(cover), size, imaging.Extension(coverFormat(cover)))
+	blob, err := s.blobs.Open(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		if err := s.thumbnail(cover, key, size); err != nil {
+			return models.Cover{}, nil, err
+		}
+		blob, err = s.blobs.Open(key)
+	}
+	if err != nil {
+		log.Errorf("Ошибка открытия файла %s: %v", key, err)
+		return models.Cover{}, nil, err
+	}
+	return cover, blob, nil
+}
+
+// DeleteCover удаляет обложку песни или группы вместе с миниатюрами.
+func (s *CoverService) DeleteCover(kind models.CoverKind, ownerID int) error {
+	cover, err := s.GetCover(kind, ownerID)
+	if err != nil {
+		return err
+	}
+	if err := s.covers.Delete(kind, ownerID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: у %s", ErrCoverNotFound, ownerName(kind, ownerID))
+		}
+		log.Errorf("Ошибка удаления обложки %s: %v", ownerName(kind, ownerID), err)
+		return err
+	}
+	s.deleteFiles(cover)
+	log.Infof("Обложка %s удалена", ownerName(kind, ownerID))
+	return nil
+}
+
+// open открывает файл обложки; отсутствие файла при наличии записи — потерянная обложка.
+func (s *CoverService) open(cover models.Cover, key string) (storage.Blob, error) {
+	blob, err := s.blobs.Open(key)
+	if errors.Is(err, storage.ErrNotFound) {
+		log.Errorf("Файл %s обложки %s отсутствует в хранилище", key, ownerName(cover.Kind, cover.OwnerID))
+		return nil, fmt.Errorf("%w: файл обложки %s отсутствует в хранилище", ErrCoverNotFound, ownerName(cover.Kind, cover.OwnerID))
+	}
+	if err != nil {
+		log.Errorf("Ошибка открытия файла %s: %v", key, err)
+		return nil, err
+	}
+	return blob, nil
+}
+
+// thumbnail создаёт миниатюру обложки размера size и сохраняет её под ключом key.
+// Одновременные запросы могут создать её дважды: запись атомарна, результат одинаков.
+func (s *CoverService) thumbnail(cover models.Cover, key string, size int) error {
+	original, err := s.open(cover, cover.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer original.Close()
+
+	var buf bytes.Buffer
+	if _, err := imaging.Thumbnail(&buf, original, size); err != nil {
+		log.Errorf("Ошибка создания миниатюры %s: %v", key, err)
+		return fmt.Errorf("ошибка создания миниатюры: %w", err)
+	}
+	if _, err := s.blobs.Put(key, &buf); err != nil {
+		log.Errorf("Ошибка сохранения файла %s: %v", key, err)
+		return fmt.Errorf("ошибка сохранения миниатюры: %w", err)
+	}
+	log.Infof("Создана миниатюра %s", key)
+	return nil
+}
+
+// deleteFiles удаляет из хранилища исходное изображение обложки и её миниатюры.
+func (s *CoverService) deleteFiles(cover models.Cover) {
+	if err := s.blobs.Delete(cover.StorageKey); err != nil {
+		log.Warnf("Не удалось удалить файл %s: %v", cover.StorageKey, err)
+	}
+	if err := s.blobs.DeletePrefix(coverThumbPrefix(cover)); err != nil {
+		log.Warnf("Не удалось удалить миниатюры %s: %v", coverThumbPrefix(cover), err)
+	}
+}
+
+// coverKinds — виды владельцев обложек.
+var coverKinds = map[models.CoverKind]bool{
+	models.CoverKindSong:  true,
+	models.CoverKindGroup: true,
+}
+
+// checkOwner проверяет, что песня или группа существует.
+func (s *CoverService) checkOwner(kind models.CoverKind, ownerID int) error {
+	var err error
+	switch kind {
+	case models.CoverKindSong:
+		_, err = s.songs.Get(ownerID)
+	case models.CoverKindGroup:
+		_, err = s.groups.Get(ownerID)
+	default:
+		return fmt.Errorf("%w: неизвестный вид обложки %q", ErrInvalidInput, kind)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.ownerNotFound(kind, ownerID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения владельца обложки %s: %v", ownerName(kind, ownerID), err)
+		return err
+	}
+	return nil
+}
+
+// ownerName возвращает владельца обложки для сообщений: «песни 42» или «группы 7».
+func ownerName(kind models.CoverKind, ownerID int) string {
+	if kind == models.CoverKindGroup {
+		return fmt.Sprintf("группы %d", ownerID)
+	}
+	return fmt.Sprintf("песни %d", ownerID)
+}
+
+func (s *CoverService) ownerNotFound(kind models.CoverKind, ownerID int) error {
+	if kind == models.CoverKindGroup {
+		return fmt.Errorf("%w: id %d", ErrGroupNotFound, ownerID)
+	}
+	return fmt.Errorf("%w: id %d", ErrSongNotFound, ownerID)
+}
+
+// coverImage возвращает изображение из загруженного файла: сам файл, если это JPEG или PNG,
+// или обложку из тегов аудиофайла.
+func coverImage(upload CoverUpload) (io.ReadSeeker, error) {
+	if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	_, _, err := imaging.DecodeConfig(upload.File)
+	if err == nil || !errors.Is(err, imaging.ErrUnsupported) {
+		return upload.File, nil
+	}
+	if _, err := upload.File.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("ошибка чтения файла: %w", err)
+	}
+	tags, err := audiotag.Read(upload.File)
+	if errors.Is(err, audiotag.ErrUnsupported) {
+		return nil, fmt.Errorf("%w: %s: нужно изображение JPEG или PNG либо аудиофайл с обложкой", ErrInvalidInput, upload.FileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidInput, upload.FileName, err)
+	}
+	if tags.Picture == nil {
+		return nil, fmt.Errorf("%w: %s: в аудиофайле нет обложки", ErrInvalidInput, upload.FileName)
+	}
+	return bytes.NewReader(tags.Picture.Data), nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/imaging"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/memory"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+)
+
+// newCoverFixture возвращает CoverService на хранилище в памяти и ID песни, у которой нет обложки.
+func newCoverFixture(t *testing.T) (*CoverService, int) {
+	t.Helper()
+	store := memory.NewStore()
+	blobs, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	songs, groups := memory.NewSongRepository(store), memory.NewGroupRepository(store)
+	service := NewSongService(songs, groups, memory.NewAlbumRepository(store), blobs)
+	id, err := service.AddSongWithAPI("Muse", "Uprising", AddSongOptions{CreateGroup: true})
+	if err != nil {
+		t.Fatalf("AddSongWithAPI: %v", err)
+	}
+	return NewCoverService(memory.NewCoverRepository(store), blobs, songs, groups), id
+}
+
+// testImage кодирует однотонное изображение width×height в формате format.
+func testImage(t *testing.T, format string, width, height int) []byte {
+	t.Helper()
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	var buf bytes.Buffer
+	var err error
+	if format == imaging.FormatPNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// apicFrame — кадр ID3v2.3 с передней обложкой.
+func apicFrame(mimeType string, data []byte) []byte {
+	body := append([]byte{0}, mimeType...)
+	body = append(body, 0, 3, 0)
+	return id3Frame("APIC", append(body, data...))
+}
+
+// flacWithPicture собирает файл FLAC из STREAMINFO и блока PICTURE с передней обложкой.
+func flacWithPicture(mimeType string, data []byte) []byte {
+	info := make([]byte, 34)
+	info[10], info[11], info[12] = 0x0A, 0xC4, 0x42 // 44100 Гц, 2 канала
+	file := append([]byte("fLaC"), 0, 0, 0, byte(len(info)))
+	file = append(file, info...)
+
+	block := binary.BigEndian.AppendUint32(nil, 3)
+	block = binary.BigEndian.AppendUint32(block, uint32(len(mimeType)))
+	block = append(block, mimeType...)
+	block = binary.BigEndian.AppendUint32(block, 0)
+	block = append(block, make([]byte, 16)...)
+	block = binary.BigEndian.AppendUint32(block, uint32(len(data)))
+	block = append(block, data...)
+	n := len(block)
+	file = append(file, 0x80|6, byte(n>>16), byte(n>>8), byte(n))
+	return append(file, block...)
+}
+
+func TestUploadCoverFromAudio(t *testing.T) {
+	tests := []struct {
+		name       string
+		fileName   string
+		file       []byte
+		wantMime   string
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name:     "MP3 с обложкой PNG",
+			fileName: "uprising.mp3",
+			file: testMP3(
+				id3Frame("TIT2", id3Text("Uprising")),
+				apicFrame("image/png", testImage(t, imaging.FormatPNG, 300, 200)),
+			),
+			wantMime: "image/png", wantWidth: 300, wantHeight: 200,
+		},
+		{
+			name:     "FLAC с обложкой JPEG",
+			fileName: "uprising.flac",
+			file:     flacWithPicture("image/jpeg", testImage(t, imaging.FormatJPEG, 200, 400)),
+			wantMime: "image/jpeg", wantWidth: 200, wantHeight: 400,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			covers, id := newCoverFixture(t)
+			cover, err := covers.UploadCover(models.CoverKindSong, id, CoverUpload{FileName: tt.fileName, File: bytes.NewReader(tt.file)})
+			if err != nil {
+				t.Fatalf("UploadCover: %v", err)
+			}
+			if cover.MimeType != tt.wantMime || cover.Width != tt.wantWidth || cover.Height != tt.wantHeight {
+				t.Errorf("обложка %s %d×%d, ожидалась %s %d×%d",
+					cover.MimeType, cover.Width, cover.Height, tt.wantMime, tt.wantWidth, tt.wantHeight)
+			}
+
+			_, blob, err := covers.OpenCover(models.CoverKindSong, id, 128)
+			if err != nil {
+				t.Fatalf("OpenCover: %v", err)
+			}
+			defer blob.Close()
+			config, format, err := imaging.DecodeConfig(blob)
+			if err != nil {
+				t.Fatalf("DecodeConfig: %v", err)
+			}
+			if imaging.MimeType(format) != tt.wantMime || max(config.Width, config.Height) != 128 {
+				t.Errorf("миниатюра %s %d×%d, ожидалась %s с большей стороной 128",
+					format, config.Width, config.Height, tt.wantMime)
+			}
+		})
+	}
+}
+
+func TestUploadCoverAudioWithoutPicture(t *testing.T) {
+	covers, id := newCoverFixture(t)
+	file := testMP3(id3Frame("TIT2", id3Text("Uprising")))
+	_, err := covers.UploadCover(models.CoverKindSong, id, CoverUpload{FileName: "uprising.mp3", File: bytes.NewReader(file)})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ошибка %v, ожидалась ErrInvalidInput", err)
+	}
+	if _, err := covers.GetCover(models.CoverKindSong, id); !errors.Is(err, ErrCoverNotFound) {
+		t.Errorf("GetCover: ошибка %v, ожидалась ErrCoverNotFound", err)
+	}
+}
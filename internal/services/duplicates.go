@@ -0,0 +1,43 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// DefaultDuplicateThreshold — порог сходства названий для кандидатов в дубликаты.
+// Выше порога нечёткого поиска: здесь важнее точность, чем полнота.
+const DefaultDuplicateThreshold = 0.6
+
+// DuplicateService ищет группы и песни, похожие на дубликаты друг друга.
+type DuplicateService struct {
+	groups repository.GroupRepository
+	songs  repository.SongRepository
+}
+
+func NewDuplicateService(groups repository.GroupRepository, songs repository.SongRepository) *DuplicateService {
+	return &DuplicateService{groups: groups, songs: songs}
+}
+
+// FindDuplicates возвращает до limit пар групп и до limit пар песен, названия которых
+// совпадают после нормализации или похожи не меньше чем на threshold.
+func (s *DuplicateService) FindDuplicates(threshold float64, limit int) (models.Duplicates, error) {
+	if threshold <= 0 || threshold > 1 {
+		return models.Duplicates{}, fmt.Errorf("%w: порог сходства должен быть в диапазоне (0, 1]", ErrInvalidInput)
+	}
+
+	groups, err := s.groups.FindDuplicates(threshold, limit)
+	if err != nil {
+		log.Errorf("Ошибка поиска дубликатов групп: %v", err)
+		return models.Duplicates{}, err
+	}
+	songs, err := s.songs.FindDuplicates(threshold, limit)
+	if err != nil {
+		log.Errorf("Ошибка поиска дубликатов песен: %v", err)
+		return models.Duplicates{}, err
+	}
+	log.Infof("Найдено кандидатов в дубликаты: %d пар групп, %d пар песен", len(groups), len(songs))
+	return models.Duplicates{Groups: groups, Songs: songs}, nil
+}
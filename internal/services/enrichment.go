@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/metadata"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+var (
+	// ErrJobNotFound возвращается, если задачи обогащения с указанным ID нет.
+	ErrJobNotFound = errors.New("задача обогащения не найдена")
+	// ErrJobRunning возвращается при попытке перезапустить выполняющуюся задачу.
+	ErrJobRunning = errors.New("задача обогащения уже выполняется")
+)
+
+// maxEnrichRetryDelay ограничивает задержку перед повтором задачи обогащения.
+const maxEnrichRetryDelay = time.Hour
+
+// EnrichmentConfig — настройки обработчиков очереди обогащения.
+type EnrichmentConfig struct {
+	// Workers — число задач, выполняемых одновременно.
+	Workers int
+	// MaxAttempts — число попыток, после которого задача считается неудачной.
+	MaxAttempts int
+	// PollInterval — как часто свободный обработчик проверяет очередь.
+	PollInterval time.Duration
+	// RetryDelay — задержка перед первым повтором; дальше она удваивается.
+	RetryDelay time.Duration
+	// JobTimeout ограничивает одну попытку. Задачу, которую обработчик не завершил
+	// за два таких срока (например, процесс упал), забирает другой обработчик.
+	JobTimeout time.Duration
+	// Policies — политика слияния для полей из models.SongFields; без политики поле только заполняется.
+	Policies map[string]models.MergePolicy
+	// Refresh — настройки планировщика повторного обогащения.
+	Refresh RefreshConfig
+}
+
+// EnrichmentService дополняет песни сведениями из источников в фоне: песня добавляется
+// сразу со статусом pending, а обработчики забирают её задачу из очереди.
+type EnrichmentService struct {
+	jobs     repository.EnrichmentRepository
+	songs    repository.SongRepository
+	metadata metadata.MetadataProvider
+	config   EnrichmentConfig
+}
+
+func NewEnrichmentService(jobs repository.EnrichmentRepository, songs repository.SongRepository,
+	provider metadata.MetadataProvider, config EnrichmentConfig) *EnrichmentService {
+	return &EnrichmentService{jobs: jobs, songs: songs, metadata: provider, config: config}
+}
+
+// Run запускает обработчики очереди и ждёт их завершения после отмены ctx.
+func (s *EnrichmentService) Run(ctx context.Context) {
+	log.Infof("Запущено обработчиков очереди обогащения: %d", s.config.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.work(ctx)
+		}()
+	}
+	wg.Wait()
+	log.Info("Обработчики очереди обогащения остановлены")
+}
+
+// work забирает задачи по одной, пока ctx не отменён; при пустой очереди ждёт PollInterval.
+func (s *EnrichmentService) work(ctx context.Context) {
+	for ctx.Err() == nil {
+		jobs, err := s.jobs.Claim(1, 2*s.config.JobTimeout)
+		if err != nil {
+			log.Errorf("Ошибка получения задач обогащения: %v", err)
+		}
+		if len(jobs) == 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.config.PollInterval):
+			}
+			continue
+		}
+		s.process(ctx, jobs[0])
+	}
+}
+
+// process выполняет одну попытку задачи и записывает её итог: успех, повтор с задержкой
+// или неудачу, если ошибка не исправится повтором или попытки исчерпаны.
+func (s *EnrichmentService) process(ctx context.Context, job models.EnrichmentJob) {
+	jobCtx, cancel := context.WithTimeout(ctx, s.config.JobTimeout)
+	err := s.enrich(jobCtx, job.SongID)
+	cancel()
+
+	switch {
+	case err == nil:
+		err = s.jobs.Complete(job.ID)
+	case ctx.Err() != nil:
+		// Сервер останавливается: задача вернётся в очередь без задержки
+		log.Infof("Задача обогащения %d прервана и возвращена в очередь", job.ID)
+		err = s.jobs.Retry(job.ID, 0, err.Error())
+	case permanentEnrichError(err) || job.Attempts >= s.config.MaxAttempts:
+		log.Warnf("Задача обогащения %d песни %d не выполнена после %d попыток: %v", job.ID, job.SongID, job.Attempts, err)
+		err = s.jobs.Fail(job.ID, err.Error())
+	default:
+		delay := s.retryDelay(job.Attempts)
+		log.Warnf("Попытка %d задачи обогащения %d не удалась, повтор через %s: %v", job.Attempts, job.ID, delay.Round(time.Second), err)
+		err = s.jobs.Retry(job.ID, delay, err.Error())
+	}
+	// Песню могли удалить вместе с задачей, пока та выполнялась
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Errorf("Ошибка сохранения итога задачи обогащения %d: %v", job.ID, err)
+	}
+}
+
+// enrich получает сведения о песне и переносит в неё дату выпуска, текст и ссылку
+// по политикам слияния полей.
+func (s *EnrichmentService) enrich(ctx context.Context, songID int) error {
+	song, err := s.songs.Get(songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	if err != nil {
+		return err
+	}
+
+	details, err := s.metadata.Lookup(ctx, song.GroupName, song.SongName)
+	if err != nil {
+		return fmt.Errorf("ошибка получения сведений о песне: %w", err)
+	}
+	log.Debugf("Сведения о песне %s - %s получены из %s, уверенность %.2f", song.GroupName, song.SongName, details.Source, details.Confidence)
+
+	releaseDate, err := parseReleaseDate(details.ReleaseDate)
+	if err != nil {
+		return fmt.Errorf("%w: источник %s: %v", ErrInvalidInput, details.Source, err)
+	}
+	update := models.SongUpdate{Author: "enrichment/" + details.Source, Enrichment: true}
+	if releaseDate != nil && (song.ReleaseDate == nil || !song.ReleaseDate.Equal(*releaseDate)) &&
+		s.merge(song, models.SongFieldReleaseDate, song.ReleaseDate == nil) {
+		update.ReleaseDate = releaseDate
+	}
+	if details.Text != "" && details.Text != song.Text && s.merge(song, models.SongFieldText, song.Text == "") {
+		update.Text = &details.Text
+	}
+	if details.Link != "" && details.Link != song.Link && s.merge(song, models.SongFieldLink, song.Link == "") {
+		update.Link = &details.Link
+	}
+	if len(update.ChangedFields()) == 0 {
+		return nil
+	}
+	if err := s.songs.Update(songID, update); err != nil {
+		return fmt.Errorf("ошибка обновления песни: %w", err)
+	}
+	log.Infof("Песня с ID %d обновлена сведениями из %s: %v", songID, details.Source, update.ChangedFields())
+	return nil
+}
+
+// merge решает по политике поля, заменить ли его значение новым от источника.
+// Пустое поле заполняется всегда, кроме поля, которое при keep-manual очистили вручную.
+func (s *EnrichmentService) merge(song models.Song, field string, blank bool) bool {
+	policy := s.config.Policies[field]
+	if policy == models.MergeKeepManual && song.IsManual(field) {
+		return false
+	}
+	if blank {
+		return true
+	}
+	switch policy {
+	case models.MergeOverwrite, models.MergeKeepManual:
+		return true
+	default:
+		return false
+	}
+}
+
+// permanentEnrichError сообщает, что повтор попытки не поможет.
+func permanentEnrichError(err error) bool {
+	return errors.Is(err, metadata.ErrNotFound) ||
+		errors.Is(err, ErrSongNotFound) ||
+		errors.Is(err, ErrInvalidInput)
+}
+
+// retryDelay возвращает задержку перед повтором после попытки attempt: RetryDelay,
+// удваиваемая с каждой попыткой, не больше maxEnrichRetryDelay, со случайной добавкой
+// до половины, чтобы задачи, упавшие вместе, не повторялись тоже вместе.
+func (s *EnrichmentService) retryDelay(attempt int) time.Duration {
+	delay := s.config.RetryDelay
+	for i := 1; i < attempt && delay < maxEnrichRetryDelay; i++ {
+		delay *= 2
+	}
+	delay = min(delay, maxEnrichRetryDelay)
+	return delay + time.Duration(rand.Int64N(int64(delay/2)+1))
+}
+
+// EnqueueSong ставит песню в очередь обогащения или перезапускает её задачу.
+func (s *EnrichmentService) EnqueueSong(songID int) (models.EnrichmentJob, error) {
+	job, err := s.jobs.Enqueue(songID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return models.EnrichmentJob{}, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	case errors.Is(err, repository.ErrConflict):
+		return models.EnrichmentJob{}, fmt.Errorf("%w: песня %d", ErrJobRunning, songID)
+	case err != nil:
+		log.Errorf("Ошибка постановки песни %d в очередь обогащения: %v", songID, err)
+		return models.EnrichmentJob{}, err
+	}
+	log.Infof("Песня с ID %d поставлена в очередь обогащения, задача %d", songID, job.ID)
+	return job, nil
+}
+
+func (s *EnrichmentService) GetJob(id int) (models.EnrichmentJob, error) {
+	job, err := s.jobs.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.EnrichmentJob{}, fmt.Errorf("%w: id %d", ErrJobNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения задачи обогащения с ID %d: %v", id, err)
+		return models.EnrichmentJob{}, err
+	}
+	return job, nil
+}
+
+// GetJobs возвращает задачи обогащения, начиная с недавно изменённых.
+func (s *EnrichmentService) GetJobs(filter models.EnrichmentJobFilter, page, limit int) ([]models.EnrichmentJob, error) {
+	if filter.Status != "" && !models.ValidJobStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: неизвестное состояние задачи %q", ErrInvalidInput, filter.Status)
+	}
+	jobs, err := s.jobs.List(filter, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения задач обогащения: %v", err)
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// RequeueJob перезапускает задачу: сбрасывает попытки и ошибку и ставит её в очередь.
+func (s *EnrichmentService) RequeueJob(id int) (models.EnrichmentJob, error) {
+	job, err := s.GetJob(id)
+	if err != nil {
+		return models.EnrichmentJob{}, err
+	}
+	if job.Status == models.JobRunning {
+		return models.EnrichmentJob{}, fmt.Errorf("%w: id %d", ErrJobRunning, id)
+	}
+	return s.EnqueueSong(job.SongID)
+}
+
+// RequeueFailed перезапускает все неудачные задачи и возвращает их число.
+func (s *EnrichmentService) RequeueFailed() (int, error) {
+	n, err := s.jobs.RequeueFailed()
+	if err != nil {
+		log.Errorf("Ошибка перезапуска задач обогащения: %v", err)
+		return 0, err
+	}
+	log.Infof("Перезапущено неудачных задач обогащения: %d", n)
+	return n, nil
+}
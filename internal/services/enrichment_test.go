@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/metadata"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/memory"
+)
+
+// stubProvider отвечает заданными сведениями или ошибкой.
+type stubProvider struct {
+	name    string
+	details metadata.Details
+	err     error
+}
+
+func (p stubProvider) Name() string {
+	return p.name
+}
+
+func (p stubProvider) Lookup(ctx context.Context, group, song string) (metadata.Details, error) {
+	return p.details, p.err
+}
+
+// enrichmentFixture — EnrichmentService на хранилище в памяти с одной песней в очереди.
+type enrichmentFixture struct {
+	service *EnrichmentService
+	jobs    *memory.EnrichmentRepository
+	songs   *memory.SongRepository
+	songID  int
+}
+
+func newEnrichmentFixture(t *testing.T, song models.Song, config EnrichmentConfig, providers ...metadata.MetadataProvider) enrichmentFixture {
+	t.Helper()
+	store := memory.NewStore()
+	groupID, err := memory.NewGroupRepository(store).Create(models.Group{Name: "Muse"})
+	if err != nil {
+		t.Fatalf("Create group: %v", err)
+	}
+	f := enrichmentFixture{
+		jobs:  memory.NewEnrichmentRepository(store),
+		songs: memory.NewSongRepository(store),
+	}
+	song.GroupID = groupID
+	if song.SongName == "" {
+		song.SongName = "Uprising"
+	}
+	if f.songID, err = f.songs.Create(song); err != nil {
+		t.Fatalf("Create song: %v", err)
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 3
+	}
+	if config.RetryDelay == 0 {
+		config.RetryDelay = time.Minute
+	}
+	if config.JobTimeout == 0 {
+		config.JobTimeout = time.Second
+	}
+	f.service = NewEnrichmentService(f.jobs, f.songs, metadata.NewChain(providers...), config)
+	return f
+}
+
+// runOnce забирает задачу песни и выполняет одну попытку.
+func (f enrichmentFixture) runOnce(t *testing.T) models.EnrichmentJob {
+	t.Helper()
+	if _, err := f.jobs.Enqueue(f.songID); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	claimed, err := f.jobs.Claim(1, time.Minute)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("Claim = %v, %v", claimed, err)
+	}
+	f.service.process(context.Background(), claimed[0])
+	job, err := f.jobs.Get(claimed[0].ID)
+	if err != nil {
+		t.Fatalf("Get job: %v", err)
+	}
+	return job
+}
+
+func TestProcessOutcome(t *testing.T) {
+	broken := stubProvider{name: "api", err: errors.New("503 Service Unavailable")}
+	notFound := stubProvider{name: "api", err: metadata.ErrNotFound}
+	fixtures := stubProvider{name: "fixtures", details: metadata.Details{Text: "Куплет", Confidence: 1}}
+
+	tests := []struct {
+		name       string
+		providers  []metadata.MetadataProvider
+		wantJob    models.JobStatus
+		wantSong   models.EnrichmentStatus
+		wantError  string
+		wantText   string
+		wantFuture bool
+	}{
+		{
+			name:      "сведения найдены",
+			providers: []metadata.MetadataProvider{fixtures},
+			wantJob:   models.JobDone,
+			wantSong:  models.EnrichmentDone,
+			wantText:  "Куплет",
+		},
+		{
+			name:      "песня неизвестна, manual",
+			providers: []metadata.MetadataProvider{notFound, metadata.NewManualProvider()},
+			wantJob:   models.JobDone,
+			wantSong:  models.EnrichmentDone,
+		},
+		{
+			name:      "песня неизвестна без manual",
+			providers: []metadata.MetadataProvider{notFound},
+			wantJob:   models.JobFailed,
+			wantSong:  models.EnrichmentFailed,
+			wantError: "не найдены",
+		},
+		{
+			name:       "временный сбой API перед manual повторяется",
+			providers:  []metadata.MetadataProvider{broken, metadata.NewManualProvider()},
+			wantJob:    models.JobQueued,
+			wantSong:   models.EnrichmentPending,
+			wantError:  "503",
+			wantFuture: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEnrichmentFixture(t, models.Song{}, EnrichmentConfig{}, tt.providers...)
+			job := f.runOnce(t)
+			if job.Status != tt.wantJob {
+				t.Errorf("job.Status = %q, want %q", job.Status, tt.wantJob)
+			}
+			if !strings.Contains(job.LastError, tt.wantError) || (tt.wantError == "") != (job.LastError == "") {
+				t.Errorf("job.LastError = %q, want %q", job.LastError, tt.wantError)
+			}
+			if tt.wantFuture && !job.RunAt.After(time.Now()) {
+				t.Errorf("job.RunAt = %v, want повтор с задержкой", job.RunAt)
+			}
+			song, err := f.songs.Get(f.songID)
+			if err != nil {
+				t.Fatalf("Get song: %v", err)
+			}
+			if song.EnrichmentStatus != tt.wantSong {
+				t.Errorf("song.EnrichmentStatus = %q, want %q", song.EnrichmentStatus, tt.wantSong)
+			}
+			if song.Text != tt.wantText {
+				t.Errorf("song.Text = %q, want %q", song.Text, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestProcessFailsAfterMaxAttempts(t *testing.T) {
+	broken := stubProvider{name: "api", err: errors.New("timeout")}
+	f := newEnrichmentFixture(t, models.Song{}, EnrichmentConfig{MaxAttempts: 1}, broken)
+	job := f.runOnce(t)
+	if job.Status != models.JobFailed || job.LastError == "" {
+		t.Errorf("после последней попытки задача = %+v, want failed с ошибкой", job)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	s := &EnrichmentService{config: EnrichmentConfig{RetryDelay: time.Second}}
+	tests := []struct {
+		attempt int
+		base    time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{30, maxEnrichRetryDelay},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 20; i++ {
+			got := s.retryDelay(tt.attempt)
+			if got < tt.base || got > tt.base+tt.base/2 {
+				t.Fatalf("retryDelay(%d) = %v, want от %v до %v", tt.attempt, got, tt.base, tt.base+tt.base/2)
+			}
+		}
+	}
+}
+
+// blockingProvider отвечает только после отмены ctx.
+type blockingProvider struct{}
+
+func (blockingProvider) Name() string {
+	return "api"
+}
+
+func (blockingProvider) Lookup(ctx context.Context, group, song string) (metadata.Details, error) {
+	<-ctx.Done()
+	return metadata.Details{}, ctx.Err()
+}
+
+func TestRunRequeuesInterruptedJob(t *testing.T) {
+	f := newEnrichmentFixture(t, models.Song{EnrichmentStatus: models.EnrichmentPending},
+		EnrichmentConfig{Workers: 1, PollInterval: time.Millisecond, JobTimeout: time.Minute}, blockingProvider{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		f.service.Run(ctx)
+		close(done)
+	}()
+	// Ждём, пока обработчик возьмёт задачу
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		jobs, err := f.jobs.List(models.EnrichmentJobFilter{Status: models.JobRunning}, 1, 0)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(jobs) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("обработчик не взял задачу")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run не завершился после отмены ctx")
+	}
+
+	jobs, err := f.jobs.List(models.EnrichmentJobFilter{SongID: f.songID}, 1, 0)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("List = %v, %v", jobs, err)
+	}
+	if job := jobs[0]; job.Status != models.JobQueued || job.RunAt.After(time.Now()) {
+		t.Errorf("прерванная задача = %+v, want queued без задержки", job)
+	}
+}
+
+func TestEnrichMergePolicies(t *testing.T) {
+	fixtures := stubProvider{name: "fixtures", details: metadata.Details{Text: "Из источника", Confidence: 1}}
+	tests := []struct {
+		name   string
+		policy models.MergePolicy
+		// update применяется к пустой песне перед обогащением
+		update models.SongUpdate
+		want   string
+	}{
+		{"без политики пустое поле заполняется", "", models.SongUpdate{}, "Из источника"},
+		{"без политики заполненное не меняется", "", models.SongUpdate{Text: ptr("Вручную")}, "Вручную"},
+		{"keep-manual: ручное поле не меняется", models.MergeKeepManual, models.SongUpdate{Text: ptr("Вручную")}, "Вручную"},
+		{"keep-manual: очищенное вручную не заполняется", models.MergeKeepManual, models.SongUpdate{Text: ptr("")}, ""},
+		{"keep-manual: поле источника обновляется", models.MergeKeepManual, models.SongUpdate{Text: ptr("Старое"), Enrichment: true}, "Из источника"},
+		{"keep-manual: пустое поле заполняется", models.MergeKeepManual, models.SongUpdate{}, "Из источника"},
+		{"fill-blank: заполненное не меняется", models.MergeFillBlank, models.SongUpdate{Text: ptr("Старое"), Enrichment: true}, "Старое"},
+		{"fill-blank: очищенное вручную заполняется", models.MergeFillBlank, models.SongUpdate{Text: ptr("")}, "Из источника"},
+		{"overwrite: ручное поле перезаписывается", models.MergeOverwrite, models.SongUpdate{Text: ptr("Вручную")}, "Из источника"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := EnrichmentConfig{Policies: map[string]models.MergePolicy{models.SongFieldText: tt.policy}}
+			f := newEnrichmentFixture(t, models.Song{}, config, fixtures)
+			if err := f.songs.Update(f.songID, tt.update); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+			if job := f.runOnce(t); job.Status != models.JobDone {
+				t.Fatalf("job = %+v, want done", job)
+			}
+			song, err := f.songs.Get(f.songID)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if song.Text != tt.want {
+				t.Errorf("Text = %q, want %q", song.Text, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+)
+
+var (
+	// ErrGroupExists возвращается при попытке создать группу с занятым названием.
+	ErrGroupExists = errors.New("группа с таким названием уже существует")
+	// ErrGroupNotEmpty возвращается при удалении группы с песнями без каскада.
+	ErrGroupNotEmpty = errors.New("у группы есть песни или альбомы")
+)
+
+type GroupService struct {
+	groups repository.GroupRepository
+	// blobs хранит обложки групп и файлы песен; они удаляются вместе с группой.
+	blobs storage.BlobStorage
+}
+
+func NewGroupService(groups repository.GroupRepository, blobs storage.BlobStorage) *GroupService {
+	return &GroupService{groups: groups, blobs: blobs}
+}
+
+// groupBlobPrefix — префикс ключей всех файлов группы в хранилище.
+func groupBlobPrefix(groupID int) string {
+	return fmt.Sprintf("groups/%d", groupID)
+}
+
+// GetGroups возвращает группы с фильтрацией по названию и стране.
+func (s *GroupService) GetGroups(filter models.GroupFilter, page, limit int) ([]models.Group, error) {
+	groups, err := s.groups.List(filter, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения групп: %v", err)
+		return nil, err
+	}
+	log.Infof("Найдено %d групп", len(groups))
+	return groups, nil
+}
+
+// GetGroup возвращает группу вместе с числом её песен.
+func (s *GroupService) GetGroup(id int) (models.Group, error) {
+	group, err := s.groups.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Group{}, fmt.Errorf("%w: id %d", ErrGroupNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения группы с ID %d: %v", id, err)
+		return models.Group{}, err
+	}
+	return group, nil
+}
+
+// CreateGroup добавляет группу и возвращает её с заполненным ID.
+func (s *GroupService) CreateGroup(group models.Group) (models.Group, error) {
+	group.Name = strings.TrimSpace(group.Name)
+	if group.Name == "" {
+		return models.Group{}, fmt.Errorf("%w: название группы не может быть пустым", ErrInvalidInput)
+	}
+	if err := validateFormedYear(group.FormedYear); err != nil {
+		return models.Group{}, err
+	}
+
+	id, err := s.groups.Create(group)
+	if errors.Is(err, repository.ErrConflict) {
+		return models.Group{}, fmt.Errorf("%w: %q", ErrGroupExists, group.Name)
+	}
+	if err != nil {
+		log.Errorf("Ошибка добавления группы: %v", err)
+		return models.Group{}, err
+	}
+	log.Infof("Группа %q добавлена с ID %d", group.Name, id)
+	return s.GetGroup(id)
+}
+
+func (s *GroupService) UpdateGroup(id int, update models.GroupUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return fmt.Errorf("%w: название группы не может быть пустым", ErrInvalidInput)
+		}
+		update.Name = &name
+	}
+	if err := validateFormedYear(update.FormedYear); err != nil {
+		return err
+	}
+
+	err := s.groups.Update(id, update)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return fmt.Errorf("%w: id %d", ErrGroupNotFound, id)
+	case errors.Is(err, repository.ErrConflict):
+		return fmt.Errorf("%w: %q", ErrGroupExists, *update.Name)
+	case err != nil:
+		log.Errorf("Ошибка обновления группы с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Группа с ID %d успешно обновлена", id)
+	return nil
+}
+
+// DeleteGroup удаляет группу. С cascade вместе с ней удаляются её песни и альбомы,
+// без него удаление группы, у которой они есть, отклоняется.
+func (s *GroupService) DeleteGroup(id int, cascade bool) error {
+	// ID песен нужны заранее: после каскадного удаления их файлы уже не найти
+	var songIDs []int
+	if cascade {
+		var err error
+		if songIDs, err = s.groups.SongIDs(id); err != nil {
+			log.Errorf("Ошибка получения песен группы с ID %d: %v", id, err)
+			return err
+		}
+	}
+
+	err := s.groups.Delete(id, cascade)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return fmt.Errorf("%w: id %d", ErrGroupNotFound, id)
+	case errors.Is(err, repository.ErrConflict):
+		return fmt.Errorf("%w: id %d", ErrGroupNotEmpty, id)
+	case err != nil:
+		log.Errorf("Ошибка удаления группы с ID %d: %v", id, err)
+		return err
+	}
+	s.deleteBlobs(id, songIDs)
+	log.Infof("Группа с ID %d успешно удалена (каскадно: %t)", id, cascade)
+	return nil
+}
+
+// MergeGroups сливает группу-дубликат sourceID в targetID: песни и альбомы переходят
+// к targetID, название источника сохраняется её псевдонимом. Возвращает итоговую группу.
+func (s *GroupService) MergeGroups(sourceID, targetID int) (models.Group, error) {
+	if targetID <= 0 {
+		return models.Group{}, fmt.Errorf("%w: не указана группа, в которую выполняется слияние", ErrInvalidInput)
+	}
+	if sourceID == targetID {
+		return models.Group{}, fmt.Errorf("%w: нельзя слить группу саму с собой", ErrInvalidInput)
+	}
+
+	err := s.groups.Merge(sourceID, targetID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Group{}, fmt.Errorf("%w: id %d или %d", ErrGroupNotFound, sourceID, targetID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка слияния группы %d в %d: %v", sourceID, targetID, err)
+		return models.Group{}, err
+	}
+	// Обложка источника удалена вместе с ним, песни с их файлами перешли к targetID
+	s.deleteBlobs(sourceID, nil)
+	log.Infof("Группа с ID %d слита в группу с ID %d", sourceID, targetID)
+	return s.GetGroup(targetID)
+}
+
+// BackfillNormalizedNames заполняет нормализованные названия групп и псевдонимов,
+// добавленных до появления нормализации. Вызывается при запуске приложения.
+func (s *GroupService) BackfillNormalizedNames() error {
+	updated, err := s.groups.BackfillNormalized()
+	if err != nil {
+		log.Errorf("Ошибка заполнения нормализованных названий групп: %v", err)
+		return err
+	}
+	if updated > 0 {
+		log.Infof("Заполнены нормализованные названия для %d групп и псевдонимов", updated)
+	}
+	return nil
+}
+
+// deleteBlobs удаляет из хранилища файлы удалённой группы и её песен.
+// Ошибки только логируются: записи о файлах уже удалены.
+func (s *GroupService) deleteBlobs(groupID int, songIDs []int) {
+	if err := s.blobs.DeletePrefix(groupBlobPrefix(groupID)); err != nil {
+		log.Warnf("Не удалось удалить файлы группы %d из хранилища: %v", groupID, err)
+	}
+	for _, songID := range songIDs {
+		if err := s.blobs.DeletePrefix(songBlobPrefix(songID)); err != nil {
+			log.Warnf("Не удалось удалить файлы песни %d из хранилища: %v", songID, err)
+		}
+	}
+}
+
+func validateFormedYear(year *int) error {
+	if year != nil && (*year < 1000 || *year > 9999) {
+		return fmt.Errorf("%w: некорректный год основания %d", ErrInvalidInput, *year)
+	}
+	return nil
+}
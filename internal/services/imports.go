@@ -0,0 +1,235 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/normalize"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+const (
+	// DefaultImportBatchSize — число песен, сохраняемых в одной транзакции, по умолчанию.
+	DefaultImportBatchSize = 100
+	// maxImportBatchSize ограничивает размер пакета, чтобы транзакция не держала блокировки слишком долго.
+	maxImportBatchSize = 1000
+)
+
+// ImportOptions — параметры массового импорта.
+type ImportOptions struct {
+	// CreateGroups разрешает создавать группы, которых нет в библиотеке.
+	CreateGroups bool
+	// Enrich ставит песни с пустыми датой выпуска, текстом или ссылкой в очередь обогащения.
+	Enrich bool
+	// BatchSize — число песен в одной транзакции; 0 — DefaultImportBatchSize.
+	BatchSize int
+}
+
+// ImportSongs добавляет песни из разобранного файла пакетами по opts.BatchSize,
+// каждый пакет — в своей транзакции. Песни, которые уже есть у группы, пропускаются.
+// Ошибка возвращается только при некорректных параметрах, итог каждой записи — в отчёте.
+func (s *SongService) ImportSongs(rows []models.ImportRow, opts ImportOptions) (models.ImportReport, error) {
+	if opts.BatchSize == 0 {
+		opts.BatchSize = DefaultImportBatchSize
+	}
+	if opts.BatchSize < 0 || opts.BatchSize > maxImportBatchSize {
+		return models.ImportReport{}, fmt.Errorf("%w: размер пакета должен быть от 1 до %d", ErrInvalidInput, maxImportBatchSize)
+	}
+
+	imp := &songImport{service: s, opts: opts, groups: map[string]groupLookup{}}
+	report := models.ImportReport{Rows: make([]models.ImportResult, 0, len(rows))}
+	for start := 0; start < len(rows); start += opts.BatchSize {
+		for _, result := range imp.batch(rows[start:min(start+opts.BatchSize, len(rows))]) {
+			report.Add(result)
+		}
+	}
+	log.Infof("Импорт завершён: добавлено %d, пропущено %d, с ошибками %d", report.Created, report.Skipped, report.Failed)
+	return report, nil
+}
+
+// groupLookup — результат поиска группы, запомненный на время импорта.
+type groupLookup struct {
+	id  int
+	err error
+}
+
+// songImport хранит состояние одного импорта: найденные группы переиспользуются
+// всеми записями файла, чтобы не искать одну группу тысячи раз.
+type songImport struct {
+	service *SongService
+	opts    ImportOptions
+	groups  map[string]groupLookup
+}
+
+// batch подготавливает записи пакета и сохраняет подготовленные одной транзакцией.
+func (imp *songImport) batch(rows []models.ImportRow) []models.ImportResult {
+	results := make([]models.ImportResult, len(rows))
+	songs := make([]models.Song, 0, len(rows))
+	pending := make([]int, 0, len(rows))
+	for i, row := range rows {
+		results[i] = models.ImportResult{Row: row.Row, Group: row.Group, Song: row.Song}
+		song, err := imp.prepare(row)
+		if err != nil {
+			results[i].Status = models.ImportFailed
+			results[i].Reason = err.Error()
+			continue
+		}
+		songs = append(songs, song)
+		pending = append(pending, i)
+	}
+	if len(songs) == 0 {
+		return results
+	}
+
+	saved, err := imp.service.songs.CreateBatch(songs)
+	if err != nil {
+		log.Errorf("Ошибка сохранения пакета импорта: %v", err)
+		for _, i := range pending {
+			results[i].Status = models.ImportFailed
+			results[i].Reason = err.Error()
+		}
+		return results
+	}
+	for j, i := range pending {
+		switch res := saved[j]; {
+		case res.Err != nil:
+			results[i].Status = models.ImportFailed
+			results[i].Reason = res.Err.Error()
+		case res.Duplicate:
+			results[i].Status = models.ImportSkipped
+			results[i].SongID = res.ID
+			results[i].Reason = "у группы уже есть песня с таким названием"
+		default:
+			results[i].Status = models.ImportCreated
+			results[i].SongID = res.ID
+		}
+	}
+	return results
+}
+
+// prepare проверяет запись, находит или создаёт её группу и при необходимости
+// отмечает песню для очереди обогащения.
+func (imp *songImport) prepare(row models.ImportRow) (models.Song, error) {
+	if row.Err != "" {
+		return models.Song{}, errors.New(row.Err)
+	}
+	if row.Group == "" || row.Song == "" {
+		return models.Song{}, fmt.Errorf("%w: не указаны группа или название песни", ErrInvalidInput)
+	}
+
+	lookup, ok := imp.groups[row.Group]
+	if !ok {
+		lookup.id, lookup.err = imp.service.resolveGroup(row.Group, imp.opts.CreateGroups)
+		imp.groups[row.Group] = lookup
+	}
+	if lookup.err != nil {
+		return models.Song{}, lookup.err
+	}
+
+	releaseDate, err := parseReleaseDate(row.ReleaseDate)
+	if err != nil {
+		return models.Song{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	genres, err := normalizeTags(row.Genres)
+	if err != nil {
+		return models.Song{}, err
+	}
+	tags, err := normalizeTags(row.Tags)
+	if err != nil {
+		return models.Song{}, err
+	}
+	song := models.Song{
+		GroupID:     lookup.id,
+		SongName:    row.Song,
+		ReleaseDate: releaseDate,
+		Text:        row.Text,
+		Link:        row.Link,
+		Genres:      genres,
+		Tags:        tags,
+	}
+	if imp.opts.Enrich && (row.ReleaseDate == "" || row.Text == "" || row.Link == "") {
+		song.EnrichmentStatus = models.EnrichmentPending
+	}
+	return song, nil
+}
+
+// PlaylistImportOptions — параметры импорта плейлиста.
+type PlaylistImportOptions struct {
+	// CreateGroups разрешает создавать группы, которых нет в библиотеке.
+	CreateGroups bool
+	// DryRun только показывает, что будет добавлено: ничего не сохраняется
+	// и в очередь обогащения не ставится.
+	DryRun bool
+}
+
+// ImportPlaylist добавляет песни плейлиста по одной тем же путём, что и AddSongWithAPI:
+// с поиском группы по нормализованному названию и постановкой в очередь обогащения.
+// Песни, которые уже есть у группы или повторяются в плейлисте, пропускаются.
+func (s *SongService) ImportPlaylist(rows []models.ImportRow, opts PlaylistImportOptions) models.ImportReport {
+	report := models.ImportReport{DryRun: opts.DryRun, Rows: make([]models.ImportResult, 0, len(rows))}
+	// seen — уже встреченные песни плейлиста, newGroups — группы, которые создаст импорт
+	seen := map[string]int{}
+	newGroups := map[string]bool{}
+	for _, row := range rows {
+		result := models.ImportResult{Row: row.Row, Group: row.Group, Song: row.Song}
+		if row.Err != "" {
+			result.Status, result.Reason = models.ImportFailed, row.Err
+			report.Add(result)
+			continue
+		}
+
+		key := normalize.Name(row.Group) + "\x00" + strings.ToLower(row.Song)
+		if first, ok := seen[key]; ok {
+			result.Status, result.Reason = models.ImportSkipped, fmt.Sprintf("повторяет запись %d", first)
+			report.Add(result)
+			continue
+		}
+		seen[key] = row.Row
+
+		groupID, err := s.groups.FindByName(row.Group)
+		switch {
+		case errors.Is(err, repository.ErrNotFound) && !opts.CreateGroups:
+			err = s.unknownGroupError(row.Group)
+		case errors.Is(err, repository.ErrNotFound):
+			groupKey := normalize.Name(row.Group)
+			result.NewGroup = !newGroups[groupKey]
+			newGroups[groupKey] = true
+			err = nil
+		case err == nil:
+			songID, findErr := s.songs.FindByName(groupID, row.Song)
+			if findErr == nil {
+				result.Status, result.SongID = models.ImportSkipped, songID
+				result.Reason = "у группы уже есть песня с таким названием"
+				report.Add(result)
+				continue
+			}
+			if !errors.Is(findErr, repository.ErrNotFound) {
+				err = findErr
+			}
+		}
+		if err != nil {
+			result.Status, result.Reason = models.ImportFailed, err.Error()
+			report.Add(result)
+			continue
+		}
+
+		if opts.DryRun {
+			result.Status = models.ImportCreated
+			report.Add(result)
+			continue
+		}
+		result.SongID, err = s.AddSongWithAPI(row.Group, row.Song, AddSongOptions{CreateGroup: opts.CreateGroups})
+		if err != nil {
+			result.Status, result.Reason, result.NewGroup = models.ImportFailed, err.Error(), false
+			report.Add(result)
+			continue
+		}
+		result.Status = models.ImportCreated
+		report.Add(result)
+	}
+	log.Infof("Импорт плейлиста завершён (пробный: %t): добавлено %d, пропущено %d, с ошибками %d",
+		opts.DryRun, report.Created, report.Skipped, report.Failed)
+	return report
+}
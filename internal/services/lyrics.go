@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/lrc"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// ErrLyricsNotFound возвращается, если у песни нет синхронизированного текста.
+var ErrLyricsNotFound = errors.New("синхронизированный текст не найден")
+
+// LyricsService хранит синхронизированные тексты песен в формате LRC.
+type LyricsService struct {
+	lyrics repository.LyricsRepository
+	songs  repository.SongRepository
+}
+
+func NewLyricsService(lyrics repository.LyricsRepository, songs repository.SongRepository) *LyricsService {
+	return &LyricsService{lyrics: lyrics, songs: songs}
+}
+
+// SaveLyrics проверяет текст LRC и сохраняет его для песни, заменяя прежний.
+// Текст хранится как загружен, чтобы его можно было отдать без потерь.
+func (s *LyricsService) SaveLyrics(songID int, text string) (models.SongLyrics, lrc.Lyrics, error) {
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	parsed, err := lrc.Parse(text)
+	if err != nil {
+		return models.SongLyrics{}, lrc.Lyrics{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	err = s.lyrics.Save(models.SongLyrics{SongID: songID, LRC: text, LineCount: len(parsed.Lines)})
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.SongLyrics{}, lrc.Lyrics{}, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка сохранения текста LRC песни %d: %v", songID, err)
+		return models.SongLyrics{}, lrc.Lyrics{}, err
+	}
+	log.Infof("Синхронизированный текст песни %d сохранён, строк: %d", songID, len(parsed.Lines))
+
+	saved, err := s.lyrics.Get(songID)
+	if err != nil {
+		log.Errorf("Ошибка получения текста LRC песни %d: %v", songID, err)
+		return models.SongLyrics{}, lrc.Lyrics{}, err
+	}
+	return saved, parsed, nil
+}
+
+// GetLyrics возвращает синхронизированный текст песни вместе с разобранными строками.
+func (s *LyricsService) GetLyrics(songID int) (models.SongLyrics, lrc.Lyrics, error) {
+	lyrics, err := s.lyrics.Get(songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.SongLyrics{}, lrc.Lyrics{}, s.missingLyrics(songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения текста LRC песни %d: %v", songID, err)
+		return models.SongLyrics{}, lrc.Lyrics{}, err
+	}
+	parsed, err := lrc.Parse(lyrics.LRC)
+	if err != nil {
+		// Текст проверяется при сохранении, так что сюда попадает только испорченная запись
+		log.Errorf("Сохранённый текст LRC песни %d не разбирается: %v", songID, err)
+		return models.SongLyrics{}, lrc.Lyrics{}, fmt.Errorf("ошибка разбора текста LRC: %w", err)
+	}
+	return lyrics, parsed, nil
+}
+
+// DeleteLyrics удаляет синхронизированный текст песни; простой текст песни остаётся.
+func (s *LyricsService) DeleteLyrics(songID int) error {
+	err := s.lyrics.Delete(songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return s.missingLyrics(songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка удаления текста LRC песни %d: %v", songID, err)
+		return err
+	}
+	log.Infof("Синхронизированный текст песни %d удалён", songID)
+	return nil
+}
+
+// missingLyrics различает отсутствие песни и отсутствие у неё синхронизированного текста.
+func (s *LyricsService) missingLyrics(songID int) error {
+	if _, err := s.songs.Get(songID); errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	return fmt.Errorf("%w: у песни %d", ErrLyricsNotFound, songID)
+}
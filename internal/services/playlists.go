@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+var (
+	// ErrPlaylistNotFound возвращается, если плейлиста с указанным ID нет.
+	ErrPlaylistNotFound = errors.New("плейлист не найден")
+	// ErrPlaylistItemNotFound возвращается, если в плейлисте нет элемента с указанным ID.
+	ErrPlaylistItemNotFound = errors.New("элемент плейлиста не найден")
+)
+
+type PlaylistService struct {
+	playlists repository.PlaylistRepository
+	songs     repository.SongRepository
+}
+
+func NewPlaylistService(playlists repository.PlaylistRepository, songs repository.SongRepository) *PlaylistService {
+	return &PlaylistService{playlists: playlists, songs: songs}
+}
+
+func (s *PlaylistService) GetPlaylists(page, limit int) ([]models.Playlist, error) {
+	playlists, err := s.playlists.List(limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения плейлистов: %v", err)
+		return nil, err
+	}
+	log.Infof("Найдено %d плейлистов", len(playlists))
+	return playlists, nil
+}
+
+func (s *PlaylistService) GetPlaylist(id int) (models.Playlist, error) {
+	playlist, err := s.playlists.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Playlist{}, fmt.Errorf("%w: id %d", ErrPlaylistNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения плейлиста с ID %d: %v", id, err)
+		return models.Playlist{}, err
+	}
+	return playlist, nil
+}
+
+// CreatePlaylist добавляет пустой плейлист и возвращает его с заполненным ID.
+func (s *PlaylistService) CreatePlaylist(playlist models.Playlist) (models.Playlist, error) {
+	playlist.Name = strings.TrimSpace(playlist.Name)
+	if playlist.Name == "" {
+		return models.Playlist{}, fmt.Errorf("%w: название плейлиста не может быть пустым", ErrInvalidInput)
+	}
+
+	id, err := s.playlists.Create(playlist)
+	if err != nil {
+		log.Errorf("Ошибка добавления плейлиста: %v", err)
+		return models.Playlist{}, err
+	}
+	log.Infof("Плейлист %q добавлен с ID %d", playlist.Name, id)
+	return s.GetPlaylist(id)
+}
+
+func (s *PlaylistService) UpdatePlaylist(id int, update models.PlaylistUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return fmt.Errorf("%w: название плейлиста не может быть пустым", ErrInvalidInput)
+		}
+		update.Name = &name
+	}
+
+	err := s.playlists.Update(id, update)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrPlaylistNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка обновления плейлиста с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Плейлист с ID %d успешно обновлён", id)
+	return nil
+}
+
+func (s *PlaylistService) DeletePlaylist(id int) error {
+	err := s.playlists.Delete(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrPlaylistNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка удаления плейлиста с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Плейлист с ID %d успешно удалён", id)
+	return nil
+}
+
+// GetPlaylistItems возвращает песни плейлиста по порядку позиций.
+func (s *PlaylistService) GetPlaylistItems(id, page, limit int) ([]models.PlaylistItem, error) {
+	if _, err := s.GetPlaylist(id); err != nil {
+		return nil, err
+	}
+	items, err := s.playlists.Items(id, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения элементов плейлиста с ID %d: %v", id, err)
+		return nil, err
+	}
+	return items, nil
+}
+
+// ExportPlaylistItems передаёт fn песни плейлиста по порядку позиций прямо из хранилища.
+// Отрицательный limit выгружает весь плейлист.
+func (s *PlaylistService) ExportPlaylistItems(id, page, limit int, fn func(models.PlaylistItem) error) error {
+	if _, err := s.GetPlaylist(id); err != nil {
+		return err
+	}
+	if err := s.playlists.EachItem(id, limit, exportOffset(page, limit), fn); err != nil {
+		log.Errorf("Ошибка выгрузки плейлиста с ID %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// AddPlaylistItem добавляет песню в плейлист на позицию position (0 — в конец)
+// и возвращает ID нового элемента.
+func (s *PlaylistService) AddPlaylistItem(playlistID, songID, position int) (int, error) {
+	if position < 0 {
+		return 0, fmt.Errorf("%w: позиция не может быть отрицательной", ErrInvalidInput)
+	}
+	if _, err := s.GetPlaylist(playlistID); err != nil {
+		return 0, err
+	}
+	if _, err := s.songs.Get(songID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return 0, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+		}
+		log.Errorf("Ошибка получения песни с ID %d: %v", songID, err)
+		return 0, err
+	}
+
+	itemID, err := s.playlists.AddItem(playlistID, songID, position)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Плейлист или песню удалили после проверки
+		return 0, fmt.Errorf("%w: id %d", ErrPlaylistNotFound, playlistID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка добавления песни в плейлист с ID %d: %v", playlistID, err)
+		return 0, err
+	}
+	log.Infof("Песня с ID %d добавлена в плейлист с ID %d", songID, playlistID)
+	return itemID, nil
+}
+
+func (s *PlaylistService) RemovePlaylistItem(playlistID, itemID int) error {
+	if _, err := s.GetPlaylist(playlistID); err != nil {
+		return err
+	}
+	err := s.playlists.RemoveItem(playlistID, itemID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrPlaylistItemNotFound, itemID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка удаления элемента %d из плейлиста с ID %d: %v", itemID, playlistID, err)
+		return err
+	}
+	log.Infof("Элемент %d удалён из плейлиста с ID %d", itemID, playlistID)
+	return nil
+}
+
+// MovePlaylistItem переносит элемент на позицию position; позиции остальных
+// элементов сдвигаются без пропусков.
+func (s *PlaylistService) MovePlaylistItem(playlistID, itemID, position int) error {
+	if position <= 0 {
+		return fmt.Errorf("%w: позиция должна быть положительной", ErrInvalidInput)
+	}
+	if _, err := s.GetPlaylist(playlistID); err != nil {
+		return err
+	}
+	err := s.playlists.MoveItem(playlistID, itemID, position)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrPlaylistItemNotFound, itemID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка перемещения элемента %d в плейлисте с ID %d: %v", itemID, playlistID, err)
+		return err
+	}
+	log.Infof("Элемент %d плейлиста с ID %d перемещён на позицию %d", itemID, playlistID, position)
+	return nil
+}
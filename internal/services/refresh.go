@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"time"
+)
+
+// RefreshConfig — настройки планировщика повторного обогащения.
+type RefreshConfig struct {
+	// Interval — как часто искать песни для повторного обогащения.
+	Interval time.Duration
+	// MaxAge — через сколько после обогащения сведения песни считаются устаревшими.
+	MaxAge time.Duration
+	// BlankAge — через сколько повторять обогащение песни с пустым текстом или ссылкой.
+	BlankAge time.Duration
+	// Batch — сколько песен ставить в очередь за раз; 0 отключает планировщик.
+	// Вместе с Interval ограничивает нагрузку на источники сведений.
+	Batch int
+}
+
+// RunRefresh раз в Refresh.Interval ставит в очередь обогащения песни с устаревшими
+// или неполными сведениями, пока ctx не отменён. Первый проход — сразу после запуска.
+func (s *EnrichmentService) RunRefresh(ctx context.Context) {
+	refresh := s.config.Refresh
+	if refresh.Batch <= 0 {
+		log.Info("Планировщик повторного обогащения отключён")
+		return
+	}
+	log.Infof("Планировщик повторного обогащения запущен: каждые %s до %d песен", refresh.Interval, refresh.Batch)
+	ticker := time.NewTicker(refresh.Interval)
+	defer ticker.Stop()
+	for {
+		// Ошибка уже записана в журнал, следующий проход повторит попытку
+		s.RefreshStale(0)
+		select {
+		case <-ctx.Done():
+			log.Info("Планировщик повторного обогащения остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshStale ставит в очередь до limit песен с устаревшими или неполными сведениями
+// (при limit <= 0 — Refresh.Batch) и возвращает их число.
+func (s *EnrichmentService) RefreshStale(limit int) (int, error) {
+	if limit <= 0 {
+		limit = s.config.Refresh.Batch
+	}
+	n, err := s.jobs.EnqueueStale(s.config.Refresh.MaxAge, s.config.Refresh.BlankAge, limit)
+	if err != nil {
+		log.Errorf("Ошибка постановки устаревших песен в очередь обогащения: %v", err)
+		return 0, err
+	}
+	if n > 0 {
+		log.Infof("Поставлено в очередь повторного обогащения песен: %d", n)
+	}
+	return n, nil
+}
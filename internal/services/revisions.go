@@ -0,0 +1,149 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/textdiff"
+)
+
+// ErrRevisionNotFound возвращается, если у песни нет версии текста с указанным номером.
+var ErrRevisionNotFound = errors.New("версия текста не найдена")
+
+// DefaultDiffContext — сколько общих строк показывать вокруг изменений, как у diff -u.
+const DefaultDiffContext = 3
+
+// GetTextRevisions возвращает страницу версий текста песни, начиная с последней.
+func (s *SongService) GetTextRevisions(songID, page, limit int) ([]models.TextRevision, error) {
+	revisions, err := s.songs.TextRevisions(songID, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения версий текста песни %d: %v", songID, err)
+		return nil, err
+	}
+	// Пустая история бывает и у песни без текста, поэтому только тогда проверяем саму песню
+	if len(revisions) == 0 {
+		if err := s.checkSong(songID); err != nil {
+			return nil, err
+		}
+	}
+	return revisions, nil
+}
+
+// GetTextRevision возвращает версию текста песни по номеру.
+func (s *SongService) GetTextRevision(songID, revision int) (models.TextRevision, error) {
+	result, err := s.songs.TextRevision(songID, revision)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.TextRevision{}, s.missingRevision(songID, revision)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения версии %d текста песни %d: %v", revision, songID, err)
+		return models.TextRevision{}, err
+	}
+	return result, nil
+}
+
+// DiffTextRevisions сравнивает версии текста from и to. Если to не больше нуля, берётся
+// последняя версия; если from меньше нуля — версия перед to. Версия 0 — пустой текст.
+func (s *SongService) DiffTextRevisions(songID, from, to, context int) (models.TextDiff, error) {
+	if to <= 0 {
+		latest, err := s.latestRevision(songID)
+		if err != nil {
+			return models.TextDiff{}, err
+		}
+		to = latest.Revision
+	}
+	if from < 0 {
+		from = to - 1
+	}
+	if context < 0 {
+		context = DefaultDiffContext
+	}
+
+	diff := models.TextDiff{SongID: songID}
+	var err error
+	if diff.From, err = s.revisionOrEmpty(songID, from); err != nil {
+		return models.TextDiff{}, err
+	}
+	if diff.To, err = s.revisionOrEmpty(songID, to); err != nil {
+		return models.TextDiff{}, err
+	}
+	diff.Diff = textdiff.Unified(revisionLabel(diff.From), revisionLabel(diff.To), diff.From.Text, diff.To.Text, context)
+	return diff, nil
+}
+
+// RestoreTextRevision делает текст версии revision текущим текстом песни. Откат тоже
+// попадает в историю новой версией; если текст уже совпадает, новая версия не создаётся.
+// Возвращает версию, которая стала последней.
+func (s *SongService) RestoreTextRevision(songID, revision int, author string) (models.TextRevision, error) {
+	restored, err := s.GetTextRevision(songID, revision)
+	if err != nil {
+		return models.TextRevision{}, err
+	}
+	text := restored.Text
+	if err := s.songs.Update(songID, models.SongUpdate{Text: &text, Author: author}); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return models.TextRevision{}, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+		}
+		log.Errorf("Ошибка восстановления версии %d текста песни %d: %v", revision, songID, err)
+		return models.TextRevision{}, err
+	}
+
+	latest, err := s.latestRevision(songID)
+	if err != nil {
+		return models.TextRevision{}, err
+	}
+	log.Infof("Текст песни %d восстановлен из версии %d, текущая версия: %d", songID, revision, latest.Revision)
+	return latest, nil
+}
+
+// latestRevision возвращает последнюю версию текста песни.
+func (s *SongService) latestRevision(songID int) (models.TextRevision, error) {
+	revisions, err := s.GetTextRevisions(songID, 1, 1)
+	if err != nil {
+		return models.TextRevision{}, err
+	}
+	if len(revisions) == 0 {
+		return models.TextRevision{}, fmt.Errorf("%w: у песни %d нет версий текста", ErrRevisionNotFound, songID)
+	}
+	return revisions[0], nil
+}
+
+// revisionOrEmpty возвращает версию текста, а для номера 0 — пустой текст.
+func (s *SongService) revisionOrEmpty(songID, revision int) (models.TextRevision, error) {
+	if revision == 0 {
+		return models.TextRevision{SongID: songID}, nil
+	}
+	return s.GetTextRevision(songID, revision)
+}
+
+// revisionLabel — заголовок версии в разнице: номер и время сохранения.
+func revisionLabel(revision models.TextRevision) string {
+	if revision.Revision == 0 {
+		return "версия 0"
+	}
+	return fmt.Sprintf("версия %d\t%s", revision.Revision, revision.CreatedAt.Format(time.RFC3339))
+}
+
+// missingRevision различает отсутствие песни и отсутствие у неё версии текста.
+func (s *SongService) missingRevision(songID, revision int) error {
+	if err := s.checkSong(songID); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: версия %d песни %d", ErrRevisionNotFound, revision, songID)
+}
+
+// checkSong проверяет, что песня есть в библиотеке.
+func (s *SongService) checkSong(songID int) error {
+	_, err := s.songs.Get(songID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения песни с ID %d: %v", songID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// ErrInvalidInput возвращается при некорректных параметрах запроса.
+var ErrInvalidInput = errors.New("некорректные входные данные")
+
+// SearchSongs выполняет полнотекстовый поиск по текстам, названиям песен и групп.
+func (s *SongService) SearchSongs(query models.SearchQuery, page, limit int) ([]models.SearchResult, error) {
+	if query.Query == "" {
+		return nil, ErrInvalidInput
+	}
+	offset := (page - 1) * limit
+
+	results, err := s.songs.Search(query, limit, offset)
+	if err != nil {
+		log.Errorf("Ошибка полнотекстового поиска: %v", err)
+		return nil, err
+	}
+	log.Infof("По запросу %q найдено %d песен", query.Query, len(results))
+	return results, nil
+}
@@ -1,175 +1,323 @@
 package services
 
 import (
-	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/EugeneKrivoshein/music_library/config"
-	"github.com/EugeneKrivoshein/music_library/internal/db/conn"
-	"github.com/EugeneKrivoshein/music_library/internal/utils"
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
 	"github.com/sirupsen/logrus"
 )
 
 type SongService struct {
-	dbProvider *conn.PostgresProvider
-	APIURL     string
+	songs  repository.SongRepository
+	groups repository.GroupRepository
+	albums repository.AlbumRepository
+	// blobs хранит файлы песен; они удаляются вместе с песней.
+	blobs storage.BlobStorage
 }
 
-func NewSongService(provider *conn.PostgresProvider, config *config.Config) *SongService {
+func NewSongService(songs repository.SongRepository, groups repository.GroupRepository,
+	albums repository.AlbumRepository, blobs storage.BlobStorage) *SongService {
 	return &SongService{
-		dbProvider: provider,
-		APIURL:     config.APIURL,
+		songs:  songs,
+		groups: groups,
+		albums: albums,
+		blobs:  blobs,
 	}
 }
 
 var log = logrus.New()
 
-func (s *SongService) GetSongs(group, song string, page, limit int) ([]map[string]interface{}, error) {
-	offset := (page - 1) * limit
-	query := `
-		SELECT s.id, g.group_name, s.song_name, s.release_date
-		FROM songs s
-		JOIN groups g ON s.group_id = g.id
-		WHERE ($1 = '' OR g.group_name ILIKE '%' || $1 || '%')
-		AND ($2 = '' OR s.song_name ILIKE '%' || $2 || '%')
-		ORDER BY s.id LIMIT $3 OFFSET $4`
-
-	db := s.dbProvider.DB()
-	rows, err := db.Query(query, group, song, limit, offset)
+var (
+	// ErrSongNotFound возвращается, если песни с указанным ID нет в библиотеке.
+	ErrSongNotFound = errors.New("песня не найдена")
+	// ErrGroupNotFound возвращается, если группы с указанным ID нет в библиотеке.
+	ErrGroupNotFound = errors.New("группа не найдена")
+	// ErrAlbumNotFound возвращается, если альбома с указанным ID нет в библиотеке.
+	ErrAlbumNotFound = errors.New("альбом не найден")
+	// ErrUnknownGroup возвращается, если группы с таким названием нет, а создавать её не просили.
+	ErrUnknownGroup = errors.New("неизвестная группа")
+)
+
+// similarGroupsLimit — сколько похожих групп предлагать, если название не найдено.
+const similarGroupsLimit = 3
+
+// AddSongOptions — необязательные параметры добавления песни.
+type AddSongOptions struct {
+	Track models.AlbumTrack
+	// CreateGroup разрешает создать группу, если её нет в библиотеке.
+	CreateGroup bool
+}
+
+// DefaultSimilarityThreshold — порог сходства для нечёткого поиска по умолчанию.
+// Ниже стандартного для pg_trgm (0.3), чтобы находить короткие названия с опечатками.
+const DefaultSimilarityThreshold = 0.25
+
+func (s *SongService) GetSongs(filter models.SongFilter, page, limit int) ([]models.Song, error) {
+	filter, err := prepareSongFilter(filter)
 	if err != nil {
-		log.Errorf("Ошибка выполнения запроса: %v", err)
-		return nil, fmt.Errorf("ошибка запроса: %w", err)
-	}
-	defer rows.Close()
-
-	songs := []map[string]interface{}{}
-	for rows.Next() {
-		var id int
-		var groupName, songName string
-		var releaseDate sql.NullString
-		if err := rows.Scan(&id, &groupName, &songName, &releaseDate); err != nil {
-			log.Errorf("Ошибка сканирования строки: %v", err)
-			return nil, err
-		}
+		return nil, err
+	}
 
-		songData := map[string]interface{}{
-			"id":           id,
-			"group":        groupName,
-			"song":         songName,
-			"release_date": releaseDate.String,
-		}
-		songs = append(songs, songData)
+	songs, err := s.songs.List(filter, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка выполнения запроса: %v", err)
+		return nil, err
 	}
 	log.Infof("Найдено %d песен", len(songs))
 	return songs, nil
 }
 
+// ExportSongs передаёт fn песни, подходящие под фильтр, по одной прямо из хранилища.
+// Отрицательный limit выгружает все песни, page при этом не учитывается.
+func (s *SongService) ExportSongs(filter models.SongFilter, page, limit int, fn func(models.Song) error) error {
+	filter, err := prepareSongFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	err = s.songs.Each(filter, limit, exportOffset(page, limit), func(song models.Song) error {
+		count++
+		return fn(song)
+	})
+	if err != nil {
+		log.Errorf("Ошибка выгрузки песен: %v", err)
+		return err
+	}
+	log.Infof("Выгружено %d песен", count)
+	return nil
+}
+
+// prepareSongFilter подставляет порог нечёткого поиска по умолчанию и нормализует жанры и теги.
+func prepareSongFilter(filter models.SongFilter) (models.SongFilter, error) {
+	if filter.Fuzzy && filter.MinScore <= 0 {
+		filter.MinScore = DefaultSimilarityThreshold
+	}
+	var err error
+	if filter.Genres, err = normalizeTags(filter.Genres); err != nil {
+		return filter, err
+	}
+	if filter.Tags, err = normalizeTags(filter.Tags); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// exportOffset возвращает смещение страницы выгрузки; без лимита выгрузка идёт с начала.
+func exportOffset(page, limit int) int {
+	if limit < 0 {
+		return 0
+	}
+	return (page - 1) * limit
+}
+
 func (s *SongService) GetSongText(id int) (string, error) {
-	query := `SELECT text FROM songs WHERE id = $1`
-	var text string
-	db := s.dbProvider.DB()
-	err := db.QueryRow(query, id).Scan(&text)
+	text, err := s.songs.GetText(id)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		if errors.Is(err, repository.ErrNotFound) {
 			log.Warnf("Песня с ID %d не найдена", id)
-			return "", fmt.Errorf("песня с id %d не найдена", id)
+			return "", fmt.Errorf("%w: id %d", ErrSongNotFound, id)
 		}
 		log.Errorf("Ошибка получения текста песни: %v", err)
-		return "", fmt.Errorf("ошибка получения текста песни: %w", err)
+		return "", err
 	}
 	log.Infof("Текст песни с ID %d успешно получен", id)
 	return text, nil
 }
 
-func (s *SongService) UpdateSong(id int, group, song string, releaseDate *string, text, link *string) error {
-	db := s.dbProvider.DB()
-
+// UpdateSong обновляет переданные поля песни. Группа задаётся названием и создаётся
+// только при createGroup; update.GroupID заполняется здесь.
+func (s *SongService) UpdateSong(id int, group string, createGroup bool, update models.SongUpdate) error {
 	// Получаем group_id, если передано новое название группы
-	var groupID *int
 	if group != "" {
-		groupID = new(int)
-		err := db.QueryRow(`SELECT id FROM groups WHERE group_name = $1`, group).Scan(groupID)
-		if err == sql.ErrNoRows {
-			// Добавляем группу, если её нет
-			err = db.QueryRow(`INSERT INTO groups (group_name) VALUES ($1) RETURNING id`, group).Scan(groupID)
+		groupID, err := s.resolveGroup(group, createGroup)
+		if err != nil {
+			return err
+		}
+		update.GroupID = &groupID
+	}
+
+	if update.AlbumID != nil {
+		groupID := 0
+		if update.GroupID != nil {
+			groupID = *update.GroupID
+		} else {
+			current, err := s.songs.Get(id)
+			if errors.Is(err, repository.ErrNotFound) {
+				return fmt.Errorf("%w: id %d", ErrSongNotFound, id)
+			}
 			if err != nil {
-				log.Errorf("Ошибка добавления группы: %v", err)
-				return fmt.Errorf("ошибка добавления группы: %w", err)
+				log.Errorf("Ошибка получения песни с ID %d: %v", id, err)
+				return err
 			}
-		} else if err != nil {
-			log.Errorf("Ошибка проверки группы: %v", err)
-			return fmt.Errorf("ошибка проверки группы: %w", err)
+			groupID = current.GroupID
+		}
+		if err := s.checkAlbum(*update.AlbumID, groupID); err != nil {
+			return err
 		}
 	}
 
-	// Обновление песни
-	query := `
-		UPDATE songs
-		SET group_id = COALESCE($1, group_id),
-		    song_name = COALESCE(NULLIF($2, ''), song_name),
-		    release_date = COALESCE($3::DATE, release_date),
-		    text = COALESCE($4, text),
-		    link = COALESCE($5, link),
-		    updated_at = CURRENT_TIMESTAMP
-		WHERE id = $6`
-	_, err := db.Exec(query, groupID, song, releaseDate, text, link, id)
-	if err != nil {
+	if err := s.songs.Update(id, update); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Warnf("Песня с ID %d не найдена", id)
+			return fmt.Errorf("%w: id %d", ErrSongNotFound, id)
+		}
 		log.Errorf("Ошибка обновления песни с ID %d: %v", id, err)
-		return fmt.Errorf("ошибка обновления песни: %w", err)
+		return err
 	}
 	log.Infof("Песня с ID %d успешно обновлена", id)
 	return nil
 }
 
-func (s *SongService) AddSongWithAPI(config *config.Config, group, song string) error {
-	db := s.dbProvider.DB()
-
-	// Проверка существования группы
-	var groupID int
-	err := db.QueryRow(`SELECT id FROM groups WHERE group_name = $1`, group).Scan(&groupID)
-	if err == sql.ErrNoRows {
-		// Группа не найдена, добавляем
-		err = db.QueryRow(`INSERT INTO groups (group_name) VALUES ($1) RETURNING id`, group).Scan(&groupID)
-		if err != nil {
-			log.Errorf("Ошибка добавления группы: %v", err)
-			return fmt.Errorf("ошибка добавления группы: %w", err)
+// FillSongFields сохраняет поля песни, найденные автоматически, например в тегах аудиофайла.
+// Как и правки обогащения, такие поля не считаются введёнными вручную, и обогащение может их уточнить.
+func (s *SongService) FillSongFields(id int, author string, update models.SongUpdate) error {
+	update.Author, update.Enrichment = author, true
+	if err := s.songs.Update(id, update); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%w: id %d", ErrSongNotFound, id)
 		}
-	} else if err != nil {
-		log.Errorf("Ошибка проверки группы: %v", err)
-		return fmt.Errorf("ошибка проверки группы: %w", err)
+		log.Errorf("Ошибка обновления песни с ID %d: %v", id, err)
+		return err
 	}
+	return nil
+}
 
-	// Получение деталей песни из внешнего API
-	details, err := utils.FetchSongDetails(config, group, song)
+// AddSongWithAPI добавляет песню и ставит её в очередь обогащения: дату выпуска, текст
+// и ссылку из источников (внешнего API и других по настройке) заполнит EnrichmentService.
+// Возвращает ID песни. Неизвестная группа создаётся только при opts.CreateGroup.
+func (s *SongService) AddSongWithAPI(group, song string, opts AddSongOptions) (int, error) {
+	// Проверка существования группы
+	groupID, err := s.resolveGroup(group, opts.CreateGroup)
 	if err != nil {
-		log.Errorf("Ошибка вызова внешнего API: %v", err)
-		return fmt.Errorf("ошибка вызова внешнего API: %w", err)
+		return 0, err
+	}
+
+	track := opts.Track
+	if track.AlbumID != nil {
+		if err := s.checkAlbum(*track.AlbumID, groupID); err != nil {
+			return 0, err
+		}
 	}
 
-	// Добавление песни
-	query := `
-		INSERT INTO songs (group_id, song_name, release_date, lyrics, link)
-		VALUES ($1, $2, $3, $4, $5)`
-	_, err = db.Exec(query, groupID, song, details.ReleaseDate, details.Text, details.Link)
+	// Добавление песни; сведения о ней подтянет очередь обогащения
+	id, err := s.songs.Create(models.Song{
+		GroupID:          groupID,
+		SongName:         song,
+		AlbumID:          track.AlbumID,
+		TrackNumber:      track.TrackNumber,
+		DiscNumber:       track.DiscNumber,
+		EnrichmentStatus: models.EnrichmentPending,
+	})
 	if err != nil {
 		log.Errorf("Ошибка сохранения песни в базу: %v", err)
-		return fmt.Errorf("ошибка сохранения песни: %w", err)
+		return 0, err
 	}
 
-	log.Infof("Песня %s - %s успешно добавлена", group, song)
-	return nil
+	log.Infof("Песня %s - %s успешно добавлена с ID %d", group, song, id)
+	return id, nil
 }
 
 // Удаление песни по ID
 func (s *SongService) DeleteSong(id int) error {
-	query := `DELETE FROM songs WHERE id = $1`
-
-	db := s.dbProvider.DB()
-	_, err := db.Exec(query, id)
-	if err != nil {
+	if err := s.songs.Delete(id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			log.Warnf("Песня с ID %d не найдена", id)
+			return fmt.Errorf("%w: id %d", ErrSongNotFound, id)
+		}
 		log.Errorf("Ошибка удаления песни с ID %d: %v", id, err)
-		return fmt.Errorf("ошибка удаления песни: %w", err)
+		return err
+	}
+	// Сведения о файлах удалены каскадно, сами файлы больше ни на что не нужны
+	if err := s.blobs.DeletePrefix(songBlobPrefix(id)); err != nil {
+		log.Warnf("Не удалось удалить файлы песни %d из хранилища: %v", id, err)
 	}
 	log.Infof("Песня с ID %d успешно удалена", id)
 	return nil
 }
+
+// checkAlbum проверяет, что альбом существует и принадлежит группе песни.
+func (s *SongService) checkAlbum(albumID, groupID int) error {
+	album, err := s.albums.Get(albumID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrAlbumNotFound, albumID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения альбома с ID %d: %v", albumID, err)
+		return err
+	}
+	if album.GroupID != groupID {
+		return fmt.Errorf("%w: альбом %d принадлежит другой группе", ErrInvalidInput, albumID)
+	}
+	return nil
+}
+
+// resolveGroup возвращает ID группы по названию с учётом нормализации и псевдонимов
+// (см. GroupRepository.FindByName). Отсутствующая группа добавляется
+// только при create, иначе возвращается ErrUnknownGroup с похожими названиями —
+// так опечатка в запросе не превращается в новую группу.
+func (s *SongService) resolveGroup(name string, create bool) (int, error) {
+	groupID, err := s.groups.FindByName(name)
+	if err == nil {
+		return groupID, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		log.Errorf("Ошибка проверки группы: %v", err)
+		return 0, err
+	}
+
+	if !create {
+		return 0, s.unknownGroupError(name)
+	}
+
+	// Группа не найдена, добавляем
+	groupID, err = s.groups.Create(models.Group{Name: name})
+	if errors.Is(err, repository.ErrConflict) {
+		// Группу успели добавить параллельным запросом
+		return s.groups.FindByName(name)
+	}
+	if err != nil {
+		log.Errorf("Ошибка добавления группы: %v", err)
+		return 0, err
+	}
+	log.Infof("Добавлена новая группа %q с ID %d", name, groupID)
+	return groupID, nil
+}
+
+// unknownGroupError формирует ошибку ErrUnknownGroup с подсказками похожих названий.
+func (s *SongService) unknownGroupError(name string) error {
+	similar, err := s.groups.FindSimilar(name, similarGroupsLimit)
+	if err != nil {
+		// Подсказки не обязательны, сама ошибка важнее
+		log.Warnf("Ошибка поиска похожих групп: %v", err)
+	}
+	if len(similar) == 0 {
+		return fmt.Errorf("%w %q (передайте create_group=true, чтобы создать её)", ErrUnknownGroup, name)
+	}
+	names := make([]string, 0, len(similar))
+	for _, group := range similar {
+		names = append(names, fmt.Sprintf("%q", group.Name))
+	}
+	return fmt.Errorf("%w %q, возможно, имелась в виду: %s", ErrUnknownGroup, name, strings.Join(names, ", "))
+}
+
+// releaseDateLayouts — форматы даты выпуска, которые встречаются во внешнем API.
+var releaseDateLayouts = []string{models.DateLayout, "02.01.2006"}
+
+// parseReleaseDate разбирает дату выпуска; пустая строка означает, что дата неизвестна.
+func parseReleaseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range releaseDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("некорректная дата выпуска %q", value)
+}
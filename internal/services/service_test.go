@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository/memory"
+	"github.com/EugeneKrivoshein/music_library/internal/storage"
+)
+
+// songFixture — SongService на хранилище в памяти вместе с его репозиториями.
+type songFixture struct {
+	service *SongService
+	songs   *memory.SongRepository
+	groups  *memory.GroupRepository
+	albums  *memory.AlbumRepository
+}
+
+func newSongFixture(t *testing.T) songFixture {
+	t.Helper()
+	store := memory.NewStore()
+	blobs, err := storage.NewLocalStorage(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalStorage: %v", err)
+	}
+	f := songFixture{
+		songs:  memory.NewSongRepository(store),
+		groups: memory.NewGroupRepository(store),
+		albums: memory.NewAlbumRepository(store),
+	}
+	f.service = NewSongService(f.songs, f.groups, f.albums, blobs)
+	return f
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestAddSongGroupResolution(t *testing.T) {
+	f := newSongFixture(t)
+	museID, err := f.groups.Create(models.Group{Name: "Muse"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := f.groups.Create(models.Group{Name: "Metallica"}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		group       string
+		createGroup bool
+		wantErr     error
+		wantHint    string
+		wantGroup   string
+	}{
+		{name: "существующая группа", group: "Muse", wantGroup: "Muse"},
+		{name: "другой регистр и пробелы", group: "  muse ", wantGroup: "Muse"},
+		{name: "опечатка без create_group", group: "Metalica", wantErr: ErrUnknownGroup, wantHint: `"Metallica"`},
+		{name: "новая группа без create_group", group: "Queen", wantErr: ErrUnknownGroup, wantHint: "create_group=true"},
+		{name: "новая группа с create_group", group: "Queen", createGroup: true, wantGroup: "Queen"},
+		{name: "созданная группа переиспользуется", group: "queen", wantGroup: "Queen"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := f.service.AddSongWithAPI(tt.group, "Song", AddSongOptions{CreateGroup: tt.createGroup})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.wantHint) {
+					t.Errorf("error = %q, want hint %s", err, tt.wantHint)
+				}
+				return
+			}
+			song, err := f.songs.Get(id)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if song.GroupName != tt.wantGroup {
+				t.Errorf("GroupName = %q, want %q", song.GroupName, tt.wantGroup)
+			}
+			if song.EnrichmentStatus != models.EnrichmentPending {
+				t.Errorf("EnrichmentStatus = %q, want pending", song.EnrichmentStatus)
+			}
+		})
+	}
+
+	groups, err := f.groups.List(models.GroupFilter{}, 10, 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(groups) != 3 {
+		t.Errorf("групп = %d, want 3: опечатка не должна создавать группу", len(groups))
+	}
+	if _, err := f.groups.Get(museID); err != nil {
+		t.Errorf("Get(Muse): %v", err)
+	}
+}
+
+func TestAddSongAlbumOfOtherGroup(t *testing.T) {
+	f := newSongFixture(t)
+	f.groups.Create(models.Group{Name: "Muse"})
+	queenID, _ := f.groups.Create(models.Group{Name: "Queen"})
+	albumID, err := f.albums.Create(models.Album{GroupID: queenID, Title: "A Night at the Opera"})
+	if err != nil {
+		t.Fatalf("Create album: %v", err)
+	}
+
+	_, err = f.service.AddSongWithAPI("Muse", "Uprising", AddSongOptions{Track: models.AlbumTrack{AlbumID: &albumID}})
+	if !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("альбом другой группы: error = %v, want ErrInvalidInput", err)
+	}
+	_, err = f.service.AddSongWithAPI("Muse", "Uprising", AddSongOptions{Track: models.AlbumTrack{AlbumID: ptr(albumID + 100)}})
+	if !errors.Is(err, ErrAlbumNotFound) {
+		t.Errorf("неизвестный альбом: error = %v, want ErrAlbumNotFound", err)
+	}
+	if songs, _ := f.songs.List(models.SongFilter{}, 10, 0); len(songs) != 0 {
+		t.Errorf("песни добавлены, несмотря на ошибку: %v", songs)
+	}
+}
+
+func TestUpdateSongPartial(t *testing.T) {
+	f := newSongFixture(t)
+	groupID, _ := f.groups.Create(models.Group{Name: "Muse"})
+	id, err := f.songs.Create(models.Song{GroupID: groupID, SongName: "Uprising", Text: "Первый куплет", Link: "https://example.com"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := f.service.UpdateSong(id, "", false, models.SongUpdate{Text: ptr("Новый текст")}); err != nil {
+		t.Fatalf("UpdateSong: %v", err)
+	}
+	song, _ := f.songs.Get(id)
+	if song.Text != "Новый текст" || song.SongName != "Uprising" || song.Link != "https://example.com" || song.GroupID != groupID {
+		t.Errorf("после изменения текста песня = %+v", song)
+	}
+
+	err = f.service.UpdateSong(id, "Queen", false, models.SongUpdate{})
+	if !errors.Is(err, ErrUnknownGroup) {
+		t.Errorf("неизвестная группа без create_group: error = %v, want ErrUnknownGroup", err)
+	}
+	if err := f.service.UpdateSong(id, "Queen", true, models.SongUpdate{}); err != nil {
+		t.Fatalf("UpdateSong(create_group): %v", err)
+	}
+	song, _ = f.songs.Get(id)
+	if song.GroupName != "Queen" || song.Text != "Новый текст" {
+		t.Errorf("после смены группы песня = %+v", song)
+	}
+
+	if err := f.service.UpdateSong(id+100, "", false, models.SongUpdate{Link: ptr("")}); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("неизвестная песня: error = %v, want ErrSongNotFound", err)
+	}
+}
+
+func TestGetSongsPagination(t *testing.T) {
+	f := newSongFixture(t)
+	groupID, _ := f.groups.Create(models.Group{Name: "Muse"})
+	for _, name := range []string{"A", "B", "C", "D", "E"} {
+		if _, err := f.songs.Create(models.Song{GroupID: groupID, SongName: name}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	tests := []struct {
+		page, limit int
+		want        string
+	}{
+		{1, 2, "AB"},
+		{2, 2, "CD"},
+		{3, 2, "E"},
+		{4, 2, ""},
+		{1, 10, "ABCDE"},
+	}
+	for _, tt := range tests {
+		songs, err := f.service.GetSongs(models.SongFilter{}, tt.page, tt.limit)
+		if err != nil {
+			t.Fatalf("GetSongs: %v", err)
+		}
+		var got strings.Builder
+		for _, song := range songs {
+			got.WriteString(song.SongName)
+		}
+		if got.String() != tt.want {
+			t.Errorf("GetSongs(page %d, limit %d) = %q, want %q", tt.page, tt.limit, got.String(), tt.want)
+		}
+	}
+}
+
+func TestGetAndDeleteMissingSong(t *testing.T) {
+	f := newSongFixture(t)
+	groupID, _ := f.groups.Create(models.Group{Name: "Muse"})
+	id, _ := f.songs.Create(models.Song{GroupID: groupID, SongName: "Uprising", Text: "Куплет"})
+
+	if text, err := f.service.GetSongText(id); err != nil || text != "Куплет" {
+		t.Errorf("GetSongText = %q, %v", text, err)
+	}
+	if err := f.service.DeleteSong(id); err != nil {
+		t.Fatalf("DeleteSong: %v", err)
+	}
+	if _, err := f.service.GetSongText(id); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("GetSongText после удаления: error = %v, want ErrSongNotFound", err)
+	}
+	if err := f.service.DeleteSong(id); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("DeleteSong повторно: error = %v, want ErrSongNotFound", err)
+	}
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// ErrSmartPlaylistNotFound возвращается, если умного плейлиста с указанным ID нет.
+var ErrSmartPlaylistNotFound = errors.New("умный плейлист не найден")
+
+const (
+	// maxRuleDepth ограничивает вложенность групп правил.
+	maxRuleDepth = 5
+	// maxRuleConditions ограничивает общее число условий в правилах плейлиста.
+	maxRuleConditions = 50
+)
+
+// ruleOps перечисляет операторы, допустимые для каждого поля правила.
+var ruleOps = map[string][]string{
+	models.RuleFieldGroup:       {models.RuleOpEquals, models.RuleOpContains, models.RuleOpNotContains, models.RuleOpStartsWith, models.RuleOpLike},
+	models.RuleFieldSong:        {models.RuleOpEquals, models.RuleOpContains, models.RuleOpNotContains, models.RuleOpStartsWith, models.RuleOpLike},
+	models.RuleFieldAlbum:       {models.RuleOpEquals, models.RuleOpContains, models.RuleOpNotContains, models.RuleOpStartsWith, models.RuleOpLike},
+	models.RuleFieldText:        {models.RuleOpContains, models.RuleOpNotContains, models.RuleOpLike},
+	models.RuleFieldReleaseDate: {models.RuleOpEquals, models.RuleOpBefore, models.RuleOpAfter, models.RuleOpBetween},
+	models.RuleFieldGenre:       {models.RuleOpIs, models.RuleOpIsNot},
+	models.RuleFieldTag:         {models.RuleOpIs, models.RuleOpIsNot},
+}
+
+type SmartPlaylistService struct {
+	smart repository.SmartPlaylistRepository
+	songs repository.SongRepository
+}
+
+func NewSmartPlaylistService(smart repository.SmartPlaylistRepository, songs repository.SongRepository) *SmartPlaylistService {
+	return &SmartPlaylistService{smart: smart, songs: songs}
+}
+
+func (s *SmartPlaylistService) GetSmartPlaylists(page, limit int) ([]models.SmartPlaylist, error) {
+	playlists, err := s.smart.List(limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения умных плейлистов: %v", err)
+		return nil, err
+	}
+	log.Infof("Найдено %d умных плейлистов", len(playlists))
+	return playlists, nil
+}
+
+func (s *SmartPlaylistService) GetSmartPlaylist(id int) (models.SmartPlaylist, error) {
+	playlist, err := s.smart.Get(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.SmartPlaylist{}, fmt.Errorf("%w: id %d", ErrSmartPlaylistNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка получения умного плейлиста с ID %d: %v", id, err)
+		return models.SmartPlaylist{}, err
+	}
+	return playlist, nil
+}
+
+// CreateSmartPlaylist проверяет правила, сохраняет плейлист и возвращает его с заполненным ID.
+func (s *SmartPlaylistService) CreateSmartPlaylist(playlist models.SmartPlaylist) (models.SmartPlaylist, error) {
+	playlist.Name = strings.TrimSpace(playlist.Name)
+	if playlist.Name == "" {
+		return models.SmartPlaylist{}, fmt.Errorf("%w: название плейлиста не может быть пустым", ErrInvalidInput)
+	}
+	rules, err := validateRules(playlist.Rules)
+	if err != nil {
+		return models.SmartPlaylist{}, err
+	}
+	playlist.Rules = rules
+
+	id, err := s.smart.Create(playlist)
+	if err != nil {
+		log.Errorf("Ошибка добавления умного плейлиста: %v", err)
+		return models.SmartPlaylist{}, err
+	}
+	log.Infof("Умный плейлист %q добавлен с ID %d", playlist.Name, id)
+	return s.GetSmartPlaylist(id)
+}
+
+func (s *SmartPlaylistService) UpdateSmartPlaylist(id int, update models.SmartPlaylistUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return fmt.Errorf("%w: название плейлиста не может быть пустым", ErrInvalidInput)
+		}
+		update.Name = &name
+	}
+	if update.Rules != nil {
+		rules, err := validateRules(*update.Rules)
+		if err != nil {
+			return err
+		}
+		update.Rules = &rules
+	}
+
+	err := s.smart.Update(id, update)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrSmartPlaylistNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка обновления умного плейлиста с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Умный плейлист с ID %d успешно обновлён", id)
+	return nil
+}
+
+func (s *SmartPlaylistService) DeleteSmartPlaylist(id int) error {
+	err := s.smart.Delete(id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: id %d", ErrSmartPlaylistNotFound, id)
+	}
+	if err != nil {
+		log.Errorf("Ошибка удаления умного плейлиста с ID %d: %v", id, err)
+		return err
+	}
+	log.Infof("Умный плейлист с ID %d успешно удалён", id)
+	return nil
+}
+
+// GetSmartPlaylistSongs подбирает песни по правилам плейлиста тем же запросом,
+// что и поиск песен, поэтому результат всегда отражает текущее состояние библиотеки.
+func (s *SmartPlaylistService) GetSmartPlaylistSongs(id, page, limit int) ([]models.Song, error) {
+	playlist, err := s.GetSmartPlaylist(id)
+	if err != nil {
+		return nil, err
+	}
+	songs, err := s.songs.List(models.SongFilter{Rules: &playlist.Rules}, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка подбора песен умного плейлиста с ID %d: %v", id, err)
+		return nil, err
+	}
+	return songs, nil
+}
+
+// ExportSmartPlaylistSongs передаёт fn подобранные по правилам песни по одной прямо из хранилища.
+// Отрицательный limit выгружает все подходящие песни.
+func (s *SmartPlaylistService) ExportSmartPlaylistSongs(id, page, limit int, fn func(models.Song) error) error {
+	playlist, err := s.GetSmartPlaylist(id)
+	if err != nil {
+		return err
+	}
+	err = s.songs.Each(models.SongFilter{Rules: &playlist.Rules}, limit, exportOffset(page, limit), fn)
+	if err != nil {
+		log.Errorf("Ошибка выгрузки умного плейлиста с ID %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// validateRules проверяет правила и возвращает их в нормализованном виде:
+// группы без способа объединения получают «all», значения очищаются от пробелов,
+// названия жанров и тегов приводятся к виду, в котором они хранятся.
+func validateRules(rule models.SmartRule) (models.SmartRule, error) {
+	conditions := 0
+	normalized, err := validateRule(rule, 1, &conditions)
+	if errors.Is(err, ErrInvalidInput) {
+		return models.SmartRule{}, err
+	}
+	if err != nil {
+		return models.SmartRule{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+	return normalized, nil
+}
+
+func validateRule(rule models.SmartRule, depth int, conditions *int) (models.SmartRule, error) {
+	// Глубина считается и для условий: условие на шестом уровне тоже превышает предел
+	if depth > maxRuleDepth {
+		return rule, fmt.Errorf("вложенность правил больше %d", maxRuleDepth)
+	}
+	if rule.IsGroup() {
+		if rule.Op != "" || len(rule.Values) > 0 {
+			return rule, errors.New("у группы правил не указано поле")
+		}
+		switch rule.Match {
+		case "":
+			rule.Match = models.RuleMatchAll
+		case models.RuleMatchAll, models.RuleMatchAny:
+		default:
+			return rule, fmt.Errorf("неизвестный способ объединения правил %q", rule.Match)
+		}
+		children := make([]models.SmartRule, 0, len(rule.Rules))
+		for _, child := range rule.Rules {
+			child, err := validateRule(child, depth+1, conditions)
+			if err != nil {
+				return rule, err
+			}
+			children = append(children, child)
+		}
+		rule.Rules = children
+		return rule, nil
+	}
+
+	if rule.Match != "" || len(rule.Rules) > 0 {
+		return rule, fmt.Errorf("условие на поле %q не может содержать вложенных правил", rule.Field)
+	}
+	*conditions++
+	if *conditions > maxRuleConditions {
+		return rule, fmt.Errorf("условий больше %d", maxRuleConditions)
+	}
+	ops, ok := ruleOps[rule.Field]
+	if !ok {
+		return rule, fmt.Errorf("неизвестное поле %q", rule.Field)
+	}
+	if !slices.Contains(ops, rule.Op) {
+		return rule, fmt.Errorf("оператор %q не применим к полю %q", rule.Op, rule.Field)
+	}
+	arity := 1
+	if rule.Op == models.RuleOpBetween {
+		arity = 2
+	}
+	if len(rule.Values) != arity {
+		return rule, fmt.Errorf("оператор %q ожидает значений: %d, получено %d", rule.Op, arity, len(rule.Values))
+	}
+
+	values := make([]string, len(rule.Values))
+	for i, value := range rule.Values {
+		values[i] = strings.TrimSpace(value)
+		if values[i] == "" {
+			return rule, fmt.Errorf("пустое значение в условии на поле %q", rule.Field)
+		}
+	}
+	rule.Values = values
+
+	switch rule.Field {
+	case models.RuleFieldReleaseDate:
+		from, _, err := models.RuleDateRange(values[0])
+		if err != nil {
+			return rule, err
+		}
+		if arity == 1 {
+			break
+		}
+		_, to, err := models.RuleDateRange(values[1])
+		if err != nil {
+			return rule, err
+		}
+		if from.After(to) {
+			return rule, fmt.Errorf("начало периода %s позже его конца %s", values[0], values[1])
+		}
+	case models.RuleFieldGenre, models.RuleFieldTag:
+		names, err := normalizeTags(values)
+		if err != nil {
+			return rule, err
+		}
+		rule.Values = names
+	}
+	return rule, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+func rule(field, op string, values ...string) models.SmartRule {
+	return models.SmartRule{Field: field, Op: op, Values: values}
+}
+
+func group(match string, rules ...models.SmartRule) models.SmartRule {
+	return models.SmartRule{Match: match, Rules: rules}
+}
+
+// nested вкладывает правило в depth-1 групп, так что само оно оказывается на уровне depth.
+func nested(leaf models.SmartRule, depth int) models.SmartRule {
+	for i := 1; i < depth; i++ {
+		leaf = group(models.RuleMatchAll, leaf)
+	}
+	return leaf
+}
+
+// conditions возвращает группу из n условий.
+func conditions(n int) models.SmartRule {
+	rules := make([]models.SmartRule, n)
+	for i := range rules {
+		rules[i] = rule(models.RuleFieldGroup, models.RuleOpEquals, "Muse")
+	}
+	return group(models.RuleMatchAny, rules...)
+}
+
+func TestValidateRulesNormalizes(t *testing.T) {
+	got, err := validateRules(models.SmartRule{Rules: []models.SmartRule{
+		rule(models.RuleFieldSong, models.RuleOpContains, "  love "),
+		group(models.RuleMatchAny,
+			rule(models.RuleFieldGenre, models.RuleOpIs, " Rock "),
+			rule(models.RuleFieldReleaseDate, models.RuleOpBetween, "1990", "1999-12-31"),
+		),
+	}})
+	if err != nil {
+		t.Fatalf("validateRules: %v", err)
+	}
+	want := group(models.RuleMatchAll,
+		rule(models.RuleFieldSong, models.RuleOpContains, "love"),
+		group(models.RuleMatchAny,
+			rule(models.RuleFieldGenre, models.RuleOpIs, "rock"),
+			rule(models.RuleFieldReleaseDate, models.RuleOpBetween, "1990", "1999-12-31"),
+		),
+	)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("validateRules = %+v, want %+v", got, want)
+	}
+}
+
+func TestValidateRulesLimits(t *testing.T) {
+	leaf := rule(models.RuleFieldGroup, models.RuleOpEquals, "Muse")
+	tests := []struct {
+		name    string
+		rule    models.SmartRule
+		wantErr string
+	}{
+		{name: "условие на предельной глубине", rule: nested(leaf, maxRuleDepth)},
+		{name: "условие глубже предела", rule: nested(leaf, maxRuleDepth+1), wantErr: "вложенность"},
+		{name: "пустая группа глубже предела", rule: nested(group(models.RuleMatchAll), maxRuleDepth+1), wantErr: "вложенность"},
+		{name: "предельное число условий", rule: conditions(maxRuleConditions)},
+		{name: "условий больше предела", rule: conditions(maxRuleConditions + 1), wantErr: "условий больше"},
+		{
+			name:    "условия считаются во всех группах",
+			rule:    group(models.RuleMatchAll, conditions(maxRuleConditions/2), conditions(maxRuleConditions/2+1)),
+			wantErr: "условий больше",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateRules(tt.rule)
+			checkRuleError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateRulesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    models.SmartRule
+		wantErr string
+	}{
+		{name: "неизвестный способ объединения", rule: group("none"), wantErr: "способ объединения"},
+		{name: "оператор у группы", rule: models.SmartRule{Op: models.RuleOpEquals}, wantErr: "не указано поле"},
+		{
+			name:    "вложенные правила у условия",
+			rule:    models.SmartRule{Field: models.RuleFieldGroup, Op: models.RuleOpEquals, Values: []string{"Muse"}, Rules: []models.SmartRule{{}}},
+			wantErr: "вложенных правил",
+		},
+		{name: "неизвестное поле", rule: rule("rating", models.RuleOpEquals, "5"), wantErr: "неизвестное поле"},
+		{name: "оператор не для поля", rule: rule(models.RuleFieldGroup, models.RuleOpBefore, "2000"), wantErr: "не применим"},
+		{name: "нет значения", rule: rule(models.RuleFieldSong, models.RuleOpEquals), wantErr: "ожидает значений"},
+		{name: "лишнее значение", rule: rule(models.RuleFieldReleaseDate, models.RuleOpBetween, "1990"), wantErr: "ожидает значений"},
+		{name: "пустое значение", rule: rule(models.RuleFieldSong, models.RuleOpEquals, "  "), wantErr: "пустое значение"},
+		{name: "неверная дата", rule: rule(models.RuleFieldReleaseDate, models.RuleOpAfter, "вчера"), wantErr: "YYYY-MM-DD"},
+		{name: "неверный конец периода", rule: rule(models.RuleFieldReleaseDate, models.RuleOpBetween, "1990", "1999-13-01"), wantErr: "YYYY-MM-DD"},
+		{name: "начало позже конца", rule: rule(models.RuleFieldReleaseDate, models.RuleOpBetween, "2000", "1999"), wantErr: "позже"},
+		{name: "длинная метка", rule: rule(models.RuleFieldTag, models.RuleOpIs, strings.Repeat("я", maxTagLength+1)), wantErr: "длиннее"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateRules(tt.rule)
+			checkRuleError(t, err, tt.wantErr)
+		})
+	}
+}
+
+// checkRuleError проверяет, что правило принято (wantErr пустой) или отклонено
+// как ErrInvalidInput с сообщением, содержащим wantErr.
+func checkRuleError(t *testing.T, err error, wantErr string) {
+	t.Helper()
+	switch {
+	case wantErr == "" && err != nil:
+		t.Errorf("ошибка %v, ожидалось без ошибки", err)
+	case wantErr == "":
+	case !errors.Is(err, ErrInvalidInput):
+		t.Errorf("ошибка %v, ожидалась ErrInvalidInput", err)
+	case !strings.Contains(err.Error(), wantErr):
+		t.Errorf("ошибка %q не содержит %q", err, wantErr)
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+	"github.com/EugeneKrivoshein/music_library/internal/repository"
+)
+
+// ErrTagNotAttached возвращается при снятии метки, которой у песни нет.
+var ErrTagNotAttached = errors.New("метка не привязана к песне")
+
+// maxTagLength — ограничение длины названия метки, как у колонок genres.name и tags.name.
+const maxTagLength = 64
+
+type TagService struct {
+	tags  repository.TagRepository
+	songs repository.SongRepository
+}
+
+func NewTagService(tags repository.TagRepository, songs repository.SongRepository) *TagService {
+	return &TagService{tags: tags, songs: songs}
+}
+
+// GetTags возвращает жанры или теги по алфавиту вместе с числом песен.
+func (s *TagService) GetTags(kind models.TagKind, page, limit int) ([]models.Tag, error) {
+	tags, err := s.tags.List(kind, limit, (page-1)*limit)
+	if err != nil {
+		log.Errorf("Ошибка получения меток %s: %v", kind, err)
+		return nil, err
+	}
+	log.Infof("Найдено %d меток %s", len(tags), kind)
+	return tags, nil
+}
+
+// GetTagCloud возвращает до limit самых частых тегов для облака тегов.
+func (s *TagService) GetTagCloud(limit int) ([]models.Tag, error) {
+	tags, err := s.tags.Popular(models.TagKindTag, limit)
+	if err != nil {
+		log.Errorf("Ошибка получения облака тегов: %v", err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+// AttachTags отмечает песню жанрами или тегами и возвращает обновлённую песню.
+func (s *TagService) AttachTags(songID int, kind models.TagKind, names []string) (models.Song, error) {
+	names, err := normalizeTags(names)
+	if err != nil {
+		return models.Song{}, err
+	}
+	if len(names) == 0 {
+		return models.Song{}, fmt.Errorf("%w: не указано ни одной метки", ErrInvalidInput)
+	}
+
+	err = s.tags.Attach(songID, kind, names)
+	if errors.Is(err, repository.ErrNotFound) {
+		return models.Song{}, fmt.Errorf("%w: id %d", ErrSongNotFound, songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка привязки меток к песне с ID %d: %v", songID, err)
+		return models.Song{}, err
+	}
+	log.Infof("Песня с ID %d отмечена метками %s: %s", songID, kind, strings.Join(names, ", "))
+
+	song, err := s.songs.Get(songID)
+	if err != nil {
+		log.Errorf("Ошибка получения песни с ID %d: %v", songID, err)
+		return models.Song{}, err
+	}
+	return song, nil
+}
+
+// DetachTag снимает жанр или тег с песни.
+func (s *TagService) DetachTag(songID int, kind models.TagKind, name string) error {
+	names, err := normalizeTags([]string{name})
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("%w: не указана метка", ErrInvalidInput)
+	}
+
+	err = s.tags.Detach(songID, kind, names[0])
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %q у песни с ID %d", ErrTagNotAttached, names[0], songID)
+	}
+	if err != nil {
+		log.Errorf("Ошибка снятия метки с песни с ID %d: %v", songID, err)
+		return err
+	}
+	log.Infof("С песни с ID %d снята метка %s %q", songID, kind, names[0])
+	return nil
+}
+
+// normalizeTags приводит названия меток к нижнему регистру без пробелов по краям,
+// убирает пустые и повторяющиеся. Так «Rock» и «rock » — одна и та же метка.
+func normalizeTags(names []string) ([]string, error) {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, fmt.Errorf("%w: название метки длиннее %d символов", ErrInvalidInput, maxTagLength)
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+// stanzaSeparator — одна или несколько пустых строк (в том числе из пробелов) между куплетами.
+var stanzaSeparator = regexp.MustCompile(`\n[ \t]*\n\s*`)
+
+// splitVerses делит текст песни на куплеты по пустым строкам.
+func splitVerses(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	verses := []string{}
+	for _, verse := range stanzaSeparator.Split(text, -1) {
+		verse = strings.TrimSpace(verse)
+		if verse != "" {
+			verses = append(verses, verse)
+		}
+	}
+	return verses
+}
+
+// GetSongVerses возвращает страницу куплетов песни.
+func (s *SongService) GetSongVerses(id, page, limit int) (*models.VersePage, error) {
+	text, err := s.GetSongText(id)
+	if err != nil {
+		return nil, err
+	}
+
+	verses := splitVerses(text)
+	result := &models.VersePage{
+		SongID:     id,
+		Page:       page,
+		Limit:      limit,
+		Total:      len(verses),
+		TotalPages: (len(verses) + limit - 1) / limit,
+		Verses:     []models.Verse{},
+	}
+
+	start := (page - 1) * limit
+	for i := start; i < len(verses) && i < start+limit; i++ {
+		result.Verses = append(result.Verses, models.Verse{Index: i + 1, Text: verses[i]})
+	}
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/EugeneKrivoshein/music_library/internal/models"
+)
+
+func TestSplitVerses(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "пустой текст", text: "", want: []string{}},
+		{name: "один куплет", text: "строка 1\nстрока 2", want: []string{"строка 1\nстрока 2"}},
+		{name: "пустая строка", text: "куплет 1\n\nкуплет 2", want: []string{"куплет 1", "куплет 2"}},
+		{name: "CRLF", text: "куплет 1\r\nещё\r\n\r\nкуплет 2\r\n", want: []string{"куплет 1\nещё", "куплет 2"}},
+		{name: "строка из пробелов", text: "куплет 1\n \t \nкуплет 2", want: []string{"куплет 1", "куплет 2"}},
+		{name: "CRLF и пробелы", text: "куплет 1\r\n  \r\n\r\n\tкуплет 2", want: []string{"куплет 1", "куплет 2"}},
+		{name: "несколько пустых строк", text: "\n\nкуплет 1\n\n\n\nкуплет 2\n\n", want: []string{"куплет 1", "куплет 2"}},
+		{name: "только пустые строки", text: "\n \n\r\n", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitVerses(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitVerses(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSongVerses(t *testing.T) {
+	f := newSongFixture(t)
+	groupID, err := f.groups.Create(models.Group{Name: "Muse"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id, err := f.songs.Create(models.Song{GroupID: groupID, SongName: "Hysteria",
+		Text: "куплет 1\r\n\r\nкуплет 2\n  \nкуплет 3\n\nкуплет 4\n\nкуплет 5"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		page, limit    int
+		wantVerses     []models.Verse
+		wantTotalPages int
+	}{
+		{name: "первая страница", page: 1, limit: 2, wantTotalPages: 3,
+			wantVerses: []models.Verse{{Index: 1, Text: "куплет 1"}, {Index: 2, Text: "куплет 2"}}},
+		{name: "неполная последняя страница", page: 3, limit: 2, wantTotalPages: 3,
+			wantVerses: []models.Verse{{Index: 5, Text: "куплет 5"}}},
+		{name: "страница за концом", page: 4, limit: 2, wantTotalPages: 3, wantVerses: []models.Verse{}},
+		{name: "все куплеты", page: 1, limit: 10, wantTotalPages: 1, wantVerses: []models.Verse{
+			{Index: 1, Text: "куплет 1"}, {Index: 2, Text: "куплет 2"}, {Index: 3, Text: "куплет 3"},
+			{Index: 4, Text: "куплет 4"}, {Index: 5, Text: "куплет 5"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := f.service.GetSongVerses(id, tt.page, tt.limit)
+			if err != nil {
+				t.Fatalf("GetSongVerses: %v", err)
+			}
+			if got.Total != 5 || got.TotalPages != tt.wantTotalPages || got.Page != tt.page || got.Limit != tt.limit {
+				t.Errorf("страница %+v, ожидалось куплетов 5 и страниц %d", got, tt.wantTotalPages)
+			}
+			if !reflect.DeepEqual(got.Verses, tt.wantVerses) {
+				t.Errorf("куплеты %+v, ожидались %+v", got.Verses, tt.wantVerses)
+			}
+		})
+	}
+
+	if _, err := f.service.GetSongVerses(id+100, 1, 2); !errors.Is(err, ErrSongNotFound) {
+		t.Errorf("GetSongVerses(нет песни): ошибка %v, ожидалась ErrSongNotFound", err)
+	}
+}
+
+func TestGetSongVersesWithoutText(t *testing.T) {
+	f := newSongFixture(t)
+	groupID, err := f.groups.Create(models.Group{Name: "Muse"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	id, err := f.songs.Create(models.Song{GroupID: groupID, SongName: "Hysteria"})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	got, err := f.service.GetSongVerses(id, 1, 10)
+	if err != nil {
+		t.Fatalf("GetSongVerses: %v", err)
+	}
+	if got.Total != 0 || got.TotalPages != 0 || len(got.Verses) != 0 {
+		t.Errorf("страница песни без текста %+v", got)
+	}
+}
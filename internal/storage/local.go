@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage хранит объекты файлами в каталоге root; ключ задаёт путь внутри него.
+type LocalStorage struct {
+	root string
+}
+
+// NewLocalStorage создаёт хранилище в каталоге root, создавая каталог при необходимости.
+func NewLocalStorage(root string) (*LocalStorage, error) {
+	if root == "" {
+		return nil, errors.New("не задан каталог хранилища")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("ошибка создания каталога хранилища: %w", err)
+	}
+	return &LocalStorage{root: root}, nil
+}
+
+// path проверяет ключ и возвращает путь к файлу объекта.
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, `\`) {
+		return "", fmt.Errorf("некорректный ключ %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", fmt.Errorf("некорректный ключ %q", key)
+		}
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+func (s *LocalStorage) Put(key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, fmt.Errorf("ошибка создания каталога: %w", err)
+	}
+	// Запись во временный файл и переименование: читатель видит либо старый объект, либо новый целиком
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("ошибка создания файла: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("ошибка записи файла: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("ошибка записи файла: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("ошибка записи файла: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return 0, fmt.Errorf("ошибка сохранения файла: %w", err)
+	}
+	return size, nil
+}
+
+// localBlob — открытый файл объекта.
+type localBlob struct {
+	*os.File
+	info BlobInfo
+}
+
+func (b localBlob) Info() BlobInfo {
+	return b.info
+}
+
+func (s *LocalStorage) Open(key string) (Blob, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия файла: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("ошибка чтения сведений о файле: %w", err)
+	}
+	return localBlob{File: file, info: BlobInfo{Size: stat.Size(), ModTime: stat.ModTime()}}, nil
+}
+
+func (s *LocalStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("ошибка удаления файла: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStorage) DeletePrefix(prefix string) error {
+	path, err := s.path(strings.TrimSuffix(prefix, "/"))
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("ошибка удаления каталога: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrNotFound возвращается, если объекта с таким ключом нет в хранилище.
+var ErrNotFound = errors.New("объект не найден")
+
+// BlobInfo — сведения о сохранённом объекте.
+type BlobInfo struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Blob — открытый для чтения объект; поддерживает перемещение, чтобы отдавать диапазоны.
+type Blob interface {
+	io.ReadSeekCloser
+	Info() BlobInfo
+}
+
+// BlobStorage хранит файлы (аудио, обложки) по ключам вида «songs/42/audio.mp3».
+// Ключ состоит из сегментов через косую черту без «.» и «..».
+type BlobStorage interface {
+	// Put сохраняет содержимое r под ключом key, заменяя прежнее, и возвращает его размер.
+	// Читатели прежнего объекта не видят частично записанный новый.
+	Put(key string, r io.Reader) (int64, error)
+	// Open открывает объект для чтения.
+	Open(key string) (Blob, error)
+	// Delete удаляет объект; отсутствие объекта ошибкой не считается.
+	Delete(key string) error
+	// DeletePrefix удаляет все объекты, ключи которых начинаются с prefix/.
+	DeletePrefix(prefix string) error
+}
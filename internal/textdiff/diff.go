@@ -0,0 +1,251 @@
+// Package textdiff сравнивает тексты построчно алгоритмом Майерса и записывает
+// разницу в едином формате (unified diff), как diff -u.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind — вид строки в разнице.
+type Kind int
+
+const (
+	Equal Kind = iota
+	Delete
+	Insert
+)
+
+// Edit — строка разницы: общая, удалённая из a или добавленная из b.
+// ALine и BLine — номера строки в a и b с нуля, -1 — строки нет в этом тексте.
+type Edit struct {
+	Kind  Kind
+	Text  string
+	ALine int
+	BLine int
+}
+
+// Lines делит текст на строки; перевод строки в конце не даёт лишней пустой строки.
+func Lines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// maxEdits ограничивает число правок, которое ищет алгоритм Майерса: время поиска растёт
+// как (n+m)·D, а память — как D², где D — число правок. Если текстам нужно больше правок,
+// всё между их общими началом и концом считается заменённым целиком.
+const maxEdits = 1000
+
+// Diff возвращает кратчайший список правок, превращающих a в b, если для этого хватает
+// maxEdits правок; иначе — удаление и вставку всех строк между общими началом и концом.
+func Diff(a, b []string) []Edit {
+	// Общие начало и конец всегда входят в кратчайший список правок, искать их не нужно
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var edits []Edit
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Kind: Equal, Text: a[i], ALine: i, BLine: i})
+	}
+	middleA, middleB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	middle, ok := myers(middleA, middleB, maxEdits)
+	if !ok {
+		middle = replace(middleA, middleB)
+	}
+	for _, e := range middle {
+		if e.ALine >= 0 {
+			e.ALine += prefix
+		}
+		if e.BLine >= 0 {
+			e.BLine += prefix
+		}
+		edits = append(edits, e)
+	}
+	for i := suffix; i > 0; i-- {
+		edits = append(edits, Edit{Kind: Equal, Text: a[len(a)-i], ALine: len(a) - i, BLine: len(b) - i})
+	}
+	return edits
+}
+
+// myers ищет кратчайший список правок не длиннее maxD; false — такого списка нет.
+func myers(a, b []string, maxD int) ([]Edit, bool) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxD)
+	// v[offset+k] — самая дальняя x на диагонали k; trace[d] хранит диагонали от -d до d
+	// перед шагом d — только их и читает backtrack
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace), true
+			}
+		}
+	}
+	return nil, false
+}
+
+// backtrack восстанавливает правки, проходя сохранённые шаги от конца к началу.
+func backtrack(a, b []string, trace [][]int) []Edit {
+	x, y := len(a), len(b)
+	var edits []Edit
+	for d := len(trace) - 1; d >= 0; d-- {
+		// Шаг 0 начинается в начале текстов
+		prevX, prevY := 0, 0
+		if d > 0 {
+			v := func(k int) int { return trace[d][k+d] }
+			k := x - y
+			prevK := k - 1
+			if k == -d || (k != d && v(k-1) < v(k+1)) {
+				prevK = k + 1
+			}
+			prevX = v(prevK)
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			edits = append(edits, Edit{Kind: Equal, Text: a[x], ALine: x, BLine: y})
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, Edit{Kind: Insert, Text: b[prevY], ALine: -1, BLine: prevY})
+			} else {
+				edits = append(edits, Edit{Kind: Delete, Text: a[prevX], ALine: prevX, BLine: -1})
+			}
+		}
+		x, y = prevX, prevY
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// replace возвращает правки, удаляющие все строки a и вставляющие все строки b.
+func replace(a, b []string) []Edit {
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i, line := range a {
+		edits = append(edits, Edit{Kind: Delete, Text: line, ALine: i, BLine: -1})
+	}
+	for i, line := range b {
+		edits = append(edits, Edit{Kind: Insert, Text: line, ALine: -1, BLine: i})
+	}
+	return edits
+}
+
+// Unified записывает разницу текстов a и b в едином формате с context общими строками
+// вокруг каждого изменения. Для одинаковых текстов возвращает пустую строку.
+func Unified(aName, bName, a, b string, context int) string {
+	edits := Diff(Lines(a), Lines(b))
+	var out strings.Builder
+	for i := 0; i < len(edits); {
+		// Ищем начало очередного изменения
+		for i < len(edits) && edits[i].Kind == Equal {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", aName, bName)
+		}
+		start := max(0, i-context)
+		// Изменения, между которыми не больше 2*context общих строк, попадают в один блок
+		end := i
+		for end < len(edits) {
+			if edits[end].Kind != Equal {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Kind == Equal {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end = min(run, end+context)
+				break
+			}
+			end = run
+		}
+		writeHunk(&out, edits, start, end)
+		i = end
+	}
+	return out.String()
+}
+
+// writeHunk записывает блок правок edits[start:end] с заголовком @@.
+func writeHunk(out *strings.Builder, edits []Edit, start, end int) {
+	aStart, bStart := -1, -1
+	var aCount, bCount int
+	// Номера строк до блока нужны для пустой стороны: diff -u пишет строку перед вставкой
+	aBefore, bBefore := 0, 0
+	for _, e := range edits[:start] {
+		if e.ALine >= 0 {
+			aBefore = e.ALine + 1
+		}
+		if e.BLine >= 0 {
+			bBefore = e.BLine + 1
+		}
+	}
+	for _, e := range edits[start:end] {
+		if e.ALine >= 0 {
+			if aStart < 0 {
+				aStart = e.ALine
+			}
+			aCount++
+		}
+		if e.BLine >= 0 {
+			if bStart < 0 {
+				bStart = e.BLine
+			}
+			bCount++
+		}
+	}
+	fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(aStart, aCount, aBefore), hunkRange(bStart, bCount, bBefore))
+	for _, e := range edits[start:end] {
+		switch e.Kind {
+		case Equal:
+			out.WriteString(" ")
+		case Delete:
+			out.WriteString("-")
+		case Insert:
+			out.WriteString("+")
+		}
+		out.WriteString(e.Text)
+		out.WriteString("\n")
+	}
+}
+
+// hunkRange записывает диапазон строк блока: «начало,число» с нумерацией с единицы.
+// Пустой диапазон указывает на строку перед ним, число 1 не пишется.
+func hunkRange(start, count, before int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
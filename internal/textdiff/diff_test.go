@@ -0,0 +1,212 @@
+package textdiff
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "", want: nil},
+		{text: "\n", want: []string{""}},
+		{text: "a", want: []string{"a"}},
+		{text: "a\nb\n", want: []string{"a", "b"}},
+		{text: "a\r\nb\r\n", want: []string{"a", "b"}},
+		{text: "a\n\nb", want: []string{"a", "", "b"}},
+	}
+	for _, tt := range tests {
+		if got := Lines(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lines(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// editScript записывает правки строкой вида " a -b +x".
+func editScript(edits []Edit) string {
+	var parts []string
+	for _, e := range edits {
+		parts = append(parts, string(" -+"[e.Kind])+e.Text)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{name: "оба пустые", a: "", b: "", want: ""},
+		{name: "одинаковые", a: "a\nb", b: "a\nb", want: " a  b"},
+		{name: "вставка в пустой", a: "", b: "a\nb", want: "+a +b"},
+		{name: "удаление всего", a: "a\nb", b: "", want: "-a -b"},
+		{name: "замена строки", a: "a\nb\nc", b: "a\nx\nc", want: " a -b +x  c"},
+		{name: "вставка в начало", a: "b\nc", b: "a\nb\nc", want: "+a  b  c"},
+		{name: "удаление с конца", a: "a\nb\nc", b: "a\nb", want: " a  b -c"},
+		{name: "полностью разные", a: "a\nb", b: "c", want: "-a -b +c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Lines(tt.a), Lines(tt.b)
+			edits := Diff(a, b)
+			if got := editScript(edits); got != tt.want {
+				t.Errorf("Diff = %q, want %q", got, tt.want)
+			}
+			// Номера строк указывают на сами строки в a и b
+			for _, e := range edits {
+				if e.ALine >= 0 && a[e.ALine] != e.Text || e.BLine >= 0 && b[e.BLine] != e.Text {
+					t.Errorf("правка %+v указывает на чужую строку", e)
+				}
+				if (e.Kind == Insert) != (e.ALine < 0) || (e.Kind == Delete) != (e.BLine < 0) {
+					t.Errorf("правка %+v: неверные номера строк", e)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffIsShortest(t *testing.T) {
+	a := Lines("a\nb\nc\na\nb\nb\na")
+	b := Lines("c\nb\na\nb\na\nc")
+	changes := 0
+	for _, e := range Diff(a, b) {
+		if e.Kind != Equal {
+			changes++
+		}
+	}
+	// Пример из статьи Майерса: кратчайший сценарий — 5 правок
+	if changes != 5 {
+		t.Errorf("правок = %d, want 5", changes)
+	}
+}
+
+// numbered возвращает n строк вида «prefix номер», начиная с from.
+func numbered(prefix string, from, n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%s %d", prefix, from+i)
+	}
+	return lines
+}
+
+func TestDiffLargeUnrelated(t *testing.T) {
+	a, b := numbered("a", 0, 4000), numbered("b", 0, 4000)
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	edits := Diff(a, b)
+	runtime.ReadMemStats(&after)
+
+	// Правок больше maxEdits: тексты заменяются целиком
+	if len(edits) != 8000 {
+		t.Fatalf("правок = %d, want 8000", len(edits))
+	}
+	for i, e := range edits {
+		if i < 4000 && e.Kind != Delete || i >= 4000 && e.Kind != Insert {
+			t.Fatalf("правка %d = %+v, want сначала удаления, затем вставки", i, e)
+		}
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 64<<20 {
+		t.Errorf("выделено %d МБ, want не больше 64", allocated>>20)
+	}
+}
+
+func TestDiffLargeWithCommonEnds(t *testing.T) {
+	head, tail := numbered("общая", 0, 3000), numbered("общая", 3000, 3000)
+	a := append(append(append([]string(nil), head...), numbered("a", 0, 2000)...), tail...)
+	b := append(append(append([]string(nil), head...), numbered("b", 0, 2000)...), tail...)
+
+	edits := Diff(a, b)
+	var equal, deleted, inserted int
+	for _, e := range edits {
+		switch e.Kind {
+		case Equal:
+			equal++
+		case Delete:
+			deleted++
+		case Insert:
+			inserted++
+		}
+	}
+	// Общие начало и конец остаются общими, даже когда середина заменяется целиком
+	if equal != 6000 || deleted != 2000 || inserted != 2000 {
+		t.Errorf("общих %d, удалено %d, вставлено %d, want 6000, 2000, 2000", equal, deleted, inserted)
+	}
+	if edits[3000].ALine != 3000 || edits[5000].BLine != 3000 || edits[len(edits)-1].ALine != len(a)-1 {
+		t.Errorf("номера строк сдвинуты неверно: %+v, %+v", edits[3000], edits[5000])
+	}
+}
+
+func TestDiffWithinBudget(t *testing.T) {
+	// Несколько правок в длинном тексте находятся точно
+	a := numbered("строка", 0, 5000)
+	b := append([]string(nil), a...)
+	b[100] = "изменена"
+	b = append(b[:2000], b[2001:]...)
+	b = append(b[:4000], append([]string{"вставлена"}, b[4000:]...)...)
+
+	if got := editScript(Diff(a, b)); strings.Count(got, " -") != 2 || strings.Count(got, " +") != 2 {
+		t.Errorf("правки не кратчайшие: удалено %d, вставлено %d", strings.Count(got, " -"), strings.Count(got, " +"))
+	}
+}
+
+func TestUnified(t *testing.T) {
+	const ten = "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	const tenChanged = "1\nX\n3\n4\n5\n6\n7\n8\nY\n10\n"
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{name: "пустые тексты", a: "", b: "", context: 3, want: ""},
+		{name: "одинаковые тексты", a: ten, b: ten, context: 3, want: ""},
+		{name: "разница только в переводе строки", a: "a\r\nb\r\n", b: "a\nb", context: 3, want: ""},
+		{
+			name: "замена строки", a: "a\nb\nc\n", b: "a\nx\nc\n", context: 1,
+			want: "@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "текст из пустого", a: "", b: "a\nb\nc", context: 3,
+			want: "@@ -0,0 +1,3 @@\n+a\n+b\n+c\n",
+		},
+		{
+			name: "удаление всего текста", a: "a\nb\nc", b: "", context: 3,
+			want: "@@ -1,3 +0,0 @@\n-a\n-b\n-c\n",
+		},
+		{
+			name: "далёкие изменения — два блока", a: ten, b: tenChanged, context: 1,
+			want: "@@ -1,3 +1,3 @@\n 1\n-2\n+X\n 3\n@@ -8,3 +8,3 @@\n 8\n-9\n+Y\n 10\n",
+		},
+		{
+			name: "близкие изменения — один блок", a: ten, b: tenChanged, context: 3,
+			want: "@@ -1,10 +1,10 @@\n 1\n-2\n+X\n 3\n 4\n 5\n 6\n 7\n 8\n-9\n+Y\n 10\n",
+		},
+		{
+			name: "вставка в начало без контекста", a: "1\n2\n3", b: "0\n1\n2\n3", context: 0,
+			want: "@@ -0,0 +1 @@\n+0\n",
+		},
+		{
+			name: "вставка в конец без контекста", a: "1\n2\n3", b: "1\n2\n3\n4", context: 0,
+			want: "@@ -3,0 +4 @@\n+4\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("a", "b", tt.a, tt.b, tt.context)
+			want := tt.want
+			if want != "" {
+				want = "--- a\n+++ b\n" + want
+			}
+			if got != want {
+				t.Errorf("Unified =\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
@@ -2,35 +2,61 @@ package utils
 
 //для работы с внешним api
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 
 	"github.com/EugeneKrivoshein/music_library/config"
+	"github.com/EugeneKrivoshein/music_library/internal/httpclient"
 )
 
+// ErrSongNotFound возвращается, если внешний API не знает песню (ответ 404).
+var ErrSongNotFound = errors.New("песня не найдена во внешнем API")
+
 type SongDetail struct {
 	ReleaseDate string `json:"releaseDate"`
 	Text        string `json:"text"`
 	Link        string `json:"link"`
 }
 
+// APIClient запрашивает сведения о песнях у внешнего API через клиент с повторами
+// и выключателем.
+type APIClient struct {
+	baseURL string
+	client  *httpclient.Client
+}
+
+func NewAPIClient(config *config.Config) *APIClient {
+	clientConfig := httpclient.DefaultConfig()
+	clientConfig.Timeout = config.APITimeout
+	clientConfig.MaxRetries = config.APIMaxRetries
+	clientConfig.FailureThreshold = config.APIBreakerThreshold
+	clientConfig.OpenTimeout = config.APIBreakerCooldown
+	clientConfig.RateLimit = config.APIRateLimit
+	clientConfig.RateBurst = config.APIRateBurst
+	return &APIClient{baseURL: config.APIURL, client: httpclient.NewClient(clientConfig)}
+}
+
 // FetchSongDetails делает запрос к внешнему API и возвращает информацию о песне.
-func FetchSongDetails(config *config.Config, group, song string) (*SongDetail, error) {
-	apiURL := config.APIURL
+func (c *APIClient) FetchSongDetails(ctx context.Context, group, song string) (*SongDetail, error) {
 	params := url.Values{}
 	params.Add("group", group)
 	params.Add("song", song)
 
-	fullURL := fmt.Sprintf("%s/info?%s", apiURL, params.Encode())
+	fullURL := fmt.Sprintf("%s/info?%s", c.baseURL, params.Encode())
 
-	resp, err := http.Get(fullURL)
+	resp, err := c.client.Get(ctx, fullURL)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s - %s", ErrSongNotFound, group, song)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("внешний API вернул ошибку: %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
 	}
@@ -42,3 +68,8 @@ func FetchSongDetails(config *config.Config, group, song string) (*SongDetail, e
 
 	return &songDetail, nil
 }
+
+// Stats возвращает состояние клиента внешнего API.
+func (c *APIClient) Stats() httpclient.Stats {
+	return c.client.Stats()
+}